@@ -58,6 +58,8 @@ const (
 
 	// ActivitySQLEditorQuery is the type for executing query.
 	ActivitySQLEditorQuery ActivityType = "bb.sql-editor.query"
+	// ActivitySQLEditorExport is the type for exporting query results.
+	ActivitySQLEditorExport ActivityType = "bb.sql-editor.export"
 )
 
 func (e ActivityType) String() string {
@@ -96,6 +98,8 @@ func (e ActivityType) String() string {
 		return "bb.project.member.role.update"
 	case ActivitySQLEditorQuery:
 		return "bb.sql-editor.query"
+	case ActivitySQLEditorExport:
+		return "bb.sql-editor.export"
 	}
 	return "bb.activity.unknown"
 }
@@ -177,6 +181,8 @@ type ActivityPipelineTaskFileCommitPayload struct {
 	Branch             string `json:"branch,omitempty"`
 	FilePath           string `json:"filePath,omitempty"`
 	CommitID           string `json:"commitId,omitempty"`
+	// MergeRequestURL is the URL of the merge request opened for the commit, only set in the merge request write back mode.
+	MergeRequestURL string `json:"mergeRequestUrl,omitempty"`
 }
 
 // ActivityPipelineTaskStatementUpdatePayload is the API message payloads for pipeline task statement updates.
@@ -248,7 +254,23 @@ type ActivitySQLEditorQueryPayload struct {
 	DurationNs   int64  `json:"durationNs"`
 	InstanceName string `json:"instanceName"`
 	DatabaseName string `json:"databaseName"`
-	Error        string `json:"error"`
+	// The type of the data source queried with, which is ADMIN if the instance has no read-only data source.
+	DataSourceType DataSourceType `json:"dataSourceType"`
+	Error          string         `json:"error"`
+}
+
+// ActivitySQLEditorExportPayload is the API message payloads for the exported query results info.
+type ActivitySQLEditorExportPayload struct {
+	// Used by activity table to display info without paying the join cost
+	Statement    string `json:"statement"`
+	DurationNs   int64  `json:"durationNs"`
+	InstanceName string `json:"instanceName"`
+	DatabaseName string `json:"databaseName"`
+	// The type of the data source queried with, which is ADMIN if the instance has no read-only data source.
+	DataSourceType DataSourceType  `json:"dataSourceType"`
+	Format         SQLExportFormat `json:"format"`
+	RowCount       int             `json:"rowCount"`
+	Error          string          `json:"error"`
 }
 
 // Activity is the API message for an activity.
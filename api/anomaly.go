@@ -85,6 +85,9 @@ type AnomalyDatabaseSchemaDriftPayload struct {
 	Expect string `json:"expect,omitempty"`
 	// The actual schema dumped from the database
 	Actual string `json:"actual,omitempty"`
+	// The DDL statements migrating the actual schema back to the expected schema
+	// Only available for the database types supported by the schema differ
+	Diff string `json:"diff,omitempty"`
 }
 
 // Anomaly is the API message for an anomaly.
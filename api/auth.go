@@ -2,7 +2,7 @@ package api
 
 import "github.com/bytebase/bytebase/plugin/vcs"
 
-// AuthProvider is the authentication provider which only supports GitLab for now.
+// AuthProvider is the authentication provider which only supports GitLab, GitHub and Gitea for now.
 type AuthProvider struct {
 	Type          vcs.Type `jsonapi:"attr,type"`
 	Name          string   `jsonapi:"attr,name"`
@@ -12,7 +12,7 @@ type AuthProvider struct {
 	Secret string `jsonapi:"attr,secret"`
 }
 
-// GitlabLogin is the API message for logins via Gitlab.
+// GitlabLogin is the API message for logins via Gitlab. It is used by GitHub and Gitea as well.
 type GitlabLogin struct {
 	InstanceURL   string `jsonapi:"attr,instanceUrl"`
 	ApplicationID string `jsonapi:"attr,applicationId"`
@@ -28,6 +28,7 @@ type Column struct {
 	CharacterSet string  `json:"characterSet"`
 	Collation    string  `json:"collation"`
 	Comment      string  `json:"comment"`
+	Extra        string  `json:"extra"`
 }
 
 // ColumnCreate is the API message for creating a column.
@@ -49,6 +50,7 @@ type ColumnCreate struct {
 	CharacterSet string
 	Collation    string
 	Comment      string
+	Extra        string
 }
 
 // ColumnFind is the API message for finding columns.
@@ -9,6 +9,8 @@ import (
 const (
 	// AdminDataSourceName is the name for administrative data source.
 	AdminDataSourceName = "Admin data source"
+	// ReadOnlyDataSourceName is the default name for read-only data source.
+	ReadOnlyDataSourceName = "Read-only data source"
 )
 
 // DataSourceType is the type of data source.
@@ -57,6 +59,10 @@ type DataSource struct {
 	Type     DataSourceType `jsonapi:"attr,type"`
 	Username string         `jsonapi:"attr,username"`
 	Password string         `jsonapi:"attr,password"`
+	// HostOverride and PortOverride are the host and port to connect to instead of the instance ones, e.g. a replica.
+	// Empty means using the host and port of the instance.
+	HostOverride string `jsonapi:"attr,hostOverride"`
+	PortOverride string `jsonapi:"attr,portOverride"`
 }
 
 // DataSourceCreate is the API message for creating a data source.
@@ -70,10 +76,12 @@ type DataSourceCreate struct {
 	DatabaseID int
 
 	// Domain specific fields
-	Name     string         `jsonapi:"attr,name"`
-	Type     DataSourceType `jsonapi:"attr,type"`
-	Username string         `jsonapi:"attr,username"`
-	Password string         `jsonapi:"attr,password"`
+	Name         string         `jsonapi:"attr,name"`
+	Type         DataSourceType `jsonapi:"attr,type"`
+	Username     string         `jsonapi:"attr,username"`
+	Password     string         `jsonapi:"attr,password"`
+	HostOverride string         `jsonapi:"attr,hostOverride"`
+	PortOverride string         `jsonapi:"attr,portOverride"`
 }
 
 // DataSourceFind is the API message for finding data sources.
@@ -103,8 +111,10 @@ type DataSourcePatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	Username *string `jsonapi:"attr,username"`
-	Password *string `jsonapi:"attr,password"`
+	Username     *string `jsonapi:"attr,username"`
+	Password     *string `jsonapi:"attr,password"`
+	HostOverride *string `jsonapi:"attr,hostOverride"`
+	PortOverride *string `jsonapi:"attr,portOverride"`
 }
 
 // DataSourceService is the service for data source.
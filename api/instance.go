@@ -36,6 +36,9 @@ type Instance struct {
 	Username      string  `jsonapi:"attr,username"`
 	// Password is not returned to the client
 	Password string
+	// Capability is the features supported by the engine, which tells the client the applicable tasks and fields.
+	// It's derived from the engine and not persisted.
+	Capability db.Capability `jsonapi:"attr,capability"`
 }
 
 // InstanceCreate is the API message for creating an instance.
@@ -148,6 +148,12 @@ type UpdateSchemaDetail struct {
 	RollbackStatement string `json:"rollbackStatement"`
 	// EarliestAllowedTs the earliest execution time of the change at system local Unix timestamp in nanoseconds.
 	EarliestAllowedTs int64 `jsonapi:"attr,earliestAllowedTs"`
+	// MigrationType is the type of the migration, overriding the MigrationType of the UpdateSchemaContext if set.
+	// It's set when the issue bundles the migration files of different types committed in a VCS push.
+	MigrationType db.MigrationType `json:"migrationType,omitempty"`
+	// VCSPushEvent is the event information for VCS push, overriding the VCSPushEvent of the UpdateSchemaContext if set.
+	// It's set when the issue bundles multiple migration files committed in a VCS push.
+	VCSPushEvent *vcs.PushEvent `json:"vcsPushEvent,omitempty"`
 }
 
 // UpdateSchemaContext is the issue create context for updating database schema.
@@ -155,12 +161,22 @@ type UpdateSchemaContext struct {
 	// MigrationType is the type of a migration.
 	MigrationType db.MigrationType `json:"migrationType"`
 	// UpdateSchemaDetail is the details of schema update.
-	// When a project is in tenant mode, there should be one item in the list.
+	// The details of the same database are performed in order in the same stage.
+	// When a project is in tenant mode, all the items should have the same database name.
 	UpdateSchemaDetailList []*UpdateSchemaDetail `json:"updateSchemaDetailList"`
 	// VCSPushEvent is the event information for VCS push.
 	VCSPushEvent *vcs.PushEvent
 }
 
+// DataSourceRequestContext is the issue create context for requesting the query access to a database in SQL editor.
+// It's also persisted as the issue payload, and the query grant is created once the issue is resolved.
+type DataSourceRequestContext struct {
+	// DatabaseID is the ID of the database to query.
+	DatabaseID int `json:"databaseId"`
+	// ExpireTs is the expiration time of the query access at system local Unix timestamp in seconds.
+	ExpireTs int64 `json:"expireTs"`
+}
+
 // IssueFind is the API message for finding issues.
 type IssueFind struct {
 	ID *int
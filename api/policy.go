@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
 )
 
 // PolicyType is the type or name of a policy.
@@ -15,11 +17,25 @@ type PipelineApprovalValue string
 // BackupPlanPolicySchedule is value for backup plan policy.
 type BackupPlanPolicySchedule string
 
+// QueryDataSourceAdminFallback is value for query data source policy.
+type QueryDataSourceAdminFallback string
+
+// MaskingType is the type of masking the column values in data masking policy.
+type MaskingType string
+
 const (
 	// PolicyTypePipelineApproval is the approval policy type.
 	PolicyTypePipelineApproval PolicyType = "bb.policy.pipeline-approval"
 	// PolicyTypeBackupPlan is the backup plan policy type.
 	PolicyTypeBackupPlan PolicyType = "bb.policy.backup-plan"
+	// PolicyTypeMaxExecutionTime is the max execution time policy type.
+	PolicyTypeMaxExecutionTime PolicyType = "bb.policy.max-execution-time"
+	// PolicyTypeQueryDataSource is the query data source policy type.
+	PolicyTypeQueryDataSource PolicyType = "bb.policy.query-data-source"
+	// PolicyTypeDataMasking is the data masking policy type.
+	PolicyTypeDataMasking PolicyType = "bb.policy.data-masking"
+	// PolicyTypeSQLReview is the SQL review policy type.
+	PolicyTypeSQLReview PolicyType = "bb.policy.sql-review"
 
 	// PipelineApprovalValueManualNever is MANUAL_APPROVAL_NEVER approval policy value.
 	PipelineApprovalValueManualNever PipelineApprovalValue = "MANUAL_APPROVAL_NEVER"
@@ -32,6 +48,18 @@ const (
 	BackupPlanPolicyScheduleDaily BackupPlanPolicySchedule = "DAILY"
 	// BackupPlanPolicyScheduleWeekly is WEEKLY backup plan policy value.
 	BackupPlanPolicyScheduleWeekly BackupPlanPolicySchedule = "WEEKLY"
+
+	// QueryDataSourceAdminFallbackAllow is ALLOW query data source policy value.
+	QueryDataSourceAdminFallbackAllow QueryDataSourceAdminFallback = "ALLOW"
+	// QueryDataSourceAdminFallbackDisallow is DISALLOW query data source policy value.
+	QueryDataSourceAdminFallbackDisallow QueryDataSourceAdminFallback = "DISALLOW"
+
+	// MaskingTypeFull replaces the whole value with the mask.
+	MaskingTypeFull MaskingType = "FULL"
+	// MaskingTypePartial keeps the first and the last quarters of the value, and masks the rest.
+	MaskingTypePartial MaskingType = "PARTIAL"
+	// MaskingTypeHash replaces the value with its SHA-256 hash, so the masked values can still be compared and grouped.
+	MaskingTypeHash MaskingType = "HASH"
 )
 
 var (
@@ -39,6 +67,10 @@ var (
 	PolicyTypes = map[PolicyType]bool{
 		PolicyTypePipelineApproval: true,
 		PolicyTypeBackupPlan:       true,
+		PolicyTypeMaxExecutionTime: true,
+		PolicyTypeQueryDataSource:  true,
+		PolicyTypeDataMasking:      true,
+		PolicyTypeSQLReview:        true,
 	}
 )
 
@@ -97,6 +129,10 @@ type PolicyService interface {
 	UpsertPolicy(ctx context.Context, upsert *PolicyUpsert) (*Policy, error)
 	GetBackupPlanPolicy(ctx context.Context, environmentID int) (*BackupPlanPolicy, error)
 	GetPipelineApprovalPolicy(ctx context.Context, environmentID int) (*PipelineApprovalPolicy, error)
+	GetMaxExecutionTimePolicy(ctx context.Context, environmentID int) (*MaxExecutionTimePolicy, error)
+	GetQueryDataSourcePolicy(ctx context.Context, environmentID int) (*QueryDataSourcePolicy, error)
+	GetDataMaskingPolicy(ctx context.Context, environmentID int) (*DataMaskingPolicy, error)
+	GetSQLReviewPolicy(ctx context.Context, environmentID int) (*SQLReviewPolicy, error)
 }
 
 // PipelineApprovalPolicy is the policy configuration for pipeline approval
@@ -143,6 +179,109 @@ func UnmarshalBackupPlanPolicy(payload string) (*BackupPlanPolicy, error) {
 	return &bp, nil
 }
 
+// MaxExecutionTimePolicy is the policy configuration for the max execution time of the SQL statements.
+// The execution is canceled once exceeding the max execution time, which is not enforced if it's 0.
+type MaxExecutionTimePolicy struct {
+	// QuerySeconds is the max execution time of the SQL editor queries in seconds.
+	QuerySeconds int `json:"querySeconds"`
+	// TaskSeconds is the max execution time of the schema and data update tasks in seconds.
+	TaskSeconds int `json:"taskSeconds"`
+}
+
+func (met MaxExecutionTimePolicy) String() (string, error) {
+	s, err := json.Marshal(met)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalMaxExecutionTimePolicy will unmarshal payload to max execution time policy.
+func UnmarshalMaxExecutionTimePolicy(payload string) (*MaxExecutionTimePolicy, error) {
+	var met MaxExecutionTimePolicy
+	if err := json.Unmarshal([]byte(payload), &met); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal max execution time policy %q: %q", payload, err)
+	}
+	return &met, nil
+}
+
+// QueryDataSourcePolicy is the policy configuration for the data source of the readonly queries.
+// The readonly queries use the read-only data source of the instance, this decides whether to fall back to
+// the admin data source if the instance has no read-only data source.
+type QueryDataSourcePolicy struct {
+	AdminFallback QueryDataSourceAdminFallback `json:"adminFallback"`
+}
+
+func (qds QueryDataSourcePolicy) String() (string, error) {
+	s, err := json.Marshal(qds)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalQueryDataSourcePolicy will unmarshal payload to query data source policy.
+func UnmarshalQueryDataSourcePolicy(payload string) (*QueryDataSourcePolicy, error) {
+	var qds QueryDataSourcePolicy
+	if err := json.Unmarshal([]byte(payload), &qds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal query data source policy %q: %q", payload, err)
+	}
+	return &qds, nil
+}
+
+// DataMaskingPolicy is the policy configuration for masking the column values in the SQL editor query results and exports.
+type DataMaskingPolicy struct {
+	RuleList []*MaskingRule `json:"ruleList"`
+}
+
+// MaskingRule is the rule masking the values of a column.
+type MaskingRule struct {
+	DatabaseName string      `json:"databaseName"`
+	TableName    string      `json:"tableName"`
+	ColumnName   string      `json:"columnName"`
+	Type         MaskingType `json:"type"`
+}
+
+func (dm DataMaskingPolicy) String() (string, error) {
+	s, err := json.Marshal(dm)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalDataMaskingPolicy will unmarshal payload to data masking policy.
+func UnmarshalDataMaskingPolicy(payload string) (*DataMaskingPolicy, error) {
+	var dm DataMaskingPolicy
+	if err := json.Unmarshal([]byte(payload), &dm); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data masking policy %q: %q", payload, err)
+	}
+	return &dm, nil
+}
+
+// SQLReviewPolicy is the policy configuration for the SQL review rules checking the statements of the schema and data update tasks.
+// The rules not in the list are disabled.
+type SQLReviewPolicy struct {
+	RuleList []*advisor.SQLReviewRule `json:"ruleList"`
+}
+
+func (sr SQLReviewPolicy) String() (string, error) {
+	s, err := json.Marshal(sr)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
+// UnmarshalSQLReviewPolicy will unmarshal payload to SQL review policy.
+func UnmarshalSQLReviewPolicy(payload string) (*SQLReviewPolicy, error) {
+	var sr SQLReviewPolicy
+	if err := json.Unmarshal([]byte(payload), &sr); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal SQL review policy %q: %q", payload, err)
+	}
+	return &sr, nil
+}
+
 // ValidatePolicy will validate the policy type and payload values.
 func ValidatePolicy(pType PolicyType, payload string) error {
 	if !PolicyTypes[pType] {
@@ -169,6 +308,50 @@ func ValidatePolicy(pType PolicyType, payload string) error {
 		if bp.Schedule != BackupPlanPolicyScheduleUnset && bp.Schedule != BackupPlanPolicyScheduleDaily && bp.Schedule != BackupPlanPolicyScheduleWeekly {
 			return fmt.Errorf("invalid backup plan policy schedule: %q", bp.Schedule)
 		}
+	case PolicyTypeMaxExecutionTime:
+		met, err := UnmarshalMaxExecutionTimePolicy(payload)
+		if err != nil {
+			return err
+		}
+		if met.QuerySeconds < 0 || met.TaskSeconds < 0 {
+			return fmt.Errorf("invalid max execution time policy, the max execution time must not be negative: %q", payload)
+		}
+	case PolicyTypeQueryDataSource:
+		qds, err := UnmarshalQueryDataSourcePolicy(payload)
+		if err != nil {
+			return err
+		}
+		if qds.AdminFallback != QueryDataSourceAdminFallbackAllow && qds.AdminFallback != QueryDataSourceAdminFallbackDisallow {
+			return fmt.Errorf("invalid query data source policy admin fallback: %q", qds.AdminFallback)
+		}
+	case PolicyTypeDataMasking:
+		dm, err := UnmarshalDataMaskingPolicy(payload)
+		if err != nil {
+			return err
+		}
+		for _, rule := range dm.RuleList {
+			if rule.DatabaseName == "" || rule.TableName == "" || rule.ColumnName == "" {
+				return fmt.Errorf("invalid data masking rule, the database, table and column names are required: %+v", *rule)
+			}
+			if rule.Type != MaskingTypeFull && rule.Type != MaskingTypePartial && rule.Type != MaskingTypeHash {
+				return fmt.Errorf("invalid data masking rule type: %q", rule.Type)
+			}
+		}
+	case PolicyTypeSQLReview:
+		sr, err := UnmarshalSQLReviewPolicy(payload)
+		if err != nil {
+			return err
+		}
+		ruleTypeMap := make(map[advisor.SQLReviewRuleType]bool)
+		for _, rule := range sr.RuleList {
+			if err := rule.Validate(); err != nil {
+				return err
+			}
+			if ruleTypeMap[rule.Type] {
+				return fmt.Errorf("duplicate SQL review rule type: %q", rule.Type)
+			}
+			ruleTypeMap[rule.Type] = true
+		}
 	}
 	return nil
 }
@@ -185,6 +368,24 @@ func GetDefaultPolicy(pType PolicyType) (string, error) {
 		return BackupPlanPolicy{
 			Schedule: BackupPlanPolicyScheduleUnset,
 		}.String()
+	case PolicyTypeMaxExecutionTime:
+		return MaxExecutionTimePolicy{
+			QuerySeconds: 0,
+			TaskSeconds:  0,
+		}.String()
+	case PolicyTypeQueryDataSource:
+		// Allow by default so the instances without read-only data source keep working.
+		return QueryDataSourcePolicy{
+			AdminFallback: QueryDataSourceAdminFallbackAllow,
+		}.String()
+	case PolicyTypeDataMasking:
+		return DataMaskingPolicy{
+			RuleList: []*MaskingRule{},
+		}.String()
+	case PolicyTypeSQLReview:
+		return SQLReviewPolicy{
+			RuleList: []*advisor.SQLReviewRule{},
+		}.String()
 	}
 	return "", nil
 }
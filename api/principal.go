@@ -36,6 +36,10 @@ const (
 	PrincipalAuthProviderBytebase PrincipalAuthProvider = "BYTEBASE"
 	// PrincipalAuthProviderGitlabSelfHost is the self-hosted GitLab authentication provider.
 	PrincipalAuthProviderGitlabSelfHost PrincipalAuthProvider = "GITLAB_SELF_HOST"
+	// PrincipalAuthProviderGitHub is the GitHub authentication provider, for both github.com and GitHub Enterprise Server.
+	PrincipalAuthProviderGitHub PrincipalAuthProvider = "GITHUB"
+	// PrincipalAuthProviderGitea is the self-hosted Gitea authentication provider.
+	PrincipalAuthProviderGitea PrincipalAuthProvider = "GITEA"
 )
 
 // Principal is the API message for principals.
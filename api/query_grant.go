@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// QueryGrant is the API message for a grant of querying a database in SQL editor.
+// The grant is created by resolving the data source request issue, and is no longer effective after it expires.
+type QueryGrant struct {
+	ID int `jsonapi:"primary,queryGrant"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID  int `jsonapi:"attr,databaseId"`
+	PrincipalID int
+	Principal   *Principal `jsonapi:"relation,principal"`
+	IssueID     int        `jsonapi:"attr,issueId"`
+
+	// Domain specific fields
+	ExpireTs int64 `jsonapi:"attr,expireTs"`
+}
+
+// QueryGrantCreate is the API message for creating a query grant.
+type QueryGrantCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	DatabaseID  int
+	PrincipalID int
+	IssueID     int
+
+	// Domain specific fields
+	ExpireTs int64
+}
+
+// QueryGrantFind is the API message for finding query grants.
+type QueryGrantFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID  *int
+	PrincipalID *int
+
+	// Domain specific fields
+	// ExpireTsAfter finds the grants expiring after the timestamp, e.g. the effective grants.
+	ExpireTsAfter *int64
+}
+
+func (find *QueryGrantFind) String() string {
+	str, err := json.Marshal(*find)
+	if err != nil {
+		return err.Error()
+	}
+	return string(str)
+}
+
+// QueryGrantDelete is the API message for deleting a query grant.
+type QueryGrantDelete struct {
+	ID int
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	DeleterID int
+}
+
+// QueryGrantService is the service for query grants.
+type QueryGrantService interface {
+	CreateQueryGrant(ctx context.Context, create *QueryGrantCreate) (*QueryGrant, error)
+	FindQueryGrantList(ctx context.Context, find *QueryGrantFind) ([]*QueryGrant, error)
+	FindQueryGrant(ctx context.Context, find *QueryGrantFind) (*QueryGrant, error)
+	DeleteQueryGrant(ctx context.Context, delete *QueryGrantDelete) error
+}
@@ -3,8 +3,89 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
 )
 
+// RepositoryWriteBackMode is how Bytebase writes back the latest schema file after migration.
+type RepositoryWriteBackMode string
+
+const (
+	// RepositoryWriteBackCommit commits the latest schema file to the branch of the push event directly.
+	RepositoryWriteBackCommit RepositoryWriteBackMode = "COMMIT"
+	// RepositoryWriteBackMergeRequest commits the latest schema file to a new branch and opens a merge request to the branch of the push event.
+	// This is useful when the branch is protected from direct pushes.
+	RepositoryWriteBackMergeRequest RepositoryWriteBackMode = "MERGE_REQUEST"
+)
+
+// ValidateRepositoryWriteBackMode validates the repository write back mode.
+func ValidateRepositoryWriteBackMode(mode RepositoryWriteBackMode) error {
+	switch mode {
+	case RepositoryWriteBackCommit, RepositoryWriteBackMergeRequest:
+		return nil
+	}
+	return fmt.Errorf("invalid write back mode %q", mode)
+}
+
+// BranchFilterRule routes the changes pushed to the matching Git ref to the databases in the environment.
+type BranchFilterRule struct {
+	// Environment is the name of the environment receiving the changes, empty means all environments.
+	Environment string
+	// RefPattern is the glob pattern of the branch name like "release/*",
+	// or the glob pattern of the full Git ref like "refs/tags/v*" to match the tags.
+	RefPattern string
+}
+
+// MatchRef returns true if the Git ref matches the ref pattern of the rule.
+// The bare ref pattern only matches the branches.
+func (rule BranchFilterRule) MatchRef(ref string) bool {
+	name := ref
+	if !strings.HasPrefix(rule.RefPattern, "refs/") {
+		if !strings.HasPrefix(ref, "refs/heads/") {
+			return false
+		}
+		name = strings.TrimPrefix(ref, "refs/heads/")
+	}
+	match, err := filepath.Match(rule.RefPattern, name)
+	return err == nil && match
+}
+
+// ParseBranchFilter parses the branch filter of the repository into the rule list.
+// The branch filter is a comma separated list of "{{ENV_NAME}}={{REF_PATTERN}}" rules, e.g. "Dev=main,Staging=release/*,Prod=refs/tags/v*".
+// A rule without the environment name like "main" applies to all environments, so a plain branch name still works as before.
+func ParseBranchFilter(branchFilter string) ([]BranchFilterRule, error) {
+	var ruleList []BranchFilterRule
+	for _, item := range strings.Split(branchFilter, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		rule := BranchFilterRule{
+			RefPattern: item,
+		}
+		if i := strings.Index(item, "="); i >= 0 {
+			rule.Environment = strings.TrimSpace(item[:i])
+			rule.RefPattern = strings.TrimSpace(item[i+1:])
+			if rule.Environment == "" {
+				return nil, fmt.Errorf("missing environment name in branch filter rule %q", item)
+			}
+		}
+		if rule.RefPattern == "" {
+			return nil, fmt.Errorf("missing ref pattern in branch filter rule %q", item)
+		}
+		if strings.HasPrefix(rule.RefPattern, "refs/") && !strings.HasPrefix(rule.RefPattern, "refs/heads/") && !strings.HasPrefix(rule.RefPattern, "refs/tags/") {
+			return nil, fmt.Errorf("invalid ref pattern %q in branch filter rule %q, only branches and tags are supported", rule.RefPattern, item)
+		}
+		if _, err := filepath.Match(rule.RefPattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid ref pattern %q in branch filter rule %q: %w", rule.RefPattern, item, err)
+		}
+		ruleList = append(ruleList, rule)
+	}
+	return ruleList, nil
+}
+
 // Repository is the API message for a repository.
 type Repository struct {
 	ID int `jsonapi:"primary,repository"`
@@ -34,7 +115,9 @@ type Repository struct {
 	// The file path template for storing the latest schema auto-generated by Bytebase after migration.
 	// If empty, then Bytebase won't auto generate it.
 	SchemaPathTemplate string `jsonapi:"attr,schemaPathTemplate"`
-	ExternalID         string `jsonapi:"attr,externalId"`
+	// The way to write back the latest schema file.
+	WriteBackMode      RepositoryWriteBackMode `jsonapi:"attr,writeBackMode"`
+	ExternalID         string                  `jsonapi:"attr,externalId"`
 	ExternalWebhookID  string
 	WebhookURLHost     string
 	WebhookEndpointID  string
@@ -56,14 +139,15 @@ type RepositoryCreate struct {
 	ProjectID int
 
 	// Domain specific fields
-	Name               string `jsonapi:"attr,name"`
-	FullPath           string `jsonapi:"attr,fullPath"`
-	WebURL             string `jsonapi:"attr,webUrl"`
-	BranchFilter       string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate string `jsonapi:"attr,schemaPathTemplate"`
-	ExternalID         string `jsonapi:"attr,externalId"`
+	Name               string                  `jsonapi:"attr,name"`
+	FullPath           string                  `jsonapi:"attr,fullPath"`
+	WebURL             string                  `jsonapi:"attr,webUrl"`
+	BranchFilter       string                  `jsonapi:"attr,branchFilter"`
+	BaseDirectory      string                  `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate   string                  `jsonapi:"attr,filePathTemplate"`
+	SchemaPathTemplate string                  `jsonapi:"attr,schemaPathTemplate"`
+	WriteBackMode      RepositoryWriteBackMode `jsonapi:"attr,writeBackMode"`
+	ExternalID         string                  `jsonapi:"attr,externalId"`
 	// Token belonged by the user linking the project to the VCS repository. We store this token together
 	// with the refresh token in the new repository record so we can use it to call VCS API on
 	// behalf of that user to perform tasks like webhook CRUD later.
@@ -105,10 +189,11 @@ type RepositoryPatch struct {
 	UpdaterID int
 
 	// Domain specific fields
-	BranchFilter       *string `jsonapi:"attr,branchFilter"`
-	BaseDirectory      *string `jsonapi:"attr,baseDirectory"`
-	FilePathTemplate   *string `jsonapi:"attr,filePathTemplate"`
-	SchemaPathTemplate *string `jsonapi:"attr,schemaPathTemplate"`
+	BranchFilter       *string                  `jsonapi:"attr,branchFilter"`
+	BaseDirectory      *string                  `jsonapi:"attr,baseDirectory"`
+	FilePathTemplate   *string                  `jsonapi:"attr,filePathTemplate"`
+	SchemaPathTemplate *string                  `jsonapi:"attr,schemaPathTemplate"`
+	WriteBackMode      *RepositoryWriteBackMode `jsonapi:"attr,writeBackMode"`
 	AccessToken        *string
 	ExpiresTs          *int64
 	RefreshToken       *string
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestParseBranchFilter(t *testing.T) {
+	tests := []struct {
+		name         string
+		branchFilter string
+		want         []BranchFilterRule
+		errPart      string
+	}{
+		{
+			"Empty",
+			"",
+			nil,
+			"",
+		}, {
+			"Plain branch",
+			"main",
+			[]BranchFilterRule{{RefPattern: "main"}},
+			"",
+		}, {
+			"Rule list",
+			"Dev=main, Staging = release/*,Prod=refs/tags/v*",
+			[]BranchFilterRule{
+				{Environment: "Dev", RefPattern: "main"},
+				{Environment: "Staging", RefPattern: "release/*"},
+				{Environment: "Prod", RefPattern: "refs/tags/v*"},
+			},
+			"",
+		}, {
+			"Missing environment name",
+			"=main",
+			nil,
+			"missing environment name",
+		}, {
+			"Missing ref pattern",
+			"Prod=",
+			nil,
+			"missing ref pattern",
+		}, {
+			"Unsupported ref",
+			"Prod=refs/merge-requests/*",
+			nil,
+			"only branches and tags are supported",
+		}, {
+			"Bad pattern",
+			"Prod=release/[",
+			nil,
+			"invalid ref pattern",
+		},
+	}
+
+	for _, test := range tests {
+		ruleList, err := ParseBranchFilter(test.branchFilter)
+		if err != nil {
+			if test.errPart == "" || !strings.Contains(err.Error(), test.errPart) {
+				t.Errorf("%q: ParseBranchFilter(%q) got error %q, want error part %q.", test.name, test.branchFilter, err.Error(), test.errPart)
+			}
+			continue
+		}
+		if test.errPart != "" {
+			t.Errorf("%q: ParseBranchFilter(%q) got no error, want error part %q.", test.name, test.branchFilter, test.errPart)
+			continue
+		}
+		diff := pretty.Diff(ruleList, test.want)
+		if len(diff) > 0 {
+			t.Errorf("%q: ParseBranchFilter(%q) got rule list %+v, want %+v, diff %+v.", test.name, test.branchFilter, ruleList, test.want, diff)
+		}
+	}
+}
+
+func TestBranchFilterRuleMatchRef(t *testing.T) {
+	tests := []struct {
+		rule BranchFilterRule
+		ref  string
+		want bool
+	}{
+		{
+			BranchFilterRule{RefPattern: "main"},
+			"refs/heads/main",
+			true,
+		}, {
+			BranchFilterRule{RefPattern: "main"},
+			"refs/tags/main",
+			false,
+		}, {
+			BranchFilterRule{RefPattern: "release/*"},
+			"refs/heads/release/1.0",
+			true,
+		}, {
+			BranchFilterRule{RefPattern: "release/*"},
+			"refs/heads/release/1.0/hotfix",
+			false,
+		}, {
+			BranchFilterRule{RefPattern: "refs/tags/v*"},
+			"refs/tags/v1.0.0",
+			true,
+		}, {
+			BranchFilterRule{RefPattern: "refs/tags/v*"},
+			"refs/heads/v1.0.0",
+			false,
+		}, {
+			BranchFilterRule{RefPattern: "refs/heads/main"},
+			"refs/heads/main",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		got := test.rule.MatchRef(test.ref)
+		if got != test.want {
+			t.Errorf("%+v.MatchRef(%q) = %v, want %v", test.rule, test.ref, got, test.want)
+		}
+	}
+}
@@ -34,13 +34,101 @@ type SQLExecute struct {
 	// The maximum row count returned, only applicable to SELECT query.
 	// Not enforced if limit <= 0.
 	Limit int `jsonapi:"attr,limit"`
+	// The client generated ID to cancel the running query with, the query can't be canceled if it's empty.
+	QueryID string `jsonapi:"attr,queryId"`
+}
+
+// SQLCancel is the API message for canceling a running query.
+type SQLCancel struct {
+	// The client generated ID of the running query of the current principal.
+	QueryID string `jsonapi:"attr,queryId"`
 }
 
 // SQLResultSet is the API message for SQL results.
 type SQLResultSet struct {
 	// A list of rows marshalled into a JSON.
+	// For the multi-statement script, it's the rows of the last statement.
 	Data string `jsonapi:"attr,data"`
 	// SQL operation may fail for connection issue and there is no proper http status code for it, so we return error in the response body.
+	// For the multi-statement script, it's the error of the last statement unless the whole script fails.
+	Error string `jsonapi:"attr,error"`
+	// The list of SingleSQLResult of the multi-statement script in the statement order, marshalled into a JSON.
+	ResultList string `jsonapi:"attr,resultList"`
+}
+
+// SingleSQLResult is the result of a statement in the multi-statement script.
+type SingleSQLResult struct {
+	Statement string `json:"statement"`
+	// A list of rows, each row is an object keyed by the column names.
+	Data       []interface{} `json:"data"`
+	RowCount   int           `json:"rowCount"`
+	DurationNs int64         `json:"durationNs"`
+	// Each statement may fail on its own, while the following statements are still executed.
+	Error string `json:"error"`
+}
+
+// SQLExplainResult is the API message for the query plan of the readonly SQL statement.
+// The /sql/explain request is the same SQLExecute message, while the statement is only explained without being executed.
+type SQLExplainResult struct {
+	// The db.QueryPlan marshalled into a JSON.
+	Plan string `jsonapi:"attr,plan"`
+	// Explaining may fail for connection issue and there is no proper http status code for it, so we return error in the response body.
+	Error string `jsonapi:"attr,error"`
+}
+
+// SQLExportFormat is the file format of the exported SQL results.
+type SQLExportFormat string
+
+const (
+	// SQLExportFormatCSV is the CSV format with a header row of the column names.
+	SQLExportFormatCSV SQLExportFormat = "CSV"
+	// SQLExportFormatJSONL is the JSON Lines format, each row is a JSON object keyed by the column names.
+	SQLExportFormatJSONL SQLExportFormat = "JSONL"
+	// SQLExportFormatXLSX is the Excel format with a header row of the column names.
+	SQLExportFormatXLSX SQLExportFormat = "XLSX"
+)
+
+// SQLExport is the API message for exporting SQL results.
+type SQLExport struct {
+	InstanceID int `jsonapi:"attr,instanceId"`
+	// For engines like MySQL, databaseName can be empty.
+	DatabaseName string          `jsonapi:"attr,databaseName"`
+	Statement    string          `jsonapi:"attr,statement"`
+	Format       SQLExportFormat `jsonapi:"attr,format"`
+	// The maximum row count exported, which is capped by the server.
+	// The cap is used if limit <= 0.
+	Limit int `jsonapi:"attr,limit"`
+	// The client generated ID to cancel the running export with, the export can't be canceled if it's empty.
+	QueryID string `jsonapi:"attr,queryId"`
+}
+
+// SQLResultStreamMessage is the API message for streaming SQL results, which are sent as newline delimited JSON messages.
+// The first message carries the columns, the following messages carry the row batches,
+// and the last message carries either the error or the done flag with the total row count.
+type SQLResultStreamMessage struct {
+	ColumnList []*db.QueryColumn `json:"columnList,omitempty"`
+	// The rows in the batch, whose values are in the column order.
+	RowList [][]interface{} `json:"rowList,omitempty"`
+	// The query may fail after the response starts, so we return error in the last message.
+	Error    string `json:"error,omitempty"`
+	Done     bool   `json:"done,omitempty"`
+	RowCount int    `json:"rowCount,omitempty"`
+}
+
+// SQLSchemaDiff is the API message for diffing the schemas of two databases.
+type SQLSchemaDiff struct {
+	// The database whose schema is to be migrated.
+	SourceDatabaseID int `jsonapi:"attr,sourceDatabaseId"`
+	// The database whose schema is the migration target.
+	TargetDatabaseID int `jsonapi:"attr,targetDatabaseId"`
+}
+
+// SQLSchemaDiffResult is the API message for schema diff results.
+type SQLSchemaDiffResult struct {
+	// The DDL statements migrating the source database schema to the target database schema.
+	// Empty if the schemas are identical.
+	Statement string `jsonapi:"attr,statement"`
+	// Diffing may fail for connection issue and there is no proper http status code for it, so we return error in the response body.
 	Error string `jsonapi:"attr,error"`
 }
 
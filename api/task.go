@@ -185,9 +185,15 @@ type TaskFind struct {
 	// Related fields
 	PipelineID *int
 	StageID    *int
+	// ProjectID finds the tasks of the issues in the project.
+	ProjectID *int
 
 	// Domain specific fields
 	StatusList *[]TaskStatus
+	// IssueStatusList finds the tasks of the issues in the statuses, e.g. the open issues.
+	IssueStatusList *[]IssueStatus
+	// VCSPushEventFile finds the tasks created from the committed file of the VCS push event.
+	VCSPushEventFile *string
 }
 
 func (find *TaskFind) String() string {
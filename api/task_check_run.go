@@ -73,6 +73,8 @@ const (
 	TaskCheckDatabaseStatementSyntax TaskCheckType = "bb.task-check.database.statement.syntax"
 	// TaskCheckDatabaseStatementCompatibility is the task check type for statement compatibility.
 	TaskCheckDatabaseStatementCompatibility TaskCheckType = "bb.task-check.database.statement.compatibility"
+	// TaskCheckDatabaseStatementSQLReview is the task check type for the SQL review rules of the environment SQL review policy.
+	TaskCheckDatabaseStatementSQLReview TaskCheckType = "bb.task-check.database.statement.sql-review"
 	// TaskCheckDatabaseConnect is the task check type for database connection.
 	TaskCheckDatabaseConnect TaskCheckType = "bb.task-check.database.connect"
 	// TaskCheckInstanceMigrationSchema is the task check type for migrating schemas.
@@ -100,6 +102,20 @@ type TaskCheckResult struct {
 	Code    common.Code     `json:"code,omitempty"`
 	Title   string          `json:"title,omitempty"`
 	Content string          `json:"content,omitempty"`
+	// Position is the position of the offending statement in the task statement, it's only set for the statement advisor checks.
+	Position *TaskCheckResultPosition `json:"position,omitempty"`
+	// Replacement is the statement replacing the offending statement to fix the result, it's empty if there is no mechanical fix.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// TaskCheckResultPosition is the position of the statement in the task statement.
+type TaskCheckResultPosition struct {
+	// StatementIndex is the 0-based index of the statement.
+	StatementIndex int `json:"statementIndex"`
+	// Line and Column are the 1-based line and column where the statement starts.
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Snippet string `json:"snippet"`
 }
 
 // TaskCheckRunResultPayload is the result payload of a task check run.
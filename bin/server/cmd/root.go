@@ -45,6 +45,8 @@ import (
 	_ "github.com/bytebase/bytebase/plugin/advisor/fake"
 	// Register mysql advisor.
 	_ "github.com/bytebase/bytebase/plugin/advisor/mysql"
+	// Register postgres advisor.
+	_ "github.com/bytebase/bytebase/plugin/advisor/pg"
 )
 
 // -----------------------------------Global constant BEGIN----------------------------------------
@@ -386,6 +388,7 @@ func (m *Main) Run(ctx context.Context) error {
 	s.LabelService = store.NewLabelService(m.l, db)
 	s.DeploymentConfigService = store.NewDeploymentConfigService(m.l, db)
 	s.SheetService = store.NewSheetService(m.l, db)
+	s.QueryGrantService = store.NewQueryGrantService(m.l, db)
 
 	s.ActivityManager = server.NewActivityManager(s, s.ActivityService)
 
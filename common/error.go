@@ -22,6 +22,7 @@ const (
 	DbConnectionFailure    Code = 101
 	DbStatementSyntaxError Code = 102
 	DbExecutionError       Code = 103
+	DbStatementNotVerified Code = 104
 
 	// 201 db migration error
 	// Db migration is a core feature, so we separate it from the db error
@@ -34,17 +35,37 @@ const (
 	TaskTimingNotAllowed Code = 301
 
 	// 10001 advisor error code
-	CompatibilityDropDatabase  Code = 10001
-	CompatibilityRenameTable   Code = 10002
-	CompatibilityDropTable     Code = 10003
-	CompatibilityRenameColumn  Code = 10004
-	CompatibilityDropColumn    Code = 10005
-	CompatibilityAddPrimaryKey Code = 10006
-	CompatibilityAddUniqueKey  Code = 10007
-	CompatibilityAddForeignKey Code = 10008
-	CompatibilityAddCheck      Code = 10009
-	CompatibilityAlterCheck    Code = 10010
-	CompatibilityAlterColumn   Code = 10011
+	CompatibilityDropDatabase               Code = 10001
+	CompatibilityRenameTable                Code = 10002
+	CompatibilityDropTable                  Code = 10003
+	CompatibilityRenameColumn               Code = 10004
+	CompatibilityDropColumn                 Code = 10005
+	CompatibilityAddPrimaryKey              Code = 10006
+	CompatibilityAddUniqueKey               Code = 10007
+	CompatibilityAddForeignKey              Code = 10008
+	CompatibilityAddCheck                   Code = 10009
+	CompatibilityAlterCheck                 Code = 10010
+	CompatibilityAlterColumn                Code = 10011
+	CompatibilityAddNotNullColumn           Code = 10012
+	CompatibilityCreateIndexNotConcurrently Code = 10013
+	CompatibilityTableNotFound              Code = 10014
+	CompatibilityColumnNotFound             Code = 10015
+	CompatibilityMixConcurrentIndex         Code = 10016
+	CompatibilityAddIndexNotOnline          Code = 10017
+	CompatibilityTableExists                Code = 10018
+
+	// 10101 SQL review rule error code
+	StatementSelectAll      Code = 10101
+	StatementNoWhere        Code = 10102
+	StatementDropDisallowed Code = 10103
+	TableNoPK               Code = 10104
+	IndexCountExceedsLimit  Code = 10105
+
+	NamingTableConventionMismatch  Code = 10106
+	NamingColumnConventionMismatch Code = 10107
+	NamingIndexConventionMismatch  Code = 10108
+	NamingUKConventionMismatch     Code = 10109
+	NamingFKConventionMismatch     Code = 10110
 )
 
 // Error represents an application-specific error. Application errors can be
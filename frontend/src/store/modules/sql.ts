@@ -1,17 +1,24 @@
 import axios from "axios";
 import {
   ConnectionInfo,
+  ExportInfo,
   InstanceId,
   INSTANCE_OPERATION_TIMEOUT,
+  QueryColumn,
   QueryInfo,
   ResourceObject,
+  SqlExplainResult,
   SqlResultSet,
+  SqlResultStreamMessage,
 } from "../../types";
 
 function convert(resultSet: ResourceObject): SqlResultSet {
   return {
     data: JSON.parse((resultSet.attributes.data as string) || "{}"),
     error: resultSet.attributes.error as string,
+    resultList: JSON.parse(
+      (resultSet.attributes.resultList as string) || "[]"
+    ),
   };
 }
 
@@ -90,6 +97,148 @@ const actions = {
     const resultSet = convert(data);
     return resultSet.data;
   },
+  // Execute the multi-statement script, and return the result of each
+  // statement in order.
+  async queryMulti({ dispatch }: any, queryInfo: QueryInfo) {
+    const data = (
+      await axios.post(
+        `/api/sql/execute`,
+        {
+          data: {
+            type: "sqlExecute",
+            attributes: {
+              ...queryInfo,
+              readonly: true,
+            },
+          },
+        },
+        {
+          timeout: INSTANCE_OPERATION_TIMEOUT,
+        }
+      )
+    ).data.data;
+
+    return convert(data);
+  },
+  // Explain the query plan without executing the query.
+  async explain(
+    { dispatch }: any,
+    queryInfo: QueryInfo
+  ): Promise<SqlExplainResult> {
+    const data = (
+      await axios.post(
+        `/api/sql/explain`,
+        {
+          data: {
+            type: "sqlExecute",
+            attributes: {
+              ...queryInfo,
+              readonly: true,
+            },
+          },
+        },
+        {
+          timeout: INSTANCE_OPERATION_TIMEOUT,
+        }
+      )
+    ).data.data;
+
+    const plan = data.attributes.plan as string;
+    return {
+      plan: plan ? JSON.parse(plan) : undefined,
+      error: data.attributes.error as string,
+    };
+  },
+  // The exported file is generated by the server, so each export is recorded
+  // as an activity.
+  async export({ dispatch }: any, exportInfo: ExportInfo): Promise<Blob> {
+    return (
+      await axios.post(
+        `/api/sql/export`,
+        {
+          data: {
+            type: "sqlExport",
+            attributes: exportInfo,
+          },
+        },
+        {
+          responseType: "blob",
+          timeout: INSTANCE_OPERATION_TIMEOUT,
+        }
+      )
+    ).data;
+  },
+  // Cancel the running query, which is canceled on the database server as well.
+  async cancel({ dispatch }: any, queryId: string) {
+    await axios.post(`/api/sql/cancel`, {
+      data: {
+        type: "sqlCancel",
+        attributes: {
+          queryId,
+        },
+      },
+    });
+  },
+  // Receive the query result in row batches, so the server doesn't need to
+  // hold all the rows in memory.
+  async queryStream({ dispatch }: any, queryInfo: QueryInfo) {
+    const response = await fetch(`/api/sql/execute/stream`, {
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+      },
+      body: JSON.stringify({
+        data: {
+          type: "sqlExecute",
+          attributes: {
+            ...queryInfo,
+            readonly: true,
+          },
+        },
+      }),
+    });
+    if (!response.ok || !response.body) {
+      throw new Error(await response.text());
+    }
+
+    let columnList: QueryColumn[] = [];
+    const rowList: Record<string, any>[] = [];
+    const handleLine = (line: string) => {
+      if (line.trim() === "") {
+        return;
+      }
+      const message = JSON.parse(line) as SqlResultStreamMessage;
+      if (message.error) {
+        throw new Error(message.error);
+      }
+      if (message.columnList) {
+        columnList = message.columnList;
+      }
+      for (const row of message.rowList || []) {
+        const rowData: Record<string, any> = {};
+        columnList.forEach((column, i) => {
+          rowData[column.name] = row[i];
+        });
+        rowList.push(rowData);
+      }
+    };
+
+    const reader = response.body.getReader();
+    const decoder = new TextDecoder();
+    let buffer = "";
+    for (;;) {
+      const { done, value } = await reader.read();
+      if (done) {
+        break;
+      }
+      buffer += decoder.decode(value, { stream: true });
+      const lineList = buffer.split("\n");
+      buffer = lineList.pop() || "";
+      lineList.forEach(handleLine);
+    }
+    handleLine(buffer);
+    return rowList;
+  },
 };
 
 const mutations = {};
@@ -1,5 +1,6 @@
 import dayjs from "dayjs";
 import { isEmpty } from "lodash-es";
+import { v1 as uuidv1 } from "uuid";
 
 import {
   SqlEditorState,
@@ -37,6 +38,7 @@ const state: () => SqlEditorState = () => ({
   connectionTree: [],
   connectionContext: getDefaultConnectionContext(),
   isExecuting: false,
+  executingQueryId: "",
   isShowExecutingHint: false,
   shouldSetContent: false,
   // Related data and status
@@ -187,24 +189,50 @@ const actions = {
     setIsFetchingQueryHistory: types.SET_IS_FETCHING_QUERY_HISTORY,
   }),
   async executeQuery(
-    { dispatch, state, rootGetters }: any,
+    { commit, dispatch, state, rootGetters }: any,
     payload: Partial<QueryInfo> = {}
   ) {
     const currentTab = rootGetters["tab/currentTab"];
-    const queryResult = await dispatch(
-      "sql/query",
+    const queryId = uuidv1();
+    commit(types.SET_SQL_EDITOR_STATE, { executingQueryId: queryId });
+    let queryResult;
+    try {
+      queryResult = await dispatch(
+        "sql/queryStream",
+        {
+          instanceId: state.connectionContext.instanceId,
+          databaseName: state.connectionContext.databaseName,
+          statement: currentTab.selectedStatement || currentTab.statement,
+          queryId,
+          ...payload,
+        },
+        { root: true }
+      );
+    } finally {
+      commit(types.SET_SQL_EDITOR_STATE, { executingQueryId: "" });
+    }
+
+    dispatch("tab/updateCurrentTab", { queryResult }, { root: true });
+    dispatch("fetchQueryHistoryList");
+    return queryResult;
+  },
+  async cancelQuery({ dispatch, state }: any) {
+    if (state.executingQueryId) {
+      await dispatch("sql/cancel", state.executingQueryId, { root: true });
+    }
+  },
+  async exportQuery({ dispatch, state, rootGetters }: any, format: string) {
+    const currentTab = rootGetters["tab/currentTab"];
+    return await dispatch(
+      "sql/export",
       {
         instanceId: state.connectionContext.instanceId,
         databaseName: state.connectionContext.databaseName,
         statement: currentTab.selectedStatement || currentTab.statement,
-        ...payload,
+        format,
       },
       { root: true }
     );
-
-    dispatch("tab/updateCurrentTab", { queryResult }, { root: true });
-    dispatch("fetchQueryHistoryList");
-    return queryResult;
   },
   async fetchConnectionByInstanceIdAndDatabaseId(
     { commit, dispatch }: any,
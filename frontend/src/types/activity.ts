@@ -113,6 +113,7 @@ export type ActivityTaskFileCommitPayload = {
   branch: string;
   filePath: string;
   commitId: string;
+  mergeRequestUrl?: string;
 };
 
 export type ActivityTaskStatementUpdatePayload = {
@@ -1,7 +1,7 @@
 // Auth
 
-// For now, a single user's auth provider should either belong to  GITLAB_SELF_HOST or BYTEBASE
-export type AuthProviderType = "GITLAB_SELF_HOST" | "BYTEBASE";
+// For now, a single user's auth provider should either belong to GITLAB_SELF_HOST, GITHUB, GITEA or BYTEBASE
+export type AuthProviderType = "GITLAB_SELF_HOST" | "GITHUB" | "GITEA" | "BYTEBASE";
 
 export type LoginInfo = {
   authProvider: AuthProviderType;
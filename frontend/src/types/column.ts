@@ -23,4 +23,5 @@ export type Column = {
   characterSet: string;
   collation: string;
   comment: string;
+  extra: string;
 };
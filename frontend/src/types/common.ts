@@ -248,6 +248,15 @@ export const unknown = (
     engine: "MYSQL",
     engineVersion: "",
     host: "",
+    capability: {
+      transactionalDDL: false,
+      indexSync: false,
+      collation: false,
+      dumpWithData: false,
+      explain: false,
+      cancel: false,
+      userGrant: false,
+    },
   };
 
   const UNKNOWN_DATABASE: Database = {
@@ -280,6 +289,8 @@ export const unknown = (
     memberList: [],
     name: "<<Unknown data source>>",
     type: "RO",
+    hostOverride: "",
+    portOverride: "",
   };
 
   const UNKNOWN_BACKUP_SETTING: BackupSetting = {
@@ -423,6 +434,7 @@ export const unknown = (
     branchFilter: "",
     filePathTemplate: "",
     schemaPathTemplate: "",
+    writeBackMode: "COMMIT",
     externalId: UNKNOWN_ID.toString(),
   };
 
@@ -636,6 +648,15 @@ export const empty = (
     engine: "MYSQL",
     engineVersion: "",
     host: "",
+    capability: {
+      transactionalDDL: false,
+      indexSync: false,
+      collation: false,
+      dumpWithData: false,
+      explain: false,
+      cancel: false,
+      userGrant: false,
+    },
   };
 
   const EMPTY_DATABASE: Database = {
@@ -668,6 +689,8 @@ export const empty = (
     memberList: [],
     name: "",
     type: "RO",
+    hostOverride: "",
+    portOverride: "",
   };
 
   const EMPTY_BACKUP_SETTING: BackupSetting = {
@@ -811,6 +834,7 @@ export const empty = (
     branchFilter: "",
     filePathTemplate: "",
     schemaPathTemplate: "",
+    writeBackMode: "COMMIT",
     externalId: EMPTY_ID.toString(),
   };
 
@@ -37,6 +37,9 @@ export type DataSource = {
   // In mysql, username can be empty which means anonymous user
   username?: string;
   password?: string;
+  // Connect to a different host and port than the instance, e.g. a read replica.
+  hostOverride: string;
+  portOverride: string;
 };
 
 export type DataSourceCreate = {
@@ -50,6 +53,8 @@ export type DataSourceCreate = {
   type: DataSourceType;
   username?: string;
   password?: string;
+  hostOverride?: string;
+  portOverride?: string;
 };
 
 export type DataSourcePatch = {
@@ -57,6 +62,8 @@ export type DataSourcePatch = {
   name?: string;
   username?: string;
   password?: string;
+  hostOverride?: string;
+  portOverride?: string;
 };
 
 export type DataSourceMember = {
@@ -12,6 +12,7 @@ export enum DBErrorCode {
   CONNECTION_ERROR = 101,
   SYNTAX_ERROR = 102,
   EXECUTION_ERROR = 103,
+  STATEMENT_NOT_VERIFIED = 104,
 }
 
 export enum MigrationErrorCode {
@@ -33,13 +34,34 @@ export enum CompatibilityErrorCode {
   COMPATIBILITY_ADD_CHECK = 10009,
   COMPATIBILITY_ALTER_CHECK = 10010,
   COMPATIBILITY_ALTER_COLUMN = 10011,
+  COMPATIBILITY_ADD_NOT_NULL_COLUMN = 10012,
+  COMPATIBILITY_CREATE_INDEX_NOT_CONCURRENTLY = 10013,
+  COMPATIBILITY_TABLE_NOT_FOUND = 10014,
+  COMPATIBILITY_COLUMN_NOT_FOUND = 10015,
+  COMPATIBILITY_MIX_CONCURRENT_INDEX = 10016,
+  COMPATIBILITY_ADD_INDEX_NOT_ONLINE = 10017,
+  COMPATIBILITY_TABLE_EXISTS = 10018,
+}
+
+export enum SQLReviewErrorCode {
+  STATEMENT_SELECT_ALL = 10101,
+  STATEMENT_NO_WHERE = 10102,
+  STATEMENT_DROP_DISALLOWED = 10103,
+  TABLE_NO_PK = 10104,
+  INDEX_COUNT_EXCEEDS_LIMIT = 10105,
+  NAMING_TABLE_CONVENTION_MISMATCH = 10106,
+  NAMING_COLUMN_CONVENTION_MISMATCH = 10107,
+  NAMING_INDEX_CONVENTION_MISMATCH = 10108,
+  NAMING_UK_CONVENTION_MISMATCH = 10109,
+  NAMING_FK_CONVENTION_MISMATCH = 10110,
 }
 
 export type ErrorCode =
   | GeneralErrorCode
   | DBErrorCode
   | MigrationErrorCode
-  | CompatibilityErrorCode;
+  | CompatibilityErrorCode
+  | SQLReviewErrorCode;
 
 export type ErrorTag = "General" | "Compatibility";
 
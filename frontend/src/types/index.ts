@@ -39,3 +39,4 @@ export * from "./sqlEditor";
 export * from "./tab";
 export * from "./subscription";
 export * from "./sheet";
+export * from "./queryGrant";
@@ -41,6 +41,17 @@ export function defaultCollation(type: EngineType): string {
   }
 }
 
+// InstanceCapability is the features supported by the engine, which tell the applicable tasks and fields.
+export type InstanceCapability = {
+  transactionalDDL: boolean;
+  indexSync: boolean;
+  collation: boolean;
+  dumpWithData: boolean;
+  explain: boolean;
+  cancel: boolean;
+  userGrant: boolean;
+};
+
 export type Instance = {
   id: InstanceId;
 
@@ -65,6 +76,7 @@ export type Instance = {
   // In mysql, username can be empty which means anonymous user
   username?: string;
   password?: string;
+  capability: InstanceCapability;
 };
 
 export type InstanceCreate = {
@@ -49,12 +49,19 @@ export type UpdateSchemaContext = {
   updateSchemaDetailList: UpdateSchemaDetail[];
 };
 
+// The query access to the database is granted once the issue is resolved, until expireTs.
+export type DataSourceRequestContext = {
+  databaseId: DatabaseId;
+  expireTs: number;
+};
+
 // eslint-disable-next-line @typescript-eslint/ban-types
 export type EmptyContext = {};
 
 export type IssueCreateContext =
   | CreateDatabaseContext
   | UpdateSchemaContext
+  | DataSourceRequestContext
   | EmptyContext;
 
 export type IssuePayload = { [key: string]: any };
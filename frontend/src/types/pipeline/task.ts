@@ -159,6 +159,7 @@ export type TaskCheckType =
   | "bb.task-check.database.statement.fake-advise"
   | "bb.task-check.database.statement.syntax"
   | "bb.task-check.database.statement.compatibility"
+  | "bb.task-check.database.statement.sql-review"
   | "bb.task-check.database.connect"
   | "bb.task-check.instance.migration-schema"
   | "bb.task-check.general.earliest-allowed-time";
@@ -169,11 +170,24 @@ export type TaskCheckDatabaseStatementAdvisePayload = {
 
 export type TaskCheckStatus = "SUCCESS" | "WARN" | "ERROR";
 
+export type TaskCheckResultPosition = {
+  // 0-based index of the statement
+  statementIndex: number;
+  // 1-based line and column where the statement starts
+  line: number;
+  column: number;
+  snippet: string;
+};
+
 export type TaskCheckResult = {
   status: TaskCheckStatus;
   code: ErrorCode;
   title: string;
   content: string;
+  // Only set for the statement advisor checks
+  position?: TaskCheckResultPosition;
+  // The statement replacing the offending statement to fix the result
+  replacement?: string;
 };
 
 export type TaskCheckRunResultPayload = {
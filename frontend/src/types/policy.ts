@@ -2,7 +2,11 @@ import { Environment, PolicyId, Principal } from ".";
 
 export type PolicyType =
   | "bb.policy.pipeline-approval"
-  | "bb.policy.backup-plan";
+  | "bb.policy.backup-plan"
+  | "bb.policy.max-execution-time"
+  | "bb.policy.query-data-source"
+  | "bb.policy.data-masking"
+  | "bb.policy.sql-review";
 
 export type PipelineApprovalPolicyValue =
   | "MANUAL_APPROVAL_NEVER"
@@ -23,9 +27,64 @@ export type PolicyBackupPlanPolicyPayload = {
 
 export const DefaultSchedulePolicy: BackupPlanPolicySchedule = "UNSET";
 
+// The max execution time in seconds, 0 means no limit.
+export type MaxExecutionTimePolicyPayload = {
+  querySeconds: number;
+  taskSeconds: number;
+};
+
+export type QueryDataSourceAdminFallback = "ALLOW" | "DISALLOW";
+
+export type QueryDataSourcePolicyPayload = {
+  adminFallback: QueryDataSourceAdminFallback;
+};
+
+export type MaskingType = "FULL" | "PARTIAL" | "HASH";
+
+export type MaskingRule = {
+  databaseName: string;
+  tableName: string;
+  columnName: string;
+  type: MaskingType;
+};
+
+export type DataMaskingPolicyPayload = {
+  ruleList: MaskingRule[];
+};
+
+export type SQLReviewRuleLevel = "ERROR" | "WARNING" | "DISABLED";
+
+export type SQLReviewRuleType =
+  | "statement.select.no-select-all"
+  | "statement.where.require"
+  | "statement.drop.disallow"
+  | "table.require-pk"
+  | "index.max-count"
+  | "naming.table"
+  | "naming.column"
+  | "naming.index.idx"
+  | "naming.index.uk"
+  | "naming.index.fk";
+
+export type SQLReviewRule = {
+  type: SQLReviewRuleType;
+  level: SQLReviewRuleLevel;
+  // The JSON configuration of the rule, e.g. {"number": 5} for "index.max-count",
+  // {"format": "^idx_{{table}}_{{column_list}}$"} for "naming.index.idx".
+  payload: string;
+};
+
+export type SQLReviewPolicyPayload = {
+  ruleList: SQLReviewRule[];
+};
+
 export type PolicyPayload =
   | PipelineApporvalPolicyPayload
-  | PolicyBackupPlanPolicyPayload;
+  | PolicyBackupPlanPolicyPayload
+  | MaxExecutionTimePolicyPayload
+  | QueryDataSourcePolicyPayload
+  | DataMaskingPolicyPayload
+  | SQLReviewPolicyPayload;
 
 export type Policy = {
   id: PolicyId;
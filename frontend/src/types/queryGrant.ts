@@ -0,0 +1,21 @@
+import { DatabaseId, IssueId, QueryGrantId } from "./id";
+import { Principal } from "./principal";
+
+// The grant of querying the database in SQL editor, created by resolving the data source request issue.
+export type QueryGrant = {
+  id: QueryGrantId;
+
+  // Standard fields
+  creator: Principal;
+  createdTs: number;
+  updater: Principal;
+  updatedTs: number;
+
+  // Related fields
+  databaseId: DatabaseId;
+  principal: Principal;
+  issueId: IssueId;
+
+  // Domain specific fields
+  expireTs: number;
+};
@@ -28,10 +28,15 @@ export type Repository = {
   branchFilter: string;
   filePathTemplate: string;
   schemaPathTemplate: string;
+  writeBackMode: RepositoryWriteBackMode;
   // e.g. In GitLab, this is the corresponding project id.
   externalId: string;
 };
 
+// COMMIT writes back the latest schema directly to the branch,
+// MERGE_REQUEST opens a merge request from a new branch instead.
+export type RepositoryWriteBackMode = "COMMIT" | "MERGE_REQUEST";
+
 export type RepositoryCreate = {
   // Related fields
   vcsId: VCSId;
@@ -44,6 +49,7 @@ export type RepositoryCreate = {
   baseDirectory: string;
   filePathTemplate: string;
   schemaPathTemplate: string;
+  writeBackMode?: RepositoryWriteBackMode;
   externalId: string;
   accessToken: string;
   expiresTs: number;
@@ -55,6 +61,7 @@ export type RepositoryPatch = {
   branchFilter?: string;
   filePathTemplate?: string;
   schemaPathTemplate?: string;
+  writeBackMode?: RepositoryWriteBackMode;
 };
 
 export type RepositoryConfig = {
@@ -62,6 +69,7 @@ export type RepositoryConfig = {
   branchFilter: string;
   filePathTemplate: string;
   schemaPathTemplate: string;
+  writeBackMode: RepositoryWriteBackMode;
 };
 
 export type ExternalRepositoryInfo = {
@@ -21,9 +21,65 @@ export type QueryInfo = {
   databaseName?: string;
   statement: string;
   limit?: number;
+  // The client generated ID to cancel the running query with.
+  queryId?: string;
+};
+
+export type SqlExportFormat = "CSV" | "JSONL" | "XLSX";
+
+export type ExportInfo = QueryInfo & {
+  format: SqlExportFormat;
 };
 
 export type SqlResultSet = {
+  // For the multi-statement script, data and error are the result of the
+  // last statement.
   data: string;
   error: string;
+  // The results of the statements of the multi-statement script in order.
+  resultList: SingleSqlResult[];
+};
+
+export type SingleSqlResult = {
+  statement: string;
+  data: Record<string, any>[] | null;
+  rowCount: number;
+  durationNs: number;
+  error: string;
+};
+
+export type QueryColumn = {
+  name: string;
+  type: string;
+};
+
+// The streaming query result is sent as newline delimited JSON messages.
+// The first message carries the columns, the following messages carry the row
+// batches, and the last message carries either the error or the done flag.
+export type SqlResultStreamMessage = {
+  columnList?: QueryColumn[];
+  rowList?: any[][];
+  error?: string;
+  done?: boolean;
+  rowCount?: number;
+};
+
+// The query plan normalized from the EXPLAIN output of the engines.
+// The estimated rows and cost are null if the engine doesn't estimate them.
+export type QueryPlanNode = {
+  operation: string;
+  detail: string;
+  estimatedRows: number | null;
+  estimatedCost: number | null;
+  children: QueryPlanNode[] | null;
+};
+
+export type QueryPlan = {
+  root: QueryPlanNode;
+  raw: string;
+};
+
+export type SqlExplainResult = {
+  plan?: QueryPlan;
+  error: string;
 };
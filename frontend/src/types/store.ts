@@ -199,6 +199,8 @@ export interface SqlEditorState {
   queryHistoryList: QueryHistory[];
   isFetchingQueryHistory: boolean;
   isExecuting: boolean;
+  // The ID of the executing query to cancel with.
+  executingQueryId: string;
   isFetchingSheet: boolean;
   isShowExecutingHint: boolean;
   sharedSheet: Sheet;
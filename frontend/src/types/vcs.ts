@@ -1,7 +1,7 @@
 import { VCSId } from "./id";
 import { Principal } from "./principal";
 
-export type VCSType = "GITLAB_SELF_HOST";
+export type VCSType = "GITLAB_SELF_HOST" | "GITHUB" | "GITEA";
 
 export interface VCSConfig {
   type: VCSType;
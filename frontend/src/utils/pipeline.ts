@@ -230,9 +230,10 @@ export function applicableTaskTransition(
     return [];
   }
 
+  // The running statement can't be canceled if the engine doesn't support canceling it.
   const list: TaskStatusTransitionType[] = APPLICABLE_TASK_TRANSITION_LIST.get(
     task.status
-  )!;
+  )!.filter((type) => type != "CANCEL" || task.instance.capability.cancel);
 
   return list.map((type: TaskStatusTransitionType) => {
     return TASK_STATUS_TRANSITION_LIST.get(type)!;
@@ -3,11 +3,15 @@
 package advisor
 
 import (
+	"bufio"
 	"fmt"
+	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
 	"go.uber.org/zap"
 )
 
@@ -45,14 +49,162 @@ const (
 	MySQLSyntax Type = "bb.plugin.advisor.mysql.syntax"
 	// MySQLMigrationCompatibility is an advisor type for MySQL migration compatibility.
 	MySQLMigrationCompatibility Type = "bb.plugin.advisor.mysql.migration-compatibility"
+	// PostgreSQLStatement is an advisor type for PostgreSQL statements, which recognizes the statements rather than checking the full syntax.
+	PostgreSQLStatement Type = "bb.plugin.advisor.postgresql.statement"
+	// PostgreSQLMigrationCompatibility is an advisor type for PostgreSQL migration compatibility.
+	PostgreSQLMigrationCompatibility Type = "bb.plugin.advisor.postgresql.migration-compatibility"
+	// MySQLNoSelectAll is an advisor type for MySQL no "SELECT *".
+	MySQLNoSelectAll Type = "bb.plugin.advisor.mysql.select.no-select-all"
+	// MySQLWhereRequirement is an advisor type for MySQL WHERE clause requirement of UPDATE and DELETE.
+	MySQLWhereRequirement Type = "bb.plugin.advisor.mysql.where.require"
+	// MySQLDisallowDrop is an advisor type for MySQL disallowing DROP DATABASE and DROP TABLE.
+	MySQLDisallowDrop Type = "bb.plugin.advisor.mysql.drop.disallow"
+	// MySQLTableRequirePK is an advisor type for MySQL table primary key requirement.
+	MySQLTableRequirePK Type = "bb.plugin.advisor.mysql.table.require-pk"
+	// MySQLIndexMaxCount is an advisor type for MySQL max index count of a table.
+	MySQLIndexMaxCount Type = "bb.plugin.advisor.mysql.index.max-count"
+	// MySQLNamingConvention is an advisor type for MySQL naming conventions of the tables, the columns, the indexes and the foreign keys.
+	MySQLNamingConvention Type = "bb.plugin.advisor.mysql.naming.convention"
+	// PostgreSQLNamingConvention is an advisor type for PostgreSQL naming conventions of the tables, the columns, the indexes and the foreign keys.
+	PostgreSQLNamingConvention Type = "bb.plugin.advisor.postgresql.naming.convention"
 )
 
+var (
+	// syntaxAdvisorMap is the syntax advisor of the engines supporting the statement syntax check.
+	syntaxAdvisorMap = map[db.Type]Type{
+		db.MySQL:    MySQLSyntax,
+		db.TiDB:     MySQLSyntax,
+		db.Postgres: PostgreSQLStatement,
+	}
+	// migrationCompatibilityAdvisorMap is the migration compatibility advisor of the engines supporting the compatibility check.
+	migrationCompatibilityAdvisorMap = map[db.Type]Type{
+		db.MySQL:    MySQLMigrationCompatibility,
+		db.TiDB:     MySQLMigrationCompatibility,
+		db.Postgres: PostgreSQLMigrationCompatibility,
+	}
+)
+
+// GetSyntaxAdvisorType returns the syntax advisor type of the engine, and false if the engine doesn't support the syntax check.
+func GetSyntaxAdvisorType(dbType db.Type) (Type, bool) {
+	advType, ok := syntaxAdvisorMap[dbType]
+	return advType, ok
+}
+
+// GetMigrationCompatibilityAdvisorType returns the migration compatibility advisor type of the engine,
+// and false if the engine doesn't support the compatibility check.
+func GetMigrationCompatibilityAdvisorType(dbType db.Type) (Type, bool) {
+	advType, ok := migrationCompatibilityAdvisorMap[dbType]
+	return advType, ok
+}
+
 // Advice is the result of an advisor.
 type Advice struct {
 	Status  Status
 	Code    common.Code
 	Title   string
 	Content string
+	// Position is the position of the offending statement, it's nil if the advice isn't about a statement, e.g. the success advice.
+	Position *Position
+	// Replacement is the statement replacing the offending statement to fix the advice, it's empty if there is no mechanical fix.
+	Replacement string
+}
+
+// Position is the position of the statement in the script.
+type Position struct {
+	// StatementIndex is the 0-based index of the statement in the script.
+	StatementIndex int
+	// Line and Column are the 1-based line and column where the statement starts in the script,
+	// or where the syntax error is for the syntax error advice.
+	Line   int
+	Column int
+	// Snippet is the statement text without the trailing semicolon.
+	Snippet string
+}
+
+// LocateStatementList returns the positions of the statements in the script. The statement texts are the slices of the script in order,
+// which may have the leading and trailing spaces. The position is nil if the statement text isn't found in the script.
+func LocateStatementList(script string, textList []string) []*Position {
+	positionList := make([]*Position, len(textList))
+	for i, start := range locateStatementList(script, textList) {
+		if start < 0 {
+			continue
+		}
+		line, column := lineColumnAt(script, start)
+		positionList[i] = &Position{
+			StatementIndex: i,
+			Line:           line,
+			Column:         column,
+			Snippet:        statementSnippet(textList[i]),
+		}
+	}
+	return positionList
+}
+
+// LocateSyntaxError returns the position of the syntax error at the byte offset of the script of the engine.
+// The statement of the error is the last statement starting at or before the offset.
+func LocateSyntaxError(dbType db.Type, script string, offset int) *Position {
+	if offset > len(script) {
+		offset = len(script)
+	}
+	var textList []string
+	sc := bufio.NewScanner(strings.NewReader(script))
+	sc.Buffer(make([]byte, bufio.MaxScanTokenSize), 16*1024*1024)
+	// The statements split before the error of splitting are still located.
+	_ = parser.ApplyMultiStatements(dbType, sc, func(stmt *parser.SingleSQL) error {
+		textList = append(textList, stmt.Text)
+		return nil
+	})
+	line, column := lineColumnAt(script, offset)
+	position := &Position{
+		Line:   line,
+		Column: column,
+	}
+	for i, start := range locateStatementList(script, textList) {
+		if start < 0 {
+			continue
+		}
+		if start > offset {
+			break
+		}
+		position.StatementIndex = i
+		position.Snippet = statementSnippet(textList[i])
+	}
+	return position
+}
+
+// locateStatementList returns the byte offsets where the statements start in the script, the offset is -1 if the statement isn't found.
+func locateStatementList(script string, textList []string) []int {
+	startList := make([]int, len(textList))
+	offset := 0
+	for i, text := range textList {
+		startList[i] = -1
+		snippet := statementSnippet(text)
+		index := strings.Index(script[offset:], snippet)
+		if snippet == "" || index < 0 {
+			continue
+		}
+		startList[i] = offset + index
+		offset = startList[i] + len(snippet)
+	}
+	return startList
+}
+
+// statementSnippet returns the statement text without the leading and trailing spaces and the trailing semicolon.
+func statementSnippet(text string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), ";"))
+}
+
+// lineColumnAt returns the 1-based line and column of the byte offset of the script, the column counts the characters.
+func lineColumnAt(script string, offset int) (int, int) {
+	lineStart := strings.LastIndex(script[:offset], "\n") + 1
+	return strings.Count(script[:offset], "\n") + 1, utf8.RuneCountInString(script[lineStart:offset]) + 1
+}
+
+// SetPosition sets the position of the advices, it's used to set the statement position to the advices found in the statement.
+func SetPosition(adviceList []Advice, position *Position) {
+	for i := range adviceList {
+		adviceList[i].Position = position
+	}
 }
 
 // Context is the context for advisor.
@@ -60,6 +212,10 @@ type Context struct {
 	Logger    *zap.Logger
 	Charset   string
 	Collation string
+	// Rule is the SQL review rule checked by the advisor, it's only set for the advisors of the SQL review rules.
+	Rule *SQLReviewRule
+	// Catalog is the synced schema of the database which the statement runs against, it's nil if the database is unknown.
+	Catalog *Catalog
 }
 
 // Advisor is the interface for advisor.
@@ -0,0 +1,263 @@
+package advisor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// Catalog is the schema of the database which the statement runs against, which is synced from the database.
+// It may be stale if the schema is changed outside Bytebase since the last sync.
+type Catalog struct {
+	TableList []*CatalogTable
+}
+
+// CatalogTable is the table in the catalog.
+type CatalogTable struct {
+	// Name is the table name, which is qualified by the schema for Postgres, e.g. "public.user".
+	Name       string
+	ColumnList []*CatalogColumn
+	IndexList  []*CatalogIndex
+}
+
+// CatalogColumn is the column in the catalog.
+type CatalogColumn struct {
+	Name string
+	// Type is the column type synced from the database, e.g. "int(11) unsigned" for MySQL and "character varying(50)" for Postgres.
+	Type         string
+	Nullable     bool
+	Default      *string
+	CharacterSet string
+	Collation    string
+	Comment      string
+	// Extra is the extra information of the MySQL column, e.g. "auto_increment".
+	Extra string
+}
+
+// CatalogIndex is the index in the catalog, e.g. the index named "PRIMARY" is the primary key of MySQL.
+type CatalogIndex struct {
+	Name   string
+	Unique bool
+	// ExpressionList is the indexed expressions in order, which are the column names for the indexes on the columns.
+	ExpressionList []string
+}
+
+// FindIndex returns the table and the index of the name, which is searched in all the tables if the table is empty.
+// It returns nil if the index isn't found.
+func (c *Catalog) FindIndex(table string, index string) (*CatalogTable, *CatalogIndex) {
+	for _, t := range c.TableList {
+		if table != "" && t.Name != table {
+			continue
+		}
+		for _, i := range t.IndexList {
+			if strings.EqualFold(i.Name, index) {
+				return t, i
+			}
+		}
+	}
+	return nil, nil
+}
+
+// CatalogState is the state of the catalog as the statements of a script are applied one by one,
+// so the tables and the columns created earlier in the same script are known to the later statements.
+type CatalogState struct {
+	tableMap map[string]*tableState
+}
+
+// tableState is the state of a table.
+type tableState struct {
+	// columnMap is the columns keyed by the lower-case column name.
+	// The value is the synced column, or nil if the column is added or changed by the script.
+	columnMap map[string]*CatalogColumn
+	// anyColumn is true if the columns of the table are unknown, e.g. the table created by CREATE TABLE ... AS SELECT.
+	anyColumn bool
+	// indexMap is the set of the lower-case index names.
+	indexMap map[string]bool
+}
+
+// NewCatalogState returns the catalog state before any statement is applied.
+func NewCatalogState(catalog *Catalog) *CatalogState {
+	state := &CatalogState{
+		tableMap: make(map[string]*tableState),
+	}
+	for _, table := range catalog.TableList {
+		t := &tableState{
+			columnMap: make(map[string]*CatalogColumn),
+			indexMap:  make(map[string]bool),
+		}
+		for _, column := range table.ColumnList {
+			t.columnMap[strings.ToLower(column.Name)] = column
+		}
+		for _, index := range table.IndexList {
+			t.indexMap[strings.ToLower(index.Name)] = true
+		}
+		state.tableMap[table.Name] = t
+	}
+	return state
+}
+
+// HasTable returns whether the table exists.
+func (s *CatalogState) HasTable(table string) bool {
+	_, ok := s.tableMap[table]
+	return ok
+}
+
+// FindColumn returns whether the column exists, and the synced column if the column isn't changed by the script so far.
+// It returns false if the table doesn't exist.
+func (s *CatalogState) FindColumn(table string, column string) (*CatalogColumn, bool) {
+	t, ok := s.tableMap[table]
+	if !ok {
+		return nil, false
+	}
+	if c, ok := t.columnMap[strings.ToLower(column)]; ok {
+		return c, true
+	}
+	return nil, t.anyColumn
+}
+
+// CreateTable creates the table with the columns and no index, the columns are unknown if the columnList is nil.
+func (s *CatalogState) CreateTable(table string, columnList []string) {
+	t := &tableState{
+		columnMap: make(map[string]*CatalogColumn),
+		anyColumn: columnList == nil,
+		indexMap:  make(map[string]bool),
+	}
+	for _, column := range columnList {
+		t.columnMap[strings.ToLower(column)] = nil
+	}
+	s.tableMap[table] = t
+}
+
+// CreateTableLike creates the table with the same columns and indexes as the existing table.
+func (s *CatalogState) CreateTableLike(table string, likeTable string) {
+	t := &tableState{
+		columnMap: make(map[string]*CatalogColumn),
+		anyColumn: true,
+		indexMap:  make(map[string]bool),
+	}
+	if like, ok := s.tableMap[likeTable]; ok {
+		t.anyColumn = like.anyColumn
+		for name := range like.columnMap {
+			t.columnMap[name] = nil
+		}
+		for name := range like.indexMap {
+			t.indexMap[name] = true
+		}
+	}
+	s.tableMap[table] = t
+}
+
+// DropTable drops the table.
+func (s *CatalogState) DropTable(table string) {
+	delete(s.tableMap, table)
+}
+
+// RenameTable renames the table, the columns are kept.
+func (s *CatalogState) RenameTable(oldTable string, newTable string) {
+	t, ok := s.tableMap[oldTable]
+	if !ok {
+		return
+	}
+	delete(s.tableMap, oldTable)
+	s.tableMap[newTable] = t
+}
+
+// AddColumn adds the column to the table.
+func (s *CatalogState) AddColumn(table string, column string) {
+	if t, ok := s.tableMap[table]; ok {
+		t.columnMap[strings.ToLower(column)] = nil
+	}
+}
+
+// DropColumn drops the column from the table.
+func (s *CatalogState) DropColumn(table string, column string) {
+	if t, ok := s.tableMap[table]; ok {
+		delete(t.columnMap, strings.ToLower(column))
+	}
+}
+
+// ChangeColumn marks the column changed and renames it if the new name is different.
+func (s *CatalogState) ChangeColumn(table string, oldColumn string, newColumn string) {
+	if t, ok := s.tableMap[table]; ok {
+		delete(t.columnMap, strings.ToLower(oldColumn))
+		t.columnMap[strings.ToLower(newColumn)] = nil
+	}
+}
+
+// IndexList returns the lower-case names of the indexes of the table in no particular order.
+func (s *CatalogState) IndexList(table string) []string {
+	t, ok := s.tableMap[table]
+	if !ok {
+		return nil
+	}
+	var indexList []string
+	for name := range t.indexMap {
+		indexList = append(indexList, name)
+	}
+	return indexList
+}
+
+// HasIndex returns whether the table has the index.
+func (s *CatalogState) HasIndex(table string, index string) bool {
+	t, ok := s.tableMap[table]
+	return ok && t.indexMap[strings.ToLower(index)]
+}
+
+// CreateIndex creates the index on the table.
+func (s *CatalogState) CreateIndex(table string, index string) {
+	if t, ok := s.tableMap[table]; ok {
+		t.indexMap[strings.ToLower(index)] = true
+	}
+}
+
+// DropIndex drops the index from the table.
+func (s *CatalogState) DropIndex(table string, index string) {
+	if t, ok := s.tableMap[table]; ok {
+		delete(t.indexMap, strings.ToLower(index))
+	}
+}
+
+// RenameIndex renames the index of the table.
+func (s *CatalogState) RenameIndex(table string, oldIndex string, newIndex string) {
+	if t, ok := s.tableMap[table]; ok && t.indexMap[strings.ToLower(oldIndex)] {
+		delete(t.indexMap, strings.ToLower(oldIndex))
+		t.indexMap[strings.ToLower(newIndex)] = true
+	}
+}
+
+// CheckTableReference returns the advice if the table referenced by the statement doesn't exist.
+func (s *CatalogState) CheckTableReference(text string, table string) []Advice {
+	if s.HasTable(table) {
+		return nil
+	}
+	return []Advice{
+		{
+			Status:  Warn,
+			Code:    common.CompatibilityTableNotFound,
+			Title:   "Table not found",
+			Content: fmt.Sprintf("%q references table %q which doesn't exist in the synced schema", text, table),
+		},
+	}
+}
+
+// CheckColumnReference returns the advices of the columns referenced by the statement which don't exist.
+// The columns are not checked if the table doesn't exist, which is reported by CheckTableReference.
+func (s *CatalogState) CheckColumnReference(text string, table string, columnList []string) []Advice {
+	if !s.HasTable(table) {
+		return nil
+	}
+	var adviceList []Advice
+	for _, column := range columnList {
+		if _, ok := s.FindColumn(table, column); ok {
+			continue
+		}
+		adviceList = append(adviceList, Advice{
+			Status:  Warn,
+			Code:    common.CompatibilityColumnNotFound,
+			Title:   "Column not found",
+			Content: fmt.Sprintf("%q references column %q.%q which doesn't exist in the synced schema", text, table, column),
+		})
+	}
+	return adviceList
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	"github.com/pingcap/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*DisallowDropAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLDisallowDrop, &DisallowDropAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLDisallowDrop, &DisallowDropAdvisor{})
+}
+
+// DisallowDropAdvisor is the advisor checking for disallowing DROP DATABASE and DROP TABLE.
+type DisallowDropAdvisor struct {
+}
+
+// Check checks for disallowing DROP DATABASE and DROP TABLE.
+func (adv *DisallowDropAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	if ctx.Rule == nil {
+		return nil, fmt.Errorf("the %s advisor requires the SQL review rule", advisor.MySQLDisallowDrop)
+	}
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &disallowDropChecker{level: level}
+	acceptStatementList(statement, root, checker, &checker.adviceList)
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type disallowDropChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+}
+
+func (v *disallowDropChecker) Enter(in ast.Node) (ast.Node, bool) {
+	disallowed := false
+	switch node := in.(type) {
+	case *ast.DropDatabaseStmt:
+		disallowed = true
+	// The views can be recreated without losing data.
+	case *ast.DropTableStmt:
+		disallowed = !node.IsView
+	}
+
+	if disallowed {
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    common.StatementDropDisallowed,
+			Title:   "Disallow DROP",
+			Content: fmt.Sprintf("%q drops the data, which is disallowed", in.Text()),
+		})
+	}
+	return in, false
+}
+
+func (v *disallowDropChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
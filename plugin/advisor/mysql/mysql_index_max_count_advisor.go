@@ -0,0 +1,232 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	"github.com/pingcap/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*IndexMaxCountAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLIndexMaxCount, &IndexMaxCountAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLIndexMaxCount, &IndexMaxCountAdvisor{})
+}
+
+// IndexMaxCountAdvisor is the advisor checking for the max index count of the created tables.
+type IndexMaxCountAdvisor struct {
+}
+
+// Check checks for the max index count of the tables created or altered, and the tables on which the indexes are created.
+// The existing indexes of the tables are counted by the catalog. If the catalog is unknown, only the indexes created
+// by the statement are counted.
+func (adv *IndexMaxCountAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	if ctx.Rule == nil {
+		return nil, fmt.Errorf("the %s advisor requires the SQL review rule", advisor.MySQLIndexMaxCount)
+	}
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalNumberTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	catalog := ctx.Catalog
+	if catalog == nil {
+		catalog = &advisor.Catalog{}
+	}
+	checker := &indexMaxCountChecker{level: level, max: payload.Number, catalog: advisor.NewCatalogState(catalog)}
+	acceptStatementList(statement, root, checker, &checker.adviceList)
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type indexMaxCountChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	max        int
+	// catalog is the catalog state as the statements are applied.
+	// The tables not in the catalog are tracked from no index once the script creates an index on them.
+	catalog *advisor.CatalogState
+}
+
+func (v *indexMaxCountChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.CreateTableStmt:
+		table := catalogTableName(node.Table)
+		if node.IfNotExists && v.catalog.HasTable(table) {
+			break
+		}
+		if node.ReferTable != nil {
+			v.catalog.CreateTableLike(table, catalogTableName(node.ReferTable))
+			break
+		}
+		var columnList []string
+		for _, column := range node.Cols {
+			columnList = append(columnList, column.Name.Name.O)
+		}
+		v.catalog.CreateTable(table, columnList)
+		for _, column := range node.Cols {
+			v.addColumnIndex(table, column)
+		}
+		for _, constraint := range node.Constraints {
+			v.addConstraintIndex(table, constraint)
+		}
+		v.checkIndexCount(table)
+	case *ast.CreateIndexStmt:
+		table := v.trackTable(node.Table)
+		if node.IfNotExists && v.catalog.HasIndex(table, node.IndexName) {
+			break
+		}
+		v.catalog.CreateIndex(table, node.IndexName)
+		v.checkIndexCount(table)
+	case *ast.AlterTableStmt:
+		table := v.trackTable(node.Table)
+		added := false
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			case ast.AlterTableAddColumns:
+				for _, column := range spec.NewColumns {
+					added = v.addColumnIndex(table, column) || added
+				}
+			case ast.AlterTableAddConstraint:
+				added = v.addConstraintIndex(table, spec.Constraint) || added
+			case ast.AlterTableDropIndex:
+				v.catalog.DropIndex(table, spec.Name)
+			case ast.AlterTableDropPrimaryKey:
+				v.catalog.DropIndex(table, primaryKeyName)
+			case ast.AlterTableRenameIndex:
+				v.catalog.RenameIndex(table, spec.FromKey.O, spec.ToKey.O)
+			case ast.AlterTableRenameTable:
+				newTable := catalogTableName(spec.NewTable)
+				v.catalog.RenameTable(table, newTable)
+				table = newTable
+			}
+		}
+		if added {
+			v.checkIndexCount(table)
+		}
+	case *ast.DropIndexStmt:
+		v.catalog.DropIndex(catalogTableName(node.Table), node.IndexName)
+	case *ast.DropTableStmt:
+		for _, table := range node.Tables {
+			v.catalog.DropTable(catalogTableName(table))
+		}
+	case *ast.RenameTableStmt:
+		for _, tableToTable := range node.TableToTables {
+			v.catalog.RenameTable(catalogTableName(tableToTable.OldTable), catalogTableName(tableToTable.NewTable))
+		}
+	}
+	return in, false
+}
+
+func (v *indexMaxCountChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// primaryKeyName is the name of the primary key of MySQL, which isn't counted.
+const primaryKeyName = "PRIMARY"
+
+// catalogTableName returns the name of the table in the catalog state.
+// The tables qualified by the database name are kept qualified, since the catalog is only of the current database.
+func catalogTableName(table *ast.TableName) string {
+	if table.Schema.O != "" {
+		return fmt.Sprintf("%s.%s", table.Schema.O, table.Name.O)
+	}
+	return table.Name.O
+}
+
+// trackTable returns the name of the table in the catalog state, and starts tracking the table if it's unknown.
+func (v *indexMaxCountChecker) trackTable(tableName *ast.TableName) string {
+	table := catalogTableName(tableName)
+	if !v.catalog.HasTable(table) {
+		v.catalog.CreateTable(table, nil)
+	}
+	return table
+}
+
+// addColumnIndex creates the primary key and the unique key defined by the column options,
+// and returns whether any index is created.
+func (v *indexMaxCountChecker) addColumnIndex(table string, column *ast.ColumnDef) bool {
+	added := false
+	for _, option := range column.Options {
+		switch option.Tp {
+		case ast.ColumnOptionPrimaryKey:
+			v.catalog.CreateIndex(table, primaryKeyName)
+		case ast.ColumnOptionUniqKey:
+			v.catalog.CreateIndex(table, v.generateIndexName(table, column.Name.Name.O))
+			added = true
+		}
+	}
+	return added
+}
+
+// addConstraintIndex creates the index of the constraint, and returns whether any index other than the primary key is created.
+// The foreign key creates the index if there isn't one, which isn't counted.
+func (v *indexMaxCountChecker) addConstraintIndex(table string, constraint *ast.Constraint) bool {
+	switch constraint.Tp {
+	case ast.ConstraintPrimaryKey:
+		v.catalog.CreateIndex(table, primaryKeyName)
+	case ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex, ast.ConstraintFulltext:
+		name := constraint.Name
+		if name == "" {
+			prefix := "idx"
+			if len(constraint.Keys) > 0 && constraint.Keys[0].Column != nil {
+				prefix = constraint.Keys[0].Column.Name.O
+			}
+			name = v.generateIndexName(table, prefix)
+		}
+		v.catalog.CreateIndex(table, name)
+		return true
+	}
+	return false
+}
+
+// generateIndexName returns the name of the unnamed index like MySQL does, which is the name of its first column,
+// suffixed by "_2", "_3" and so on if the name is taken.
+func (v *indexMaxCountChecker) generateIndexName(table string, prefix string) string {
+	name := prefix
+	for i := 2; v.catalog.HasIndex(table, name); i++ {
+		name = fmt.Sprintf("%s_%d", prefix, i)
+	}
+	return name
+}
+
+// checkIndexCount appends the advice if the table has more indexes than the limit, the primary key isn't counted.
+func (v *indexMaxCountChecker) checkIndexCount(table string) {
+	count := 0
+	for _, index := range v.catalog.IndexList(table) {
+		if index != strings.ToLower(primaryKeyName) {
+			count++
+		}
+	}
+	if count > v.max {
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    common.IndexCountExceedsLimit,
+			Title:   "Index count exceeds the limit",
+			Content: fmt.Sprintf("Table %q has %d indexes, exceeding the limit %d", table, count, v.max),
+		})
+	}
+}
@@ -2,16 +2,23 @@ package mysql
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/bytebase/bytebase/plugin/db"
 
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/types"
 )
 
 var (
 	_ advisor.Advisor = (*CompatibilityAdvisor)(nil)
+
+	// createTablePrefixRegexp matches the keywords before the position of IF NOT EXISTS in the CREATE TABLE statement.
+	createTablePrefixRegexp = regexp.MustCompile(`(?i)^\s*CREATE\s+(TEMPORARY\s+)?TABLE`)
 )
 
 func init() {
@@ -29,19 +36,26 @@ func (adv *CompatibilityAdvisor) Check(ctx advisor.Context, statement string) ([
 
 	root, _, err := p.Parse(statement, ctx.Charset, ctx.Collation)
 	if err != nil {
-		return []advisor.Advice{
-			{
-				Status:  advisor.Error,
-				Title:   "Syntax error",
-				Content: err.Error(),
-			},
-		}, nil
+		return []advisor.Advice{syntaxErrorAdvice(statement, err)}, nil
 	}
 
-	c := &compatibilityChecker{}
-	for _, stmtNode := range root {
-		(stmtNode).Accept(c)
+	c := &compatibilityChecker{
+		primaryKeyMap:   make(map[string]map[string]bool),
+		createdTableMap: make(map[string]bool),
+	}
+	if ctx.Catalog != nil {
+		c.catalog = advisor.NewCatalogState(ctx.Catalog)
+		for _, table := range ctx.Catalog.TableList {
+			if _, index := ctx.Catalog.FindIndex(table.Name, primaryKeyName); index != nil {
+				columnMap := make(map[string]bool)
+				for _, column := range index.ExpressionList {
+					columnMap[strings.ToLower(column)] = true
+				}
+				c.primaryKeyMap[table.Name] = columnMap
+			}
+		}
 	}
+	acceptStatementList(statement, root, c, &c.advisorList)
 
 	if len(c.advisorList) == 0 {
 		c.advisorList = append(c.advisorList, advisor.Advice{
@@ -55,10 +69,18 @@ func (adv *CompatibilityAdvisor) Check(ctx advisor.Context, statement string) ([
 
 type compatibilityChecker struct {
 	advisorList []advisor.Advice
+	// catalog is the catalog state as the statements are applied, it's nil if the catalog is unknown.
+	catalog *advisor.CatalogState
+	// primaryKeyMap is the lower-case primary key columns of the tables in the catalog, which are NOT NULL implicitly.
+	primaryKeyMap map[string]map[string]bool
+	// createdTableMap is the lower-case tables created by the statements, which are empty, so the index creation doesn't block any write.
+	createdTableMap map[string]bool
 }
 
 func (v *compatibilityChecker) Enter(in ast.Node) (ast.Node, bool) {
 	code := common.Ok
+	// onlineAdvice is the advice of the index creation blocking the writes, it's appended after the incompatible one.
+	var onlineAdvice *advisor.Advice
 	switch node := in.(type) {
 	// DROP DATABASE
 	case *ast.DropDatabaseStmt:
@@ -66,69 +88,42 @@ func (v *compatibilityChecker) Enter(in ast.Node) (ast.Node, bool) {
 	// RENAME TABLE
 	case *ast.RenameTableStmt:
 		code = common.CompatibilityRenameTable
+		for _, tableToTable := range node.TableToTables {
+			if v.isTracked(tableToTable.OldTable) {
+				v.checkTable(node.Text(), tableToTable.OldTable.Name.O)
+				v.catalog.RenameTable(tableToTable.OldTable.Name.O, tableToTable.NewTable.Name.O)
+			}
+		}
 	// DROP TABLE/VIEW
 	case *ast.DropTableStmt:
 		code = common.CompatibilityDropTable
-	// ALTER TABLE
-	case *ast.AlterTableStmt:
-		for _, spec := range node.Specs {
-			fmt.Printf("spec %d: %+v\n\n", spec.Tp, spec)
-			// RENAME COLUMN
-			if spec.Tp == ast.AlterTableRenameColumn {
-				code = common.CompatibilityRenameColumn
-				break
-			}
-			// DROP COLUMN
-			if spec.Tp == ast.AlterTableDropColumn {
-				code = common.CompatibilityDropColumn
-				break
-			}
-
-			if spec.Tp == ast.AlterTableAddConstraint {
-				// ADD PRIMARY KEY
-				if spec.Constraint.Tp == ast.ConstraintPrimaryKey {
-					code = common.CompatibilityAddPrimaryKey
-					break
-				}
-				// ADD UNIQUE/UNIQUE KEY/UNIQUE INDEX
-				if spec.Constraint.Tp == ast.ConstraintPrimaryKey ||
-					spec.Constraint.Tp == ast.ConstraintUniq ||
-					spec.Constraint.Tp == ast.ConstraintUniqKey {
-					code = common.CompatibilityAddUniqueKey
-					break
-				}
-				// ADD FOREIGN KEY
-				if spec.Constraint.Tp == ast.ConstraintForeignKey {
-					code = common.CompatibilityAddForeignKey
-					break
-				}
-				// Check is only supported after 8.0.16 https://dev.mysql.com/doc/refman/8.0/en/create-table-check-constraints.html
-				// ADD CHECK ENFORCED
-				if spec.Constraint.Tp == ast.ConstraintCheck && spec.Constraint.Enforced {
-					code = common.CompatibilityAddCheck
-					break
-				}
-			}
-
-			// Check is only supported after 8.0.16 https://dev.mysql.com/doc/refman/8.0/en/create-table-check-constraints.html
-			// ALTER CHECK ENFORCED
-			if spec.Tp == ast.AlterTableAlterCheck {
-				if spec.Constraint.Enforced {
-					code = common.CompatibilityAlterCheck
-					break
-				}
-			}
-
-			// MODIFY COLUMN / CHANGE COLUMN
-			// Due to the limitation that we don't know the current data type of the column before the change,
-			// so we treat all as incompatible. This generates false positive when:
-			// 1. Change to a compatible data type such as INT to BIGINT
-			// 2. Change property like comment, change it to NULL
-			if spec.Tp == ast.AlterTableModifyColumn || spec.Tp == ast.AlterTableChangeColumn {
-				code = common.CompatibilityAlterColumn
-				break
+		for _, table := range node.Tables {
+			if v.isTracked(table) {
+				v.catalog.DropTable(table.Name.O)
 			}
 		}
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		exists := v.isTracked(node.Table) && v.catalog.HasTable(node.Table.Name.O)
+		if exists && !node.IfNotExists {
+			v.advisorList = append(v.advisorList, advisor.Advice{
+				Status:      advisor.Error,
+				Code:        common.CompatibilityTableExists,
+				Title:       "Table already exists",
+				Content:     fmt.Sprintf("Table %q already exists, so %q fails, use CREATE TABLE IF NOT EXISTS instead", node.Table.Name.O, node.Text()),
+				Replacement: ifNotExistsReplacement(trimStatement(node.Text())),
+			})
+		}
+		if !exists {
+			v.createdTableMap[node.Table.Name.L] = true
+		}
+		if v.isTracked(node.Table) {
+			v.createTable(node)
+		}
+	// ALTER TABLE
+	case *ast.AlterTableStmt:
+		code = v.alterTable(node)
+		onlineAdvice = v.checkAlterTableOnline(node)
 
 	// ALTER VIEW TBD: https://github.com/pingcap/parser/pull/1252
 	// case *ast.AlterViewStmt:
@@ -138,6 +133,11 @@ func (v *compatibilityChecker) Enter(in ast.Node) (ast.Node, bool) {
 		if node.KeyType == ast.IndexKeyTypeUnique {
 			code = common.CompatibilityAddUniqueKey
 		}
+		if v.isTracked(node.Table) {
+			v.checkTable(node.Text(), node.Table.Name.O)
+			v.checkColumn(node.Text(), node.Table.Name.O, indexColumnList(node.IndexPartSpecifications))
+		}
+		onlineAdvice = v.checkCreateIndexOnline(node)
 	}
 
 	if code != common.Ok {
@@ -148,9 +148,410 @@ func (v *compatibilityChecker) Enter(in ast.Node) (ast.Node, bool) {
 			Content: fmt.Sprintf("%q may cause incompatibility with the existing data and code", in.Text()),
 		})
 	}
+	if onlineAdvice != nil {
+		v.advisorList = append(v.advisorList, *onlineAdvice)
+	}
 	return in, false
 }
 
+// checkCreateIndexOnline returns the advice if CREATE INDEX on the existing table doesn't specify ALGORITHM or LOCK,
+// which may block the writes, or nil otherwise. FULLTEXT and SPATIAL indexes can't be created with LOCK=NONE.
+func (v *compatibilityChecker) checkCreateIndexOnline(node *ast.CreateIndexStmt) *advisor.Advice {
+	if node.LockAlg != nil || node.KeyType == ast.IndexKeyTypeFullText || node.KeyType == ast.IndexKeyTypeSpatial ||
+		v.isCreatedTable(node.Table) {
+		return nil
+	}
+	return &advisor.Advice{
+		Status:      advisor.Warn,
+		Code:        common.CompatibilityAddIndexNotOnline,
+		Title:       "Index creation may block writes",
+		Content:     fmt.Sprintf("%q may block the writes of table %q until the index is built, use ALGORITHM=INPLACE LOCK=NONE instead", node.Text(), node.Table.Name.O),
+		Replacement: trimStatement(node.Text()) + " ALGORITHM=INPLACE LOCK=NONE",
+	}
+}
+
+// checkAlterTableOnline returns the advice if ALTER TABLE adding the secondary indexes to the existing table doesn't specify
+// ALGORITHM or LOCK, which may block the writes, or nil otherwise. The statement is only replaced if it only adds the indexes,
+// since the other specs may not support LOCK=NONE.
+func (v *compatibilityChecker) checkAlterTableOnline(node *ast.AlterTableStmt) *advisor.Advice {
+	if v.isCreatedTable(node.Table) {
+		return nil
+	}
+	addIndex, onlyAddIndex := false, true
+	for _, spec := range node.Specs {
+		switch {
+		case spec.Tp == ast.AlterTableAlgorithm || spec.Tp == ast.AlterTableLock:
+			return nil
+		case isAddSecondaryIndex(spec):
+			addIndex = true
+		default:
+			onlyAddIndex = false
+		}
+	}
+	if !addIndex {
+		return nil
+	}
+	advice := &advisor.Advice{
+		Status:  advisor.Warn,
+		Code:    common.CompatibilityAddIndexNotOnline,
+		Title:   "Index creation may block writes",
+		Content: fmt.Sprintf("%q may block the writes of table %q until the index is built, use ALGORITHM=INPLACE, LOCK=NONE instead", node.Text(), node.Table.Name.O),
+	}
+	if onlyAddIndex {
+		advice.Replacement = trimStatement(node.Text()) + ", ALGORITHM=INPLACE, LOCK=NONE"
+	} else {
+		advice.Content = fmt.Sprintf("%q may block the writes of table %q until the index is built, add the index with ALGORITHM=INPLACE, LOCK=NONE in a separate statement instead", node.Text(), node.Table.Name.O)
+	}
+	return advice
+}
+
+// isCreatedTable returns whether the table is created by the previous statements.
+func (v *compatibilityChecker) isCreatedTable(table *ast.TableName) bool {
+	return table.Schema.O == "" && v.createdTableMap[table.Name.L]
+}
+
+// isAddSecondaryIndex returns whether the spec adds the secondary index supporting LOCK=NONE, i.e. not PRIMARY or FULLTEXT.
+func isAddSecondaryIndex(spec *ast.AlterTableSpec) bool {
+	if spec.Tp != ast.AlterTableAddConstraint {
+		return false
+	}
+	switch spec.Constraint.Tp {
+	case ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		return true
+	}
+	return false
+}
+
+// trimStatement returns the statement text without the spaces and the semicolon at the end.
+func trimStatement(text string) string {
+	return strings.TrimRight(strings.TrimSpace(text), "; \t\r\n")
+}
+
+// ifNotExistsReplacement returns the CREATE TABLE statement with IF NOT EXISTS, or empty if the statement doesn't start with
+// CREATE [TEMPORARY] TABLE, e.g. there are comments between the keywords.
+func ifNotExistsReplacement(text string) string {
+	loc := createTablePrefixRegexp.FindStringIndex(text)
+	if loc == nil {
+		return ""
+	}
+	return text[:loc[1]] + " IF NOT EXISTS" + text[loc[1]:]
+}
+
 func (v *compatibilityChecker) Leave(in ast.Node) (ast.Node, bool) {
 	return in, true
 }
+
+// isTracked returns whether the table is tracked by the catalog state.
+// The tables qualified by the database name are not tracked, since the catalog is only of the current database.
+func (v *compatibilityChecker) isTracked(table *ast.TableName) bool {
+	return v.catalog != nil && table.Schema.O == ""
+}
+
+func (v *compatibilityChecker) checkTable(text string, table string) {
+	v.advisorList = append(v.advisorList, v.catalog.CheckTableReference(text, table)...)
+}
+
+func (v *compatibilityChecker) checkColumn(text string, table string, columnList []string) {
+	v.advisorList = append(v.advisorList, v.catalog.CheckColumnReference(text, table, columnList)...)
+}
+
+// checkReference checks the table and the columns referenced by the foreign key exist.
+func (v *compatibilityChecker) checkReference(text string, refer *ast.ReferenceDef) {
+	if refer == nil || !v.isTracked(refer.Table) {
+		return
+	}
+	v.checkTable(text, refer.Table.Name.O)
+	v.checkColumn(text, refer.Table.Name.O, indexColumnList(refer.IndexPartSpecifications))
+}
+
+// createTable applies the CREATE TABLE statement to the catalog state, and checks the foreign key references.
+func (v *compatibilityChecker) createTable(node *ast.CreateTableStmt) {
+	table := node.Table.Name.O
+	switch {
+	case node.IfNotExists && v.catalog.HasTable(table):
+	case node.ReferTable != nil:
+		v.catalog.CreateTableLike(table, node.ReferTable.Name.O)
+	case node.Select != nil:
+		v.catalog.CreateTable(table, nil)
+	default:
+		columnList := []string{}
+		for _, column := range node.Cols {
+			columnList = append(columnList, column.Name.Name.O)
+		}
+		v.catalog.CreateTable(table, columnList)
+	}
+
+	for _, column := range node.Cols {
+		for _, option := range column.Options {
+			if option.Tp == ast.ColumnOptionReference {
+				v.checkReference(node.Text(), option.Refer)
+			}
+		}
+	}
+	for _, constraint := range node.Constraints {
+		if constraint.Tp == ast.ConstraintForeignKey {
+			v.checkReference(node.Text(), constraint.Refer)
+		}
+	}
+}
+
+// alterTable returns the code of the first incompatible spec of the ALTER TABLE statement,
+// and applies the specs to the catalog state.
+func (v *compatibilityChecker) alterTable(node *ast.AlterTableStmt) common.Code {
+	tracked := v.isTracked(node.Table)
+	if tracked {
+		v.checkTable(node.Text(), node.Table.Name.O)
+		tracked = v.catalog.HasTable(node.Table.Name.O)
+	}
+	code := common.Ok
+	for _, spec := range node.Specs {
+		specCode := v.alterTableSpec(node, spec, tracked)
+		if code == common.Ok {
+			code = specCode
+		}
+	}
+	return code
+}
+
+// alterTableSpec returns the code of the ALTER TABLE spec, and applies the spec to the catalog state if the table is tracked.
+func (v *compatibilityChecker) alterTableSpec(node *ast.AlterTableStmt, spec *ast.AlterTableSpec, tracked bool) common.Code {
+	text := node.Text()
+	table := node.Table.Name.O
+	switch spec.Tp {
+	// RENAME COLUMN
+	case ast.AlterTableRenameColumn:
+		if tracked {
+			v.checkColumn(text, table, []string{spec.OldColumnName.Name.O})
+			v.catalog.ChangeColumn(table, spec.OldColumnName.Name.O, spec.NewColumnName.Name.O)
+		}
+		return common.CompatibilityRenameColumn
+	// DROP COLUMN
+	case ast.AlterTableDropColumn:
+		if tracked {
+			v.checkColumn(text, table, []string{spec.OldColumnName.Name.O})
+			v.catalog.DropColumn(table, spec.OldColumnName.Name.O)
+		}
+		return common.CompatibilityDropColumn
+	// ADD COLUMN
+	case ast.AlterTableAddColumns:
+		if tracked {
+			for _, column := range spec.NewColumns {
+				v.catalog.AddColumn(table, column.Name.Name.O)
+			}
+		}
+	// RENAME TO
+	case ast.AlterTableRenameTable:
+		if tracked && spec.NewTable.Schema.O == "" {
+			v.catalog.RenameTable(table, spec.NewTable.Name.O)
+		}
+	case ast.AlterTableAddConstraint:
+		if tracked {
+			v.checkColumn(text, table, indexColumnList(spec.Constraint.Keys))
+		}
+		// ADD PRIMARY KEY
+		if spec.Constraint.Tp == ast.ConstraintPrimaryKey {
+			return common.CompatibilityAddPrimaryKey
+		}
+		// ADD UNIQUE/UNIQUE KEY/UNIQUE INDEX
+		if spec.Constraint.Tp == ast.ConstraintUniq ||
+			spec.Constraint.Tp == ast.ConstraintUniqKey {
+			return common.CompatibilityAddUniqueKey
+		}
+		// ADD FOREIGN KEY
+		if spec.Constraint.Tp == ast.ConstraintForeignKey {
+			v.checkReference(text, spec.Constraint.Refer)
+			return common.CompatibilityAddForeignKey
+		}
+		// Check is only supported after 8.0.16 https://dev.mysql.com/doc/refman/8.0/en/create-table-check-constraints.html
+		// ADD CHECK ENFORCED
+		if spec.Constraint.Tp == ast.ConstraintCheck && spec.Constraint.Enforced {
+			return common.CompatibilityAddCheck
+		}
+	// Check is only supported after 8.0.16 https://dev.mysql.com/doc/refman/8.0/en/create-table-check-constraints.html
+	// ALTER CHECK ENFORCED
+	case ast.AlterTableAlterCheck:
+		if spec.Constraint.Enforced {
+			return common.CompatibilityAlterCheck
+		}
+	// MODIFY COLUMN / CHANGE COLUMN
+	case ast.AlterTableModifyColumn, ast.AlterTableChangeColumn:
+		return v.alterColumn(node, spec, tracked)
+	}
+	return common.Ok
+}
+
+// alterColumn returns the code of MODIFY COLUMN and CHANGE COLUMN.
+// Without the current definition of the column in the catalog, we treat all changes as incompatible.
+// Otherwise, the renaming is incompatible, and the change is compatible if the type is the same or widened,
+// e.g. INT to BIGINT and VARCHAR(50) to VARCHAR(100), and the column doesn't become NOT NULL, e.g. only changing the comment.
+func (v *compatibilityChecker) alterColumn(node *ast.AlterTableStmt, spec *ast.AlterTableSpec, tracked bool) common.Code {
+	if !tracked || len(spec.NewColumns) == 0 {
+		return common.CompatibilityAlterColumn
+	}
+	table := node.Table.Name.O
+	newColumn := spec.NewColumns[0]
+	oldName := newColumn.Name.Name.O
+	if spec.Tp == ast.AlterTableChangeColumn {
+		oldName = spec.OldColumnName.Name.O
+	}
+	v.checkColumn(node.Text(), table, []string{oldName})
+	column, _ := v.catalog.FindColumn(table, oldName)
+	v.catalog.ChangeColumn(table, oldName, newColumn.Name.Name.O)
+
+	if column == nil {
+		return common.CompatibilityAlterColumn
+	}
+	if !strings.EqualFold(oldName, newColumn.Name.Name.O) {
+		return common.CompatibilityRenameColumn
+	}
+	if !isCompatibleColumnChange(column, newColumn, v.primaryKeyMap[table][strings.ToLower(oldName)]) {
+		return common.CompatibilityAlterColumn
+	}
+	return common.Ok
+}
+
+var (
+	// integerTypeRank is the rank of the integer types by the value range.
+	integerTypeRank = map[byte]int{
+		mysql.TypeTiny:     1,
+		mysql.TypeShort:    2,
+		mysql.TypeInt24:    3,
+		mysql.TypeLong:     4,
+		mysql.TypeLonglong: 5,
+	}
+	// textTypeRank is the rank of the TEXT and the BLOB types by the max length.
+	textTypeRank = map[byte]int{
+		mysql.TypeTinyBlob:   1,
+		mysql.TypeBlob:       2,
+		mysql.TypeMediumBlob: 3,
+		mysql.TypeLongBlob:   4,
+	}
+)
+
+// isCompatibleColumnChange returns whether the new column definition keeps the existing data and code working with the column.
+// The unspecified character set and collation are considered unchanged, while the unspecified DEFAULT, AUTO_INCREMENT and
+// NOT NULL are dropped by MODIFY and CHANGE, except NOT NULL of the primary key column.
+func isCompatibleColumnChange(column *advisor.CatalogColumn, newColumn *ast.ColumnDef, primaryKey bool) bool {
+	oldType, err := parseColumnType(column.Type)
+	if err != nil {
+		return false
+	}
+	newType := newColumn.Tp
+	if !isWidenedType(oldType, newType) {
+		return false
+	}
+
+	collation := newType.Collate
+	notNull, hasDefault, autoIncrement := primaryKey, false, false
+	for _, option := range newColumn.Options {
+		switch option.Tp {
+		// NOT NULL rejects the existing NULL values and the writes of NULL.
+		case ast.ColumnOptionNotNull, ast.ColumnOptionPrimaryKey:
+			if column.Nullable {
+				return false
+			}
+			notNull = true
+		case ast.ColumnOptionDefaultValue:
+			hasDefault = true
+		case ast.ColumnOptionAutoIncrement:
+			autoIncrement = true
+		case ast.ColumnOptionCollate:
+			collation = option.StrValue
+		}
+	}
+	// Dropping them breaks the writes omitting the column, and the code assuming the column isn't NULL.
+	if !column.Nullable && !notNull {
+		return false
+	}
+	if column.Default != nil && !hasDefault {
+		return false
+	}
+	if strings.Contains(strings.ToLower(column.Extra), "auto_increment") && !autoIncrement {
+		return false
+	}
+	if !mysql.HasBinaryFlag(newType.Flag) {
+		if newType.Charset != "" && !strings.EqualFold(newType.Charset, column.CharacterSet) {
+			return false
+		}
+		if collation != "" && !strings.EqualFold(collation, column.Collation) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWidenedType returns whether the new type can hold all values of the old type in the same representation.
+func isWidenedType(oldType *types.FieldType, newType *types.FieldType) bool {
+	if mysql.HasBinaryFlag(oldType.Flag) != mysql.HasBinaryFlag(newType.Flag) {
+		return false
+	}
+	oldIntegerRank, oldIsInteger := integerTypeRank[oldType.Tp]
+	newIntegerRank, newIsInteger := integerTypeRank[newType.Tp]
+	if oldIsInteger || newIsInteger {
+		return oldIsInteger && newIsInteger &&
+			newIntegerRank >= oldIntegerRank &&
+			mysql.HasUnsignedFlag(oldType.Flag) == mysql.HasUnsignedFlag(newType.Flag)
+	}
+
+	if oldType.Tp != newType.Tp {
+		switch {
+		// VARCHAR to TEXT, VARBINARY to BLOB, TEXT to MEDIUMTEXT etc.
+		case oldType.Tp == mysql.TypeVarchar || oldType.Tp == mysql.TypeVarString:
+			return textTypeRank[newType.Tp] >= textTypeRank[mysql.TypeBlob]
+		case textTypeRank[oldType.Tp] > 0:
+			return textTypeRank[newType.Tp] >= textTypeRank[oldType.Tp]
+		case oldType.Tp == mysql.TypeFloat:
+			return newType.Tp == mysql.TypeDouble
+		}
+		return false
+	}
+
+	switch oldType.Tp {
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString:
+		return newType.Flen >= oldType.Flen
+	case mysql.TypeNewDecimal:
+		// The precision and the scale of DECIMAL default to 10 and 0.
+		oldFlen, oldDecimal := decimalPrecisionAndScale(oldType)
+		newFlen, newDecimal := decimalPrecisionAndScale(newType)
+		return newDecimal >= oldDecimal && newFlen-newDecimal >= oldFlen-oldDecimal
+	case mysql.TypeEnum, mysql.TypeSet:
+		// Appending the values at the end keeps the existing values and their indexes.
+		if len(newType.Elems) < len(oldType.Elems) {
+			return false
+		}
+		for i, elem := range oldType.Elems {
+			if newType.Elems[i] != elem {
+				return false
+			}
+		}
+		return true
+	}
+	return newType.Flen == oldType.Flen && newType.Decimal == oldType.Decimal
+}
+
+func decimalPrecisionAndScale(tp *types.FieldType) (int, int) {
+	flen, decimal := tp.Flen, tp.Decimal
+	if flen == types.UnspecifiedLength {
+		flen = 10
+	}
+	if decimal == types.UnspecifiedLength {
+		decimal = 0
+	}
+	return flen, decimal
+}
+
+// parseColumnType parses the column type synced from the database, e.g. "int(11) unsigned".
+func parseColumnType(columnType string) (*types.FieldType, error) {
+	root, _, err := newParser().Parse(fmt.Sprintf("CREATE TABLE t (c %s)", columnType), "", "")
+	if err != nil {
+		return nil, err
+	}
+	if len(root) != 1 {
+		return nil, fmt.Errorf("invalid column type %q", columnType)
+	}
+	node, ok := root[0].(*ast.CreateTableStmt)
+	if !ok || len(node.Cols) != 1 {
+		return nil, fmt.Errorf("invalid column type %q", columnType)
+	}
+	return node.Cols[0].Tp, nil
+}
@@ -42,10 +42,11 @@ func TestBasic(t *testing.T) {
 			statement: "DROP DATABASE d1",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityDropDatabase,
-					Title:   "Potential incompatible migration",
-					Content: "\"DROP DATABASE d1\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityDropDatabase,
+					Title:    "Potential incompatible migration",
+					Content:  "\"DROP DATABASE d1\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "DROP DATABASE d1"},
 				},
 			},
 		},
@@ -53,10 +54,11 @@ func TestBasic(t *testing.T) {
 			statement: "DROP TABLE t1",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityDropTable,
-					Title:   "Potential incompatible migration",
-					Content: "\"DROP TABLE t1\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityDropTable,
+					Title:    "Potential incompatible migration",
+					Content:  "\"DROP TABLE t1\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "DROP TABLE t1"},
 				},
 			},
 		},
@@ -64,10 +66,11 @@ func TestBasic(t *testing.T) {
 			statement: "RENAME TABLE t1 to t2",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityRenameTable,
-					Title:   "Potential incompatible migration",
-					Content: "\"RENAME TABLE t1 to t2\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityRenameTable,
+					Title:    "Potential incompatible migration",
+					Content:  "\"RENAME TABLE t1 to t2\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "RENAME TABLE t1 to t2"},
 				},
 			},
 		},
@@ -75,10 +78,11 @@ func TestBasic(t *testing.T) {
 			statement: "DROP VIEW v1",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityDropTable,
-					Title:   "Potential incompatible migration",
-					Content: "\"DROP VIEW v1\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityDropTable,
+					Title:    "Potential incompatible migration",
+					Content:  "\"DROP VIEW v1\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "DROP VIEW v1"},
 				},
 			},
 		},
@@ -86,10 +90,19 @@ func TestBasic(t *testing.T) {
 			statement: "CREATE UNIQUE INDEX idx1 ON t1 (f1)",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAddUniqueKey,
-					Title:   "Potential incompatible migration",
-					Content: "\"CREATE UNIQUE INDEX idx1 ON t1 (f1)\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddUniqueKey,
+					Title:    "Potential incompatible migration",
+					Content:  "\"CREATE UNIQUE INDEX idx1 ON t1 (f1)\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "CREATE UNIQUE INDEX idx1 ON t1 (f1)"},
+				},
+				{
+					Status:      advisor.Warn,
+					Code:        common.CompatibilityAddIndexNotOnline,
+					Title:       "Index creation may block writes",
+					Content:     "\"CREATE UNIQUE INDEX idx1 ON t1 (f1)\" may block the writes of table \"t1\" until the index is built, use ALGORITHM=INPLACE LOCK=NONE instead",
+					Position:    &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "CREATE UNIQUE INDEX idx1 ON t1 (f1)"},
+					Replacement: "CREATE UNIQUE INDEX idx1 ON t1 (f1) ALGORITHM=INPLACE LOCK=NONE",
 				},
 			},
 		},
@@ -97,16 +110,18 @@ func TestBasic(t *testing.T) {
 			statement: "DROP TABLE t1;DROP TABLE t2;",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityDropTable,
-					Title:   "Potential incompatible migration",
-					Content: "\"DROP TABLE t1;\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityDropTable,
+					Title:    "Potential incompatible migration",
+					Content:  "\"DROP TABLE t1;\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "DROP TABLE t1"},
 				},
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityDropTable,
-					Title:   "Potential incompatible migration",
-					Content: "\"DROP TABLE t2;\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityDropTable,
+					Title:    "Potential incompatible migration",
+					Content:  "\"DROP TABLE t2;\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 15, Snippet: "DROP TABLE t2"},
 				},
 			},
 		},
@@ -132,10 +147,11 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 RENAME COLUMN f1 to f2",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityRenameColumn,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 RENAME COLUMN f1 to f2\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityRenameColumn,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 RENAME COLUMN f1 to f2\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 RENAME COLUMN f1 to f2"},
 				},
 			},
 		},
@@ -143,10 +159,11 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 DROP COLUMN f1",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityDropColumn,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 DROP COLUMN f1\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityDropColumn,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 DROP COLUMN f1\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 DROP COLUMN f1"},
 				},
 			},
 		},
@@ -154,10 +171,11 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 ADD PRIMARY KEY (f1)",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAddPrimaryKey,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 ADD PRIMARY KEY (f1)\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddPrimaryKey,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 ADD PRIMARY KEY (f1)\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD PRIMARY KEY (f1)"},
 				},
 			},
 		},
@@ -165,10 +183,19 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 ADD UNIQUE (f1)",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAddUniqueKey,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 ADD UNIQUE (f1)\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddUniqueKey,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 ADD UNIQUE (f1)\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD UNIQUE (f1)"},
+				},
+				{
+					Status:      advisor.Warn,
+					Code:        common.CompatibilityAddIndexNotOnline,
+					Title:       "Index creation may block writes",
+					Content:     "\"ALTER TABLE t1 ADD UNIQUE (f1)\" may block the writes of table \"t1\" until the index is built, use ALGORITHM=INPLACE, LOCK=NONE instead",
+					Position:    &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD UNIQUE (f1)"},
+					Replacement: "ALTER TABLE t1 ADD UNIQUE (f1), ALGORITHM=INPLACE, LOCK=NONE",
 				},
 			},
 		},
@@ -176,10 +203,19 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 ADD UNIQUE KEY (f1)",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAddUniqueKey,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 ADD UNIQUE KEY (f1)\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddUniqueKey,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 ADD UNIQUE KEY (f1)\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD UNIQUE KEY (f1)"},
+				},
+				{
+					Status:      advisor.Warn,
+					Code:        common.CompatibilityAddIndexNotOnline,
+					Title:       "Index creation may block writes",
+					Content:     "\"ALTER TABLE t1 ADD UNIQUE KEY (f1)\" may block the writes of table \"t1\" until the index is built, use ALGORITHM=INPLACE, LOCK=NONE instead",
+					Position:    &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD UNIQUE KEY (f1)"},
+					Replacement: "ALTER TABLE t1 ADD UNIQUE KEY (f1), ALGORITHM=INPLACE, LOCK=NONE",
 				},
 			},
 		},
@@ -187,10 +223,19 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 ADD UNIQUE INDEX (f1)",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAddUniqueKey,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 ADD UNIQUE INDEX (f1)\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddUniqueKey,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 ADD UNIQUE INDEX (f1)\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD UNIQUE INDEX (f1)"},
+				},
+				{
+					Status:      advisor.Warn,
+					Code:        common.CompatibilityAddIndexNotOnline,
+					Title:       "Index creation may block writes",
+					Content:     "\"ALTER TABLE t1 ADD UNIQUE INDEX (f1)\" may block the writes of table \"t1\" until the index is built, use ALGORITHM=INPLACE, LOCK=NONE instead",
+					Position:    &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD UNIQUE INDEX (f1)"},
+					Replacement: "ALTER TABLE t1 ADD UNIQUE INDEX (f1), ALGORITHM=INPLACE, LOCK=NONE",
 				},
 			},
 		},
@@ -198,10 +243,11 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 ADD FOREIGN KEY (f1) REFERENCES t2(f2)",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAddForeignKey,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 ADD FOREIGN KEY (f1) REFERENCES t2(f2)\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddForeignKey,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 ADD FOREIGN KEY (f1) REFERENCES t2(f2)\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD FOREIGN KEY (f1) REFERENCES t2(f2)"},
 				},
 			},
 		},
@@ -209,10 +255,11 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 ADD CHECK (f1 > 0)",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAddCheck,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 ADD CHECK (f1 > 0)\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddCheck,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 ADD CHECK (f1 > 0)\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD CHECK (f1 > 0)"},
 				},
 			},
 		},
@@ -230,10 +277,11 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 ALTER CHECK chk1 ENFORCED",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAlterCheck,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 ALTER CHECK chk1 ENFORCED\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAlterCheck,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 ALTER CHECK chk1 ENFORCED\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ALTER CHECK chk1 ENFORCED"},
 				},
 			},
 		},
@@ -251,10 +299,11 @@ func TestAlterTable(t *testing.T) {
 			statement: "ALTER TABLE t1 ADD CONSTRAINT CHECK (f1 > 0)",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAddCheck,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 ADD CONSTRAINT CHECK (f1 > 0)\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddCheck,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 ADD CONSTRAINT CHECK (f1 > 0)\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD CONSTRAINT CHECK (f1 > 0)"},
 				},
 			},
 		},
@@ -280,10 +329,11 @@ func TestAlterTableChangeColumnType(t *testing.T) {
 			statement: "ALTER TABLE t1 CHANGE f1 f2 TEXT",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAlterColumn,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 CHANGE f1 f2 TEXT\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAlterColumn,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 CHANGE f1 f2 TEXT\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 CHANGE f1 f2 TEXT"},
 				},
 			},
 		},
@@ -291,10 +341,11 @@ func TestAlterTableChangeColumnType(t *testing.T) {
 			statement: "ALTER TABLE t1 MODIFY f1 TEXT",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAlterColumn,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 MODIFY f1 TEXT\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAlterColumn,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 MODIFY f1 TEXT\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 MODIFY f1 TEXT"},
 				},
 			},
 		},
@@ -302,10 +353,11 @@ func TestAlterTableChangeColumnType(t *testing.T) {
 			statement: "ALTER TABLE t1 MODIFY f1 TEXT NULL",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAlterColumn,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 MODIFY f1 TEXT NULL\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAlterColumn,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 MODIFY f1 TEXT NULL\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 MODIFY f1 TEXT NULL"},
 				},
 			},
 		},
@@ -313,10 +365,11 @@ func TestAlterTableChangeColumnType(t *testing.T) {
 			statement: "ALTER TABLE t1 MODIFY f1 TEXT NOT NULL",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAlterColumn,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 MODIFY f1 TEXT NOT NULL\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAlterColumn,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 MODIFY f1 TEXT NOT NULL\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 MODIFY f1 TEXT NOT NULL"},
 				},
 			},
 		},
@@ -324,10 +377,11 @@ func TestAlterTableChangeColumnType(t *testing.T) {
 			statement: "ALTER TABLE t1 MODIFY f1 TEXT COMMENT 'bla'",
 			want: []advisor.Advice{
 				{
-					Status:  advisor.Warn,
-					Code:    common.CompatibilityAlterColumn,
-					Title:   "Potential incompatible migration",
-					Content: "\"ALTER TABLE t1 MODIFY f1 TEXT COMMENT 'bla'\" may cause incompatibility with the existing data and code",
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAlterColumn,
+					Title:    "Potential incompatible migration",
+					Content:  "\"ALTER TABLE t1 MODIFY f1 TEXT COMMENT 'bla'\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 MODIFY f1 TEXT COMMENT 'bla'"},
 				},
 			},
 		},
@@ -335,3 +389,221 @@ func TestAlterTableChangeColumnType(t *testing.T) {
 
 	runTests(t, tests)
 }
+
+func TestCatalog(t *testing.T) {
+	adv := CompatibilityAdvisor{}
+	zero := "0"
+	ctx := advisor.Context{
+		Catalog: &advisor.Catalog{
+			TableList: []*advisor.CatalogTable{
+				{
+					Name: "t1",
+					ColumnList: []*advisor.CatalogColumn{
+						{Name: "id", Type: "int(11)"},
+						{Name: "name", Type: "varchar(50)", Nullable: true, CharacterSet: "utf8mb4", Collation: "utf8mb4_general_ci"},
+						{Name: "price", Type: "decimal(10,2)"},
+						{Name: "status", Type: "enum('a','b')"},
+						{Name: "amount", Type: "int unsigned"},
+						{Name: "counter", Type: "int(11)", Default: &zero},
+						{Name: "seq", Type: "int(11)", Extra: "auto_increment"},
+					},
+					IndexList: []*advisor.CatalogIndex{{Name: "PRIMARY", Unique: true, ExpressionList: []string{"id"}}},
+				},
+				{
+					Name:       "t2",
+					ColumnList: []*advisor.CatalogColumn{{Name: "id", Type: "bigint"}},
+				},
+				{
+					Name:       "t6",
+					ColumnList: []*advisor.CatalogColumn{{Name: "c", Type: "int(11)", Default: &zero, Extra: "auto_increment"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		statement string
+		want      []common.Code
+	}{
+		{
+			statement: "ALTER TABLE t1 MODIFY id BIGINT NOT NULL, MODIFY name TEXT, MODIFY price DECIMAL(12,2) NOT NULL, MODIFY status ENUM('a','b','c') NOT NULL",
+			want:      []common.Code{common.Ok},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY id BIGINT, MODIFY counter BIGINT NOT NULL DEFAULT 0, MODIFY seq BIGINT NOT NULL AUTO_INCREMENT",
+			want:      []common.Code{common.Ok},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY price DECIMAL(12,2)",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY counter BIGINT NOT NULL",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY seq BIGINT NOT NULL",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t6 MODIFY c BIGINT",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY name VARCHAR(100) COMMENT 'bla'",
+			want:      []common.Code{common.Ok},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY id SMALLINT",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY amount BIGINT",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY name VARCHAR(20)",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY name VARCHAR(100) NOT NULL",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY name VARCHAR(100) CHARACTER SET latin1",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY price DECIMAL(10,4)",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 MODIFY status ENUM('b','a')",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 CHANGE name title VARCHAR(100)",
+			want:      []common.Code{common.CompatibilityRenameColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 ADD COLUMN c INT; ALTER TABLE t1 MODIFY c BIGINT",
+			want:      []common.Code{common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE t3 ADD COLUMN c INT; ALTER TABLE t1 DROP COLUMN c",
+			want:      []common.Code{common.CompatibilityTableNotFound, common.CompatibilityColumnNotFound, common.CompatibilityDropColumn},
+		},
+		{
+			statement: "ALTER TABLE t1 ADD FOREIGN KEY (id) REFERENCES t2 (uid); CREATE INDEX idx_t1_c ON t1 (c)",
+			want:      []common.Code{common.CompatibilityColumnNotFound, common.CompatibilityAddForeignKey, common.CompatibilityColumnNotFound, common.CompatibilityAddIndexNotOnline},
+		},
+		{
+			statement: "CREATE TABLE t3 (id INT, t1_id INT REFERENCES t1 (id), FOREIGN KEY (id) REFERENCES t4 (id)); CREATE INDEX idx_t3_id ON t3 (id); RENAME TABLE t2 TO t5; ALTER TABLE t2 ADD COLUMN c INT; ALTER TABLE other.t ADD COLUMN c INT",
+			want:      []common.Code{common.CompatibilityTableNotFound, common.CompatibilityRenameTable, common.CompatibilityTableNotFound},
+		},
+	}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(ctx, tc.statement)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		var codeList []common.Code
+		for _, advice := range adviceList {
+			codeList = append(codeList, advice.Code)
+		}
+		if !reflect.DeepEqual(tc.want, codeList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, codeList)
+		}
+	}
+}
+
+func TestReplacement(t *testing.T) {
+	adv := CompatibilityAdvisor{}
+	ctx := advisor.Context{
+		Catalog: &advisor.Catalog{
+			TableList: []*advisor.CatalogTable{
+				{
+					Name:       "t1",
+					ColumnList: []*advisor.CatalogColumn{{Name: "id", Type: "int(11)"}, {Name: "name", Type: "varchar(50)"}},
+				},
+			},
+		},
+	}
+
+	tests := []test{
+		{
+			statement: "CREATE TABLE t1 (id INT);",
+			want: []advisor.Advice{
+				{
+					Status:      advisor.Error,
+					Code:        common.CompatibilityTableExists,
+					Title:       "Table already exists",
+					Content:     "Table \"t1\" already exists, so \"CREATE TABLE t1 (id INT);\" fails, use CREATE TABLE IF NOT EXISTS instead",
+					Position:    &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "CREATE TABLE t1 (id INT)"},
+					Replacement: "CREATE TABLE IF NOT EXISTS t1 (id INT)",
+				},
+			},
+		},
+		{
+			statement: "CREATE INDEX idx_t1_name ON t1 (name);",
+			want: []advisor.Advice{
+				{
+					Status:      advisor.Warn,
+					Code:        common.CompatibilityAddIndexNotOnline,
+					Title:       "Index creation may block writes",
+					Content:     "\"CREATE INDEX idx_t1_name ON t1 (name);\" may block the writes of table \"t1\" until the index is built, use ALGORITHM=INPLACE LOCK=NONE instead",
+					Position:    &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "CREATE INDEX idx_t1_name ON t1 (name)"},
+					Replacement: "CREATE INDEX idx_t1_name ON t1 (name) ALGORITHM=INPLACE LOCK=NONE",
+				},
+			},
+		},
+		{
+			statement: "ALTER TABLE t1 ADD COLUMN c INT, ADD INDEX idx_t1_c (c)",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddIndexNotOnline,
+					Title:    "Index creation may block writes",
+					Content:  "\"ALTER TABLE t1 ADD COLUMN c INT, ADD INDEX idx_t1_c (c)\" may block the writes of table \"t1\" until the index is built, add the index with ALGORITHM=INPLACE, LOCK=NONE in a separate statement instead",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t1 ADD COLUMN c INT, ADD INDEX idx_t1_c (c)"},
+				},
+			},
+		},
+		{
+			statement: "CREATE TABLE IF NOT EXISTS t1 (id INT); ALTER TABLE t1 ADD INDEX idx_t1_name (name), ALGORITHM=INPLACE, LOCK=NONE; CREATE FULLTEXT INDEX idx_t1_name_ft ON t1 (name)",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    common.Ok,
+					Title:   "OK",
+					Content: "Migration is backward compatible",
+				},
+			},
+		},
+		{
+			statement: "CREATE TABLE t2 (id INT); CREATE INDEX idx_t2_id ON t2 (id); ALTER TABLE t2 ADD UNIQUE INDEX uk_t2_id (id)",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Warn,
+					Code:     common.CompatibilityAddUniqueKey,
+					Title:    "Potential incompatible migration",
+					Content:  "\" ALTER TABLE t2 ADD UNIQUE INDEX uk_t2_id (id)\" may cause incompatibility with the existing data and code",
+					Position: &advisor.Position{StatementIndex: 2, Line: 1, Column: 62, Snippet: "ALTER TABLE t2 ADD UNIQUE INDEX uk_t2_id (id)"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(ctx, tc.statement)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		if !reflect.DeepEqual(tc.want, adviceList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, adviceList)
+		}
+	}
+}
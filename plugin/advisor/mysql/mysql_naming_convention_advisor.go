@@ -0,0 +1,184 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	"github.com/pingcap/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*NamingConventionAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLNamingConvention, &NamingConventionAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLNamingConvention, &NamingConventionAdvisor{})
+}
+
+// NamingConventionAdvisor is the advisor checking for the naming conventions of the tables, the columns, the indexes and the foreign keys.
+type NamingConventionAdvisor struct {
+}
+
+// Check checks the names in the CREATE TABLE, ALTER TABLE, RENAME TABLE and CREATE INDEX statements against the naming convention rule.
+func (adv *NamingConventionAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	if ctx.Rule == nil {
+		return nil, fmt.Errorf("the %s advisor requires the SQL review rule", advisor.MySQLNamingConvention)
+	}
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	collector := &namingObjectCollector{catalog: ctx.Catalog}
+	positionList := statementPositionList(statement, root)
+	for i, stmtNode := range root {
+		collector.position = positionList[i]
+		(stmtNode).Accept(collector)
+	}
+	adviceList, err := advisor.CheckNamingConvention(ctx.Rule, collector.objectList)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// namingObjectCollector collects the objects named by the statements.
+type namingObjectCollector struct {
+	objectList []*advisor.NamingObject
+	// catalog is the synced schema to find the indexes renamed by the statements, it's nil if the catalog is unknown.
+	catalog *advisor.Catalog
+	// position is the position of the statement being visited.
+	position *advisor.Position
+}
+
+func (v *namingObjectCollector) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.CreateTableStmt:
+		table := node.Table.Name.O
+		v.addTable(table)
+		for _, column := range node.Cols {
+			v.addColumn(table, column)
+		}
+		for _, constraint := range node.Constraints {
+			v.addConstraint(table, constraint)
+		}
+	case *ast.AlterTableStmt:
+		table := node.Table.Name.O
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			case ast.AlterTableRenameTable:
+				v.addTable(spec.NewTable.Name.O)
+			case ast.AlterTableAddColumns:
+				for _, column := range spec.NewColumns {
+					v.addColumn(table, column)
+				}
+				for _, constraint := range spec.NewConstraints {
+					v.addConstraint(table, constraint)
+				}
+			case ast.AlterTableChangeColumn:
+				for _, column := range spec.NewColumns {
+					v.addColumn(table, column)
+				}
+			case ast.AlterTableRenameColumn:
+				v.objectList = append(v.objectList, &advisor.NamingObject{
+					Type:     advisor.NamingObjectColumn,
+					Name:     spec.NewColumnName.Name.O,
+					Table:    table,
+					Position: v.position,
+				})
+			case ast.AlterTableAddConstraint:
+				v.addConstraint(table, spec.Constraint)
+			case ast.AlterTableRenameIndex:
+				// Whether the index is unique and its columns are unknown from the statement, so the index is skipped if it's unknown.
+				if object := advisor.RenamedNamingObject(v.objectList, v.catalog, table, table, spec.FromKey.O, spec.ToKey.O); object != nil {
+					object.Position = v.position
+					v.objectList = append(v.objectList, object)
+				}
+			}
+		}
+	case *ast.RenameTableStmt:
+		for _, tableToTable := range node.TableToTables {
+			v.addTable(tableToTable.NewTable.Name.O)
+		}
+	case *ast.CreateIndexStmt:
+		objectType := advisor.NamingObjectIndex
+		if node.KeyType == ast.IndexKeyTypeUnique {
+			objectType = advisor.NamingObjectUniqueKey
+		}
+		v.objectList = append(v.objectList, &advisor.NamingObject{
+			Type:       objectType,
+			Name:       node.IndexName,
+			Table:      node.Table.Name.O,
+			ColumnList: indexColumnList(node.IndexPartSpecifications),
+			Position:   v.position,
+		})
+	}
+	return in, false
+}
+
+func (v *namingObjectCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (v *namingObjectCollector) addTable(table string) {
+	v.objectList = append(v.objectList, &advisor.NamingObject{
+		Type:     advisor.NamingObjectTable,
+		Name:     table,
+		Position: v.position,
+	})
+}
+
+func (v *namingObjectCollector) addColumn(table string, column *ast.ColumnDef) {
+	v.objectList = append(v.objectList, &advisor.NamingObject{
+		Type:     advisor.NamingObjectColumn,
+		Name:     column.Name.Name.O,
+		Table:    table,
+		Position: v.position,
+	})
+}
+
+func (v *namingObjectCollector) addConstraint(table string, constraint *ast.Constraint) {
+	object := &advisor.NamingObject{
+		Name:       constraint.Name,
+		Table:      table,
+		ColumnList: indexColumnList(constraint.Keys),
+		Position:   v.position,
+	}
+	switch constraint.Tp {
+	case ast.ConstraintIndex, ast.ConstraintKey, ast.ConstraintFulltext:
+		object.Type = advisor.NamingObjectIndex
+	case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		object.Type = advisor.NamingObjectUniqueKey
+	case ast.ConstraintForeignKey:
+		object.Type = advisor.NamingObjectForeignKey
+		object.ReferencedTable = constraint.Refer.Table.Name.O
+		object.ReferencedColumnList = indexColumnList(constraint.Refer.IndexPartSpecifications)
+	default:
+		return
+	}
+	v.objectList = append(v.objectList, object)
+}
+
+// indexColumnList returns the column names of the index, the expression parts are skipped.
+func indexColumnList(keyList []*ast.IndexPartSpecification) []string {
+	var columnList []string
+	for _, key := range keyList {
+		if key.Column != nil {
+			columnList = append(columnList, key.Column.Name.O)
+		}
+	}
+	return columnList
+}
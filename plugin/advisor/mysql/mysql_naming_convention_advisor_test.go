@@ -0,0 +1,169 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestNamingConventionAdvisor(t *testing.T) {
+	adv := NamingConventionAdvisor{}
+	catalog := &advisor.Catalog{
+		TableList: []*advisor.CatalogTable{
+			{
+				Name: "s",
+				IndexList: []*advisor.CatalogIndex{
+					{Name: "idx_s_b", ExpressionList: []string{"b"}},
+					{Name: "uk_s_c", Unique: true, ExpressionList: []string{"c"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		rule      *advisor.SQLReviewRule
+		catalog   *advisor.Catalog
+		statement string
+		want      []advisor.Advice
+	}{
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleTableNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^[a-z]+(_[a-z]+)*$"}`},
+			statement: "CREATE TABLE user_info (id INT); RENAME TABLE user_info TO userInfo; ALTER TABLE t RENAME TO t_1",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingTableConventionMismatch,
+					Title:    "Mismatch table naming convention",
+					Content:  "Table \"userInfo\" mismatches the naming convention, expect \"^[a-z]+(_[a-z]+)*$\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 34, Snippet: "RENAME TABLE user_info TO userInfo"},
+				},
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingTableConventionMismatch,
+					Title:    "Mismatch table naming convention",
+					Content:  "Table \"t_1\" mismatches the naming convention, expect \"^[a-z]+(_[a-z]+)*$\"",
+					Position: &advisor.Position{StatementIndex: 2, Line: 1, Column: 70, Snippet: "ALTER TABLE t RENAME TO t_1"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleColumnNaming, Level: advisor.RuleLevelWarning, Payload: `{"format": "^[a-z]+(_[a-z]+)*$"}`},
+			statement: "CREATE TABLE t (user_id INT, userName TEXT); ALTER TABLE t ADD COLUMN created_ts INT, CHANGE user_id UserID INT, RENAME COLUMN userName TO user_name",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Warn,
+					Code:     common.NamingColumnConventionMismatch,
+					Title:    "Mismatch column naming convention",
+					Content:  "Column \"t\".\"userName\" mismatches the naming convention, expect \"^[a-z]+(_[a-z]+)*$\"",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "CREATE TABLE t (user_id INT, userName TEXT)"},
+				},
+				{
+					Status:   advisor.Warn,
+					Code:     common.NamingColumnConventionMismatch,
+					Title:    "Mismatch column naming convention",
+					Content:  "Column \"t\".\"UserID\" mismatches the naming convention, expect \"^[a-z]+(_[a-z]+)*$\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 46, Snippet: "ALTER TABLE t ADD COLUMN created_ts INT, CHANGE user_id UserID INT, RENAME COLUMN userName TO user_name"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleIDXNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^idx_{{table}}_{{column_list}}$"}`},
+			statement: "CREATE TABLE t (a INT, b INT, INDEX idx_t_a_b (a, b), UNIQUE KEY a_b (a, b)); CREATE INDEX t_b ON t (b); ALTER TABLE t ADD INDEX (a)",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingIndexConventionMismatch,
+					Title:    "Mismatch index naming convention",
+					Content:  "Index \"t_b\" on table \"t\" mismatches the naming convention, expect \"^idx_t_b$\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 79, Snippet: "CREATE INDEX t_b ON t (b)"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleUKNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^uk_{{table}}_{{column_list}}$"}`},
+			statement: "CREATE TABLE t (a INT, b INT, UNIQUE KEY a_b (a, b)); CREATE UNIQUE INDEX uk_t_b ON t (b)",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingUKConventionMismatch,
+					Title:    "Mismatch unique key naming convention",
+					Content:  "Unique key \"a_b\" on table \"t\" mismatches the naming convention, expect \"^uk_t_a_b$\"",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "CREATE TABLE t (a INT, b INT, UNIQUE KEY a_b (a, b))"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleFKNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^fk_{{referencing_table}}_{{referencing_column}}_{{referenced_table}}_{{referenced_column}}$"}`},
+			statement: "ALTER TABLE t ADD CONSTRAINT fk_t_org_id_org_id FOREIGN KEY (org_id) REFERENCES org (id), ADD CONSTRAINT t_user FOREIGN KEY (user_id) REFERENCES user (id)",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingFKConventionMismatch,
+					Title:    "Mismatch foreign key naming convention",
+					Content:  "Foreign key \"t_user\" on table \"t\" mismatches the naming convention, expect \"^fk_t_user_id_user_id$\"",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t ADD CONSTRAINT fk_t_org_id_org_id FOREIGN KEY (org_id) REFERENCES org (id), ADD CONSTRAINT t_user FOREIGN KEY (user_id) REFERENCES user (id)"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleIDXNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^idx_{{table}}_{{column_list}}$"}`},
+			catalog:   catalog,
+			statement: "CREATE TABLE t (a INT, INDEX idx_t_a (a)); ALTER TABLE t RENAME INDEX idx_t_a TO t_a; ALTER TABLE s RENAME INDEX idx_s_b TO s_b; ALTER TABLE s RENAME KEY unknown TO x",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingIndexConventionMismatch,
+					Title:    "Mismatch index naming convention",
+					Content:  "Index \"t_a\" on table \"t\" mismatches the naming convention, expect \"^idx_t_a$\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 44, Snippet: "ALTER TABLE t RENAME INDEX idx_t_a TO t_a"},
+				},
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingIndexConventionMismatch,
+					Title:    "Mismatch index naming convention",
+					Content:  "Index \"s_b\" on table \"s\" mismatches the naming convention, expect \"^idx_s_b$\"",
+					Position: &advisor.Position{StatementIndex: 2, Line: 1, Column: 87, Snippet: "ALTER TABLE s RENAME INDEX idx_s_b TO s_b"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleUKNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^uk_{{table}}_{{column_list}}$"}`},
+			catalog:   catalog,
+			statement: "ALTER TABLE s RENAME INDEX uk_s_c TO s_c",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingUKConventionMismatch,
+					Title:    "Mismatch unique key naming convention",
+					Content:  "Unique key \"s_c\" on table \"s\" mismatches the naming convention, expect \"^uk_s_c$\"",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE s RENAME INDEX uk_s_c TO s_c"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleTableNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^[a-z]+$"}`},
+			statement: "DROP TABLE MyTable",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    common.Ok,
+					Title:   "OK",
+					Content: "",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(advisor.Context{Rule: tc.rule, Catalog: tc.catalog}, tc.statement)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		if !reflect.DeepEqual(tc.want, adviceList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, adviceList)
+		}
+	}
+}
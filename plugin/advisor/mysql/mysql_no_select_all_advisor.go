@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	"github.com/pingcap/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*NoSelectAllAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLNoSelectAll, &NoSelectAllAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLNoSelectAll, &NoSelectAllAdvisor{})
+}
+
+// NoSelectAllAdvisor is the advisor checking for no "SELECT *".
+type NoSelectAllAdvisor struct {
+}
+
+// Check checks for no "SELECT *".
+func (adv *NoSelectAllAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	if ctx.Rule == nil {
+		return nil, fmt.Errorf("the %s advisor requires the SQL review rule", advisor.MySQLNoSelectAll)
+	}
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &noSelectAllChecker{level: level}
+	positionList := statementPositionList(statement, root)
+	for i, stmtNode := range root {
+		checker.text = stmtNode.Text()
+		n := len(checker.adviceList)
+		(stmtNode).Accept(checker)
+		advisor.SetPosition(checker.adviceList[n:], positionList[i])
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type noSelectAllChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	// text is the text of the statement being checked.
+	text string
+}
+
+func (v *noSelectAllChecker) Enter(in ast.Node) (ast.Node, bool) {
+	if node, ok := in.(*ast.SelectStmt); ok && node.Fields != nil {
+		for _, field := range node.Fields.Fields {
+			if field.WildCard != nil {
+				v.adviceList = append(v.adviceList, advisor.Advice{
+					Status:  v.level,
+					Code:    common.StatementSelectAll,
+					Title:   "No SELECT all",
+					Content: fmt.Sprintf("%q uses SELECT all", v.text),
+				})
+				// Report the statement once.
+				return in, true
+			}
+		}
+	}
+	return in, false
+}
+
+func (v *noSelectAllChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -1,6 +1,21 @@
 package mysql
 
-import "github.com/pingcap/parser"
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+)
+
+var (
+	// syntaxErrorRegexp matches the line and the start of the text near the syntax error reported by the parser.
+	syntaxErrorRegexp = regexp.MustCompile(`^line (\d+) column \d+ near "`)
+)
 
 // Wrapper for parser.New().
 func newParser() *parser.Parser {
@@ -12,3 +27,87 @@ func newParser() *parser.Parser {
 
 	return p
 }
+
+// parseStatement parses the statement for the SQL review rule advisors.
+// It returns the syntax error advice instead if the statement fails to parse.
+func parseStatement(statement string, charset string, collation string) ([]ast.StmtNode, []advisor.Advice) {
+	p := newParser()
+
+	root, _, err := p.Parse(statement, charset, collation)
+	if err != nil {
+		return nil, []advisor.Advice{syntaxErrorAdvice(statement, err)}
+	}
+	return root, nil
+}
+
+// syntaxErrorAdvice returns the advice of the syntax error, which is positioned at the error reported by the parser.
+func syntaxErrorAdvice(statement string, err error) advisor.Advice {
+	advice := advisor.Advice{
+		Status:  advisor.Error,
+		Code:    common.DbStatementSyntaxError,
+		Title:   "Syntax error",
+		Content: err.Error(),
+	}
+	if offset, ok := syntaxErrorOffset(statement, err.Error()); ok {
+		advice.Position = advisor.LocateSyntaxError(db.MySQL, statement, offset)
+	}
+	return advice
+}
+
+// syntaxErrorOffset returns the byte offset of the token where the syntax error is. The parser reports the line and the
+// column after the token, followed by the text from the token to the end of the statement, which is truncated to 2048 bytes.
+// So the offset is the last one before the end of the reported line where the rest of the statement matches the text.
+func syntaxErrorOffset(statement string, message string) (int, bool) {
+	match := syntaxErrorRegexp.FindStringSubmatch(message)
+	if match == nil {
+		return 0, false
+	}
+	line, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	near := message[len(match[0]):]
+
+	lineStart := 0
+	for i := 1; i < line; i++ {
+		index := strings.Index(statement[lineStart:], "\n")
+		if index < 0 {
+			break
+		}
+		lineStart += index + 1
+	}
+	lineEnd := len(statement)
+	if index := strings.Index(statement[lineStart:], "\n"); index >= 0 {
+		lineEnd = lineStart + index
+	}
+	for offset := lineEnd; offset >= 0; offset-- {
+		text := statement[offset:]
+		if len(text) > 2048 {
+			text = text[:2048]
+		}
+		if strings.HasPrefix(near, text+`"`) {
+			return offset, true
+		}
+	}
+	return 0, false
+}
+
+// acceptStatementList visits the statements in order, and sets the position of the statement to the advices
+// appended to the adviceList by visiting the statement.
+func acceptStatementList(statement string, root []ast.StmtNode, visitor ast.Visitor, adviceList *[]advisor.Advice) {
+	positionList := statementPositionList(statement, root)
+	for i, stmtNode := range root {
+		n := len(*adviceList)
+		(stmtNode).Accept(visitor)
+		advisor.SetPosition((*adviceList)[n:], positionList[i])
+	}
+}
+
+// statementPositionList returns the positions of the parsed statements in the script.
+func statementPositionList(statement string, root []ast.StmtNode) []*advisor.Position {
+	var textList []string
+	for _, stmtNode := range root {
+		textList = append(textList, stmtNode.Text())
+	}
+	return advisor.LocateStatementList(statement, textList)
+}
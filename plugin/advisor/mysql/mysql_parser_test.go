@@ -1,6 +1,11 @@
 package mysql
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
 
 func TestMysql8WindowFunction(t *testing.T) {
 	parser := newParser()
@@ -12,3 +17,35 @@ func TestMysql8WindowFunction(t *testing.T) {
 		t.Errorf("Expect no warning, but got %+v", warns)
 	}
 }
+
+func TestSyntaxErrorAdvice(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      *advisor.Position
+	}{
+		{
+			statement: "SELECT 1; SELEC 2",
+			want:      &advisor.Position{StatementIndex: 1, Line: 1, Column: 11, Snippet: "SELEC 2"},
+		},
+		{
+			statement: "SELECT 1;\n-- Create the table.\nCREATE TABLE t (\n  id INT,\n);",
+			want:      &advisor.Position{StatementIndex: 1, Line: 5, Column: 1, Snippet: "CREATE TABLE t (\n  id INT,\n)"},
+		},
+		{
+			statement: "SELECT 1;\nCREATE TABLE t (\n  id INT\n",
+			want:      &advisor.Position{StatementIndex: 1, Line: 4, Column: 1, Snippet: "CREATE TABLE t (\n  id INT"},
+		},
+	}
+
+	for _, test := range tests {
+		_, _, err := newParser().Parse(test.statement, "", "")
+		if err == nil {
+			t.Errorf("statement=%s: expected the syntax error", test.statement)
+			continue
+		}
+		advice := syntaxErrorAdvice(test.statement, err)
+		if !reflect.DeepEqual(advice.Position, test.want) {
+			t.Errorf("statement=%s: expected position %+v, got %+v", test.statement, test.want, advice.Position)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+)
+
+func TestSQLReviewCheck(t *testing.T) {
+	ruleList := []*advisor.SQLReviewRule{
+		{Type: advisor.RuleStatementNoSelectAll, Level: advisor.RuleLevelWarning},
+		{Type: advisor.RuleStatementRequireWhere, Level: advisor.RuleLevelError},
+		{Type: advisor.RuleStatementDisallowDrop, Level: advisor.RuleLevelError},
+		{Type: advisor.RuleTableRequirePK, Level: advisor.RuleLevelDisabled},
+		{Type: advisor.RuleIndexMaxCount, Level: advisor.RuleLevelWarning, Payload: `{"number": 2}`},
+	}
+	logger, _ := zap.NewDevelopmentConfig().Build()
+	checkContext := advisor.SQLReviewCheckContext{
+		Logger: logger,
+		DbType: db.MySQL,
+	}
+
+	tests := []struct {
+		statement string
+		want      []advisor.Advice
+	}{
+		{
+			statement: "UPDATE t SET a = 1 WHERE id = 1; INSERT INTO t SELECT id, a FROM s",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    common.Ok,
+					Title:   "OK",
+					Content: "The statement passes the SQL review rules",
+				},
+			},
+		},
+		{
+			statement: "INSERT INTO t SELECT * FROM s; DELETE FROM t; DROP VIEW v; DROP TABLE t",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Warn,
+					Code:     common.StatementSelectAll,
+					Title:    "No SELECT all",
+					Content:  "\"INSERT INTO t SELECT * FROM s;\" uses SELECT all",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "INSERT INTO t SELECT * FROM s"},
+				},
+				{
+					Status:   advisor.Error,
+					Code:     common.StatementNoWhere,
+					Title:    "Require WHERE clause",
+					Content:  "\" DELETE FROM t;\" requires WHERE clause",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 32, Snippet: "DELETE FROM t"},
+				},
+				{
+					Status:   advisor.Error,
+					Code:     common.StatementDropDisallowed,
+					Title:    "Disallow DROP",
+					Content:  "\" DROP TABLE t\" drops the data, which is disallowed",
+					Position: &advisor.Position{StatementIndex: 3, Line: 1, Column: 60, Snippet: "DROP TABLE t"},
+				},
+			},
+		},
+		{
+			statement: "CREATE TABLE t (id INT, a INT UNIQUE, b INT, INDEX idx_b (b), FULLTEXT INDEX idx_c (c))",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Warn,
+					Code:     common.IndexCountExceedsLimit,
+					Title:    "Index count exceeds the limit",
+					Content:  "Table \"t\" has 3 indexes, exceeding the limit 2",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "CREATE TABLE t (id INT, a INT UNIQUE, b INT, INDEX idx_b (b), FULLTEXT INDEX idx_c (c))"},
+				},
+			},
+		},
+		{
+			statement: "DELETE FROM",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.DbStatementSyntaxError,
+					Title:    "Syntax error",
+					Content:  "line 1 column 11 near \"\" ",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 12, Snippet: "DELETE FROM"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		adviceList, err := advisor.SQLReviewCheck(tc.statement, ruleList, checkContext)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		if !reflect.DeepEqual(tc.want, adviceList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, adviceList)
+		}
+	}
+}
+
+func TestTableRequirePKAdvisor(t *testing.T) {
+	adv := TableRequirePKAdvisor{}
+	ctx := advisor.Context{
+		Rule: &advisor.SQLReviewRule{Type: advisor.RuleTableRequirePK, Level: advisor.RuleLevelError},
+	}
+
+	tests := []struct {
+		statement string
+		want      []common.Code
+	}{
+		{
+			statement: "CREATE TABLE t (id INT PRIMARY KEY)",
+			want:      []common.Code{common.Ok},
+		},
+		{
+			statement: "CREATE TABLE t (id INT, PRIMARY KEY (id))",
+			want:      []common.Code{common.Ok},
+		},
+		{
+			statement: "CREATE TABLE t LIKE s",
+			want:      []common.Code{common.Ok},
+		},
+		{
+			statement: "CREATE TABLE t (id INT); ALTER TABLE s DROP PRIMARY KEY",
+			want:      []common.Code{common.TableNoPK, common.TableNoPK},
+		},
+	}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(ctx, tc.statement)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		var codeList []common.Code
+		for _, advice := range adviceList {
+			codeList = append(codeList, advice.Code)
+		}
+		if !reflect.DeepEqual(tc.want, codeList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, codeList)
+		}
+	}
+}
+
+func TestIndexMaxCountAdvisor(t *testing.T) {
+	adv := IndexMaxCountAdvisor{}
+	catalog := &advisor.Catalog{
+		TableList: []*advisor.CatalogTable{
+			{
+				Name: "t",
+				IndexList: []*advisor.CatalogIndex{
+					{Name: "PRIMARY", Unique: true},
+					{Name: "idx_a"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		statement string
+		catalog   *advisor.Catalog
+		want      []string
+	}{
+		{
+			statement: "CREATE INDEX idx_b ON t (b)",
+			catalog:   catalog,
+			want:      nil,
+		},
+		{
+			statement: "CREATE INDEX idx_b ON t (b); CREATE UNIQUE INDEX uk_c ON t (c)",
+			catalog:   catalog,
+			want:      []string{`Table "t" has 3 indexes, exceeding the limit 2`},
+		},
+		{
+			statement: "ALTER TABLE t ADD INDEX (b), ADD UNIQUE KEY (c)",
+			catalog:   catalog,
+			want:      []string{`Table "t" has 3 indexes, exceeding the limit 2`},
+		},
+		{
+			statement: "ALTER TABLE t DROP INDEX idx_a, ADD INDEX (b), ADD COLUMN c INT UNIQUE",
+			catalog:   catalog,
+			want:      nil,
+		},
+		{
+			statement: "ALTER TABLE t DROP INDEX idx_a; ALTER TABLE t ADD INDEX (b), ADD INDEX (b), ADD PRIMARY KEY (id)",
+			catalog:   catalog,
+			want:      nil,
+		},
+		{
+			// The existing indexes are unknown without the catalog.
+			statement: "CREATE INDEX idx_b ON t (b); CREATE INDEX idx_c ON t (c)",
+			want:      nil,
+		},
+		{
+			statement: "CREATE TABLE s (id INT PRIMARY KEY, a INT, INDEX (a)); ALTER TABLE s ADD INDEX (a), ADD INDEX (a)",
+			want:      []string{`Table "s" has 3 indexes, exceeding the limit 2`},
+		},
+		{
+			statement: "RENAME TABLE t TO s; CREATE INDEX idx_b ON s (b); CREATE INDEX idx_c ON s (c)",
+			catalog:   catalog,
+			want:      []string{`Table "s" has 3 indexes, exceeding the limit 2`},
+		},
+		{
+			statement: "CREATE TABLE s LIKE t; CREATE INDEX idx_b ON s (b); DROP INDEX idx_b ON s; CREATE INDEX idx_c ON s (c)",
+			catalog:   catalog,
+			want:      nil,
+		},
+	}
+
+	for _, tc := range tests {
+		ctx := advisor.Context{
+			Rule:    &advisor.SQLReviewRule{Type: advisor.RuleIndexMaxCount, Level: advisor.RuleLevelWarning, Payload: `{"number": 2}`},
+			Catalog: tc.catalog,
+		}
+		adviceList, err := adv.Check(ctx, tc.statement)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		var contentList []string
+		for _, advice := range adviceList {
+			if advice.Code == common.IndexCountExceedsLimit {
+				contentList = append(contentList, advice.Content)
+			}
+		}
+		if !reflect.DeepEqual(tc.want, contentList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, contentList)
+		}
+	}
+}
+
+func TestSQLReviewRuleAdvisorWithoutRule(t *testing.T) {
+	for _, adv := range []advisor.Advisor{
+		&NoSelectAllAdvisor{},
+		&WhereRequirementAdvisor{},
+		&DisallowDropAdvisor{},
+		&TableRequirePKAdvisor{},
+		&IndexMaxCountAdvisor{},
+		&NamingConventionAdvisor{},
+	} {
+		if _, err := adv.Check(advisor.Context{}, "CREATE TABLE t (id INT)"); err == nil {
+			t.Errorf("%T: expected error without the rule", adv)
+		}
+	}
+}
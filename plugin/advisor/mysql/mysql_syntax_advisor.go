@@ -25,14 +25,7 @@ func (adv *SyntaxAdvisor) Check(ctx advisor.Context, statement string) ([]adviso
 
 	_, warns, err := p.Parse(statement, ctx.Charset, ctx.Collation)
 	if err != nil {
-		return []advisor.Advice{
-			{
-				Status:  advisor.Error,
-				Code:    common.DbStatementSyntaxError,
-				Title:   "Syntax error",
-				Content: err.Error(),
-			},
-		}, nil
+		return []advisor.Advice{syntaxErrorAdvice(statement, err)}, nil
 	}
 
 	advisorList := make([]advisor.Advice, 0, len(warns)+1)
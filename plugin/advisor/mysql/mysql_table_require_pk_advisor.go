@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	"github.com/pingcap/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*TableRequirePKAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLTableRequirePK, &TableRequirePKAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLTableRequirePK, &TableRequirePKAdvisor{})
+}
+
+// TableRequirePKAdvisor is the advisor checking for the table primary key requirement.
+type TableRequirePKAdvisor struct {
+}
+
+// Check checks for the table primary key requirement.
+func (adv *TableRequirePKAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	if ctx.Rule == nil {
+		return nil, fmt.Errorf("the %s advisor requires the SQL review rule", advisor.MySQLTableRequirePK)
+	}
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &tableRequirePKChecker{level: level}
+	acceptStatementList(statement, root, checker, &checker.adviceList)
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type tableRequirePKChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+}
+
+func (v *tableRequirePKChecker) Enter(in ast.Node) (ast.Node, bool) {
+	missingPK := false
+	switch node := in.(type) {
+	case *ast.CreateTableStmt:
+		// CREATE TABLE ... LIKE copies the primary key of the referenced table.
+		if node.ReferTable == nil {
+			missingPK = !hasPrimaryKey(node)
+		}
+	case *ast.AlterTableStmt:
+		for _, spec := range node.Specs {
+			if spec.Tp == ast.AlterTableDropPrimaryKey {
+				missingPK = true
+				break
+			}
+		}
+	}
+
+	if missingPK {
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    common.TableNoPK,
+			Title:   "Require PRIMARY KEY",
+			Content: fmt.Sprintf("%q leaves the table without PRIMARY KEY", in.Text()),
+		})
+	}
+	return in, false
+}
+
+func (v *tableRequirePKChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// hasPrimaryKey returns whether the created table has the primary key, which is defined either as a table constraint or as a column option.
+func hasPrimaryKey(node *ast.CreateTableStmt) bool {
+	for _, constraint := range node.Constraints {
+		if constraint.Tp == ast.ConstraintPrimaryKey {
+			return true
+		}
+	}
+	for _, column := range node.Cols {
+		for _, option := range column.Options {
+			if option.Tp == ast.ColumnOptionPrimaryKey {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package mysql
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	"github.com/pingcap/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*WhereRequirementAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLWhereRequirement, &WhereRequirementAdvisor{})
+	advisor.Register(db.TiDB, advisor.MySQLWhereRequirement, &WhereRequirementAdvisor{})
+}
+
+// WhereRequirementAdvisor is the advisor checking for the WHERE clause requirement of UPDATE and DELETE.
+type WhereRequirementAdvisor struct {
+}
+
+// Check checks for the WHERE clause requirement of UPDATE and DELETE.
+func (adv *WhereRequirementAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	if ctx.Rule == nil {
+		return nil, fmt.Errorf("the %s advisor requires the SQL review rule", advisor.MySQLWhereRequirement)
+	}
+	root, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &whereRequirementChecker{level: level}
+	acceptStatementList(statement, root, checker, &checker.adviceList)
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type whereRequirementChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+}
+
+func (v *whereRequirementChecker) Enter(in ast.Node) (ast.Node, bool) {
+	missingWhere := false
+	switch node := in.(type) {
+	case *ast.UpdateStmt:
+		missingWhere = node.Where == nil
+	case *ast.DeleteStmt:
+		missingWhere = node.Where == nil
+	}
+
+	if missingWhere {
+		v.adviceList = append(v.adviceList, advisor.Advice{
+			Status:  v.level,
+			Code:    common.StatementNoWhere,
+			Title:   "Require WHERE clause",
+			Content: fmt.Sprintf("%q requires WHERE clause", in.Text()),
+		})
+	}
+	return in, false
+}
+
+func (v *whereRequirementChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
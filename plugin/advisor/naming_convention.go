@@ -0,0 +1,225 @@
+package advisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// NamingObjectType is the type of the object checked by the naming convention rules.
+type NamingObjectType string
+
+const (
+	// NamingObjectTable is the table named by CREATE TABLE, RENAME TABLE and ALTER TABLE ... RENAME TO.
+	NamingObjectTable NamingObjectType = "TABLE"
+	// NamingObjectColumn is the column added or renamed.
+	NamingObjectColumn NamingObjectType = "COLUMN"
+	// NamingObjectIndex is the non-unique index.
+	NamingObjectIndex NamingObjectType = "INDEX"
+	// NamingObjectUniqueKey is the unique index or the unique constraint.
+	NamingObjectUniqueKey NamingObjectType = "UNIQUE KEY"
+	// NamingObjectForeignKey is the foreign key constraint.
+	NamingObjectForeignKey NamingObjectType = "FOREIGN KEY"
+
+	// The template tokens in the format of the index naming rules.
+	tableNameTemplateToken  = "{{table}}"
+	columnListTemplateToken = "{{column_list}}"
+	// The template tokens in the format of the foreign key naming rule.
+	referencingTableNameTemplateToken  = "{{referencing_table}}"
+	referencingColumnNameTemplateToken = "{{referencing_column}}"
+	referencedTableNameTemplateToken   = "{{referenced_table}}"
+	referencedColumnNameTemplateToken  = "{{referenced_column}}"
+)
+
+var (
+	namingRuleObjectTypeMap = map[SQLReviewRuleType]NamingObjectType{
+		RuleTableNaming:  NamingObjectTable,
+		RuleColumnNaming: NamingObjectColumn,
+		RuleIDXNaming:    NamingObjectIndex,
+		RuleUKNaming:     NamingObjectUniqueKey,
+		RuleFKNaming:     NamingObjectForeignKey,
+	}
+	namingRuleCodeMap = map[SQLReviewRuleType]common.Code{
+		RuleTableNaming:  common.NamingTableConventionMismatch,
+		RuleColumnNaming: common.NamingColumnConventionMismatch,
+		RuleIDXNaming:    common.NamingIndexConventionMismatch,
+		RuleUKNaming:     common.NamingUKConventionMismatch,
+		RuleFKNaming:     common.NamingFKConventionMismatch,
+	}
+	// namingRuleTemplateTokenMap is the template tokens supported by the format of the rule.
+	namingRuleTemplateTokenMap = map[SQLReviewRuleType][]string{
+		RuleIDXNaming: {tableNameTemplateToken, columnListTemplateToken},
+		RuleUKNaming:  {tableNameTemplateToken, columnListTemplateToken},
+		RuleFKNaming:  {referencingTableNameTemplateToken, referencingColumnNameTemplateToken, referencedTableNameTemplateToken, referencedColumnNameTemplateToken},
+	}
+)
+
+// NamingRulePayload is the payload of the naming convention rules.
+type NamingRulePayload struct {
+	// Format is the regular expression which the names must match, e.g. "^[a-z]+(_[a-z]+)*$" for snake_case.
+	// The formats of the index and the foreign key rules can contain the template tokens which are replaced by the names
+	// of the object, e.g. "^idx_{{table}}_{{column_list}}$" where the column list is the column names joined by "_",
+	// and "^fk_{{referencing_table}}_{{referencing_column}}_{{referenced_table}}_{{referenced_column}}$".
+	Format string `json:"format"`
+}
+
+// UnmarshalNamingRulePayload will unmarshal the payload of the naming convention rule.
+func UnmarshalNamingRulePayload(payload string) (*NamingRulePayload, error) {
+	var nr NamingRulePayload
+	if err := json.Unmarshal([]byte(payload), &nr); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal naming rule payload %q: %q", payload, err)
+	}
+	return &nr, nil
+}
+
+// NamingObject is the object named by the statement, which is checked by the naming convention rules.
+type NamingObject struct {
+	Type NamingObjectType
+	Name string
+	// Table is the table of the column, the index and the foreign key.
+	Table string
+	// ColumnList is the columns of the index and the referencing columns of the foreign key.
+	ColumnList []string
+	// ReferencedTable and ReferencedColumnList are only set for the foreign key.
+	ReferencedTable      string
+	ReferencedColumnList []string
+	// Position is the position of the statement naming the object.
+	Position *Position
+}
+
+// validateNamingRule validates the format of the naming convention rule is a valid regular expression after replacing the template tokens.
+func validateNamingRule(rule *SQLReviewRule) error {
+	payload, err := UnmarshalNamingRulePayload(rule.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.Format == "" {
+		return fmt.Errorf("invalid SQL review rule %q, the format must not be empty", rule.Type)
+	}
+	format := payload.Format
+	for _, token := range namingRuleTemplateTokenMap[rule.Type] {
+		format = strings.ReplaceAll(format, token, "x")
+	}
+	if _, err := regexp.Compile(format); err != nil {
+		return fmt.Errorf("invalid SQL review rule %q, the format %q is not a valid regular expression: %w", rule.Type, payload.Format, err)
+	}
+	return nil
+}
+
+// CheckNamingConvention checks the names of the objects of the rule type against the naming convention rule,
+// and returns the advices of the mismatched names. The unnamed objects are skipped, since the names are generated by the database.
+func CheckNamingConvention(rule *SQLReviewRule, objectList []*NamingObject) ([]Advice, error) {
+	objectType, ok := namingRuleObjectTypeMap[rule.Type]
+	if !ok {
+		return nil, fmt.Errorf("%q is not a naming convention rule", rule.Type)
+	}
+	level, err := NewStatusBySQLReviewRuleLevel(rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := UnmarshalNamingRulePayload(rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []Advice
+	for _, object := range objectList {
+		if object.Type != objectType || object.Name == "" {
+			continue
+		}
+		format := expandNamingTemplate(payload.Format, object)
+		re, err := regexp.Compile(format)
+		if err != nil {
+			return nil, fmt.Errorf("invalid format %q of SQL review rule %q: %w", payload.Format, rule.Type, err)
+		}
+		if re.MatchString(object.Name) {
+			continue
+		}
+		adviceList = append(adviceList, Advice{
+			Status:   level,
+			Code:     namingRuleCodeMap[rule.Type],
+			Title:    fmt.Sprintf("Mismatch %s naming convention", strings.ToLower(string(objectType))),
+			Content:  fmt.Sprintf("%s mismatches the naming convention, expect %q", describeNamingObject(object), format),
+			Position: object.Position,
+		})
+	}
+	return adviceList, nil
+}
+
+// RenamedNamingObject returns the naming object of the index or the constraint of the table renamed from oldName to newName.
+// Its type and columns are those of the object named earlier in the script, or else of the index in the catalog, where
+// catalogTable is the table name in the catalog. The empty table matches the object of any table named in the script.
+// It returns nil if the renamed object is unknown, e.g. the foreign key and the check constraint named outside the
+// script, which aren't in the catalog.
+func RenamedNamingObject(objectList []*NamingObject, catalog *Catalog, table string, catalogTable string, oldName string, newName string) *NamingObject {
+	for i := len(objectList) - 1; i >= 0; i-- {
+		object := objectList[i]
+		if object.Type == NamingObjectTable || object.Type == NamingObjectColumn {
+			continue
+		}
+		if (table == "" || object.Table == table) && strings.EqualFold(object.Name, oldName) {
+			renamed := *object
+			renamed.Name = newName
+			return &renamed
+		}
+	}
+	if catalog == nil {
+		return nil
+	}
+	_, index := catalog.FindIndex(catalogTable, oldName)
+	if index == nil {
+		return nil
+	}
+	object := &NamingObject{
+		Type:       NamingObjectIndex,
+		Name:       newName,
+		Table:      table,
+		ColumnList: index.ExpressionList,
+	}
+	if index.Unique {
+		object.Type = NamingObjectUniqueKey
+	}
+	return object
+}
+
+// expandNamingTemplate replaces the template tokens in the format with the quoted names of the object.
+func expandNamingTemplate(format string, object *NamingObject) string {
+	var oldnew []string
+	switch object.Type {
+	case NamingObjectIndex, NamingObjectUniqueKey:
+		oldnew = []string{
+			tableNameTemplateToken, regexp.QuoteMeta(object.Table),
+			columnListTemplateToken, regexp.QuoteMeta(strings.Join(object.ColumnList, "_")),
+		}
+	case NamingObjectForeignKey:
+		oldnew = []string{
+			referencingTableNameTemplateToken, regexp.QuoteMeta(object.Table),
+			referencingColumnNameTemplateToken, regexp.QuoteMeta(strings.Join(object.ColumnList, "_")),
+			referencedTableNameTemplateToken, regexp.QuoteMeta(object.ReferencedTable),
+			referencedColumnNameTemplateToken, regexp.QuoteMeta(strings.Join(object.ReferencedColumnList, "_")),
+		}
+	default:
+		return format
+	}
+	return strings.NewReplacer(oldnew...).Replace(format)
+}
+
+// describeNamingObject returns the object type and the qualified name of the object, e.g. `Column "user"."userName"`.
+func describeNamingObject(object *NamingObject) string {
+	switch object.Type {
+	case NamingObjectTable:
+		return fmt.Sprintf("Table %q", object.Name)
+	case NamingObjectColumn:
+		return fmt.Sprintf("Column %q.%q", object.Table, object.Name)
+	case NamingObjectIndex:
+		return fmt.Sprintf("Index %q on table %q", object.Name, object.Table)
+	case NamingObjectUniqueKey:
+		return fmt.Sprintf("Unique key %q on table %q", object.Name, object.Table)
+	case NamingObjectForeignKey:
+		return fmt.Sprintf("Foreign key %q on table %q", object.Name, object.Table)
+	}
+	return fmt.Sprintf("%q", object.Name)
+}
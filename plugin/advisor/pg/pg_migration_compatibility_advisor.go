@@ -0,0 +1,449 @@
+package pg
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+var (
+	_ advisor.Advisor = (*CompatibilityAdvisor)(nil)
+
+	// createIndexPrefixRegexp matches the keywords before the position of CONCURRENTLY in the CREATE INDEX statement.
+	createIndexPrefixRegexp = regexp.MustCompile(`(?i)^\s*CREATE\s+(UNIQUE\s+)?INDEX`)
+
+	// serialTypes is the serial types which have the default value from the sequence.
+	serialTypes = map[string]bool{
+		"smallserial": true,
+		"serial":      true,
+		"bigserial":   true,
+		"serial2":     true,
+		"serial4":     true,
+		"serial8":     true,
+	}
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLMigrationCompatibility, &CompatibilityAdvisor{})
+}
+
+// CompatibilityAdvisor is the advisor checking for schema backward compatibility.
+type CompatibilityAdvisor struct {
+}
+
+// Check checks schema backward compatibility.
+func (adv *CompatibilityAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	c := &compatibilityChecker{
+		createdTableMap: make(map[string]bool),
+		soleStatement:   len(stmtList) == 1,
+	}
+	if ctx.Catalog != nil {
+		c.catalog = advisor.NewCatalogState(ctx.Catalog)
+	}
+	concurrentCount := 0
+	for _, stmt := range stmtList {
+		if parser.IsPGConcurrentIndexStmt(stmt) {
+			concurrentCount++
+		}
+	}
+	positionList := statementPositionList(statement, stmtList)
+	for i, stmt := range stmtList {
+		n := len(c.adviceList)
+		// The execution rejects the script mixing them with the other statements, since they can't run inside the transaction.
+		if concurrentCount > 0 && concurrentCount < len(stmtList) && parser.IsPGConcurrentIndexStmt(stmt) {
+			c.adviceList = append(c.adviceList, advisor.Advice{
+				Status:  advisor.Error,
+				Code:    common.CompatibilityMixConcurrentIndex,
+				Title:   "Concurrent index mixed with other statements",
+				Content: fmt.Sprintf("%q can't run inside a transaction block, so it can't be mixed with the other statements, please move it to a separate migration", stmt.Text()),
+			})
+		}
+		c.check(stmt)
+		advisor.SetPosition(c.adviceList[n:], positionList[i])
+	}
+
+	if len(c.adviceList) == 0 {
+		c.adviceList = append(c.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "Migration is backward compatible"})
+	}
+	return c.adviceList, nil
+}
+
+type compatibilityChecker struct {
+	adviceList []advisor.Advice
+	// createdTableMap is the tables created by the statements, which are empty, so the index creation doesn't block any write.
+	createdTableMap map[string]bool
+	// catalog is the catalog state as the statements are applied, it's nil if the catalog is unknown.
+	catalog *advisor.CatalogState
+	// soleStatement is whether the script has only one statement, which can be replaced by CREATE INDEX CONCURRENTLY.
+	soleStatement bool
+}
+
+func (c *compatibilityChecker) check(stmt parser.PGStmt) {
+	code := common.Ok
+	switch node := stmt.(type) {
+	// DROP DATABASE/SCHEMA/TABLE/VIEW
+	case *parser.PGDropStmt:
+		switch node.ObjectType {
+		case parser.PGObjectDatabase, parser.PGObjectSchema:
+			code = common.CompatibilityDropDatabase
+		case parser.PGObjectTable, parser.PGObjectView, parser.PGObjectMaterializedView:
+			code = common.CompatibilityDropTable
+			if c.catalog != nil {
+				for _, table := range node.NameList {
+					c.catalog.DropTable(qualifiedTableName(table))
+				}
+			}
+		}
+	case *parser.PGCreateTableStmt:
+		c.createdTableMap[node.Table.Name] = true
+		if c.catalog != nil {
+			c.createTable(node)
+		}
+	// ALTER TABLE
+	case *parser.PGAlterTableStmt:
+		code = c.alterTable(node)
+	// CREATE INDEX
+	case *parser.PGCreateIndexStmt:
+		if node.Unique {
+			code = common.CompatibilityAddUniqueKey
+		}
+		if c.catalog != nil {
+			c.checkTable(node.Text(), node.Table)
+			c.checkColumn(node.Text(), node.Table, node.ColumnList)
+		}
+		if !node.Concurrently && !c.createdTableMap[node.Table.Name] {
+			advice := advisor.Advice{
+				Status:  advisor.Warn,
+				Code:    common.CompatibilityCreateIndexNotConcurrently,
+				Title:   "Index creation blocks writes",
+				Content: fmt.Sprintf("%q blocks the writes of table %q until the index is built, use CREATE INDEX CONCURRENTLY instead", node.Text(), node.Table.Name),
+			}
+			// CREATE INDEX CONCURRENTLY can't be mixed with the other statements, so it's only replaced for the sole statement.
+			if c.soleStatement {
+				advice.Replacement = concurrentlyReplacement(node.Text())
+			} else {
+				advice.Content = fmt.Sprintf("%q blocks the writes of table %q until the index is built, use CREATE INDEX CONCURRENTLY in a separate migration instead", node.Text(), node.Table.Name)
+			}
+			c.adviceList = append(c.adviceList, advice)
+		}
+	}
+
+	if code != common.Ok {
+		content := fmt.Sprintf("%q may cause incompatibility with the existing data and code", stmt.Text())
+		if code == common.CompatibilityAddNotNullColumn {
+			content = fmt.Sprintf("%q adds the NOT NULL column without DEFAULT, which fails if the table has rows", stmt.Text())
+		}
+		c.adviceList = append(c.adviceList, advisor.Advice{
+			Status:  advisor.Warn,
+			Code:    code,
+			Title:   "Potential incompatible migration",
+			Content: content,
+		})
+	}
+}
+
+func (c *compatibilityChecker) checkTable(text string, table *parser.PGTableName) {
+	c.adviceList = append(c.adviceList, c.catalog.CheckTableReference(text, qualifiedTableName(table))...)
+}
+
+func (c *compatibilityChecker) checkColumn(text string, table *parser.PGTableName, columnList []string) {
+	c.adviceList = append(c.adviceList, c.catalog.CheckColumnReference(text, qualifiedTableName(table), columnList)...)
+}
+
+// checkConstraint checks the columns of the constraint exist, and the table and the columns referenced by the foreign key exist.
+func (c *compatibilityChecker) checkConstraint(text string, table *parser.PGTableName, constraint *parser.PGConstraint) {
+	c.checkColumn(text, table, constraint.ColumnList)
+	if constraint.Type == parser.PGConstraintForeignKey && constraint.ReferencedTable != nil {
+		c.checkTable(text, constraint.ReferencedTable)
+		c.checkColumn(text, constraint.ReferencedTable, constraint.ReferencedColumnList)
+	}
+}
+
+// createTable applies the CREATE TABLE statement to the catalog state, and checks the constraints.
+func (c *compatibilityChecker) createTable(node *parser.PGCreateTableStmt) {
+	table := qualifiedTableName(node.Table)
+	if node.IfNotExists && c.catalog.HasTable(table) {
+		return
+	}
+	// The columns are unknown for CREATE TABLE ... AS, OF and PARTITION OF.
+	var columnList []string
+	for _, column := range node.ColumnList {
+		columnList = append(columnList, column.Name)
+	}
+	c.catalog.CreateTable(table, columnList)
+
+	for _, column := range node.ColumnList {
+		for _, constraint := range column.ConstraintList {
+			c.checkConstraint(node.Text(), node.Table, constraint)
+		}
+	}
+	for _, constraint := range node.ConstraintList {
+		c.checkConstraint(node.Text(), node.Table, constraint)
+	}
+}
+
+// alterTable returns the code of the first incompatible action of the ALTER TABLE statement,
+// and applies the actions to the catalog state.
+func (c *compatibilityChecker) alterTable(node *parser.PGAlterTableStmt) common.Code {
+	tracked := false
+	if c.catalog != nil {
+		c.checkTable(node.Text(), node.Table)
+		tracked = c.catalog.HasTable(qualifiedTableName(node.Table))
+	}
+	code := common.Ok
+	for _, action := range node.ActionList {
+		actionCode := c.alterTableAction(node, action, tracked)
+		if code == common.Ok {
+			code = actionCode
+		}
+	}
+	return code
+}
+
+// alterTableAction returns the code of the ALTER TABLE action, and applies the action to the catalog state if the table is tracked.
+func (c *compatibilityChecker) alterTableAction(node *parser.PGAlterTableStmt, action parser.PGAlterTableAction, tracked bool) common.Code {
+	text := node.Text()
+	table := qualifiedTableName(node.Table)
+	switch action := action.(type) {
+	// RENAME TO
+	case *parser.PGRenameTableAction:
+		if tracked {
+			c.catalog.RenameTable(table, qualifiedTableName(&parser.PGTableName{Schema: node.Table.Schema, Name: action.NewName}))
+		}
+		return common.CompatibilityRenameTable
+	// RENAME COLUMN
+	case *parser.PGRenameColumnAction:
+		if tracked {
+			c.checkColumn(text, node.Table, []string{action.Column})
+			c.catalog.ChangeColumn(table, action.Column, action.NewName)
+		}
+		return common.CompatibilityRenameColumn
+	// DROP COLUMN
+	case *parser.PGDropColumnAction:
+		if tracked {
+			if !action.IfExists {
+				c.checkColumn(text, node.Table, []string{action.Column})
+			}
+			c.catalog.DropColumn(table, action.Column)
+		}
+		return common.CompatibilityDropColumn
+	// ADD COLUMN ... NOT NULL without DEFAULT
+	case *parser.PGAddColumnAction:
+		if tracked {
+			c.catalog.AddColumn(table, action.Column.Name)
+			for _, constraint := range action.Column.ConstraintList {
+				c.checkConstraint(text, node.Table, constraint)
+			}
+		}
+		if isNotNullWithoutDefault(action.Column) {
+			return common.CompatibilityAddNotNullColumn
+		}
+	case *parser.PGAddConstraintAction:
+		if tracked {
+			c.checkConstraint(text, node.Table, action.Constraint)
+		}
+		switch action.Constraint.Type {
+		// ADD PRIMARY KEY
+		case parser.PGConstraintPrimaryKey:
+			return common.CompatibilityAddPrimaryKey
+		// ADD UNIQUE
+		case parser.PGConstraintUnique:
+			return common.CompatibilityAddUniqueKey
+		// ADD FOREIGN KEY, NOT VALID skips checking the existing rows but still checks the new rows.
+		case parser.PGConstraintForeignKey:
+			return common.CompatibilityAddForeignKey
+		// ADD CHECK, unless NOT VALID which is like the unenforced check of MySQL for the existing rows.
+		case parser.PGConstraintCheck:
+			if !action.NotValid {
+				return common.CompatibilityAddCheck
+			}
+		}
+	// ALTER COLUMN TYPE
+	// Without the current type of the column in the catalog, we treat all type changes as incompatible.
+	case *parser.PGAlterColumnTypeAction:
+		if !tracked {
+			return common.CompatibilityAlterColumn
+		}
+		c.checkColumn(text, node.Table, []string{action.Column})
+		column, _ := c.catalog.FindColumn(table, action.Column)
+		c.catalog.ChangeColumn(table, action.Column, action.Column)
+		if column == nil || !isWidenedType(column.Type, action.Type) {
+			return common.CompatibilityAlterColumn
+		}
+	// SET NOT NULL, which is compatible if the column is already NOT NULL.
+	case *parser.PGSetNotNullAction:
+		if !tracked {
+			return common.CompatibilityAlterColumn
+		}
+		c.checkColumn(text, node.Table, []string{action.Column})
+		column, _ := c.catalog.FindColumn(table, action.Column)
+		if column == nil || column.Nullable {
+			return common.CompatibilityAlterColumn
+		}
+	}
+	return common.Ok
+}
+
+// concurrentlyReplacement returns the CREATE INDEX statement with CONCURRENTLY, or empty if the statement doesn't start with
+// CREATE [UNIQUE] INDEX, e.g. there are comments between the keywords.
+func concurrentlyReplacement(text string) string {
+	loc := createIndexPrefixRegexp.FindStringIndex(text)
+	if loc == nil {
+		return ""
+	}
+	return text[:loc[1]] + " CONCURRENTLY" + text[loc[1]:]
+}
+
+// qualifiedTableName returns the table name qualified by the schema as the synced schema, the schema defaults to "public".
+func qualifiedTableName(table *parser.PGTableName) string {
+	schema := table.Schema
+	if schema == "" {
+		schema = "public"
+	}
+	return fmt.Sprintf("%s.%s", schema, table.Name)
+}
+
+// isNotNullWithoutDefault returns whether the column is NOT NULL, or the primary key, without the default value.
+func isNotNullWithoutDefault(column *parser.PGColumnDef) bool {
+	if column.HasDefault || serialTypes[strings.ToLower(column.Type)] {
+		return false
+	}
+	if column.NotNull {
+		return true
+	}
+	for _, constraint := range column.ConstraintList {
+		if constraint.Type == parser.PGConstraintPrimaryKey {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// typeNameMap maps the type names and the aliases to the type names synced from information_schema.columns.
+	typeNameMap = map[string]string{
+		"int2":              "smallint",
+		"smallint":          "smallint",
+		"int":               "integer",
+		"int4":              "integer",
+		"integer":           "integer",
+		"int8":              "bigint",
+		"bigint":            "bigint",
+		"float4":            "real",
+		"real":              "real",
+		"float8":            "double precision",
+		"double precision":  "double precision",
+		"decimal":           "numeric",
+		"numeric":           "numeric",
+		"varchar":           "character varying",
+		"character varying": "character varying",
+		"char":              "character",
+		"bpchar":            "character",
+		"character":         "character",
+		"text":              "text",
+		"bool":              "boolean",
+		"boolean":           "boolean",
+	}
+	// integerTypeRank is the rank of the integer types by the value range.
+	integerTypeRank = map[string]int{
+		"smallint": 1,
+		"integer":  2,
+		"bigint":   3,
+	}
+)
+
+// isWidenedType returns whether the new type can hold all values of the old type in the same representation,
+// e.g. integer to bigint, character varying(50) to character varying(100) and character varying to text.
+func isWidenedType(oldType string, newType string) bool {
+	oldName, oldModifierList := parseType(oldType)
+	newName, newModifierList := parseType(newType)
+	if oldName == newName && reflect.DeepEqual(oldModifierList, newModifierList) {
+		return true
+	}
+
+	oldRank, oldIsInteger := integerTypeRank[oldName]
+	newRank, newIsInteger := integerTypeRank[newName]
+	if oldIsInteger && newIsInteger {
+		return newRank >= oldRank
+	}
+
+	switch oldName {
+	case "character varying":
+		switch {
+		case newName == "text":
+			return true
+		case newName != "character varying":
+			return false
+		// The unconstrained length holds any length.
+		case len(newModifierList) == 0:
+			return true
+		case len(oldModifierList) == 0:
+			return false
+		}
+		return newModifierList[0] >= oldModifierList[0]
+	case "real":
+		return newName == "double precision"
+	case "numeric":
+		if newName != "numeric" {
+			return false
+		}
+		if len(newModifierList) == 0 {
+			return true
+		}
+		if len(oldModifierList) == 0 {
+			return false
+		}
+		oldPrecision, oldScale := numericPrecisionAndScale(oldModifierList)
+		newPrecision, newScale := numericPrecisionAndScale(newModifierList)
+		return newScale >= oldScale && newPrecision-newScale >= oldPrecision-oldScale
+	}
+	return false
+}
+
+// parseType returns the normalized type name and the type modifiers, e.g. "VARCHAR(100)" to "character varying" and [100].
+// The type name is kept in lower case if it's not a known alias.
+func parseType(typ string) (string, []int) {
+	typ = strings.ToLower(typ)
+	var modifierList []int
+	if start := strings.Index(typ, "("); start >= 0 {
+		if end := strings.Index(typ[start:], ")"); end >= 0 {
+			for _, modifier := range strings.Split(typ[start+1:start+end], ",") {
+				n, err := strconv.Atoi(strings.TrimSpace(modifier))
+				if err != nil {
+					// Keep the unknown modifiers in the type name, so the type only equals to the same type.
+					return strings.Join(strings.Fields(typ), " "), nil
+				}
+				modifierList = append(modifierList, n)
+			}
+			typ = typ[:start] + " " + typ[start+end+1:]
+		}
+	}
+	name := strings.Join(strings.Fields(typ), " ")
+	if alias, ok := typeNameMap[name]; ok {
+		name = alias
+	}
+	return name, modifierList
+}
+
+func numericPrecisionAndScale(modifierList []int) (int, int) {
+	if len(modifierList) == 1 {
+		return modifierList[0], 0
+	}
+	return modifierList[0], modifierList[1]
+}
@@ -0,0 +1,175 @@
+package pg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestCompatibilityAdvisor(t *testing.T) {
+	adv := CompatibilityAdvisor{}
+
+	tests := []struct {
+		statement string
+		want      []common.Code
+	}{
+		{
+			statement: "CREATE TABLE t (id serial PRIMARY KEY, name text NOT NULL); CREATE INDEX idx_t_name ON t (name); ALTER TABLE t ADD COLUMN a int DEFAULT 0 NOT NULL, ADD COLUMN b serial PRIMARY KEY, ADD COLUMN c text",
+			want:      []common.Code{common.Ok},
+		},
+		{
+			statement: "DROP DATABASE d; DROP SCHEMA s; DROP TABLE t; DROP VIEW v; DROP INDEX idx",
+			want:      []common.Code{common.CompatibilityDropDatabase, common.CompatibilityDropDatabase, common.CompatibilityDropTable, common.CompatibilityDropTable},
+		},
+		{
+			statement: "ALTER TABLE t RENAME TO t1; ALTER TABLE t RENAME a TO b; ALTER TABLE t ADD COLUMN c int, DROP COLUMN a",
+			want:      []common.Code{common.CompatibilityRenameTable, common.CompatibilityRenameColumn, common.CompatibilityDropColumn},
+		},
+		{
+			statement: "ALTER TABLE t ADD PRIMARY KEY (id); ALTER TABLE t ADD CONSTRAINT uk UNIQUE (a); ALTER TABLE t ADD FOREIGN KEY (b) REFERENCES s (id) NOT VALID; ALTER TABLE t ADD CHECK (c > 0); ALTER TABLE t ADD CHECK (c > 0) NOT VALID",
+			want:      []common.Code{common.CompatibilityAddPrimaryKey, common.CompatibilityAddUniqueKey, common.CompatibilityAddForeignKey, common.CompatibilityAddCheck},
+		},
+		{
+			statement: "ALTER TABLE t ALTER COLUMN a TYPE bigint; ALTER TABLE t ALTER COLUMN b SET NOT NULL; ALTER TABLE t ADD COLUMN c int NOT NULL; ALTER TABLE t ALTER COLUMN d SET DEFAULT 0",
+			want:      []common.Code{common.CompatibilityAlterColumn, common.CompatibilityAlterColumn, common.CompatibilityAddNotNullColumn},
+		},
+		{
+			statement: "CREATE INDEX idx_t_a ON t (a); CREATE UNIQUE INDEX CONCURRENTLY uk_t_b ON t (b); CREATE UNIQUE INDEX uk_t_c ON t (c)",
+			want:      []common.Code{common.CompatibilityCreateIndexNotConcurrently, common.CompatibilityMixConcurrentIndex, common.CompatibilityAddUniqueKey, common.CompatibilityCreateIndexNotConcurrently, common.CompatibilityAddUniqueKey},
+		},
+		{
+			statement: "CREATE INDEX CONCURRENTLY idx_t_a ON t (a); DROP INDEX CONCURRENTLY idx_t_b",
+			want:      []common.Code{common.Ok},
+		},
+		{
+			statement: "ALTER TABLE t ADD COLUMN",
+			want:      []common.Code{common.DbStatementSyntaxError},
+		},
+	}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(advisor.Context{}, tc.statement)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		var codeList []common.Code
+		for _, advice := range adviceList {
+			codeList = append(codeList, advice.Code)
+		}
+		if !reflect.DeepEqual(tc.want, codeList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, codeList)
+		}
+	}
+
+	adviceList, err := adv.Check(advisor.Context{}, "ALTER TABLE t ADD COLUMN a int NOT NULL; CREATE INDEX ON t (b)")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []advisor.Advice{
+		{
+			Status:   advisor.Warn,
+			Code:     common.CompatibilityAddNotNullColumn,
+			Title:    "Potential incompatible migration",
+			Content:  "\"ALTER TABLE t ADD COLUMN a int NOT NULL\" adds the NOT NULL column without DEFAULT, which fails if the table has rows",
+			Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t ADD COLUMN a int NOT NULL"},
+		},
+		{
+			Status:   advisor.Warn,
+			Code:     common.CompatibilityCreateIndexNotConcurrently,
+			Title:    "Index creation blocks writes",
+			Content:  "\"CREATE INDEX ON t (b)\" blocks the writes of table \"t\" until the index is built, use CREATE INDEX CONCURRENTLY in a separate migration instead",
+			Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 42, Snippet: "CREATE INDEX ON t (b)"},
+		},
+	}
+	if !reflect.DeepEqual(want, adviceList) {
+		t.Errorf("expected %+v, got %+v", want, adviceList)
+	}
+}
+
+func TestCompatibilityAdvisorWithCatalog(t *testing.T) {
+	adv := CompatibilityAdvisor{}
+	ctx := advisor.Context{
+		Catalog: &advisor.Catalog{
+			TableList: []*advisor.CatalogTable{
+				{
+					Name: "public.t",
+					ColumnList: []*advisor.CatalogColumn{
+						{Name: "id", Type: "integer"},
+						{Name: "name", Type: "character varying(50)", Nullable: true},
+						{Name: "code", Type: "character varying(10)"},
+						{Name: "price", Type: "numeric"},
+					},
+				},
+				{
+					Name:       "s.u",
+					ColumnList: []*advisor.CatalogColumn{{Name: "id", Type: "bigint"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		statement string
+		want      []common.Code
+	}{
+		{
+			statement: "ALTER TABLE t ALTER COLUMN id TYPE bigint, ALTER COLUMN name TYPE varchar(100), ALTER COLUMN code SET NOT NULL; ALTER TABLE public.t ALTER COLUMN code TYPE text",
+			want:      []common.Code{common.Ok},
+		},
+		{
+			statement: "ALTER TABLE t ALTER COLUMN id TYPE smallint; ALTER TABLE t ALTER COLUMN name TYPE varchar(20); ALTER TABLE t ALTER COLUMN name SET NOT NULL; ALTER TABLE t ALTER COLUMN price TYPE numeric(10, 2)",
+			want:      []common.Code{common.CompatibilityAlterColumn, common.CompatibilityAlterColumn, common.CompatibilityAlterColumn, common.CompatibilityAlterColumn},
+		},
+		{
+			statement: "ALTER TABLE u ADD COLUMN c int; ALTER TABLE s.u ADD COLUMN c int; ALTER TABLE s.u ALTER COLUMN c TYPE bigint; ALTER TABLE t DROP COLUMN c; ALTER TABLE t DROP COLUMN IF EXISTS c",
+			want:      []common.Code{common.CompatibilityTableNotFound, common.CompatibilityAlterColumn, common.CompatibilityColumnNotFound, common.CompatibilityDropColumn, common.CompatibilityDropColumn},
+		},
+		{
+			statement: "CREATE TABLE v (id int REFERENCES s.u (uid), t_id int, FOREIGN KEY (t_id) REFERENCES w (id)); CREATE INDEX CONCURRENTLY idx_v_c ON v (c); ALTER TABLE t RENAME TO w; CREATE INDEX CONCURRENTLY idx_t_id ON t (id)",
+			want:      []common.Code{common.CompatibilityColumnNotFound, common.CompatibilityTableNotFound, common.CompatibilityMixConcurrentIndex, common.CompatibilityColumnNotFound, common.CompatibilityRenameTable, common.CompatibilityMixConcurrentIndex, common.CompatibilityTableNotFound},
+		},
+	}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(ctx, tc.statement)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		var codeList []common.Code
+		for _, advice := range adviceList {
+			codeList = append(codeList, advice.Code)
+		}
+		if !reflect.DeepEqual(tc.want, codeList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, codeList)
+		}
+	}
+}
+
+func TestCompatibilityAdvisorPosition(t *testing.T) {
+	adv := CompatibilityAdvisor{}
+	adviceList, err := adv.Check(advisor.Context{}, "-- Add the unique key.\n\n  CREATE UNIQUE INDEX uk_t_a\n  ON t (a);")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	position := &advisor.Position{StatementIndex: 0, Line: 3, Column: 3, Snippet: "CREATE UNIQUE INDEX uk_t_a\n  ON t (a)"}
+	want := []struct {
+		code        common.Code
+		position    *advisor.Position
+		replacement string
+	}{
+		{common.CompatibilityCreateIndexNotConcurrently, position, "CREATE UNIQUE INDEX CONCURRENTLY uk_t_a\n  ON t (a)"},
+		{common.CompatibilityAddUniqueKey, position, ""},
+	}
+	if len(adviceList) != len(want) {
+		t.Fatalf("expected %d advices, got %+v", len(want), adviceList)
+	}
+	for i, advice := range adviceList {
+		if advice.Code != want[i].code || !reflect.DeepEqual(advice.Position, want[i].position) || advice.Replacement != want[i].replacement {
+			t.Errorf("expected %+v, got %+v", want[i], advice)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+var (
+	_ advisor.Advisor = (*NamingConventionAdvisor)(nil)
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLNamingConvention, &NamingConventionAdvisor{})
+}
+
+// NamingConventionAdvisor is the advisor checking for the naming conventions of the tables, the columns, the indexes and the foreign keys.
+type NamingConventionAdvisor struct {
+}
+
+// Check checks the names in the CREATE TABLE, ALTER TABLE, CREATE INDEX and ALTER INDEX statements against the naming convention rule.
+// The type and the columns of the renamed constraints and indexes are from the script or the catalog, and they are skipped if unknown.
+// The renamed primary key in the catalog is checked as the unique key, since the catalog doesn't tell them apart.
+func (adv *NamingConventionAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	if ctx.Rule == nil {
+		return nil, fmt.Errorf("the %s advisor requires the SQL review rule", advisor.PostgreSQLNamingConvention)
+	}
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	var objectList []*advisor.NamingObject
+	positionList := statementPositionList(statement, stmtList)
+	for i, stmt := range stmtList {
+		n := len(objectList)
+		switch node := stmt.(type) {
+		case *parser.PGCreateTableStmt:
+			table := node.Table.Name
+			objectList = append(objectList, &advisor.NamingObject{
+				Type: advisor.NamingObjectTable,
+				Name: table,
+			})
+			for _, column := range node.ColumnList {
+				objectList = append(objectList, columnNamingObjectList(table, column)...)
+			}
+			for _, constraint := range node.ConstraintList {
+				if object := constraintNamingObject(table, constraint); object != nil {
+					objectList = append(objectList, object)
+				}
+			}
+		case *parser.PGAlterTableStmt:
+			table := node.Table.Name
+			for _, action := range node.ActionList {
+				switch action := action.(type) {
+				case *parser.PGRenameTableAction:
+					objectList = append(objectList, &advisor.NamingObject{
+						Type: advisor.NamingObjectTable,
+						Name: action.NewName,
+					})
+				case *parser.PGRenameColumnAction:
+					objectList = append(objectList, &advisor.NamingObject{
+						Type:  advisor.NamingObjectColumn,
+						Name:  action.NewName,
+						Table: table,
+					})
+				case *parser.PGAddColumnAction:
+					objectList = append(objectList, columnNamingObjectList(table, action.Column)...)
+				case *parser.PGAddConstraintAction:
+					if object := constraintNamingObject(table, action.Constraint); object != nil {
+						objectList = append(objectList, object)
+					}
+				case *parser.PGRenameConstraintAction:
+					if object := advisor.RenamedNamingObject(objectList, ctx.Catalog, table, qualifiedTableName(node.Table), action.Constraint, action.NewName); object != nil {
+						objectList = append(objectList, object)
+					}
+				}
+			}
+		case *parser.PGRenameIndexStmt:
+			// The table of the index is unknown from the statement, so the index is searched in all the tables of the script first.
+			object := advisor.RenamedNamingObject(objectList, nil, "", "", node.Index.Name, node.NewName)
+			if object == nil {
+				if catalogTable := catalogIndexTable(ctx.Catalog, node.Index); catalogTable != "" {
+					table := catalogTable[strings.Index(catalogTable, ".")+1:]
+					object = advisor.RenamedNamingObject(nil, ctx.Catalog, table, catalogTable, node.Index.Name, node.NewName)
+				}
+			}
+			if object != nil {
+				objectList = append(objectList, object)
+			}
+		case *parser.PGCreateIndexStmt:
+			objectType := advisor.NamingObjectIndex
+			if node.Unique {
+				objectType = advisor.NamingObjectUniqueKey
+			}
+			objectList = append(objectList, &advisor.NamingObject{
+				Type:       objectType,
+				Name:       node.Name,
+				Table:      node.Table.Name,
+				ColumnList: node.ColumnList,
+			})
+		}
+		for _, object := range objectList[n:] {
+			object.Position = positionList[i]
+		}
+	}
+
+	adviceList, err := advisor.CheckNamingConvention(ctx.Rule, objectList)
+	if err != nil {
+		return nil, err
+	}
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// columnNamingObjectList returns the column and its named column constraints.
+func columnNamingObjectList(table string, column *parser.PGColumnDef) []*advisor.NamingObject {
+	objectList := []*advisor.NamingObject{
+		{
+			Type:  advisor.NamingObjectColumn,
+			Name:  column.Name,
+			Table: table,
+		},
+	}
+	for _, constraint := range column.ConstraintList {
+		if object := constraintNamingObject(table, constraint); object != nil {
+			objectList = append(objectList, object)
+		}
+	}
+	return objectList
+}
+
+// constraintNamingObject returns the naming object of the unique and the foreign key constraints, or nil for the other constraints.
+func constraintNamingObject(table string, constraint *parser.PGConstraint) *advisor.NamingObject {
+	switch constraint.Type {
+	case parser.PGConstraintUnique:
+		return &advisor.NamingObject{
+			Type:       advisor.NamingObjectUniqueKey,
+			Name:       constraint.Name,
+			Table:      table,
+			ColumnList: constraint.ColumnList,
+		}
+	case parser.PGConstraintForeignKey:
+		return &advisor.NamingObject{
+			Type:                 advisor.NamingObjectForeignKey,
+			Name:                 constraint.Name,
+			Table:                table,
+			ColumnList:           constraint.ColumnList,
+			ReferencedTable:      constraint.ReferencedTable.Name,
+			ReferencedColumnList: constraint.ReferencedColumnList,
+		}
+	}
+	return nil
+}
+
+// catalogIndexTable returns the table of the index in the catalog, which is qualified by the schema, or "" if the index isn't found.
+func catalogIndexTable(catalog *advisor.Catalog, index *parser.PGTableName) string {
+	if catalog == nil {
+		return ""
+	}
+	schema := index.Schema
+	if schema == "" {
+		schema = "public"
+	}
+	for _, table := range catalog.TableList {
+		if !strings.HasPrefix(table.Name, schema+".") {
+			continue
+		}
+		if t, _ := catalog.FindIndex(table.Name, index.Name); t != nil {
+			return table.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package pg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestNamingConventionAdvisor(t *testing.T) {
+	adv := NamingConventionAdvisor{}
+	catalog := &advisor.Catalog{
+		TableList: []*advisor.CatalogTable{
+			{
+				Name: "public.s",
+				IndexList: []*advisor.CatalogIndex{
+					{Name: "idx_s_b", ExpressionList: []string{"b"}},
+					{Name: "uk_s_c", Unique: true, ExpressionList: []string{"c"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		rule      *advisor.SQLReviewRule
+		catalog   *advisor.Catalog
+		statement string
+		want      []advisor.Advice
+	}{
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleTableNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^[a-z]+(_[a-z]+)*$"}`},
+			statement: "CREATE TABLE UserInfo (id int); ALTER TABLE user_info RENAME TO \"UserInfo\"",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingTableConventionMismatch,
+					Title:    "Mismatch table naming convention",
+					Content:  "Table \"UserInfo\" mismatches the naming convention, expect \"^[a-z]+(_[a-z]+)*$\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 33, Snippet: "ALTER TABLE user_info RENAME TO \"UserInfo\""},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleColumnNaming, Level: advisor.RuleLevelWarning, Payload: `{"format": "^[a-z]+(_[a-z]+)*$"}`},
+			statement: "CREATE TABLE t (\"userName\" text); ALTER TABLE t ADD COLUMN \"createdTs\" int; ALTER TABLE t RENAME COLUMN a TO b_c",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Warn,
+					Code:     common.NamingColumnConventionMismatch,
+					Title:    "Mismatch column naming convention",
+					Content:  "Column \"t\".\"userName\" mismatches the naming convention, expect \"^[a-z]+(_[a-z]+)*$\"",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "CREATE TABLE t (\"userName\" text)"},
+				},
+				{
+					Status:   advisor.Warn,
+					Code:     common.NamingColumnConventionMismatch,
+					Title:    "Mismatch column naming convention",
+					Content:  "Column \"t\".\"createdTs\" mismatches the naming convention, expect \"^[a-z]+(_[a-z]+)*$\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 35, Snippet: "ALTER TABLE t ADD COLUMN \"createdTs\" int"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleIDXNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^idx_{{table}}_{{column_list}}$"}`},
+			statement: "CREATE INDEX idx_t_a_b ON public.t (a, b); CREATE INDEX t_b_idx ON t (b); CREATE UNIQUE INDEX t_c ON t (c)",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingIndexConventionMismatch,
+					Title:    "Mismatch index naming convention",
+					Content:  "Index \"t_b_idx\" on table \"t\" mismatches the naming convention, expect \"^idx_t_b$\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 44, Snippet: "CREATE INDEX t_b_idx ON t (b)"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleUKNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^uk_{{table}}_{{column_list}}$"}`},
+			statement: "CREATE TABLE t (a int CONSTRAINT a_key UNIQUE, b int UNIQUE, CONSTRAINT uk_t_a_b UNIQUE (a, b))",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingUKConventionMismatch,
+					Title:    "Mismatch unique key naming convention",
+					Content:  "Unique key \"a_key\" on table \"t\" mismatches the naming convention, expect \"^uk_t_a$\"",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "CREATE TABLE t (a int CONSTRAINT a_key UNIQUE, b int UNIQUE, CONSTRAINT uk_t_a_b UNIQUE (a, b))"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleIDXNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^idx_{{table}}_{{column_list}}$"}`},
+			catalog:   catalog,
+			statement: "CREATE INDEX idx_t_a ON t (a); ALTER INDEX idx_t_a RENAME TO t_a_idx; ALTER INDEX public.idx_s_b RENAME TO s_b_idx",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingIndexConventionMismatch,
+					Title:    "Mismatch index naming convention",
+					Content:  "Index \"t_a_idx\" on table \"t\" mismatches the naming convention, expect \"^idx_t_a$\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 32, Snippet: "ALTER INDEX idx_t_a RENAME TO t_a_idx"},
+				},
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingIndexConventionMismatch,
+					Title:    "Mismatch index naming convention",
+					Content:  "Index \"s_b_idx\" on table \"s\" mismatches the naming convention, expect \"^idx_s_b$\"",
+					Position: &advisor.Position{StatementIndex: 2, Line: 1, Column: 71, Snippet: "ALTER INDEX public.idx_s_b RENAME TO s_b_idx"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleUKNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^uk_{{table}}_{{column_list}}$"}`},
+			catalog:   catalog,
+			statement: "ALTER TABLE t ADD CONSTRAINT uk_t_a UNIQUE (a); ALTER TABLE t RENAME CONSTRAINT uk_t_a TO t_a_key; ALTER TABLE s RENAME CONSTRAINT uk_s_c TO s_c_key; ALTER INDEX other.uk_s_c RENAME TO x",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingUKConventionMismatch,
+					Title:    "Mismatch unique key naming convention",
+					Content:  "Unique key \"t_a_key\" on table \"t\" mismatches the naming convention, expect \"^uk_t_a$\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 49, Snippet: "ALTER TABLE t RENAME CONSTRAINT uk_t_a TO t_a_key"},
+				},
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingUKConventionMismatch,
+					Title:    "Mismatch unique key naming convention",
+					Content:  "Unique key \"s_c_key\" on table \"s\" mismatches the naming convention, expect \"^uk_s_c$\"",
+					Position: &advisor.Position{StatementIndex: 2, Line: 1, Column: 100, Snippet: "ALTER TABLE s RENAME CONSTRAINT uk_s_c TO s_c_key"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleFKNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^fk_{{referencing_table}}_{{referencing_column}}_{{referenced_table}}_{{referenced_column}}$"}`},
+			statement: "ALTER TABLE t ADD CONSTRAINT t_org_fkey FOREIGN KEY (org_id) REFERENCES org (id) ON DELETE CASCADE",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.NamingFKConventionMismatch,
+					Title:    "Mismatch foreign key naming convention",
+					Content:  "Foreign key \"t_org_fkey\" on table \"t\" mismatches the naming convention, expect \"^fk_t_org_id_org_id$\"",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "ALTER TABLE t ADD CONSTRAINT t_org_fkey FOREIGN KEY (org_id) REFERENCES org (id) ON DELETE CASCADE"},
+				},
+			},
+		},
+		{
+			rule:      &advisor.SQLReviewRule{Type: advisor.RuleTableNaming, Level: advisor.RuleLevelError, Payload: `{"format": "^[a-z]+$"}`},
+			statement: "CREATE TABLE t (id int",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.DbStatementSyntaxError,
+					Title:    "Syntax error",
+					Content:  "syntax error at end of input",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 23, Snippet: "CREATE TABLE t (id int"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(advisor.Context{Rule: tc.rule, Catalog: tc.catalog}, tc.statement)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		if !reflect.DeepEqual(tc.want, adviceList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, adviceList)
+		}
+	}
+}
+
+func TestNamingConventionAdvisorWithoutRule(t *testing.T) {
+	adv := NamingConventionAdvisor{}
+	if _, err := adv.Check(advisor.Context{}, "CREATE TABLE t (id INT)"); err == nil {
+		t.Errorf("expected error without the rule")
+	}
+}
@@ -0,0 +1,39 @@
+package pg
+
+import (
+	"errors"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+// parseStatement parses the statement for the advisors.
+// It returns the syntax error advice instead if the statement fails to parse.
+func parseStatement(statement string) ([]parser.PGStmt, []advisor.Advice) {
+	stmtList, err := parser.ParsePGStatements(statement)
+	if err != nil {
+		advice := advisor.Advice{
+			Status:  advisor.Error,
+			Code:    common.DbStatementSyntaxError,
+			Title:   "Syntax error",
+			Content: err.Error(),
+		}
+		var syntaxErr *parser.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			advice.Position = advisor.LocateSyntaxError(db.Postgres, statement, syntaxErr.Offset)
+		}
+		return nil, []advisor.Advice{advice}
+	}
+	return stmtList, nil
+}
+
+// statementPositionList returns the positions of the parsed statements in the script.
+func statementPositionList(statement string, stmtList []parser.PGStmt) []*advisor.Position {
+	var textList []string
+	for _, stmt := range stmtList {
+		textList = append(textList, stmt.Text())
+	}
+	return advisor.LocateStatementList(statement, textList)
+}
@@ -0,0 +1,140 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+var (
+	_ advisor.Advisor = (*StatementAdvisor)(nil)
+
+	// statementKeywords is the leading keywords of the Postgres statements.
+	statementKeywords = map[string]bool{
+		"ABORT":      true,
+		"ALTER":      true,
+		"ANALYZE":    true,
+		"BEGIN":      true,
+		"CALL":       true,
+		"CHECKPOINT": true,
+		"CLOSE":      true,
+		"CLUSTER":    true,
+		"COMMENT":    true,
+		"COMMIT":     true,
+		"COPY":       true,
+		"CREATE":     true,
+		"DEALLOCATE": true,
+		"DECLARE":    true,
+		"DELETE":     true,
+		"DISCARD":    true,
+		"DO":         true,
+		"DROP":       true,
+		"END":        true,
+		"EXECUTE":    true,
+		"EXPLAIN":    true,
+		"FETCH":      true,
+		"GRANT":      true,
+		"IMPORT":     true,
+		"INSERT":     true,
+		"LISTEN":     true,
+		"LOAD":       true,
+		"LOCK":       true,
+		"MERGE":      true,
+		"MOVE":       true,
+		"NOTIFY":     true,
+		"PREPARE":    true,
+		"REASSIGN":   true,
+		"REFRESH":    true,
+		"REINDEX":    true,
+		"RELEASE":    true,
+		"RESET":      true,
+		"REVOKE":     true,
+		"ROLLBACK":   true,
+		"SAVEPOINT":  true,
+		"SECURITY":   true,
+		"SELECT":     true,
+		"SET":        true,
+		"SHOW":       true,
+		"START":      true,
+		"TABLE":      true,
+		"TRUNCATE":   true,
+		"UNLISTEN":   true,
+		"UPDATE":     true,
+		"VACUUM":     true,
+		"VALUES":     true,
+		"WITH":       true,
+	}
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLStatement, &StatementAdvisor{})
+}
+
+// StatementAdvisor is the advisor checking the statements are known to Postgres.
+// It isn't a full syntax check, the parser only validates the recognized DDL statements, see parser.ParsePGStatements.
+type StatementAdvisor struct {
+}
+
+// Check parses the given statement and checks the statements are known.
+// It returns the error for the unknown leading keyword, and the warning for the statement not verified by the parser.
+func (adv *StatementAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	var adviceList []advisor.Advice
+	positionList := statementPositionList(statement, stmtList)
+	for i, stmt := range stmtList {
+		if _, ok := stmt.(*parser.PGUnknownStmt); !ok {
+			continue
+		}
+		if isKnownStatement(stmt.Text()) {
+			adviceList = append(adviceList, advisor.Advice{
+				Status:   advisor.Warn,
+				Code:     common.DbStatementNotVerified,
+				Title:    "Statement not verified",
+				Content:  fmt.Sprintf("The syntax of %q isn't verified, it's only checked by the execution", stmt.Text()),
+				Position: positionList[i],
+			})
+			continue
+		}
+		adviceList = append(adviceList, advisor.Advice{
+			Status:   advisor.Error,
+			Code:     common.DbStatementSyntaxError,
+			Title:    "Syntax error",
+			Content:  fmt.Sprintf("%q doesn't start with a known Postgres statement keyword", stmt.Text()),
+			Position: positionList[i],
+		})
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "OK",
+		})
+	}
+	return adviceList, nil
+}
+
+// isKnownStatement returns whether the statement starts with a Postgres statement keyword, the parenthesized query,
+// or the \connect meta-command supported by the execution.
+func isKnownStatement(text string) bool {
+	if strings.HasPrefix(text, "(") || strings.HasPrefix(text, `\connect `) {
+		return true
+	}
+	end := strings.IndexFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if end < 0 {
+		end = len(text)
+	}
+	return statementKeywords[strings.ToUpper(text[:end])]
+}
@@ -0,0 +1,84 @@
+package pg
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestStatementAdvisor(t *testing.T) {
+	adv := StatementAdvisor{}
+
+	tests := []struct {
+		statement string
+		want      []advisor.Advice
+	}{
+		{
+			statement: "CREATE TABLE t (id int); ALTER INDEX idx_a RENAME TO idx_b",
+			want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    common.Ok,
+					Title:   "OK",
+					Content: "OK",
+				},
+			},
+		},
+		{
+			statement: "INSERT INTO t VALUES (1); SELEC 1",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Warn,
+					Code:     common.DbStatementNotVerified,
+					Title:    "Statement not verified",
+					Content:  "The syntax of \"INSERT INTO t VALUES (1)\" isn't verified, it's only checked by the execution",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 1, Snippet: "INSERT INTO t VALUES (1)"},
+				},
+				{
+					Status:   advisor.Error,
+					Code:     common.DbStatementSyntaxError,
+					Title:    "Syntax error",
+					Content:  "\"SELEC 1\" doesn't start with a known Postgres statement keyword",
+					Position: &advisor.Position{StatementIndex: 1, Line: 1, Column: 27, Snippet: "SELEC 1"},
+				},
+			},
+		},
+		{
+			statement: "CREATE TABLE t (id int",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.DbStatementSyntaxError,
+					Title:    "Syntax error",
+					Content:  "syntax error at end of input",
+					Position: &advisor.Position{StatementIndex: 0, Line: 1, Column: 23, Snippet: "CREATE TABLE t (id int"},
+				},
+			},
+		},
+		{
+			statement: "SELECT 1;\nCREATE TABLE t (id int,)",
+			want: []advisor.Advice{
+				{
+					Status:   advisor.Error,
+					Code:     common.DbStatementSyntaxError,
+					Title:    "Syntax error",
+					Content:  "syntax error at or near \")\"",
+					Position: &advisor.Position{StatementIndex: 1, Line: 2, Column: 24, Snippet: "CREATE TABLE t (id int,)"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(advisor.Context{}, tc.statement)
+		if err != nil {
+			t.Errorf("statement=%s: expected no error, got %v", tc.statement, err)
+			continue
+		}
+		if !reflect.DeepEqual(tc.want, adviceList) {
+			t.Errorf("statement=%s: expected %+v, got %+v", tc.statement, tc.want, adviceList)
+		}
+	}
+}
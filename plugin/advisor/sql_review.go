@@ -0,0 +1,220 @@
+package advisor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+)
+
+// SQLReviewRuleLevel is the error level of a SQL review rule.
+type SQLReviewRuleLevel string
+
+// SQLReviewRuleType is the type of a SQL review rule.
+type SQLReviewRuleType string
+
+const (
+	// RuleLevelError is the error level of the SQL review rule, the violation blocks the task.
+	RuleLevelError SQLReviewRuleLevel = "ERROR"
+	// RuleLevelWarning is the warning level of the SQL review rule.
+	RuleLevelWarning SQLReviewRuleLevel = "WARNING"
+	// RuleLevelDisabled is the level of the disabled SQL review rule.
+	RuleLevelDisabled SQLReviewRuleLevel = "DISABLED"
+
+	// RuleStatementNoSelectAll disallows "SELECT *".
+	RuleStatementNoSelectAll SQLReviewRuleType = "statement.select.no-select-all"
+	// RuleStatementRequireWhere requires the WHERE clause for UPDATE and DELETE.
+	RuleStatementRequireWhere SQLReviewRuleType = "statement.where.require"
+	// RuleStatementDisallowDrop disallows dropping the databases and the tables, which is usually enabled for the production environment.
+	RuleStatementDisallowDrop SQLReviewRuleType = "statement.drop.disallow"
+	// RuleTableRequirePK requires the primary key for the created tables.
+	RuleTableRequirePK SQLReviewRuleType = "table.require-pk"
+	// RuleIndexMaxCount limits the index count of the created tables, its payload is NumberTypeRulePayload.
+	RuleIndexMaxCount SQLReviewRuleType = "index.max-count"
+	// RuleTableNaming enforces the table naming convention, its payload is NamingRulePayload.
+	RuleTableNaming SQLReviewRuleType = "naming.table"
+	// RuleColumnNaming enforces the column naming convention, its payload is NamingRulePayload.
+	RuleColumnNaming SQLReviewRuleType = "naming.column"
+	// RuleIDXNaming enforces the index naming convention, its payload is NamingRulePayload.
+	RuleIDXNaming SQLReviewRuleType = "naming.index.idx"
+	// RuleUKNaming enforces the unique key naming convention, its payload is NamingRulePayload.
+	RuleUKNaming SQLReviewRuleType = "naming.index.uk"
+	// RuleFKNaming enforces the foreign key naming convention, its payload is NamingRulePayload.
+	RuleFKNaming SQLReviewRuleType = "naming.index.fk"
+)
+
+// sqlReviewRuleAdvisorMap is the catalog of the SQL review rules, which maps the rule to its advisor for each engine.
+// The rule is skipped for the engines without the advisor.
+var sqlReviewRuleAdvisorMap = map[SQLReviewRuleType]map[db.Type]Type{
+	RuleStatementNoSelectAll: {
+		db.MySQL: MySQLNoSelectAll,
+		db.TiDB:  MySQLNoSelectAll,
+	},
+	RuleStatementRequireWhere: {
+		db.MySQL: MySQLWhereRequirement,
+		db.TiDB:  MySQLWhereRequirement,
+	},
+	RuleStatementDisallowDrop: {
+		db.MySQL: MySQLDisallowDrop,
+		db.TiDB:  MySQLDisallowDrop,
+	},
+	RuleTableRequirePK: {
+		db.MySQL: MySQLTableRequirePK,
+		db.TiDB:  MySQLTableRequirePK,
+	},
+	RuleIndexMaxCount: {
+		db.MySQL: MySQLIndexMaxCount,
+		db.TiDB:  MySQLIndexMaxCount,
+	},
+	RuleTableNaming: {
+		db.MySQL:    MySQLNamingConvention,
+		db.TiDB:     MySQLNamingConvention,
+		db.Postgres: PostgreSQLNamingConvention,
+	},
+	RuleColumnNaming: {
+		db.MySQL:    MySQLNamingConvention,
+		db.TiDB:     MySQLNamingConvention,
+		db.Postgres: PostgreSQLNamingConvention,
+	},
+	RuleIDXNaming: {
+		db.MySQL:    MySQLNamingConvention,
+		db.TiDB:     MySQLNamingConvention,
+		db.Postgres: PostgreSQLNamingConvention,
+	},
+	RuleUKNaming: {
+		db.MySQL:    MySQLNamingConvention,
+		db.TiDB:     MySQLNamingConvention,
+		db.Postgres: PostgreSQLNamingConvention,
+	},
+	RuleFKNaming: {
+		db.MySQL:    MySQLNamingConvention,
+		db.TiDB:     MySQLNamingConvention,
+		db.Postgres: PostgreSQLNamingConvention,
+	},
+}
+
+// SQLReviewRule is the SQL review rule configured in the SQL review policy.
+type SQLReviewRule struct {
+	Type  SQLReviewRuleType  `json:"type"`
+	Level SQLReviewRuleLevel `json:"level"`
+	// Payload is the JSON configuration of the rule, the format depends on the rule type.
+	Payload string `json:"payload"`
+}
+
+// NumberTypeRulePayload is the payload of the rules with a number limit, e.g. RuleIndexMaxCount.
+type NumberTypeRulePayload struct {
+	Number int `json:"number"`
+}
+
+// UnmarshalNumberTypeRulePayload will unmarshal the payload of the rule with a number limit.
+func UnmarshalNumberTypeRulePayload(payload string) (*NumberTypeRulePayload, error) {
+	var nr NumberTypeRulePayload
+	if err := json.Unmarshal([]byte(payload), &nr); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal number type rule payload %q: %q", payload, err)
+	}
+	return &nr, nil
+}
+
+// Validate validates the rule type, the level and the payload.
+func (rule *SQLReviewRule) Validate() error {
+	if _, ok := sqlReviewRuleAdvisorMap[rule.Type]; !ok {
+		return fmt.Errorf("invalid SQL review rule type: %q", rule.Type)
+	}
+	if rule.Level != RuleLevelError && rule.Level != RuleLevelWarning && rule.Level != RuleLevelDisabled {
+		return fmt.Errorf("invalid SQL review rule level: %q", rule.Level)
+	}
+	if rule.Type == RuleIndexMaxCount {
+		payload, err := UnmarshalNumberTypeRulePayload(rule.Payload)
+		if err != nil {
+			return err
+		}
+		if payload.Number <= 0 {
+			return fmt.Errorf("invalid SQL review rule %q, the number must be positive: %q", rule.Type, rule.Payload)
+		}
+	}
+	if _, ok := namingRuleObjectTypeMap[rule.Type]; ok {
+		return validateNamingRule(rule)
+	}
+	return nil
+}
+
+// NewStatusBySQLReviewRuleLevel returns the advice status of violating the rule of the level.
+func NewStatusBySQLReviewRuleLevel(level SQLReviewRuleLevel) (Status, error) {
+	switch level {
+	case RuleLevelError:
+		return Error, nil
+	case RuleLevelWarning:
+		return Warn, nil
+	}
+	return "", fmt.Errorf("unexpected SQL review rule level: %q", level)
+}
+
+// IsSQLReviewSupported returns whether any enabled rule of the list has the advisor for the engine.
+func IsSQLReviewSupported(dbType db.Type, ruleList []*SQLReviewRule) bool {
+	for _, rule := range ruleList {
+		if rule.Level == RuleLevelDisabled {
+			continue
+		}
+		if _, ok := sqlReviewRuleAdvisorMap[rule.Type][dbType]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SQLReviewCheckContext is the context for the SQL review check.
+type SQLReviewCheckContext struct {
+	Logger    *zap.Logger
+	DbType    db.Type
+	Charset   string
+	Collation string
+	// Catalog is the synced schema of the database which the statement runs against, it's nil if the database is unknown.
+	Catalog *Catalog
+}
+
+// SQLReviewCheck runs the advisors of the enabled rules against the statement, the advices of the rules are in the order of the rule list.
+// It returns a success advice if the statement violates no rule.
+func SQLReviewCheck(statement string, ruleList []*SQLReviewRule, checkContext SQLReviewCheckContext) ([]Advice, error) {
+	var adviceList []Advice
+	for _, rule := range ruleList {
+		if rule.Level == RuleLevelDisabled {
+			continue
+		}
+		advType, ok := sqlReviewRuleAdvisorMap[rule.Type][checkContext.DbType]
+		if !ok {
+			continue
+		}
+
+		list, err := Check(checkContext.DbType, advType, Context{
+			Logger:    checkContext.Logger,
+			Charset:   checkContext.Charset,
+			Collation: checkContext.Collation,
+			Rule:      rule,
+			Catalog:   checkContext.Catalog,
+		}, statement)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check statement by rule %q: %w", rule.Type, err)
+		}
+		for _, advice := range list {
+			// All the rules report the same syntax error, so there is no need to check the other rules.
+			if advice.Code == common.DbStatementSyntaxError {
+				return []Advice{advice}, nil
+			}
+			if advice.Status != Success {
+				adviceList = append(adviceList, advice)
+			}
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, Advice{
+			Status:  Success,
+			Code:    common.Ok,
+			Title:   "OK",
+			Content: "The statement passes the SQL review rules",
+		})
+	}
+	return adviceList, nil
+}
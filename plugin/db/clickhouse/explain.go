@@ -0,0 +1,54 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// Explain explains the query plan of a readonly SQL statement.
+// ClickHouse doesn't estimate the rows and the cost in EXPLAIN.
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	_, rowList, err := util.QueryExplain(ctx, driver.l, driver.db, "EXPLAIN "+statement, nil /* canceler */)
+	if err != nil {
+		return nil, err
+	}
+	var lineList []string
+	for _, row := range rowList {
+		if len(row) != 1 {
+			return nil, fmt.Errorf("expect one column in the EXPLAIN output, got %d", len(row))
+		}
+		lineList = append(lineList, fmt.Sprint(row[0]))
+	}
+	return convertPlan(lineList)
+}
+
+// convertPlan converts the EXPLAIN output, whose lines are the plan steps indented by two spaces per level,
+// e.g. "  ReadFromMergeTree (default.t)".
+func convertPlan(lineList []string) (*db.QueryPlan, error) {
+	var nodeList []*db.QueryPlanNode
+	var depthList []int
+	for _, line := range lineList {
+		step := strings.TrimLeft(line, " ")
+		node := &db.QueryPlanNode{Operation: step}
+		// The step description is enclosed in the parentheses following the step name.
+		if i := strings.Index(step, " ("); i >= 0 && strings.HasSuffix(step, ")") {
+			node.Operation = step[:i]
+			node.Detail = step[i+2 : len(step)-1]
+		}
+		nodeList = append(nodeList, node)
+		depthList = append(depthList, (len(line)-len(step))/2)
+	}
+
+	root, err := util.BuildPlanTree(nodeList, depthList)
+	if err != nil {
+		return nil, err
+	}
+	return &db.QueryPlan{
+		Root: root,
+		Raw:  strings.Join(lineList, "\n"),
+	}, nil
+}
@@ -0,0 +1,35 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+)
+
+func TestConvertPlan(t *testing.T) {
+	lineList := []string{
+		"Expression ((Projection + Before ORDER BY))",
+		"  SettingQuotaAndLimits (Set limits and quota after reading from storage)",
+		"    ReadFromMergeTree",
+	}
+	want := &db.QueryPlanNode{
+		Operation: "Expression",
+		Detail:    "(Projection + Before ORDER BY)",
+		Children: []*db.QueryPlanNode{
+			{
+				Operation: "SettingQuotaAndLimits",
+				Detail:    "Set limits and quota after reading from storage",
+				Children:  []*db.QueryPlanNode{{Operation: "ReadFromMergeTree"}},
+			},
+		},
+	}
+
+	plan, err := convertPlan(lineList)
+	if err != nil {
+		t.Fatalf("convertPlan() unexpected error: %v", err)
+	}
+	if diff := pretty.Diff(plan.Root, want); len(diff) > 0 {
+		t.Errorf("got %+v, want %+v, diff %+v.", plan.Root, want, diff)
+	}
+}
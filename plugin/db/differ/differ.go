@@ -0,0 +1,300 @@
+// Package differ computes the DDL statements migrating one database schema to another.
+package differ
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// index is an index grouped from the per-expression db.Index rows.
+type index struct {
+	name        string
+	expressions []string
+	// Type is the index method, e.g. BTREE, HASH, FULLTEXT.
+	typ     string
+	unique  bool
+	primary bool
+	comment string
+}
+
+// dialect generates the engine specific DDL statements.
+// The returned statements don't include the trailing delimiter.
+type dialect interface {
+	isPrimaryKey(idx *db.Index) bool
+	hasDefault(column *db.Column) bool
+
+	createTable(table *db.Table, indexList []*index) []string
+	dropTable(table *db.Table) string
+	alterTableComment(table *db.Table) string
+
+	// addColumn adds the column after the column named after, or as the first column if after is empty.
+	addColumn(table *db.Table, column *db.Column, after string) []string
+	modifyColumn(table *db.Table, oldColumn, newColumn *db.Column) []string
+	dropColumn(table *db.Table, column *db.Column) string
+
+	createIndex(table *db.Table, idx *index) string
+	dropIndex(table *db.Table, idx *index) string
+
+	createView(view *db.View) []string
+	dropView(view *db.View) string
+}
+
+func newDialect(dbType db.Type) (dialect, error) {
+	switch dbType {
+	case db.MySQL, db.TiDB:
+		return &mysqlDialect{}, nil
+	case db.Postgres:
+		return &pgDialect{}, nil
+	}
+	return nil, fmt.Errorf("schema diff isn't supported for %s", dbType)
+}
+
+// SchemaDiff returns the DDL statements which migrate the old schema to the new schema.
+// Statements are ordered so that they can be executed as is: removed or changed views and indexes are dropped first,
+// then tables are dropped, created and altered, and new or changed indexes and views are created last.
+// Each statement is terminated with a semicolon and a newline. It returns an empty string if the schemas are identical.
+//
+// The diff is limited by the metadata available in db.Schema, e.g. AUTO_INCREMENT and foreign keys aren't migrated.
+// Renames are emitted as drop then create.
+func SchemaDiff(dbType db.Type, oldSchema, newSchema *db.Schema) (string, error) {
+	d, err := newDialect(dbType)
+	if err != nil {
+		return "", err
+	}
+
+	var dropViewList, dropIndexList, dropTableList, createTableList, alterTableList, createIndexList, createViewList []string
+
+	// Views
+	oldViewMap := make(map[string]*db.View)
+	for i := range oldSchema.ViewList {
+		oldViewMap[oldSchema.ViewList[i].Name] = &oldSchema.ViewList[i]
+	}
+	newViewMap := make(map[string]*db.View)
+	for i := range newSchema.ViewList {
+		newViewMap[newSchema.ViewList[i].Name] = &newSchema.ViewList[i]
+	}
+	for i := range oldSchema.ViewList {
+		oldView := &oldSchema.ViewList[i]
+		if newView, ok := newViewMap[oldView.Name]; !ok || !viewEqual(oldView, newView) {
+			dropViewList = append(dropViewList, d.dropView(oldView))
+		}
+	}
+	for i := range newSchema.ViewList {
+		newView := &newSchema.ViewList[i]
+		if oldView, ok := oldViewMap[newView.Name]; !ok || !viewEqual(oldView, newView) {
+			createViewList = append(createViewList, d.createView(newView)...)
+		}
+	}
+
+	// Tables
+	oldTableMap := make(map[string]*db.Table)
+	for i := range oldSchema.TableList {
+		oldTableMap[oldSchema.TableList[i].Name] = &oldSchema.TableList[i]
+	}
+	newTableMap := make(map[string]*db.Table)
+	for i := range newSchema.TableList {
+		newTableMap[newSchema.TableList[i].Name] = &newSchema.TableList[i]
+	}
+	for i := range oldSchema.TableList {
+		oldTable := &oldSchema.TableList[i]
+		if _, ok := newTableMap[oldTable.Name]; !ok {
+			dropTableList = append(dropTableList, d.dropTable(oldTable))
+		}
+	}
+	for i := range newSchema.TableList {
+		newTable := &newSchema.TableList[i]
+		oldTable, ok := oldTableMap[newTable.Name]
+		if !ok {
+			createTableList = append(createTableList, d.createTable(newTable, groupIndexList(d, newTable.IndexList))...)
+			continue
+		}
+
+		// Indexes
+		oldIndexList := groupIndexList(d, oldTable.IndexList)
+		newIndexList := groupIndexList(d, newTable.IndexList)
+		oldIndexMap := make(map[string]*index)
+		for _, idx := range oldIndexList {
+			oldIndexMap[idx.name] = idx
+		}
+		newIndexMap := make(map[string]*index)
+		for _, idx := range newIndexList {
+			newIndexMap[idx.name] = idx
+		}
+		for _, oldIndex := range oldIndexList {
+			if newIndex, ok := newIndexMap[oldIndex.name]; !ok || !indexEqual(oldIndex, newIndex) {
+				dropIndexList = append(dropIndexList, d.dropIndex(oldTable, oldIndex))
+			}
+		}
+		for _, newIndex := range newIndexList {
+			if oldIndex, ok := oldIndexMap[newIndex.name]; !ok || !indexEqual(oldIndex, newIndex) {
+				createIndexList = append(createIndexList, d.createIndex(newTable, newIndex))
+			}
+		}
+
+		// Columns
+		oldColumnMap := make(map[string]*db.Column)
+		for i := range oldTable.ColumnList {
+			oldColumnMap[oldTable.ColumnList[i].Name] = &oldTable.ColumnList[i]
+		}
+		newColumnMap := make(map[string]*db.Column)
+		for i := range newTable.ColumnList {
+			newColumnMap[newTable.ColumnList[i].Name] = &newTable.ColumnList[i]
+		}
+		after := ""
+		for _, newColumn := range sortColumnList(newTable.ColumnList) {
+			oldColumn, ok := oldColumnMap[newColumn.Name]
+			if !ok {
+				alterTableList = append(alterTableList, d.addColumn(newTable, newColumn, after)...)
+			} else if !columnEqual(d, oldColumn, newColumn) {
+				alterTableList = append(alterTableList, d.modifyColumn(newTable, oldColumn, newColumn)...)
+			}
+			after = newColumn.Name
+		}
+		for _, oldColumn := range sortColumnList(oldTable.ColumnList) {
+			if _, ok := newColumnMap[oldColumn.Name]; !ok {
+				alterTableList = append(alterTableList, d.dropColumn(oldTable, oldColumn))
+			}
+		}
+
+		if oldTable.Comment != newTable.Comment {
+			alterTableList = append(alterTableList, d.alterTableComment(newTable))
+		}
+	}
+
+	var buf strings.Builder
+	for _, list := range [][]string{dropViewList, dropIndexList, dropTableList, createTableList, alterTableList, createIndexList, createViewList} {
+		for _, stmt := range list {
+			if _, err := buf.WriteString(stmt + ";\n"); err != nil {
+				return "", err
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
+// ParseSchema builds the schema from the schema dump or the schema file in the SQL dialect of the database type.
+// The result only includes tables, columns, indexes and views, and is meant to be used with SchemaDiff.
+// Both sides of a diff should come from ParseSchema, because the parsed metadata is normalized differently from
+// what the driver syncs, e.g. view definitions.
+func ParseSchema(dbType db.Type, statement string) (*db.Schema, error) {
+	switch dbType {
+	case db.MySQL, db.TiDB:
+		return parseMySQLSchema(statement)
+	}
+	return nil, fmt.Errorf("schema parsing isn't supported for %s", dbType)
+}
+
+// IsParseSchemaSupported returns whether ParseSchema supports the database type.
+func IsParseSchemaSupported(dbType db.Type) bool {
+	switch dbType {
+	case db.MySQL, db.TiDB:
+		return true
+	}
+	return false
+}
+
+// indexExpression is the expression of the index at the position.
+type indexExpression struct {
+	position   int
+	expression string
+}
+
+// groupIndexList groups the index expressions by index name in the order of the first occurrence.
+// The expressions of each index are sorted by the position.
+func groupIndexList(d dialect, indexList []db.Index) []*index {
+	var list []*index
+	indexMap := make(map[string]*index)
+	expressionMap := make(map[string][]indexExpression)
+	for i := range indexList {
+		dbIndex := &indexList[i]
+		idx, ok := indexMap[dbIndex.Name]
+		if !ok {
+			idx = &index{
+				name:    dbIndex.Name,
+				typ:     dbIndex.Type,
+				unique:  dbIndex.Unique,
+				primary: d.isPrimaryKey(dbIndex),
+				comment: dbIndex.Comment,
+			}
+			indexMap[dbIndex.Name] = idx
+			list = append(list, idx)
+		}
+		expressionMap[dbIndex.Name] = append(expressionMap[dbIndex.Name], indexExpression{
+			position:   dbIndex.Position,
+			expression: dbIndex.Expression,
+		})
+	}
+	for _, idx := range list {
+		expressionList := expressionMap[idx.name]
+		sort.SliceStable(expressionList, func(i, j int) bool {
+			return expressionList[i].position < expressionList[j].position
+		})
+		for _, e := range expressionList {
+			idx.expressions = append(idx.expressions, e.expression)
+		}
+	}
+	return list
+}
+
+// sortColumnList returns the columns sorted by the position.
+func sortColumnList(columnList []db.Column) []*db.Column {
+	var list []*db.Column
+	for i := range columnList {
+		list = append(list, &columnList[i])
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Position < list[j].Position
+	})
+	return list
+}
+
+func indexEqual(a, b *index) bool {
+	if a.unique != b.unique || a.primary != b.primary || a.comment != b.comment {
+		return false
+	}
+	// The index method may be omitted in the schema definition.
+	if a.typ != "" && b.typ != "" && !strings.EqualFold(a.typ, b.typ) {
+		return false
+	}
+	if len(a.expressions) != len(b.expressions) {
+		return false
+	}
+	for i := range a.expressions {
+		if a.expressions[i] != b.expressions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func columnEqual(d dialect, a, b *db.Column) bool {
+	if !strings.EqualFold(a.Type, b.Type) || a.Nullable != b.Nullable || a.Comment != b.Comment {
+		return false
+	}
+	// The character set and collation may be inherited from the table, so we only compare them if both are specified.
+	if a.CharacterSet != "" && b.CharacterSet != "" && !strings.EqualFold(a.CharacterSet, b.CharacterSet) {
+		return false
+	}
+	if a.Collation != "" && b.Collation != "" && !strings.EqualFold(a.Collation, b.Collation) {
+		return false
+	}
+	if d.hasDefault(a) != d.hasDefault(b) {
+		return false
+	}
+	if d.hasDefault(a) && *a.Default != *b.Default {
+		return false
+	}
+	return true
+}
+
+func viewEqual(a, b *db.View) bool {
+	return normalizeViewDefinition(a.Definition) == normalizeViewDefinition(b.Definition) && a.Comment == b.Comment
+}
+
+// normalizeViewDefinition collapses the whitespaces and removes the trailing semicolon.
+func normalizeViewDefinition(definition string) string {
+	return strings.TrimSuffix(strings.Join(strings.Fields(definition), " "), ";")
+}
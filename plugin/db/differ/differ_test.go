@@ -0,0 +1,189 @@
+package differ
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+func TestMySQLSchemaDiff(t *testing.T) {
+	tests := []struct {
+		oldSchema string
+		newSchema string
+		want      string
+	}{
+		{
+			oldSchema: "CREATE TABLE t (id INT NOT NULL, PRIMARY KEY (id));",
+			newSchema: "CREATE TABLE t (id INT NOT NULL, PRIMARY KEY (id));",
+			want:      "",
+		},
+		{
+			oldSchema: "",
+			newSchema: "CREATE TABLE t (id INT NOT NULL, name VARCHAR(255) DEFAULT 'x' COMMENT 'the name', PRIMARY KEY (id), KEY idx_name (name)) ENGINE=InnoDB;",
+			want: "CREATE TABLE `t` (\n" +
+				"  `id` int NOT NULL,\n" +
+				"  `name` varchar(255) DEFAULT 'x' COMMENT 'the name',\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  KEY `idx_name` (`name`)\n" +
+				") ENGINE=InnoDB;\n",
+		},
+		{
+			oldSchema: "CREATE TABLE t (id INT NOT NULL);\nCREATE TABLE t2 (id INT);",
+			newSchema: "CREATE TABLE t (id INT NOT NULL);",
+			want:      "DROP TABLE `t2`;\n",
+		},
+		{
+			oldSchema: "CREATE TABLE t (id INT NOT NULL, age INT, legacy INT, KEY idx_age (age));",
+			newSchema: "CREATE TABLE t (id INT NOT NULL, name VARCHAR(64) NOT NULL, age BIGINT, UNIQUE KEY idx_age (age));",
+			want: "DROP INDEX `idx_age` ON `t`;\n" +
+				"ALTER TABLE `t` ADD COLUMN `name` varchar(64) NOT NULL AFTER `id`;\n" +
+				"ALTER TABLE `t` MODIFY COLUMN `age` bigint;\n" +
+				"ALTER TABLE `t` DROP COLUMN `legacy`;\n" +
+				"CREATE UNIQUE INDEX `idx_age` ON `t` (`age`);\n",
+		},
+		{
+			oldSchema: "CREATE TABLE t (id INT NOT NULL, created_ts TIMESTAMP DEFAULT CURRENT_TIMESTAMP);",
+			newSchema: "CREATE TABLE t (id INT NOT NULL, created_ts TIMESTAMP DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (id)) COMMENT 'table';",
+			want: "ALTER TABLE `t` COMMENT = 'table';\n" +
+				"ALTER TABLE `t` ADD PRIMARY KEY (`id`);\n",
+		},
+		{
+			oldSchema: "CREATE TABLE t (id INT NOT NULL);\nCREATE VIEW v AS SELECT id FROM t;",
+			newSchema: "CREATE TABLE t (id INT NOT NULL, amount INT DEFAULT 0);\nCREATE VIEW v AS SELECT id, amount FROM t;",
+			want: "DROP VIEW `v`;\n" +
+				"ALTER TABLE `t` ADD COLUMN `amount` int DEFAULT 0 AFTER `id`;\n" +
+				"CREATE VIEW `v` AS SELECT `id`,`amount` FROM `t`;\n",
+		},
+	}
+
+	for _, test := range tests {
+		oldSchema, err := ParseSchema(db.MySQL, test.oldSchema)
+		if err != nil {
+			t.Fatalf("ParseSchema(%q) got error: %v", test.oldSchema, err)
+		}
+		newSchema, err := ParseSchema(db.MySQL, test.newSchema)
+		if err != nil {
+			t.Fatalf("ParseSchema(%q) got error: %v", test.newSchema, err)
+		}
+		diff, err := SchemaDiff(db.MySQL, oldSchema, newSchema)
+		if err != nil {
+			t.Fatalf("SchemaDiff(%q, %q) got error: %v", test.oldSchema, test.newSchema, err)
+		}
+		if diff != test.want {
+			t.Errorf("SchemaDiff(%q, %q) = %q, want %q", test.oldSchema, test.newSchema, diff, test.want)
+		}
+	}
+}
+
+func TestPostgresSchemaDiff(t *testing.T) {
+	emptyDefault := ""
+	serialDefault := "nextval('t_id_seq'::regclass)"
+	tests := []struct {
+		oldSchema *db.Schema
+		newSchema *db.Schema
+		want      string
+	}{
+		{
+			oldSchema: &db.Schema{},
+			newSchema: &db.Schema{
+				TableList: []db.Table{
+					{
+						Name:    "public.t",
+						Comment: "table",
+						ColumnList: []db.Column{
+							{Name: "id", Position: 1, Type: "integer", Default: &serialDefault},
+							{Name: "name", Position: 2, Type: "text", Nullable: true, Default: &emptyDefault},
+						},
+						IndexList: []db.Index{
+							{Name: "t_pkey", Expression: "id", Position: 1, Type: "btree", Unique: true},
+							{Name: "idx_name", Expression: "lower(name)", Position: 1, Type: "btree"},
+						},
+					},
+				},
+			},
+			want: "CREATE TABLE \"public\".\"t\" (\n" +
+				"  \"id\" integer NOT NULL DEFAULT nextval('t_id_seq'::regclass),\n" +
+				"  \"name\" text,\n" +
+				"  CONSTRAINT \"t_pkey\" PRIMARY KEY (id)\n" +
+				");\n" +
+				"COMMENT ON TABLE \"public\".\"t\" IS 'table';\n" +
+				"CREATE INDEX \"idx_name\" ON \"public\".\"t\" USING btree (lower(name));\n",
+		},
+		{
+			oldSchema: &db.Schema{
+				TableList: []db.Table{
+					{
+						Name: "public.t",
+						ColumnList: []db.Column{
+							{Name: "id", Position: 1, Type: "integer"},
+							{Name: "name", Position: 2, Type: "text", Nullable: true},
+						},
+						IndexList: []db.Index{
+							{Name: "idx_name", Expression: "name", Position: 1, Type: "btree"},
+						},
+					},
+				},
+				ViewList: []db.View{
+					{Name: "public.v", Definition: " SELECT t.id\n   FROM t;"},
+				},
+			},
+			newSchema: &db.Schema{
+				TableList: []db.Table{
+					{
+						Name: "public.t",
+						ColumnList: []db.Column{
+							{Name: "id", Position: 1, Type: "bigint", Comment: "the id"},
+							{Name: "name", Position: 2, Type: "text"},
+						},
+					},
+				},
+				ViewList: []db.View{
+					{Name: "public.v", Definition: "SELECT t.id FROM t;"},
+				},
+			},
+			want: "DROP INDEX \"public\".\"idx_name\";\n" +
+				"ALTER TABLE \"public\".\"t\" ALTER COLUMN \"id\" SET DATA TYPE bigint;\n" +
+				"COMMENT ON COLUMN \"public\".\"t\".\"id\" IS 'the id';\n" +
+				"ALTER TABLE \"public\".\"t\" ALTER COLUMN \"name\" SET NOT NULL;\n",
+		},
+		// The index expressions are synced out of the position order.
+		{
+			oldSchema: &db.Schema{},
+			newSchema: &db.Schema{
+				TableList: []db.Table{
+					{
+						Name: "public.t",
+						ColumnList: []db.Column{
+							{Name: "a", Position: 1, Type: "integer"},
+							{Name: "b", Position: 2, Type: "integer"},
+							{Name: "c", Position: 3, Type: "integer"},
+						},
+						IndexList: []db.Index{
+							{Name: "idx_c_a_b", Expression: "b", Position: 3, Type: "btree"},
+							{Name: "idx_c_a_b", Expression: "c", Position: 1, Type: "btree"},
+							{Name: "idx_c_a_b", Expression: "a", Position: 2, Type: "btree"},
+						},
+					},
+				},
+			},
+			want: "CREATE TABLE \"public\".\"t\" (\n" +
+				"  \"a\" integer NOT NULL,\n" +
+				"  \"b\" integer NOT NULL,\n" +
+				"  \"c\" integer NOT NULL\n" +
+				");\n" +
+				"CREATE INDEX \"idx_c_a_b\" ON \"public\".\"t\" USING btree (c, a, b);\n",
+		},
+	}
+
+	for _, test := range tests {
+		diff, err := SchemaDiff(db.Postgres, test.oldSchema, test.newSchema)
+		if err != nil {
+			t.Fatalf("SchemaDiff() got error: %v", err)
+		}
+		if diff != test.want {
+			t.Errorf("SchemaDiff() = %q, want %q", diff, test.want)
+		}
+	}
+}
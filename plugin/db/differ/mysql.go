@@ -0,0 +1,193 @@
+package differ
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+var (
+	mysqlIdentifierRegexp    = regexp.MustCompile("^[a-zA-Z0-9_$]+$")
+	mysqlNumericLiteralRegex = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// mysqlPrimaryKeyName is the name of the primary key index in MySQL.
+const mysqlPrimaryKeyName = "PRIMARY"
+
+// mysqlDialect is the dialect for MySQL and TiDB.
+type mysqlDialect struct{}
+
+func (*mysqlDialect) isPrimaryKey(idx *db.Index) bool {
+	return idx.Name == mysqlPrimaryKeyName
+}
+
+func (*mysqlDialect) hasDefault(column *db.Column) bool {
+	return column.Default != nil
+}
+
+func (d *mysqlDialect) createTable(table *db.Table, indexList []*index) []string {
+	var lines []string
+	for _, column := range sortColumnList(table.ColumnList) {
+		lines = append(lines, "  "+d.columnDefinition(column))
+	}
+	for _, idx := range indexList {
+		lines = append(lines, "  "+d.indexDefinition(idx))
+	}
+	stmt := fmt.Sprintf("CREATE TABLE %s (\n%s\n)", quoteMySQLIdentifier(table.Name), strings.Join(lines, ",\n"))
+	if table.Engine != "" {
+		stmt += fmt.Sprintf(" ENGINE=%s", table.Engine)
+	}
+	if table.Collation != "" {
+		stmt += fmt.Sprintf(" COLLATE=%s", table.Collation)
+	}
+	if table.Comment != "" {
+		stmt += fmt.Sprintf(" COMMENT=%s", quoteMySQLString(table.Comment))
+	}
+	return []string{stmt}
+}
+
+func (*mysqlDialect) dropTable(table *db.Table) string {
+	return fmt.Sprintf("DROP TABLE %s", quoteMySQLIdentifier(table.Name))
+}
+
+func (*mysqlDialect) alterTableComment(table *db.Table) string {
+	return fmt.Sprintf("ALTER TABLE %s COMMENT = %s", quoteMySQLIdentifier(table.Name), quoteMySQLString(table.Comment))
+}
+
+func (d *mysqlDialect) addColumn(table *db.Table, column *db.Column, after string) []string {
+	position := " FIRST"
+	if after != "" {
+		position = fmt.Sprintf(" AFTER %s", quoteMySQLIdentifier(after))
+	}
+	return []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s%s", quoteMySQLIdentifier(table.Name), d.columnDefinition(column), position)}
+}
+
+func (d *mysqlDialect) modifyColumn(table *db.Table, _, newColumn *db.Column) []string {
+	return []string{fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", quoteMySQLIdentifier(table.Name), d.columnDefinition(newColumn))}
+}
+
+func (*mysqlDialect) dropColumn(table *db.Table, column *db.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", quoteMySQLIdentifier(table.Name), quoteMySQLIdentifier(column.Name))
+}
+
+func (d *mysqlDialect) createIndex(table *db.Table, idx *index) string {
+	if idx.primary {
+		return fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", quoteMySQLIdentifier(table.Name), d.indexExpressions(idx))
+	}
+	keyword := "INDEX"
+	switch {
+	case idx.unique:
+		keyword = "UNIQUE INDEX"
+	case strings.EqualFold(idx.typ, "FULLTEXT"):
+		keyword = "FULLTEXT INDEX"
+	case strings.EqualFold(idx.typ, "SPATIAL"):
+		keyword = "SPATIAL INDEX"
+	}
+	stmt := fmt.Sprintf("CREATE %s %s ON %s (%s)", keyword, quoteMySQLIdentifier(idx.name), quoteMySQLIdentifier(table.Name), d.indexExpressions(idx))
+	return stmt + d.indexOption(idx)
+}
+
+func (*mysqlDialect) dropIndex(table *db.Table, idx *index) string {
+	if idx.primary {
+		return fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", quoteMySQLIdentifier(table.Name))
+	}
+	return fmt.Sprintf("DROP INDEX %s ON %s", quoteMySQLIdentifier(idx.name), quoteMySQLIdentifier(table.Name))
+}
+
+func (*mysqlDialect) createView(view *db.View) []string {
+	return []string{fmt.Sprintf("CREATE VIEW %s AS %s", quoteMySQLIdentifier(view.Name), strings.TrimSuffix(strings.TrimSpace(view.Definition), ";"))}
+}
+
+func (*mysqlDialect) dropView(view *db.View) string {
+	return fmt.Sprintf("DROP VIEW %s", quoteMySQLIdentifier(view.Name))
+}
+
+func (*mysqlDialect) columnDefinition(column *db.Column) string {
+	def := fmt.Sprintf("%s %s", quoteMySQLIdentifier(column.Name), column.Type)
+	if column.CharacterSet != "" {
+		def += fmt.Sprintf(" CHARACTER SET %s", column.CharacterSet)
+	}
+	if column.Collation != "" {
+		def += fmt.Sprintf(" COLLATE %s", column.Collation)
+	}
+	if !column.Nullable {
+		def += " NOT NULL"
+	}
+	if column.Default != nil {
+		def += fmt.Sprintf(" DEFAULT %s", formatMySQLDefault(*column.Default))
+	}
+	if column.Comment != "" {
+		def += fmt.Sprintf(" COMMENT %s", quoteMySQLString(column.Comment))
+	}
+	return def
+}
+
+func (d *mysqlDialect) indexDefinition(idx *index) string {
+	if idx.primary {
+		return fmt.Sprintf("PRIMARY KEY (%s)", d.indexExpressions(idx))
+	}
+	keyword := "KEY"
+	switch {
+	case idx.unique:
+		keyword = "UNIQUE KEY"
+	case strings.EqualFold(idx.typ, "FULLTEXT"):
+		keyword = "FULLTEXT KEY"
+	case strings.EqualFold(idx.typ, "SPATIAL"):
+		keyword = "SPATIAL KEY"
+	}
+	return fmt.Sprintf("%s %s (%s)", keyword, quoteMySQLIdentifier(idx.name), d.indexExpressions(idx)) + d.indexOption(idx)
+}
+
+func (*mysqlDialect) indexExpressions(idx *index) string {
+	var list []string
+	for _, expression := range idx.expressions {
+		// Functional key parts must be enclosed within parentheses.
+		if mysqlIdentifierRegexp.MatchString(expression) {
+			list = append(list, quoteMySQLIdentifier(expression))
+		} else {
+			list = append(list, fmt.Sprintf("(%s)", expression))
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+func (*mysqlDialect) indexOption(idx *index) string {
+	option := ""
+	if strings.EqualFold(idx.typ, "HASH") {
+		option += " USING HASH"
+	}
+	if idx.comment != "" {
+		option += fmt.Sprintf(" COMMENT %s", quoteMySQLString(idx.comment))
+	}
+	return option
+}
+
+func quoteMySQLIdentifier(name string) string {
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(name, "`", "``"))
+}
+
+func quoteMySQLString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+}
+
+// formatMySQLDefault formats the column default value from information_schema.COLUMNS.COLUMN_DEFAULT,
+// which is unquoted for both literals and expressions.
+func formatMySQLDefault(value string) string {
+	upper := strings.ToUpper(value)
+	switch {
+	case mysqlNumericLiteralRegex.MatchString(value):
+		return value
+	case upper == "NULL":
+		return value
+	case strings.HasPrefix(upper, "CURRENT_TIMESTAMP"), strings.HasPrefix(upper, "NOW("), strings.HasPrefix(upper, "LOCALTIMESTAMP"):
+		return value
+	case strings.HasPrefix(upper, "B'"), strings.HasPrefix(upper, "X'"):
+		return value
+	case strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")"):
+		return value
+	}
+	return quoteMySQLString(value)
+}
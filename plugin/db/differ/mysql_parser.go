@@ -0,0 +1,270 @@
+package differ
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	bbparser "github.com/bytebase/bytebase/plugin/parser"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+)
+
+// parseMySQLSchema builds the schema from the CREATE TABLE, CREATE INDEX and CREATE VIEW statements.
+// Routines, events and triggers are delimited by ";;" in the schema dump and are ignored.
+func parseMySQLSchema(statement string) (*db.Schema, error) {
+	p := parser.New()
+	p.EnableWindowFunc(true)
+
+	schema := &db.Schema{}
+	var stmtList []string
+	sc := bufio.NewScanner(strings.NewReader(statement))
+	sc.Buffer(make([]byte, bufio.MaxScanTokenSize), 16*1024*1024)
+	if err := bbparser.ApplyMultiStatements(db.MySQL, sc, func(stmt *bbparser.SingleSQL) error {
+		if stmt.Delimiter == ";" {
+			stmtList = append(stmtList, stmt.Text)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range stmtList {
+		nodeList, _, err := p.Parse(stmt, "", "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse statement %q: %w", stmt, err)
+		}
+		for _, node := range nodeList {
+			switch node := node.(type) {
+			case *ast.CreateTableStmt:
+				table, err := convertMySQLCreateTable(node)
+				if err != nil {
+					return nil, err
+				}
+				schema.TableList = append(schema.TableList, *table)
+			case *ast.CreateIndexStmt:
+				for i := range schema.TableList {
+					if schema.TableList[i].Name == node.Table.Name.O {
+						table := &schema.TableList[i]
+						indexList, err := convertMySQLIndexPartList(node.IndexName, node.IndexPartSpecifications, node.KeyType == ast.IndexKeyTypeUnique, mysqlIndexType(node.KeyType, node.IndexOption), node.IndexOption)
+						if err != nil {
+							return nil, err
+						}
+						table.IndexList = append(table.IndexList, indexList...)
+					}
+				}
+			case *ast.CreateViewStmt:
+				definition, err := restoreMySQLNode(node.Select, format.DefaultRestoreFlags)
+				if err != nil {
+					return nil, err
+				}
+				schema.ViewList = append(schema.ViewList, db.View{
+					Name:       node.ViewName.Name.O,
+					Definition: definition,
+				})
+			}
+		}
+	}
+	return schema, nil
+}
+
+func convertMySQLCreateTable(node *ast.CreateTableStmt) (*db.Table, error) {
+	table := &db.Table{
+		Name: node.Table.Name.O,
+		Type: "BASE TABLE",
+	}
+	for _, option := range node.Options {
+		switch option.Tp {
+		case ast.TableOptionEngine:
+			table.Engine = option.StrValue
+		case ast.TableOptionCollate:
+			table.Collation = option.StrValue
+		case ast.TableOptionComment:
+			table.Comment = option.StrValue
+		}
+	}
+
+	for i, col := range node.Cols {
+		column := db.Column{
+			Name:         col.Name.Name.O,
+			Position:     i + 1,
+			Nullable:     true,
+			CharacterSet: col.Tp.Charset,
+			Collation:    col.Tp.Collate,
+		}
+		tp := col.Tp.Clone()
+		tp.Charset = ""
+		tp.Collate = ""
+		typeString, err := restoreMySQLNode(tp, format.RestoreKeyWordLowercase|format.RestoreStringSingleQuotes)
+		if err != nil {
+			return nil, err
+		}
+		column.Type = typeString
+		for _, option := range col.Options {
+			switch option.Tp {
+			case ast.ColumnOptionPrimaryKey:
+				column.Nullable = false
+				table.IndexList = append(table.IndexList, db.Index{
+					Name:       mysqlPrimaryKeyName,
+					Expression: column.Name,
+					Position:   1,
+					Type:       "BTREE",
+					Unique:     true,
+					Visible:    true,
+				})
+			case ast.ColumnOptionUniqKey:
+				table.IndexList = append(table.IndexList, db.Index{
+					Name:       column.Name,
+					Expression: column.Name,
+					Position:   1,
+					Type:       "BTREE",
+					Unique:     true,
+					Visible:    true,
+				})
+			case ast.ColumnOptionNotNull:
+				column.Nullable = false
+			case ast.ColumnOptionNull:
+				column.Nullable = true
+			case ast.ColumnOptionDefaultValue:
+				defaultValue, err := convertMySQLDefault(option.Expr)
+				if err != nil {
+					return nil, err
+				}
+				column.Default = defaultValue
+			case ast.ColumnOptionComment:
+				if valueExpr, ok := option.Expr.(ast.ValueExpr); ok {
+					column.Comment = valueExpr.GetString()
+				}
+			case ast.ColumnOptionCollate:
+				column.Collation = option.StrValue
+			}
+		}
+		table.ColumnList = append(table.ColumnList, column)
+	}
+
+	for _, constraint := range node.Constraints {
+		unique := false
+		name := constraint.Name
+		switch constraint.Tp {
+		case ast.ConstraintPrimaryKey:
+			unique = true
+			name = mysqlPrimaryKeyName
+		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+			unique = true
+		case ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintFulltext:
+		default:
+			continue
+		}
+		indexType := mysqlIndexType(ast.IndexKeyTypeNone, constraint.Option)
+		if constraint.Tp == ast.ConstraintFulltext {
+			indexType = "FULLTEXT"
+		}
+		indexList, err := convertMySQLIndexPartList(name, constraint.Keys, unique, indexType, constraint.Option)
+		if err != nil {
+			return nil, err
+		}
+		if constraint.Tp == ast.ConstraintPrimaryKey {
+			for _, idx := range indexList {
+				for i := range table.ColumnList {
+					if table.ColumnList[i].Name == idx.Expression {
+						table.ColumnList[i].Nullable = false
+					}
+				}
+			}
+		}
+		table.IndexList = append(table.IndexList, indexList...)
+	}
+	return table, nil
+}
+
+func convertMySQLIndexPartList(name string, partList []*ast.IndexPartSpecification, unique bool, indexType string, option *ast.IndexOption) ([]db.Index, error) {
+	// MySQL names the unnamed index after its first column.
+	if name == "" && len(partList) > 0 && partList[0].Column != nil {
+		name = partList[0].Column.Name.O
+	}
+	comment := ""
+	visible := true
+	if option != nil {
+		comment = option.Comment
+		visible = option.Visibility != ast.IndexVisibilityInvisible
+	}
+	var indexList []db.Index
+	for i, part := range partList {
+		expression := ""
+		if part.Column != nil {
+			expression = part.Column.Name.O
+		} else if part.Expr != nil {
+			text, err := restoreMySQLNode(part.Expr, format.DefaultRestoreFlags)
+			if err != nil {
+				return nil, err
+			}
+			expression = text
+		}
+		indexList = append(indexList, db.Index{
+			Name:       name,
+			Expression: expression,
+			Position:   i + 1,
+			Type:       indexType,
+			Unique:     unique,
+			Visible:    visible,
+			Comment:    comment,
+		})
+	}
+	return indexList, nil
+}
+
+func mysqlIndexType(keyType ast.IndexKeyType, option *ast.IndexOption) string {
+	switch keyType {
+	case ast.IndexKeyTypeFullText:
+		return "FULLTEXT"
+	case ast.IndexKeyTypeSpatial:
+		return "SPATIAL"
+	}
+	if option != nil && option.Tp == model.IndexTypeHash {
+		return "HASH"
+	}
+	return "BTREE"
+}
+
+// convertMySQLDefault converts the default value to the unquoted form used by information_schema.COLUMNS.COLUMN_DEFAULT.
+func convertMySQLDefault(expr ast.ExprNode) (*string, error) {
+	if valueExpr, ok := expr.(ast.ValueExpr); ok {
+		if valueExpr.GetValue() == nil {
+			return nil, nil
+		}
+		value := valueExpr.GetString()
+		if value == "" {
+			// GetString() only returns the value of the string literals.
+			text, err := restoreMySQLNode(expr, format.DefaultRestoreFlags)
+			if err != nil {
+				return nil, err
+			}
+			if !strings.HasPrefix(text, "'") {
+				value = text
+			}
+		}
+		return &value, nil
+	}
+	text, err := restoreMySQLNode(expr, format.DefaultRestoreFlags)
+	if err != nil {
+		return nil, err
+	}
+	// CURRENT_TIMESTAMP is restored as CURRENT_TIMESTAMP().
+	text = strings.TrimSuffix(text, "()")
+	return &text, nil
+}
+
+type mysqlRestorer interface {
+	Restore(ctx *format.RestoreCtx) error
+}
+
+func restoreMySQLNode(node mysqlRestorer, flags format.RestoreFlags) (string, error) {
+	var buf strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(flags, &buf)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,180 @@
+package differ
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// pgDialect is the dialect for Postgres.
+// Table and view names are in the "schema.name" form returned by the Postgres driver.
+type pgDialect struct{}
+
+// isPrimaryKey reports whether the index backs the primary key constraint.
+// The Postgres driver doesn't expose the constraint type, so we rely on the default "<table>_pkey" naming.
+func (*pgDialect) isPrimaryKey(idx *db.Index) bool {
+	return idx.Unique && strings.HasSuffix(idx.Name, "_pkey")
+}
+
+// hasDefault reports whether the column has a default. The Postgres driver uses the empty string for no default,
+// and an empty string literal default is returned as a cast expression instead.
+func (*pgDialect) hasDefault(column *db.Column) bool {
+	return column.Default != nil && *column.Default != ""
+}
+
+func (d *pgDialect) createTable(table *db.Table, indexList []*index) []string {
+	var lines []string
+	for _, column := range sortColumnList(table.ColumnList) {
+		lines = append(lines, "  "+d.columnDefinition(column))
+	}
+	for _, idx := range indexList {
+		if idx.primary {
+			lines = append(lines, fmt.Sprintf("  CONSTRAINT %s PRIMARY KEY (%s)", quotePgIdentifier(idx.name), strings.Join(idx.expressions, ", ")))
+		}
+	}
+	stmtList := []string{fmt.Sprintf("CREATE TABLE %s (\n%s\n)", quotePgQualifiedName(table.Name), strings.Join(lines, ",\n"))}
+	if table.Comment != "" {
+		stmtList = append(stmtList, d.alterTableComment(table))
+	}
+	for _, column := range sortColumnList(table.ColumnList) {
+		if column.Comment != "" {
+			stmtList = append(stmtList, d.columnComment(table, column))
+		}
+	}
+	for _, idx := range indexList {
+		if !idx.primary {
+			stmtList = append(stmtList, d.createIndex(table, idx))
+		}
+	}
+	return stmtList
+}
+
+func (*pgDialect) dropTable(table *db.Table) string {
+	return fmt.Sprintf("DROP TABLE %s", quotePgQualifiedName(table.Name))
+}
+
+func (*pgDialect) alterTableComment(table *db.Table) string {
+	return fmt.Sprintf("COMMENT ON TABLE %s IS %s", quotePgQualifiedName(table.Name), quotePgComment(table.Comment))
+}
+
+func (d *pgDialect) addColumn(table *db.Table, column *db.Column, _ string) []string {
+	stmtList := []string{fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", quotePgQualifiedName(table.Name), d.columnDefinition(column))}
+	if column.Comment != "" {
+		stmtList = append(stmtList, d.columnComment(table, column))
+	}
+	return stmtList
+}
+
+func (d *pgDialect) modifyColumn(table *db.Table, oldColumn, newColumn *db.Column) []string {
+	tableName := quotePgQualifiedName(table.Name)
+	columnName := quotePgIdentifier(newColumn.Name)
+	var stmtList []string
+	if !strings.EqualFold(oldColumn.Type, newColumn.Type) || !strings.EqualFold(oldColumn.Collation, newColumn.Collation) {
+		stmt := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DATA TYPE %s", tableName, columnName, newColumn.Type)
+		if newColumn.Collation != "" {
+			stmt += fmt.Sprintf(" COLLATE %s", quotePgIdentifier(newColumn.Collation))
+		}
+		stmtList = append(stmtList, stmt)
+	}
+	if oldColumn.Nullable != newColumn.Nullable {
+		if newColumn.Nullable {
+			stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", tableName, columnName))
+		} else {
+			stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", tableName, columnName))
+		}
+	}
+	if d.hasDefault(oldColumn) != d.hasDefault(newColumn) || (d.hasDefault(newColumn) && *oldColumn.Default != *newColumn.Default) {
+		if d.hasDefault(newColumn) {
+			stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", tableName, columnName, *newColumn.Default))
+		} else {
+			stmtList = append(stmtList, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", tableName, columnName))
+		}
+	}
+	if oldColumn.Comment != newColumn.Comment {
+		stmtList = append(stmtList, d.columnComment(table, newColumn))
+	}
+	return stmtList
+}
+
+func (*pgDialect) dropColumn(table *db.Table, column *db.Column) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", quotePgQualifiedName(table.Name), quotePgIdentifier(column.Name))
+}
+
+func (*pgDialect) createIndex(table *db.Table, idx *index) string {
+	if idx.primary {
+		return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s PRIMARY KEY (%s)", quotePgQualifiedName(table.Name), quotePgIdentifier(idx.name), strings.Join(idx.expressions, ", "))
+	}
+	keyword := "INDEX"
+	if idx.unique {
+		keyword = "UNIQUE INDEX"
+	}
+	method := ""
+	if idx.typ != "" {
+		method = fmt.Sprintf(" USING %s", idx.typ)
+	}
+	// Postgres index expressions are returned by pg_get_indexdef() and already quoted if needed.
+	return fmt.Sprintf("CREATE %s %s ON %s%s (%s)", keyword, quotePgIdentifier(idx.name), quotePgQualifiedName(table.Name), method, strings.Join(idx.expressions, ", "))
+}
+
+func (*pgDialect) dropIndex(table *db.Table, idx *index) string {
+	if idx.primary {
+		return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", quotePgQualifiedName(table.Name), quotePgIdentifier(idx.name))
+	}
+	// Indexes live in the same schema as the table.
+	name := quotePgIdentifier(idx.name)
+	if i := strings.Index(table.Name, "."); i >= 0 {
+		name = fmt.Sprintf("%s.%s", quotePgIdentifier(table.Name[:i]), name)
+	}
+	return fmt.Sprintf("DROP INDEX %s", name)
+}
+
+func (*pgDialect) createView(view *db.View) []string {
+	stmtList := []string{fmt.Sprintf("CREATE VIEW %s AS %s", quotePgQualifiedName(view.Name), strings.TrimSuffix(strings.TrimSpace(view.Definition), ";"))}
+	if view.Comment != "" {
+		stmtList = append(stmtList, fmt.Sprintf("COMMENT ON VIEW %s IS %s", quotePgQualifiedName(view.Name), quotePgComment(view.Comment)))
+	}
+	return stmtList
+}
+
+func (*pgDialect) dropView(view *db.View) string {
+	return fmt.Sprintf("DROP VIEW %s", quotePgQualifiedName(view.Name))
+}
+
+func (d *pgDialect) columnDefinition(column *db.Column) string {
+	def := fmt.Sprintf("%s %s", quotePgIdentifier(column.Name), column.Type)
+	if column.Collation != "" {
+		def += fmt.Sprintf(" COLLATE %s", quotePgIdentifier(column.Collation))
+	}
+	if !column.Nullable {
+		def += " NOT NULL"
+	}
+	if d.hasDefault(column) {
+		def += fmt.Sprintf(" DEFAULT %s", *column.Default)
+	}
+	return def
+}
+
+func (*pgDialect) columnComment(table *db.Table, column *db.Column) string {
+	return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", quotePgQualifiedName(table.Name), quotePgIdentifier(column.Name), quotePgComment(column.Comment))
+}
+
+func quotePgIdentifier(name string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(name, `"`, `""`))
+}
+
+// quotePgQualifiedName quotes the "schema.name" form.
+func quotePgQualifiedName(name string) string {
+	if i := strings.Index(name, "."); i >= 0 {
+		return fmt.Sprintf("%s.%s", quotePgIdentifier(name[:i]), quotePgIdentifier(name[i+1:]))
+	}
+	return quotePgIdentifier(name)
+}
+
+// quotePgComment quotes the comment, and an empty comment removes the existing one.
+func quotePgComment(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(comment, "'", "''"))
+}
@@ -75,6 +75,8 @@ type Column struct {
 	Collation string
 	// Comment isn't supported for SQLite.
 	Comment string
+	// Extra is only supported for MySQL and TiDB, e.g. "auto_increment" and "on update CURRENT_TIMESTAMP".
+	Extra string
 }
 
 // Table is the database table.
@@ -101,22 +103,41 @@ type Table struct {
 	// Comment isn't supported for SQLite.
 	Comment    string
 	ColumnList []Column
-	// IndexList isn't supported for ClickHouse, Snowflake.
+	// IndexList is only set if the engine supports Capability.IndexSync.
 	IndexList []Index
 }
 
 // Schema is the database schema.
 type Schema struct {
 	Name string
-	// CharacterSet isn't supported for ClickHouse, Snowflake.
+	// CharacterSet is only set if the engine supports Capability.Collation.
 	CharacterSet string
-	// Collation isn't supported for ClickHouse, Snowflake.
+	// Collation is only set if the engine supports Capability.Collation.
 	Collation string
 	UserList  []User
 	TableList []Table
 	ViewList  []View
 }
 
+// Capability is the features supported by an engine, which determine the tasks, the task checks and the API fields
+// applicable to the databases of the engine.
+type Capability struct {
+	// TransactionalDDL is whether the DDL statements are rolled back with the transaction, so a failed migration leaves no partial change.
+	TransactionalDDL bool `json:"transactionalDDL"`
+	// IndexSync is whether the table indexes are synced.
+	IndexSync bool `json:"indexSync"`
+	// Collation is whether the database has the character set and the collation, which can be specified on creating the database.
+	Collation bool `json:"collation"`
+	// DumpWithData is whether the dump contains the data in addition to the schema, so the backup can restore the data.
+	DumpWithData bool `json:"dumpWithData"`
+	// Explain is whether explaining the query plan is supported.
+	Explain bool `json:"explain"`
+	// Cancel is whether the running statement is canceled on the database server once its context is canceled.
+	Cancel bool `json:"cancel"`
+	// UserGrant is whether the database users and their grants are synced.
+	UserGrant bool `json:"userGrant"`
+}
+
 var (
 	driversMu sync.RWMutex
 	drivers   = make(map[Type]driverFunc)
@@ -167,6 +188,10 @@ const (
 	// Data is the migration type for DATA.
 	// Used for DML change.
 	Data MigrationType = "DATA"
+	// Rollback is the migration type for ROLLBACK.
+	// Used for the down file of a migration, it is attached to the task of the migration with the same version
+	// instead of being applied on its own.
+	Rollback MigrationType = "ROLLBACK"
 )
 
 func (e MigrationType) String() string {
@@ -179,6 +204,8 @@ func (e MigrationType) String() string {
 		return "BRANCH"
 	case Data:
 		return "DATA"
+	case Rollback:
+		return "ROLLBACK"
 	}
 	return "UNKNOWN"
 }
@@ -229,6 +256,53 @@ type MigrationInfo struct {
 	CreateDatabase bool
 }
 
+// ParseSchemaFileInfo matches filePath against schemaPathTemplate
+// If filePath matches, then it will derive MigrationInfo for applying the declarative schema file.
+// The version is left empty since the schema file path doesn't contain a version.
+// Both filePath and schemaPathTemplate are the full file path (including the base directory) of the repository.
+func ParseSchemaFileInfo(filePath string, schemaPathTemplate string) (*MigrationInfo, error) {
+	placeholderList := []string{
+		"ENV_NAME",
+		"DB_NAME",
+	}
+	filePathRegex := schemaPathTemplate
+	for _, placeholder := range placeholderList {
+		filePathRegex = strings.ReplaceAll(filePathRegex, fmt.Sprintf("{{%s}}", placeholder), fmt.Sprintf("(?P<%s>[a-zA-Z0-9+-=/_#?!$. ]+)", placeholder))
+	}
+	myRegex, err := regexp.Compile(fmt.Sprintf("^%s$", filePathRegex))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema path template: %q", schemaPathTemplate)
+	}
+	if !myRegex.MatchString(filePath) {
+		return nil, fmt.Errorf("file path %q does not match schema path template %q", filePath, schemaPathTemplate)
+	}
+
+	mi := &MigrationInfo{
+		Engine: VCS,
+		Type:   Migrate,
+	}
+	matchList := myRegex.FindStringSubmatch(filePath)
+	for _, placeholder := range placeholderList {
+		index := myRegex.SubexpIndex(placeholder)
+		if index >= 0 {
+			switch placeholder {
+			case "ENV_NAME":
+				mi.Environment = matchList[index]
+			case "DB_NAME":
+				mi.Namespace = matchList[index]
+				mi.Database = matchList[index]
+			}
+		}
+	}
+
+	if mi.Namespace == "" {
+		return nil, fmt.Errorf("file path %q does not contain {{DB_NAME}}, configured schema path template %q", filePath, schemaPathTemplate)
+	}
+	mi.Description = fmt.Sprintf("Apply %s declarative schema change", mi.Database)
+
+	return mi, nil
+}
+
 // ParseMigrationInfo matches filePath against filePathTemplate
 // If filePath matches, then it will derive MigrationInfo from the filePath.
 // Both filePath and filePathTemplate are the full file path (including the base directory) of the repository.
@@ -276,8 +350,10 @@ func ParseMigrationInfo(filePath string, filePathTemplate string) (*MigrationInf
 					mi.Type = Data
 				case "migrate":
 					mi.Type = Migrate
+				case "rollback", "down":
+					mi.Type = Rollback
 				default:
-					return nil, fmt.Errorf("file path %q contains invalid migration type %q, must be 'baseline', 'migrate' or 'data'", filePath, matchList[index])
+					return nil, fmt.Errorf("file path %q contains invalid migration type %q, must be 'baseline', 'migrate', 'data' or 'rollback'", filePath, matchList[index])
 				}
 			case "DESCRIPTION":
 				mi.Description = matchList[index]
@@ -298,6 +374,8 @@ func ParseMigrationInfo(filePath string, filePathTemplate string) (*MigrationInf
 			mi.Description = fmt.Sprintf("Create %s baseline", mi.Database)
 		case Data:
 			mi.Description = fmt.Sprintf("Create %s data change", mi.Database)
+		case Rollback:
+			mi.Description = fmt.Sprintf("Create %s schema migration rollback", mi.Database)
 		default:
 			mi.Description = fmt.Sprintf("Create %s schema migration", mi.Database)
 		}
@@ -363,6 +441,44 @@ type ConnectionContext struct {
 	InstanceName    string
 }
 
+// QueryColumn is the metadata of a column in the query result.
+type QueryColumn struct {
+	Name string `json:"name"`
+	// Type is the database type name of the column, such as "VARCHAR" and "INT4".
+	Type string `json:"type"`
+}
+
+// QueryCursor is the cursor of a readonly query result, which returns the column metadata first, then the rows in batches.
+type QueryCursor interface {
+	// Columns returns the column metadata of the query result.
+	Columns() []*QueryColumn
+	// Next returns the next batch of at most batchSize rows, whose values are in the column order.
+	// Returns io.EOF if there are no more rows. No batch size enforced if batchSize <= 0.
+	Next(batchSize int) ([][]interface{}, error)
+	// Close releases the connection held by the cursor.
+	Close() error
+}
+
+// QueryPlanNode is a node of the query plan tree normalized from the EXPLAIN output of the engines.
+type QueryPlanNode struct {
+	// Operation is the engine specific operation of the node, such as "Seq Scan" for Postgres.
+	Operation string `json:"operation"`
+	// Detail is the human readable detail of the operation, such as the scanned table and the filter condition.
+	Detail string `json:"detail"`
+	// EstimatedRows is the number of rows estimated to be output by the node, nil if the engine doesn't estimate it.
+	EstimatedRows *float64 `json:"estimatedRows"`
+	// EstimatedCost is the cost estimated by the engine in its own unit, nil if the engine doesn't estimate it.
+	EstimatedCost *float64         `json:"estimatedCost"`
+	Children      []*QueryPlanNode `json:"children"`
+}
+
+// QueryPlan is the query plan of a readonly statement.
+type QueryPlan struct {
+	Root *QueryPlanNode `json:"root"`
+	// Raw is the original EXPLAIN output of the engine.
+	Raw string `json:"raw"`
+}
+
 // Driver is the interface for database driver.
 type Driver interface {
 	// A driver might support multiple engines (e.g. MySQL driver can support both MySQL and TiDB),
@@ -370,6 +486,8 @@ type Driver interface {
 	Open(ctx context.Context, dbType Type, config ConnectionConfig, connCtx ConnectionContext) (Driver, error)
 	// Remember to call Close to avoid connection leak
 	Close(ctx context.Context) error
+	// GetCapability returns the capability of the engine, it doesn't require opening the driver.
+	GetCapability(dbType Type) Capability
 	Ping(ctx context.Context) error
 	GetDbConnection(ctx context.Context, database string) (*sql.DB, error)
 	GetVersion(ctx context.Context) (string, error)
@@ -378,6 +496,12 @@ type Driver interface {
 	// Used for execute readonly SELECT statement
 	// limit is the maximum row count returned. No limit enforced if limit <= 0
 	Query(ctx context.Context, statement string, limit int) ([]interface{}, error)
+	// Used for streaming the result of readonly SELECT statement without holding all the rows in memory.
+	// limit is the maximum row count returned. No limit enforced if limit <= 0
+	// Remember to call Close on the returned cursor to release the connection.
+	OpenCursor(ctx context.Context, statement string, limit int) (QueryCursor, error)
+	// Used for explaining the query plan of readonly SELECT statement without executing it.
+	Explain(ctx context.Context, statement string) (*QueryPlan, error)
 
 	// Migration related
 	// Check whether we need to setup migration (e.g. creating/upgrading the migration related tables)
@@ -435,6 +559,18 @@ func Open(ctx context.Context, dbType Type, driverConfig DriverConfig, connectio
 	return driver, nil
 }
 
+// GetCapability returns the capability of the engine without connecting to the database.
+func GetCapability(dbType Type) (Capability, error) {
+	driversMu.RLock()
+	f, ok := drivers[dbType]
+	driversMu.RUnlock()
+	if !ok {
+		return Capability{}, fmt.Errorf("db: unknown driver %v", dbType)
+	}
+
+	return f(DriverConfig{}).GetCapability(dbType), nil
+}
+
 // FormatParamNameInQuestionMark formats the param name in question mark.
 // For example, it will be WHERE hello = ? AND world = ?.
 func FormatParamNameInQuestionMark(paramNames []string) string {
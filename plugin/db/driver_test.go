@@ -180,6 +180,42 @@ func TestParseMigrationInfo(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			filePath:         "db_shop1__001foo__rollback",
+			filePathTemplate: "{{DB_NAME}}__{{VERSION}}__{{TYPE}}",
+			want: MigrationInfo{
+				Version:     "001foo",
+				Namespace:   "db_shop1",
+				Database:    "db_shop1",
+				Environment: "",
+				Engine:      VCS,
+				Type:        Rollback,
+				Description: "Create db_shop1 schema migration rollback",
+				Creator:     "",
+			},
+			wantErr: "",
+		},
+		{
+			filePath:         "db_shop1__001foo__down__add_col.sql",
+			filePathTemplate: "{{DB_NAME}}__{{VERSION}}__{{TYPE}}__{{DESCRIPTION}}.sql",
+			want: MigrationInfo{
+				Version:     "001foo",
+				Namespace:   "db_shop1",
+				Database:    "db_shop1",
+				Environment: "",
+				Engine:      VCS,
+				Type:        Rollback,
+				Description: "Add col",
+				Creator:     "",
+			},
+			wantErr: "",
+		},
+		{
+			filePath:         "db_shop1__001foo__undo",
+			filePathTemplate: "{{DB_NAME}}__{{VERSION}}__{{TYPE}}",
+			want:             MigrationInfo{},
+			wantErr:          "invalid migration type",
+		},
 		{
 			filePath:         "db",
 			filePathTemplate: "{{DB_NAME}}__{{VERSION}}",
@@ -212,3 +248,63 @@ func TestParseMigrationInfo(t *testing.T) {
 
 	}
 }
+
+func TestParseSchemaFileInfo(t *testing.T) {
+	type test struct {
+		filePath           string
+		schemaPathTemplate string
+		want               MigrationInfo
+		wantErr            string
+	}
+
+	tests := []test{
+		{
+			filePath:           "bytebase/.db1__LATEST.sql",
+			schemaPathTemplate: "bytebase/.{{DB_NAME}}__LATEST.sql",
+			want: MigrationInfo{
+				Namespace:   "db1",
+				Database:    "db1",
+				Environment: "",
+				Engine:      VCS,
+				Type:        Migrate,
+				Description: "Apply db1 declarative schema change",
+			},
+			wantErr: "",
+		},
+		{
+			filePath:           "bytebase/prod/.db1__LATEST.sql",
+			schemaPathTemplate: "bytebase/{{ENV_NAME}}/.{{DB_NAME}}__LATEST.sql",
+			want: MigrationInfo{
+				Namespace:   "db1",
+				Database:    "db1",
+				Environment: "prod",
+				Engine:      VCS,
+				Type:        Migrate,
+				Description: "Apply db1 declarative schema change",
+			},
+			wantErr: "",
+		},
+		{
+			filePath:           "bytebase/db1__001__migrate.sql",
+			schemaPathTemplate: "bytebase/.{{DB_NAME}}__LATEST.sql",
+			wantErr:            "does not match schema path template",
+		},
+	}
+
+	for _, tc := range tests {
+		mi, err := ParseSchemaFileInfo(tc.filePath, tc.schemaPathTemplate)
+		if err != nil {
+			if tc.wantErr == "" {
+				t.Errorf("filePath=%s, schemaPathTemplate=%s: expected no error, got %v", tc.filePath, tc.schemaPathTemplate, err)
+			} else if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("filePath=%s, schemaPathTemplate=%s: expected error %s, got %v", tc.filePath, tc.schemaPathTemplate, tc.wantErr, err)
+			}
+		} else {
+			if tc.wantErr != "" {
+				t.Errorf("filePath=%s, schemaPathTemplate=%s: expected error %s, got nil", tc.filePath, tc.schemaPathTemplate, tc.wantErr)
+			} else if !reflect.DeepEqual(tc.want, *mi) {
+				t.Errorf("filePath=%s, schemaPathTemplate=%s: expected %+v, got %+v", tc.filePath, tc.schemaPathTemplate, tc.want, *mi)
+			}
+		}
+	}
+}
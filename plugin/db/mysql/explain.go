@@ -0,0 +1,179 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// Explain explains the query plan of a readonly SQL statement.
+// MySQL outputs the plan in JSON, while TiDB outputs the plan as the rows of an indented operator tree.
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	if driver.dbType == db.TiDB {
+		columnList, rowList, err := util.QueryExplain(ctx, driver.l, driver.db, "EXPLAIN "+statement, driver.getStatementCanceler())
+		if err != nil {
+			return nil, err
+		}
+		return convertTiDBPlan(columnList, rowList)
+	}
+
+	_, rowList, err := util.QueryExplain(ctx, driver.l, driver.db, "EXPLAIN FORMAT=JSON "+statement, driver.getStatementCanceler())
+	if err != nil {
+		return nil, err
+	}
+	if len(rowList) != 1 || len(rowList[0]) != 1 {
+		return nil, fmt.Errorf("expect one row and one column in the EXPLAIN output, got %d rows", len(rowList))
+	}
+	return convertMySQLPlan(fmt.Sprint(rowList[0][0]))
+}
+
+// mysqlPlanDetailKeyList is the list of the keys composing the detail of the plan node, in the displayed order.
+var mysqlPlanDetailKeyList = []string{"select_id", "table_name", "access_type", "key", "ref", "attached_condition", "message"}
+
+// convertMySQLPlan converts the EXPLAIN FORMAT=JSON output, where the plan nodes are the nested objects,
+// such as "query_block", "table", "nested_loop" and "ordering_operation".
+func convertMySQLPlan(raw string) (*db.QueryPlan, error) {
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &object); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the EXPLAIN output %q, error: %w", raw, err)
+	}
+	queryBlock, ok := object["query_block"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("missing query_block in the EXPLAIN output %q", raw)
+	}
+	return &db.QueryPlan{
+		Root: convertMySQLPlanObject("query_block", queryBlock),
+		Raw:  raw,
+	}, nil
+}
+
+func convertMySQLPlanObject(operation string, object map[string]interface{}) *db.QueryPlanNode {
+	node := &db.QueryPlanNode{Operation: operation}
+
+	var detailList []string
+	for _, key := range mysqlPlanDetailKeyList {
+		if value, ok := object[key]; ok {
+			detailList = append(detailList, fmt.Sprintf("%s: %v", key, value))
+		}
+	}
+	node.Detail = strings.Join(detailList, ", ")
+
+	if rows, ok := object["rows_produced_per_join"]; ok {
+		node.EstimatedRows = util.ParsePlanNumber(rows)
+	} else if rows, ok := object["rows_examined_per_scan"]; ok {
+		node.EstimatedRows = util.ParsePlanNumber(rows)
+	}
+	if costInfo, ok := object["cost_info"].(map[string]interface{}); ok {
+		if cost, ok := costInfo["query_cost"]; ok {
+			node.EstimatedCost = util.ParsePlanNumber(cost)
+		} else if cost, ok := costInfo["prefix_cost"]; ok {
+			node.EstimatedCost = util.ParsePlanNumber(cost)
+		}
+	}
+
+	node.Children = convertMySQLPlanChildren(object)
+	return node
+}
+
+// convertMySQLPlanChildren converts the nested objects to the child nodes, the arrays of objects such as "nested_loop"
+// are converted to a node whose children are the nested objects of the array elements.
+func convertMySQLPlanChildren(object map[string]interface{}) []*db.QueryPlanNode {
+	// Sort the keys for the stable output, since the key order is lost in the unmarshalled map.
+	var keyList []string
+	for key := range object {
+		keyList = append(keyList, key)
+	}
+	sort.Strings(keyList)
+
+	var children []*db.QueryPlanNode
+	for _, key := range keyList {
+		if key == "cost_info" {
+			continue
+		}
+		switch value := object[key].(type) {
+		case map[string]interface{}:
+			children = append(children, convertMySQLPlanObject(key, value))
+		case []interface{}:
+			node := &db.QueryPlanNode{Operation: key}
+			for _, element := range value {
+				if elementObject, ok := element.(map[string]interface{}); ok {
+					node.Children = append(node.Children, convertMySQLPlanChildren(elementObject)...)
+				}
+			}
+			// Skip the arrays of scalars such as "used_columns".
+			if len(node.Children) > 0 {
+				children = append(children, node)
+			}
+		}
+	}
+	return children
+}
+
+// convertTiDBPlan converts the EXPLAIN output of TiDB, where the "id" column is the operator prefixed by the tree drawing,
+// e.g. "└─TableFullScan_4", and each level is indented by two characters.
+func convertTiDBPlan(columnList []*db.QueryColumn, rowList [][]interface{}) (*db.QueryPlan, error) {
+	columnIndex := make(map[string]int)
+	for i, column := range columnList {
+		columnIndex[column.Name] = i
+	}
+	idIndex, ok := columnIndex["id"]
+	if !ok {
+		return nil, fmt.Errorf("missing id column in the EXPLAIN output")
+	}
+	rowsIndex, ok := columnIndex["estRows"]
+	if !ok {
+		// Before TiDB 4.0, the estimated rows column is named "count".
+		rowsIndex, ok = columnIndex["count"]
+	}
+	if !ok {
+		rowsIndex = -1
+	}
+	costIndex, ok := columnIndex["estCost"]
+	if !ok {
+		costIndex = -1
+	}
+
+	var nodeList []*db.QueryPlanNode
+	var depthList []int
+	var rawList []string
+	for _, row := range rowList {
+		var valueList []string
+		for _, value := range row {
+			valueList = append(valueList, fmt.Sprint(value))
+		}
+		rawList = append(rawList, strings.Join(valueList, "\t"))
+
+		id := valueList[idIndex]
+		operation := strings.TrimLeft(id, " │├└─")
+		node := &db.QueryPlanNode{Operation: operation}
+		var detailList []string
+		for _, name := range []string{"task", "access object", "operator info"} {
+			if i, ok := columnIndex[name]; ok && valueList[i] != "" {
+				detailList = append(detailList, fmt.Sprintf("%s: %s", name, valueList[i]))
+			}
+		}
+		node.Detail = strings.Join(detailList, ", ")
+		if rowsIndex >= 0 {
+			node.EstimatedRows = util.ParsePlanNumber(valueList[rowsIndex])
+		}
+		if costIndex >= 0 {
+			node.EstimatedCost = util.ParsePlanNumber(valueList[costIndex])
+		}
+		nodeList = append(nodeList, node)
+		depthList = append(depthList, (len([]rune(id))-len([]rune(operation)))/2)
+	}
+
+	root, err := util.BuildPlanTree(nodeList, depthList)
+	if err != nil {
+		return nil, err
+	}
+	return &db.QueryPlan{
+		Root: root,
+		Raw:  strings.Join(rawList, "\n"),
+	}, nil
+}
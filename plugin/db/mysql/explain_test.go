@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+)
+
+func newFloat(f float64) *float64 {
+	return &f
+}
+
+func TestConvertMySQLPlan(t *testing.T) {
+	raw := `{
+  "query_block": {
+    "select_id": 1,
+    "cost_info": {"query_cost": "3.20"},
+    "nested_loop": [
+      {
+        "table": {
+          "table_name": "t1",
+          "access_type": "ALL",
+          "rows_examined_per_scan": 10,
+          "rows_produced_per_join": 10,
+          "cost_info": {"read_cost": "1.00", "eval_cost": "1.00", "prefix_cost": "2.00"},
+          "used_columns": ["id"]
+        }
+      },
+      {
+        "table": {
+          "table_name": "t2",
+          "access_type": "eq_ref",
+          "key": "PRIMARY",
+          "rows_examined_per_scan": 1,
+          "rows_produced_per_join": 10,
+          "cost_info": {"prefix_cost": "3.20"}
+        }
+      }
+    ]
+  }
+}`
+	want := &db.QueryPlanNode{
+		Operation:     "query_block",
+		Detail:        "select_id: 1",
+		EstimatedCost: newFloat(3.2),
+		Children: []*db.QueryPlanNode{
+			{
+				Operation: "nested_loop",
+				Children: []*db.QueryPlanNode{
+					{
+						Operation:     "table",
+						Detail:        "table_name: t1, access_type: ALL",
+						EstimatedRows: newFloat(10),
+						EstimatedCost: newFloat(2),
+					},
+					{
+						Operation:     "table",
+						Detail:        "table_name: t2, access_type: eq_ref, key: PRIMARY",
+						EstimatedRows: newFloat(10),
+						EstimatedCost: newFloat(3.2),
+					},
+				},
+			},
+		},
+	}
+
+	plan, err := convertMySQLPlan(raw)
+	if err != nil {
+		t.Fatalf("convertMySQLPlan() unexpected error: %v", err)
+	}
+	if diff := pretty.Diff(plan.Root, want); len(diff) > 0 {
+		t.Errorf("got %+v, want %+v, diff %+v.", plan.Root, want, diff)
+	}
+	if plan.Raw != raw {
+		t.Errorf("got raw %q, want %q", plan.Raw, raw)
+	}
+
+	if _, err := convertMySQLPlan(`{"message": "no query block"}`); err == nil {
+		t.Errorf("convertMySQLPlan() expected error on missing query_block")
+	}
+}
+
+func TestConvertTiDBPlan(t *testing.T) {
+	columnList := []*db.QueryColumn{{Name: "id"}, {Name: "estRows"}, {Name: "task"}, {Name: "access object"}, {Name: "operator info"}}
+	rowList := [][]interface{}{
+		{"Projection_4", "3.33", "root", "", "test.t.a"},
+		{"└─TableReader_7", "3.33", "root", "", "data:Selection_6"},
+		{"  └─Selection_6", "3.33", "cop[tikv]", "", "gt(test.t.a, 1)"},
+		{"    └─TableFullScan_5", "10000.00", "cop[tikv]", "table:t", "keep order:false"},
+	}
+	want := &db.QueryPlanNode{
+		Operation:     "Projection_4",
+		Detail:        "task: root, operator info: test.t.a",
+		EstimatedRows: newFloat(3.33),
+		Children: []*db.QueryPlanNode{
+			{
+				Operation:     "TableReader_7",
+				Detail:        "task: root, operator info: data:Selection_6",
+				EstimatedRows: newFloat(3.33),
+				Children: []*db.QueryPlanNode{
+					{
+						Operation:     "Selection_6",
+						Detail:        "task: cop[tikv], operator info: gt(test.t.a, 1)",
+						EstimatedRows: newFloat(3.33),
+						Children: []*db.QueryPlanNode{
+							{
+								Operation:     "TableFullScan_5",
+								Detail:        "task: cop[tikv], access object: table:t, operator info: keep order:false",
+								EstimatedRows: newFloat(10000),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	plan, err := convertTiDBPlan(columnList, rowList)
+	if err != nil {
+		t.Fatalf("convertTiDBPlan() unexpected error: %v", err)
+	}
+	if diff := pretty.Diff(plan.Root, want); len(diff) > 0 {
+		t.Errorf("got %+v, want %+v, diff %+v.", plan.Root, want, diff)
+	}
+}
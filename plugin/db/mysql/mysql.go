@@ -15,6 +15,7 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
+	"github.com/bytebase/bytebase/plugin/parser"
 	"github.com/go-sql-driver/mysql"
 	"go.uber.org/zap"
 )
@@ -36,6 +37,20 @@ var (
 
 	_ db.Driver              = (*Driver)(nil)
 	_ util.MigrationExecutor = (*Driver)(nil)
+
+	mysqlStatementCanceler = &util.StatementCanceler{
+		SessionIDQuery: "SELECT CONNECTION_ID()",
+		CancelStatement: func(sessionID int64) string {
+			return fmt.Sprintf("KILL QUERY %d", sessionID)
+		},
+	}
+	// TiDB only kills the query of the session on the same TiDB server with KILL TIDB QUERY, unless global kill is enabled.
+	tidbStatementCanceler = &util.StatementCanceler{
+		SessionIDQuery: "SELECT CONNECTION_ID()",
+		CancelStatement: func(sessionID int64) string {
+			return fmt.Sprintf("KILL TIDB QUERY %d", sessionID)
+		},
+	}
 )
 
 func init() {
@@ -116,6 +131,18 @@ func (driver *Driver) Close(ctx context.Context) error {
 	return driver.db.Close()
 }
 
+// GetCapability returns the capability of MySQL and TiDB.
+func (*Driver) GetCapability(dbType db.Type) db.Capability {
+	return db.Capability{
+		IndexSync:    true,
+		Collation:    true,
+		DumpWithData: true,
+		Explain:      true,
+		Cancel:       true,
+		UserGrant:    true,
+	}
+}
+
 // Ping pings the database.
 func (driver *Driver) Ping(ctx context.Context) error {
 	return driver.db.PingContext(ctx)
@@ -258,7 +285,8 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 				COLUMN_TYPE,
 				IFNULL(CHARACTER_SET_NAME, ''),
 				IFNULL(COLLATION_NAME, ''),
-				COLUMN_COMMENT
+				COLUMN_COMMENT,
+				EXTRA
 			FROM information_schema.COLUMNS
 			WHERE ` + columnWhere
 	columnRows, err := driver.db.QueryContext(ctx, query)
@@ -286,6 +314,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 			&column.CharacterSet,
 			&column.Collation,
 			&column.Comment,
+			&column.Extra,
 		); err != nil {
 			return nil, nil, err
 		}
@@ -531,6 +560,12 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 	}
 	defer tx.Rollback()
 
+	stopWatch, err := driver.getStatementCanceler().Watch(ctx, driver.l, driver.db, tx)
+	if err != nil {
+		return err
+	}
+	defer stopWatch()
+
 	_, err = tx.ExecContext(ctx, statement)
 
 	if err == nil {
@@ -544,7 +579,20 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 
 // Query queries a SQL statement.
 func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.l, driver.db, statement, limit)
+	return util.Query(ctx, driver.l, driver.db, statement, limit, driver.getStatementCanceler())
+}
+
+// getStatementCanceler returns the canceler killing the running statement on the database server.
+func (driver *Driver) getStatementCanceler() *util.StatementCanceler {
+	if driver.dbType == db.TiDB {
+		return tidbStatementCanceler
+	}
+	return mysqlStatementCanceler
+}
+
+// OpenCursor opens the cursor of a readonly SQL statement.
+func (driver *Driver) OpenCursor(ctx context.Context, statement string, limit int) (db.QueryCursor, error) {
+	return util.OpenCursor(ctx, driver.l, driver.db, statement, limit, driver.getStatementCanceler())
 }
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -903,14 +951,14 @@ func (driver *Driver) Restore(ctx context.Context, sc *bufio.Scanner) (err error
 	}
 	defer txn.Rollback()
 
-	f := func(stmt string) error {
-		if _, err := txn.Exec(stmt); err != nil {
+	f := func(stmt *parser.SingleSQL) error {
+		if _, err := txn.Exec(stmt.Text); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	if err := util.ApplyMultiStatements(sc, f); err != nil {
+	if err := parser.ApplyMultiStatements(db.MySQL, sc, f); err != nil {
 		return err
 	}
 
@@ -0,0 +1,93 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// pgPlanDetailKeyList is the list of the plan node keys composing the detail of the plan node, in the displayed order.
+var pgPlanDetailKeyList = []string{
+	"Relation Name",
+	"Alias",
+	"Index Name",
+	"CTE Name",
+	"Function Name",
+	"Subplan Name",
+	"Join Type",
+	"Strategy",
+	"Index Cond",
+	"Hash Cond",
+	"Merge Cond",
+	"Join Filter",
+	"Filter",
+	"Sort Key",
+	"Group Key",
+}
+
+// Explain explains the query plan of a readonly SQL statement.
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	_, rowList, err := util.QueryExplain(ctx, driver.l, driver.db, "EXPLAIN (FORMAT JSON) "+statement, statementCanceler)
+	if err != nil {
+		return nil, err
+	}
+	if len(rowList) != 1 || len(rowList[0]) != 1 {
+		return nil, fmt.Errorf("expect one row and one column in the EXPLAIN output, got %d rows", len(rowList))
+	}
+	return convertPlan(fmt.Sprint(rowList[0][0]))
+}
+
+// convertPlan converts the EXPLAIN (FORMAT JSON) output, which is an array with one element per statement,
+// and the plan nodes are nested in the "Plans" array of the parent node.
+func convertPlan(raw string) (*db.QueryPlan, error) {
+	var list []struct {
+		Plan map[string]interface{} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the EXPLAIN output %q, error: %w", raw, err)
+	}
+	if len(list) != 1 || list[0].Plan == nil {
+		return nil, fmt.Errorf("expect one plan in the EXPLAIN output %q", raw)
+	}
+	return &db.QueryPlan{
+		Root: convertPlanNode(list[0].Plan),
+		Raw:  raw,
+	}, nil
+}
+
+func convertPlanNode(plan map[string]interface{}) *db.QueryPlanNode {
+	node := &db.QueryPlanNode{
+		Operation:     fmt.Sprint(plan["Node Type"]),
+		EstimatedRows: util.ParsePlanNumber(plan["Plan Rows"]),
+		EstimatedCost: util.ParsePlanNumber(plan["Total Cost"]),
+	}
+
+	var detailList []string
+	for _, key := range pgPlanDetailKeyList {
+		switch value := plan[key].(type) {
+		case nil:
+		case []interface{}:
+			var itemList []string
+			for _, item := range value {
+				itemList = append(itemList, fmt.Sprint(item))
+			}
+			detailList = append(detailList, fmt.Sprintf("%s: %s", key, strings.Join(itemList, ", ")))
+		default:
+			detailList = append(detailList, fmt.Sprintf("%s: %v", key, value))
+		}
+	}
+	node.Detail = strings.Join(detailList, ", ")
+
+	if children, ok := plan["Plans"].([]interface{}); ok {
+		for _, child := range children {
+			if childPlan, ok := child.(map[string]interface{}); ok {
+				node.Children = append(node.Children, convertPlanNode(childPlan))
+			}
+		}
+	}
+	return node
+}
@@ -0,0 +1,93 @@
+package pg
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+)
+
+func newFloat(f float64) *float64 {
+	return &f
+}
+
+func TestConvertPlan(t *testing.T) {
+	raw := `[
+  {
+    "Plan": {
+      "Node Type": "Hash Join",
+      "Join Type": "Inner",
+      "Startup Cost": 1.09,
+      "Total Cost": 2.24,
+      "Plan Rows": 4,
+      "Hash Cond": "(t1.id = t2.id)",
+      "Plans": [
+        {
+          "Node Type": "Seq Scan",
+          "Relation Name": "t1",
+          "Alias": "t1",
+          "Total Cost": 1.04,
+          "Plan Rows": 4,
+          "Filter": "(id > 1)"
+        },
+        {
+          "Node Type": "Sort",
+          "Total Cost": 1.05,
+          "Plan Rows": 4,
+          "Sort Key": ["t2.id", "t2.name DESC"],
+          "Plans": [
+            {
+              "Node Type": "Index Scan",
+              "Relation Name": "t2",
+              "Alias": "t2",
+              "Index Name": "t2_pkey",
+              "Total Cost": 1.04,
+              "Plan Rows": 4
+            }
+          ]
+        }
+      ]
+    }
+  }
+]`
+	want := &db.QueryPlanNode{
+		Operation:     "Hash Join",
+		Detail:        "Join Type: Inner, Hash Cond: (t1.id = t2.id)",
+		EstimatedRows: newFloat(4),
+		EstimatedCost: newFloat(2.24),
+		Children: []*db.QueryPlanNode{
+			{
+				Operation:     "Seq Scan",
+				Detail:        "Relation Name: t1, Alias: t1, Filter: (id > 1)",
+				EstimatedRows: newFloat(4),
+				EstimatedCost: newFloat(1.04),
+			},
+			{
+				Operation:     "Sort",
+				Detail:        "Sort Key: t2.id, t2.name DESC",
+				EstimatedRows: newFloat(4),
+				EstimatedCost: newFloat(1.05),
+				Children: []*db.QueryPlanNode{
+					{
+						Operation:     "Index Scan",
+						Detail:        "Relation Name: t2, Alias: t2, Index Name: t2_pkey",
+						EstimatedRows: newFloat(4),
+						EstimatedCost: newFloat(1.04),
+					},
+				},
+			},
+		},
+	}
+
+	plan, err := convertPlan(raw)
+	if err != nil {
+		t.Fatalf("convertPlan() unexpected error: %v", err)
+	}
+	if diff := pretty.Diff(plan.Root, want); len(diff) > 0 {
+		t.Errorf("got %+v, want %+v, diff %+v.", plan.Root, want, diff)
+	}
+
+	if _, err := convertPlan(`[]`); err == nil {
+		t.Errorf("convertPlan() expected error on empty plan list")
+	}
+}
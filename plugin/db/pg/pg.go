@@ -17,6 +17,7 @@ import (
 
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
+	"github.com/bytebase/bytebase/plugin/parser"
 	"go.uber.org/zap"
 )
 
@@ -28,6 +29,12 @@ var (
 		"template0": true,
 		"template1": true,
 	}
+	statementCanceler = &util.StatementCanceler{
+		SessionIDQuery: "SELECT pg_backend_pid()",
+		CancelStatement: func(sessionID int64) string {
+			return fmt.Sprintf("SELECT pg_cancel_backend(%d)", sessionID)
+		},
+	}
 	reserved = map[string]bool{
 		"AES128":            true,
 		"AES256":            true,
@@ -303,6 +310,19 @@ func (driver *Driver) Close(ctx context.Context) error {
 	return driver.db.Close()
 }
 
+// GetCapability returns the capability of Postgres.
+func (*Driver) GetCapability(dbType db.Type) db.Capability {
+	return db.Capability{
+		TransactionalDDL: true,
+		IndexSync:        true,
+		Collation:        true,
+		DumpWithData:     true,
+		Explain:          true,
+		Cancel:           true,
+		UserGrant:        true,
+	}
+}
+
 // Ping pings the database.
 func (driver *Driver) Ping(ctx context.Context) error {
 	return driver.db.PingContext(ctx)
@@ -413,7 +433,7 @@ func (driver *Driver) SyncSchema(ctx context.Context) ([]*db.User, []*db.Schema,
 				dbColumn.Name = col.columnName
 				dbColumn.Position = col.ordinalPosition
 				dbColumn.Default = &col.columnDefault
-				dbColumn.Type = col.dataType
+				dbColumn.Type = col.Type()
 				dbColumn.Nullable = col.isNullable
 				dbColumn.Collation = col.collationName
 				dbColumn.Comment = col.comment
@@ -505,26 +525,38 @@ func (driver *Driver) getUserList(ctx context.Context) ([]*db.User, error) {
 
 // Execute executes a SQL statement.
 func (driver *Driver) Execute(ctx context.Context, statement string, useTransaction bool) error {
+	// CREATE INDEX CONCURRENTLY and DROP INDEX CONCURRENTLY can't run inside a transaction block,
+	// so they are executed one by one without the transaction, and they can't be mixed with the other statements,
+	// which would lose the atomicity of the transaction.
+	if useTransaction {
+		concurrentCount, count := countConcurrentIndexStatement(statement)
+		if concurrentCount > 0 && concurrentCount < count {
+			return fmt.Errorf("CREATE INDEX CONCURRENTLY and DROP INDEX CONCURRENTLY can't run inside a transaction block, so they can't be mixed with the other statements, please move them to a separate migration")
+		}
+		if concurrentCount > 0 {
+			useTransaction = false
+		}
+	}
 	// We don't use transaction for creating databases in Postgres.
 	// https://github.com/bytebase/bytebase/issues/202
 	if !useTransaction {
-		f := func(stmt string) error {
+		f := func(stmt *parser.SingleSQL) error {
 			// For the case of `\connect "dbname";`, we need to use GetDbConnection() instead of executing the statement.
-			if strings.HasPrefix(stmt, "\\connect ") {
-				parts := strings.Split(stmt, `"`)
+			if strings.HasPrefix(stmt.Text, "\\connect ") {
+				parts := strings.Split(stmt.Text, `"`)
 				if len(parts) != 3 {
-					return fmt.Errorf("invalid statement %q", stmt)
+					return fmt.Errorf("invalid statement %q", stmt.Text)
 				}
 				_, err := driver.GetDbConnection(ctx, parts[1])
 				return err
 			}
-			if _, err := driver.db.ExecContext(ctx, stmt); err != nil {
+			if _, err := driver.db.ExecContext(ctx, stmt.Text); err != nil {
 				return err
 			}
 			return nil
 		}
 		sc := bufio.NewScanner(strings.NewReader(statement))
-		if err := util.ApplyMultiStatements(sc, f); err != nil {
+		if err := parser.ApplyMultiStatements(db.Postgres, sc, f); err != nil {
 			return err
 		}
 		return nil
@@ -536,6 +568,12 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 	}
 	defer tx.Rollback()
 
+	stopWatch, err := statementCanceler.Watch(ctx, driver.l, driver.db, tx)
+	if err != nil {
+		return err
+	}
+	defer stopWatch()
+
 	_, err = tx.ExecContext(ctx, statement)
 
 	if err == nil {
@@ -547,9 +585,31 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 	return err
 }
 
+// countConcurrentIndexStatement returns the count of the statements creating or dropping the index concurrently,
+// and the count of all the statements.
+func countConcurrentIndexStatement(statement string) (int, int) {
+	stmtList, err := parser.ParsePGStatements(statement)
+	if err != nil {
+		// The syntax error is reported by executing the statement.
+		return 0, 0
+	}
+	concurrentCount := 0
+	for _, stmt := range stmtList {
+		if parser.IsPGConcurrentIndexStmt(stmt) {
+			concurrentCount++
+		}
+	}
+	return concurrentCount, len(stmtList)
+}
+
 // Query queries a SQL statement.
 func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.l, driver.db, statement, limit)
+	return util.Query(ctx, driver.l, driver.db, statement, limit, statementCanceler)
+}
+
+// OpenCursor opens the cursor of a readonly SQL statement.
+func (driver *Driver) OpenCursor(ctx context.Context, statement string, limit int) (db.QueryCursor, error) {
+	return util.OpenCursor(ctx, driver.l, driver.db, statement, limit, statementCanceler)
 }
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -927,14 +987,14 @@ func (driver *Driver) Restore(ctx context.Context, sc *bufio.Scanner) (err error
 	}
 	defer txn.Rollback()
 
-	f := func(stmt string) error {
-		if _, err := txn.Exec(stmt); err != nil {
+	f := func(stmt *parser.SingleSQL) error {
+		if _, err := txn.Exec(stmt.Text); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	if err := util.ApplyMultiStatements(sc, f); err != nil {
+	if err := parser.ApplyMultiStatements(db.Postgres, sc, f); err != nil {
 		return err
 	}
 
@@ -1253,12 +1313,17 @@ func (t *tableSchema) Statement() string {
 	return s
 }
 
-// Statement returns the statement of a table column.
-func (c *columnSchema) Statement() string {
-	s := fmt.Sprintf("%s %s", c.columnName, c.dataType)
+// Type returns the type of a table column with the maximum length if any, e.g. "character varying(50)".
+func (c *columnSchema) Type() string {
 	if c.characterMaximumLength != "" {
-		s += fmt.Sprintf("(%s)", c.characterMaximumLength)
+		return fmt.Sprintf("%s(%s)", c.dataType, c.characterMaximumLength)
 	}
+	return c.dataType
+}
+
+// Statement returns the statement of a table column.
+func (c *columnSchema) Statement() string {
+	s := fmt.Sprintf("%s %s", c.columnName, c.Type())
 	if !c.isNullable {
 		s = s + " NOT NULL"
 	}
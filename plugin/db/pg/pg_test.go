@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"testing"
+)
+
+func TestColumnType(t *testing.T) {
+	tests := []struct {
+		column *columnSchema
+		want   string
+	}{
+		{
+			column: &columnSchema{dataType: "integer"},
+			want:   "integer",
+		},
+		{
+			column: &columnSchema{dataType: "character varying", characterMaximumLength: "50"},
+			want:   "character varying(50)",
+		},
+		{
+			column: &columnSchema{dataType: "character", characterMaximumLength: "1"},
+			want:   "character(1)",
+		},
+		{
+			column: &columnSchema{dataType: "text"},
+			want:   "text",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.column.Type()
+		if got != test.want {
+			t.Errorf("columnType(%q, %q) got %q, want %q", test.column.dataType, test.column.characterMaximumLength, got, test.want)
+		}
+	}
+}
+
+func TestColumnStatement(t *testing.T) {
+	column := &columnSchema{
+		columnName:             "name",
+		dataType:               "character varying",
+		characterMaximumLength: "50",
+		columnDefault:          "''::character varying",
+		isNullable:             false,
+	}
+	want := "name character varying(50) NOT NULL DEFAULT ''::character varying"
+	if got := column.Statement(); got != want {
+		t.Errorf("Statement() got %q, want %q", got, want)
+	}
+}
+
+func TestCountConcurrentIndexStatement(t *testing.T) {
+	tests := []struct {
+		statement      string
+		wantConcurrent int
+		wantCount      int
+	}{
+		{
+			statement:      "CREATE INDEX idx_a ON t (a); INSERT INTO t VALUES (1)",
+			wantConcurrent: 0,
+			wantCount:      2,
+		},
+		{
+			statement:      "CREATE INDEX CONCURRENTLY idx_a ON t (a); DROP INDEX CONCURRENTLY idx_b",
+			wantConcurrent: 2,
+			wantCount:      2,
+		},
+		{
+			statement:      "CREATE INDEX CONCURRENTLY idx_a ON t (a); ALTER TABLE t ADD COLUMN b int",
+			wantConcurrent: 1,
+			wantCount:      2,
+		},
+	}
+
+	for _, test := range tests {
+		concurrentCount, count := countConcurrentIndexStatement(test.statement)
+		if concurrentCount != test.wantConcurrent || count != test.wantCount {
+			t.Errorf("countConcurrentIndexStatement(%q) got (%d, %d), want (%d, %d)", test.statement, concurrentCount, count, test.wantConcurrent, test.wantCount)
+		}
+	}
+}
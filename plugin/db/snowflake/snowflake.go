@@ -14,6 +14,7 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
+	"github.com/bytebase/bytebase/plugin/parser"
 	snow "github.com/snowflakedb/gosnowflake"
 	"go.uber.org/zap"
 )
@@ -108,6 +109,14 @@ func (driver *Driver) Close(ctx context.Context) error {
 	return driver.db.Close()
 }
 
+// GetCapability returns the capability of Snowflake.
+func (*Driver) GetCapability(dbType db.Type) db.Capability {
+	return db.Capability{
+		Cancel:    true,
+		UserGrant: true,
+	}
+}
+
 // Ping pings the database.
 func (driver *Driver) Ping(ctx context.Context) error {
 	return driver.db.PingContext(ctx)
@@ -478,12 +487,12 @@ func (driver *Driver) getUserList(ctx context.Context) ([]*db.User, error) {
 // Execute executes a SQL statement.
 func (driver *Driver) Execute(ctx context.Context, statement string, useTransaction bool) error {
 	count := 0
-	f := func(stmt string) error {
+	f := func(stmt *parser.SingleSQL) error {
 		count++
 		return nil
 	}
 	sc := bufio.NewScanner(strings.NewReader(statement))
-	if err := util.ApplyMultiStatements(sc, f); err != nil {
+	if err := parser.ApplyMultiStatements(db.Snowflake, sc, f); err != nil {
 		return err
 	}
 
@@ -516,7 +525,17 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 
 // Query queries a SQL statement.
 func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.l, driver.db, statement, limit)
+	return util.Query(ctx, driver.l, driver.db, statement, limit, nil /* canceler */)
+}
+
+// OpenCursor opens the cursor of a readonly SQL statement.
+func (driver *Driver) OpenCursor(ctx context.Context, statement string, limit int) (db.QueryCursor, error) {
+	return util.OpenCursor(ctx, driver.l, driver.db, statement, limit, nil /* canceler */)
+}
+
+// Explain explains the query plan of a readonly SQL statement.
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	return nil, common.Errorf(common.NotImplemented, fmt.Errorf("explaining the query plan is not supported for Snowflake"))
 }
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -967,14 +986,14 @@ func (driver *Driver) Restore(ctx context.Context, sc *bufio.Scanner) (err error
 	}
 	defer txn.Rollback()
 
-	f := func(stmt string) error {
-		if _, err := txn.Exec(stmt); err != nil {
+	f := func(stmt *parser.SingleSQL) error {
+		if _, err := txn.Exec(stmt.Text); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	if err := util.ApplyMultiStatements(sc, f); err != nil {
+	if err := parser.ApplyMultiStatements(db.Snowflake, sc, f); err != nil {
 		return err
 	}
 
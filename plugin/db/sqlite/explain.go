@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+// Explain explains the query plan of a readonly SQL statement.
+// SQLite doesn't estimate the rows and the cost in EXPLAIN QUERY PLAN.
+func (driver *Driver) Explain(ctx context.Context, statement string) (*db.QueryPlan, error) {
+	columnList, rowList, err := util.QueryExplain(ctx, driver.l, driver.db, "EXPLAIN QUERY PLAN "+statement, nil /* canceler */)
+	if err != nil {
+		return nil, err
+	}
+	return convertPlan(columnList, rowList)
+}
+
+// convertPlan converts the EXPLAIN QUERY PLAN output, whose rows are the plan nodes with the "id", "parent" and "detail" columns.
+// The top level nodes have parent 0.
+func convertPlan(columnList []*db.QueryColumn, rowList [][]interface{}) (*db.QueryPlan, error) {
+	columnIndex := make(map[string]int)
+	for i, column := range columnList {
+		columnIndex[column.Name] = i
+	}
+	for _, name := range []string{"id", "parent", "detail"} {
+		if _, ok := columnIndex[name]; !ok {
+			return nil, fmt.Errorf("missing %s column in the EXPLAIN QUERY PLAN output", name)
+		}
+	}
+
+	root := &db.QueryPlanNode{Operation: "QUERY PLAN"}
+	nodeMap := map[int64]*db.QueryPlanNode{0: root}
+	var rawList []string
+	for _, row := range rowList {
+		id, err := strconv.ParseInt(fmt.Sprint(row[columnIndex["id"]]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id in the EXPLAIN QUERY PLAN output, error: %w", err)
+		}
+		parentID, err := strconv.ParseInt(fmt.Sprint(row[columnIndex["parent"]]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parent in the EXPLAIN QUERY PLAN output, error: %w", err)
+		}
+		detail := fmt.Sprint(row[columnIndex["detail"]])
+		rawList = append(rawList, fmt.Sprintf("%d\t%d\t%s", id, parentID, detail))
+
+		// SQLite lists the parent node before its children.
+		parent, ok := nodeMap[parentID]
+		if !ok {
+			return nil, fmt.Errorf("parent %d of the plan node %d not found in the EXPLAIN QUERY PLAN output", parentID, id)
+		}
+		node := &db.QueryPlanNode{Operation: detail}
+		parent.Children = append(parent.Children, node)
+		nodeMap[id] = node
+	}
+
+	return &db.QueryPlan{
+		Root: root,
+		Raw:  strings.Join(rawList, "\n"),
+	}, nil
+}
@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+)
+
+func TestConvertPlan(t *testing.T) {
+	columnList := []*db.QueryColumn{{Name: "id"}, {Name: "parent"}, {Name: "notused"}, {Name: "detail"}}
+	rowList := [][]interface{}{
+		{"2", "0", "0", "SCAN t1"},
+		{"5", "0", "0", "SEARCH t2 USING INTEGER PRIMARY KEY (rowid=?)"},
+		{"9", "0", "0", "CORRELATED SCALAR SUBQUERY 1"},
+		{"13", "9", "0", "SCAN t3"},
+	}
+	want := &db.QueryPlanNode{
+		Operation: "QUERY PLAN",
+		Children: []*db.QueryPlanNode{
+			{Operation: "SCAN t1"},
+			{Operation: "SEARCH t2 USING INTEGER PRIMARY KEY (rowid=?)"},
+			{
+				Operation: "CORRELATED SCALAR SUBQUERY 1",
+				Children:  []*db.QueryPlanNode{{Operation: "SCAN t3"}},
+			},
+		},
+	}
+
+	plan, err := convertPlan(columnList, rowList)
+	if err != nil {
+		t.Fatalf("convertPlan() unexpected error: %v", err)
+	}
+	if diff := pretty.Diff(plan.Root, want); len(diff) > 0 {
+		t.Errorf("got %+v, want %+v, diff %+v.", plan.Root, want, diff)
+	}
+
+	if _, err := convertPlan(columnList, [][]interface{}{{"3", "2", "0", "SCAN t1"}}); err == nil {
+		t.Errorf("convertPlan() expected error on missing parent")
+	}
+}
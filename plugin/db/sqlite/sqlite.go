@@ -16,6 +16,7 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
+	"github.com/bytebase/bytebase/plugin/parser"
 	"go.uber.org/zap"
 )
 
@@ -72,6 +73,16 @@ func (driver *Driver) Close(ctx context.Context) error {
 	return nil
 }
 
+// GetCapability returns the capability of SQLite.
+func (*Driver) GetCapability(dbType db.Type) db.Capability {
+	return db.Capability{
+		TransactionalDDL: true,
+		DumpWithData:     true,
+		Explain:          true,
+		Cancel:           true,
+	}
+}
+
 // Ping pings the database.
 func (driver *Driver) Ping(ctx context.Context) error {
 	return driver.db.PingContext(ctx)
@@ -273,7 +284,12 @@ func (driver *Driver) Execute(ctx context.Context, statement string, useTransact
 
 // Query queries a SQL statement.
 func (driver *Driver) Query(ctx context.Context, statement string, limit int) ([]interface{}, error) {
-	return util.Query(ctx, driver.l, driver.db, statement, limit)
+	return util.Query(ctx, driver.l, driver.db, statement, limit, nil /* canceler */)
+}
+
+// OpenCursor opens the cursor of a readonly SQL statement.
+func (driver *Driver) OpenCursor(ctx context.Context, statement string, limit int) (db.QueryCursor, error) {
+	return util.OpenCursor(ctx, driver.l, driver.db, statement, limit, nil /* canceler */)
 }
 
 // NeedsSetupMigration returns whether it needs to setup migration.
@@ -714,14 +730,14 @@ func (driver *Driver) Restore(ctx context.Context, sc *bufio.Scanner) (err error
 	}
 	defer txn.Rollback()
 
-	f := func(stmt string) error {
-		if _, err := txn.Exec(stmt); err != nil {
+	f := func(stmt *parser.SingleSQL) error {
+		if _, err := txn.Exec(stmt.Text); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	if err := util.ApplyMultiStatements(sc, f); err != nil {
+	if err := parser.ApplyMultiStatements(db.SQLite, sc, f); err != nil {
 		return err
 	}
 
@@ -1,11 +1,12 @@
 package util
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,6 +17,8 @@ import (
 
 const (
 	bytebaseDatabase = "bytebase"
+	// statementCancelTimeout is the timeout of canceling the running statement on the database server.
+	statementCancelTimeout = 10 * time.Second
 )
 
 // FormatErrorWithQuery will format the error with failed query.
@@ -23,77 +26,6 @@ func FormatErrorWithQuery(err error, query string) error {
 	return common.Errorf(common.DbExecutionError, fmt.Errorf("failed to execute error: %w\n\nquery:\n%q", err, query))
 }
 
-// ApplyMultiStatements will apply the splitted statements from scanner.
-func ApplyMultiStatements(sc *bufio.Scanner, f func(string) error) error {
-	s := ""
-	delimiter := false
-	comment := false
-	for sc.Scan() {
-		line := sc.Text()
-
-		execute := false
-		switch {
-		case strings.HasPrefix(line, "/*"):
-			if strings.Contains(line, "*/") {
-				if !strings.HasSuffix(line, "*/") {
-					return fmt.Errorf("`*/` must be the end of the line; new statement should start as a new line")
-				}
-			} else {
-				comment = true
-			}
-			continue
-		case comment && !strings.Contains(line, "*/"):
-			// Skip the line when in comment mode.
-			continue
-		case comment && strings.Contains(line, "*/"):
-			if !strings.HasSuffix(line, "*/") {
-				return fmt.Errorf("`*/` must be the end of the line; new statement should start as a new line")
-			}
-			comment = false
-			continue
-		case s == "" && line == "":
-			continue
-		case strings.HasPrefix(line, "--"):
-			continue
-		case line == "DELIMITER ;;":
-			delimiter = true
-			continue
-		case line == "DELIMITER ;" && delimiter:
-			delimiter = false
-			execute = true
-		case strings.HasSuffix(line, ";"):
-			s = s + line + "\n"
-			if !delimiter {
-				execute = true
-			}
-		default:
-			s = s + line + "\n"
-			continue
-		}
-		if execute {
-			s = strings.Trim(s, "\n\t ")
-			if s != "" {
-				if err := f(s); err != nil {
-					return fmt.Errorf("execute query %q failed: %v", s, err)
-				}
-			}
-			s = ""
-		}
-	}
-	// Apply the remaining content.
-	s = strings.Trim(s, "\n\t ")
-	if s != "" {
-		if err := f(s); err != nil {
-			return fmt.Errorf("execute query %q failed: %v", s, err)
-		}
-	}
-
-	if err := sc.Err(); err != nil {
-		return err
-	}
-	return nil
-}
-
 // NeedsSetupMigrationSchema will return whether it's needed to setup migration schema.
 func NeedsSetupMigrationSchema(ctx context.Context, sqldb *sql.DB, query string) (bool, error) {
 	rows, err := sqldb.QueryContext(ctx, query)
@@ -151,7 +83,8 @@ func ExecuteMigration(ctx context.Context, l *zap.Logger, executor MigrationExec
 	startedNs := time.Now().UnixNano()
 
 	defer func() {
-		if err := endMigration(ctx, l, executor, startedNs, insertedID, updatedSchema, resErr == nil /*isDone*/); err != nil {
+		// Use a fresh context so the migration history is still updated to FAILED if the migration is canceled or timed out.
+		if err := endMigration(context.Background(), l, executor, startedNs, insertedID, updatedSchema, resErr == nil /*isDone*/); err != nil {
 			l.Error("Failed to update migration history record",
 				zap.Error(err),
 				zap.Int64("migration_id", migrationHistoryID),
@@ -281,80 +214,299 @@ func endMigration(ctx context.Context, l *zap.Logger, executor MigrationExecutor
 }
 
 // Query will execute a readonly / SELECT query.
-func Query(ctx context.Context, l *zap.Logger, db *sql.DB, statement string, limit int) ([]interface{}, error) {
+// The query is canceled on the database server once ctx is done if canceler is not nil.
+func Query(ctx context.Context, l *zap.Logger, db *sql.DB, statement string, limit int, canceler *StatementCanceler) ([]interface{}, error) {
+	cursor, err := OpenCursor(ctx, l, db, statement, limit, canceler)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	columnList := cursor.Columns()
+	resultSet := []interface{}{}
+	for {
+		rowList, err := cursor.Next(0)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rowList {
+			rowData := map[string]interface{}{}
+			for i, column := range columnList {
+				rowData[column.Name] = row[i]
+			}
+			resultSet = append(resultSet, rowData)
+		}
+	}
+
+	return resultSet, nil
+}
+
+// OpenCursor opens the cursor of the readonly query, the rows are scanned on demand when fetching the next batch.
+// The query is canceled on the database server once ctx is done if canceler is not nil.
+func OpenCursor(ctx context.Context, l *zap.Logger, sqldb *sql.DB, statement string, limit int, canceler *StatementCanceler) (db.QueryCursor, error) {
 	// Not all sql engines support ReadOnly flag, so we will use tx rollback semantics to enforce readonly.
-	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	tx, err := sqldb.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		return nil, err
 	}
-	defer tx.Rollback()
+
+	stopWatch, err := canceler.Watch(ctx, l, sqldb, tx)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
 	rows, err := tx.QueryContext(ctx, statement)
 	if err != nil {
+		stopWatch()
+		tx.Rollback()
 		return nil, FormatErrorWithQuery(err, statement)
 	}
-	defer rows.Close()
 
 	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
+		rows.Close()
+		stopWatch()
+		tx.Rollback()
 		return nil, formatError(err)
 	}
+	var columnList []*db.QueryColumn
+	for _, columnType := range columnTypes {
+		columnList = append(columnList, &db.QueryColumn{
+			Name: columnType.Name(),
+			Type: columnType.DatabaseTypeName(),
+		})
+	}
+
+	return &queryCursor{
+		tx:          tx,
+		stopWatch:   stopWatch,
+		rows:        rows,
+		columnTypes: columnTypes,
+		columnList:  columnList,
+		limit:       limit,
+	}, nil
+}
 
-	colCount := len(columnTypes)
-	rowCount := 0
-	resultSet := []interface{}{}
-	for rows.Next() {
-		scanArgs := make([]interface{}, colCount)
-		for i, v := range columnTypes {
-			switch v.DatabaseTypeName() {
-			case "VARCHAR", "TEXT", "UUID", "TIMESTAMP":
-				scanArgs[i] = new(sql.NullString)
-			case "BOOL":
-				scanArgs[i] = new(sql.NullBool)
-			case "INT4":
-				scanArgs[i] = new(sql.NullInt64)
-			default:
-				scanArgs[i] = new(sql.NullString)
-			}
+// QueryExplain runs the EXPLAIN statement in a readonly transaction, and returns the columns and all the rows of the EXPLAIN output.
+// The statement is canceled on the database server once ctx is done if canceler is not nil.
+func QueryExplain(ctx context.Context, l *zap.Logger, sqldb *sql.DB, statement string, canceler *StatementCanceler) ([]*db.QueryColumn, [][]interface{}, error) {
+	cursor, err := OpenCursor(ctx, l, sqldb, statement, 0 /* limit */, canceler)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close()
+
+	var rowList [][]interface{}
+	for {
+		batch, err := cursor.Next(0)
+		if err == io.EOF {
+			break
 		}
+		if err != nil {
+			return nil, nil, err
+		}
+		rowList = append(rowList, batch...)
+	}
+	return cursor.Columns(), rowList, nil
+}
 
-		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, formatError(err)
+// BuildPlanTree builds the plan tree from the nodes listed in pre-order along with their depths, where the root has depth 0.
+// The nodes are wrapped in a "Plan" root node if there are multiple nodes with depth 0.
+func BuildPlanTree(nodeList []*db.QueryPlanNode, depthList []int) (*db.QueryPlanNode, error) {
+	if len(nodeList) == 0 {
+		return nil, fmt.Errorf("empty query plan")
+	}
+	if len(nodeList) != len(depthList) {
+		return nil, fmt.Errorf("got %d query plan nodes with %d depths", len(nodeList), len(depthList))
+	}
+
+	root := &db.QueryPlanNode{Operation: "Plan"}
+	// stack[i] is the last node with depth i-1, stack[0] is the root.
+	stack := []*db.QueryPlanNode{root}
+	for i, node := range nodeList {
+		depth := depthList[i]
+		if depth < 0 || depth > len(stack)-1 {
+			return nil, fmt.Errorf("query plan node %q has invalid depth %d", node.Operation, depth)
 		}
+		stack = stack[:depth+1]
+		parent := stack[depth]
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, node)
+	}
+	if len(root.Children) == 1 {
+		return root.Children[0], nil
+	}
+	return root, nil
+}
 
-		rowData := map[string]interface{}{}
-		for i, v := range columnTypes {
-			if z, ok := (scanArgs[i]).(*sql.NullBool); ok {
-				rowData[v.Name()] = z.Bool
-				continue
-			}
-			if z, ok := (scanArgs[i]).(*sql.NullString); ok {
-				rowData[v.Name()] = z.String
-				continue
-			}
-			if z, ok := (scanArgs[i]).(*sql.NullInt64); ok {
-				rowData[v.Name()] = z.Int64
-				continue
-			}
-			if z, ok := (scanArgs[i]).(*sql.NullFloat64); ok {
-				rowData[v.Name()] = z.Float64
-				continue
-			}
-			if z, ok := (scanArgs[i]).(*sql.NullInt32); ok {
-				rowData[v.Name()] = z.Int32
-				continue
+// ParsePlanNumber parses the number in the EXPLAIN output, which may be either a string or a number.
+// Returns nil if the value isn't a number.
+func ParsePlanNumber(value interface{}) *float64 {
+	var f float64
+	switch v := value.(type) {
+	case float64:
+		f = v
+	case int64:
+		f = float64(v)
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil
+		}
+		f = parsed
+	default:
+		return nil
+	}
+	return &f
+}
+
+// queryCursor is the query cursor on the rows of a readonly transaction.
+type queryCursor struct {
+	tx          *sql.Tx
+	stopWatch   func()
+	rows        *sql.Rows
+	columnTypes []*sql.ColumnType
+	columnList  []*db.QueryColumn
+	// limit is the maximum row count returned. No limit enforced if limit <= 0
+	limit    int
+	rowCount int
+}
+
+// Columns returns the column metadata of the query result.
+func (c *queryCursor) Columns() []*db.QueryColumn {
+	return c.columnList
+}
+
+// Next returns the next batch of at most batchSize rows, or io.EOF if there are no more rows.
+func (c *queryCursor) Next(batchSize int) ([][]interface{}, error) {
+	var rowList [][]interface{}
+	for (batchSize <= 0 || len(rowList) < batchSize) && (c.limit <= 0 || c.rowCount < c.limit) && c.rows.Next() {
+		row, err := scanRow(c.rows, c.columnTypes)
+		if err != nil {
+			return nil, err
+		}
+		rowList = append(rowList, row)
+		c.rowCount++
+	}
+	if err := c.rows.Err(); err != nil {
+		return nil, formatError(err)
+	}
+	if len(rowList) == 0 {
+		return nil, io.EOF
+	}
+	return rowList, nil
+}
+
+// Close closes the rows and rolls back the readonly transaction.
+func (c *queryCursor) Close() error {
+	err := c.rows.Close()
+	// Stop watching before releasing the session, otherwise we may cancel the statement of the next session user.
+	c.stopWatch()
+	if err != nil {
+		c.tx.Rollback()
+		return err
+	}
+	return c.tx.Rollback()
+}
+
+// StatementCanceler cancels the running statement of a database session on the database server.
+// The database drivers only abandon the connection on the client side once the context is done,
+// while the statement keeps running on the database server unless it's canceled there.
+type StatementCanceler struct {
+	// SessionIDQuery is the query returning the ID of the current session, e.g. "SELECT CONNECTION_ID()" for MySQL.
+	SessionIDQuery string
+	// CancelStatement returns the statement canceling the running statement of the session, e.g. "KILL QUERY 42" for MySQL.
+	CancelStatement func(sessionID int64) string
+}
+
+// SessionQueryer is the connection pinned to a database session, e.g. *sql.Tx or *sql.Conn.
+type SessionQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Watch cancels the running statement of the session of q on the database server once ctx is done,
+// the cancel statement is executed on a separate connection of sqldb.
+// The returned stop function must be called once the statements on q finish and before the session is released.
+// Watch is a no-op if the canceler is nil.
+func (c *StatementCanceler) Watch(ctx context.Context, l *zap.Logger, sqldb *sql.DB, q SessionQueryer) (stop func(), err error) {
+	if c == nil {
+		return func() {}, nil
+	}
+
+	var sessionID int64
+	if err := q.QueryRowContext(ctx, c.SessionIDQuery).Scan(&sessionID); err != nil {
+		return nil, FormatErrorWithQuery(err, c.SessionIDQuery)
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-done:
+		case <-ctx.Done():
+			// ctx is already done, so we need a fresh context to cancel the statement.
+			cancelCtx, cancel := context.WithTimeout(context.Background(), statementCancelTimeout)
+			defer cancel()
+			statement := c.CancelStatement(sessionID)
+			if _, err := sqldb.ExecContext(cancelCtx, statement); err != nil {
+				l.Warn("Failed to cancel the running statement on the database server",
+					zap.Int64("session_id", sessionID),
+					zap.String("statement", statement),
+					zap.Error(err),
+				)
 			}
-			rowData[v.Name()] = scanArgs[i]
 		}
+	}()
 
-		resultSet = append(resultSet, rowData)
-		rowCount++
-		if rowCount == limit {
-			break
+	return func() {
+		close(done)
+		<-exited
+	}, nil
+}
+
+// scanRow scans the current row into the values in the column order.
+func scanRow(rows *sql.Rows, columnTypes []*sql.ColumnType) ([]interface{}, error) {
+	scanArgs := make([]interface{}, len(columnTypes))
+	for i, v := range columnTypes {
+		switch v.DatabaseTypeName() {
+		case "VARCHAR", "TEXT", "UUID", "TIMESTAMP":
+			scanArgs[i] = new(sql.NullString)
+		case "BOOL":
+			scanArgs[i] = new(sql.NullBool)
+		case "INT4":
+			scanArgs[i] = new(sql.NullInt64)
+		default:
+			scanArgs[i] = new(sql.NullString)
 		}
 	}
 
-	return resultSet, nil
+	if err := rows.Scan(scanArgs...); err != nil {
+		return nil, formatError(err)
+	}
+
+	row := make([]interface{}, len(columnTypes))
+	for i := range columnTypes {
+		switch z := scanArgs[i].(type) {
+		case *sql.NullBool:
+			row[i] = z.Bool
+		case *sql.NullString:
+			row[i] = z.String
+		case *sql.NullInt64:
+			row[i] = z.Int64
+		case *sql.NullFloat64:
+			row[i] = z.Float64
+		case *sql.NullInt32:
+			row[i] = z.Int32
+		default:
+			row[i] = scanArgs[i]
+		}
+	}
+	return row, nil
 }
 
 // FindMigrationHistoryList will find the list of migration history.
@@ -0,0 +1,146 @@
+package util
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+	"go.uber.org/zap"
+
+	// Register sqlite3 driver.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestOpenCursor(t *testing.T) {
+	ctx := context.Background()
+	sqldb, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer sqldb.Close()
+	if _, err := sqldb.ExecContext(ctx, `
+		CREATE TABLE t (id INT4, name TEXT);
+		INSERT INTO t VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e');
+	`); err != nil {
+		t.Fatalf("failed to prepare table: %v", err)
+	}
+
+	tests := []struct {
+		limit     int
+		batchSize int
+		want      [][][]interface{}
+	}{
+		{
+			limit:     0,
+			batchSize: 2,
+			want: [][][]interface{}{
+				{{int64(1), "a"}, {int64(2), "b"}},
+				{{int64(3), "c"}, {int64(4), "d"}},
+				{{int64(5), "e"}},
+			},
+		},
+		{
+			limit:     3,
+			batchSize: 2,
+			want: [][][]interface{}{
+				{{int64(1), "a"}, {int64(2), "b"}},
+				{{int64(3), "c"}},
+			},
+		},
+		{
+			limit:     0,
+			batchSize: 0,
+			want: [][][]interface{}{
+				{{int64(1), "a"}, {int64(2), "b"}, {int64(3), "c"}, {int64(4), "d"}, {int64(5), "e"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		cursor, err := OpenCursor(ctx, zap.NewNop(), sqldb, "SELECT id, name FROM t ORDER BY id", test.limit, nil /* canceler */)
+		if err != nil {
+			t.Fatalf("OpenCursor() unexpected error: %v", err)
+		}
+		wantColumnList := []*db.QueryColumn{{Name: "id", Type: "INT4"}, {Name: "name", Type: "TEXT"}}
+		if diff := pretty.Diff(cursor.Columns(), wantColumnList); len(diff) > 0 {
+			t.Errorf("Columns() got %+v, want %+v, diff %+v.", cursor.Columns(), wantColumnList, diff)
+		}
+		var got [][][]interface{}
+		for {
+			rowList, err := cursor.Next(test.batchSize)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Next() unexpected error: %v", err)
+			}
+			got = append(got, rowList)
+		}
+		if err := cursor.Close(); err != nil {
+			t.Errorf("Close() unexpected error: %v", err)
+		}
+		if diff := pretty.Diff(got, test.want); len(diff) > 0 {
+			t.Errorf("limit %d, batch size %d: got %+v, want %+v, diff %+v.", test.limit, test.batchSize, got, test.want, diff)
+		}
+	}
+}
+
+func TestStatementCancelerWatch(t *testing.T) {
+	sqldb, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer sqldb.Close()
+
+	canceledSessionIDs := make(chan int64, 1)
+	canceler := &StatementCanceler{
+		SessionIDQuery: "SELECT 42",
+		CancelStatement: func(sessionID int64) string {
+			canceledSessionIDs <- sessionID
+			return "SELECT 1"
+		},
+	}
+
+	// The statement is canceled once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	stop, err := canceler.Watch(ctx, zap.NewNop(), sqldb, sqldb)
+	if err != nil {
+		t.Fatalf("Watch() unexpected error: %v", err)
+	}
+	cancel()
+	select {
+	case sessionID := <-canceledSessionIDs:
+		if sessionID != 42 {
+			t.Errorf("canceled session ID got %d, want %d", sessionID, 42)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("the statement is not canceled after the context is done")
+	}
+	stop()
+
+	// The statement isn't canceled once stopped.
+	ctx, cancel = context.WithCancel(context.Background())
+	stop, err = canceler.Watch(ctx, zap.NewNop(), sqldb, sqldb)
+	if err != nil {
+		t.Fatalf("Watch() unexpected error: %v", err)
+	}
+	stop()
+	cancel()
+	select {
+	case sessionID := <-canceledSessionIDs:
+		t.Errorf("the statement of session %d is canceled after stopped", sessionID)
+	default:
+	}
+
+	// A nil canceler is a no-op.
+	var nilCanceler *StatementCanceler
+	stop, err = nilCanceler.Watch(context.Background(), zap.NewNop(), sqldb, sqldb)
+	if err != nil {
+		t.Fatalf("Watch() unexpected error: %v", err)
+	}
+	stop()
+}
@@ -0,0 +1,71 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+)
+
+func TestBuildPlanTree(t *testing.T) {
+	tests := []struct {
+		name          string
+		operationList []string
+		depthList     []int
+		want          *db.QueryPlanNode
+		wantErr       bool
+	}{
+		{
+			name:          "single root",
+			operationList: []string{"a", "b", "c", "d"},
+			depthList:     []int{0, 1, 2, 1},
+			want: &db.QueryPlanNode{
+				Operation: "a",
+				Children: []*db.QueryPlanNode{
+					{Operation: "b", Children: []*db.QueryPlanNode{{Operation: "c"}}},
+					{Operation: "d"},
+				},
+			},
+		},
+		{
+			name:          "multiple roots",
+			operationList: []string{"a", "b"},
+			depthList:     []int{0, 0},
+			want: &db.QueryPlanNode{
+				Operation: "Plan",
+				Children:  []*db.QueryPlanNode{{Operation: "a"}, {Operation: "b"}},
+			},
+		},
+		{
+			name:          "skipped level",
+			operationList: []string{"a", "b"},
+			depthList:     []int{0, 2},
+			wantErr:       true,
+		},
+		{
+			name:    "empty",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		var nodeList []*db.QueryPlanNode
+		for _, operation := range test.operationList {
+			nodeList = append(nodeList, &db.QueryPlanNode{Operation: operation})
+		}
+		got, err := BuildPlanTree(nodeList, test.depthList)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("%q: BuildPlanTree() unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("%q: BuildPlanTree() expected error", test.name)
+			continue
+		}
+		if diff := pretty.Diff(got, test.want); len(diff) > 0 {
+			t.Errorf("%q: got %+v, want %+v, diff %+v.", test.name, got, test.want, diff)
+		}
+	}
+}
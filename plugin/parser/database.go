@@ -0,0 +1,302 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/pingcap/parser/ast"
+)
+
+// ExtractDatabaseList returns the databases accessed by the readonly statement, in the order of appearance.
+// The unqualified tables are in the current database, and the statement referencing no table, e.g. "SELECT @@version",
+// accesses the current database. The statements of Postgres and SQLite can only access the current database.
+// The table references of ClickHouse and Snowflake are extracted from the tokens, and it returns an error for the
+// statements whose accessed databases can't be told from the tokens, e.g. SHOW and the table functions.
+func ExtractDatabaseList(engineType db.Type, statement string, currentDatabase string) ([]string, error) {
+	switch engineType {
+	case db.Postgres, db.SQLite:
+		return []string{currentDatabase}, nil
+	case db.MySQL, db.TiDB:
+		nodeList, err := parseMySQL(statement)
+		if err != nil {
+			return nil, err
+		}
+		collector := &databaseCollector{
+			currentDatabase: currentDatabase,
+			databaseMap:     make(map[string]bool),
+		}
+		for _, node := range nodeList {
+			node.Accept(collector)
+		}
+		if len(collector.databaseList) == 0 && currentDatabase != "" {
+			return []string{currentDatabase}, nil
+		}
+		return collector.databaseList, nil
+	case db.ClickHouse, db.Snowflake:
+		return extractTokenDatabaseList(engineType, statement, currentDatabase)
+	}
+	return nil, fmt.Errorf("extracting the accessed databases is not supported for %s", engineType)
+}
+
+var (
+	// fromClauseEndKeywords is the keywords ending the FROM clause, after which the top-level commas don't separate the tables.
+	fromClauseEndKeywords = map[string]bool{
+		"WHERE":     true,
+		"PREWHERE":  true,
+		"GROUP":     true,
+		"HAVING":    true,
+		"ORDER":     true,
+		"LIMIT":     true,
+		"QUALIFY":   true,
+		"WINDOW":    true,
+		"UNION":     true,
+		"EXCEPT":    true,
+		"MINUS":     true,
+		"INTERSECT": true,
+		"SETTINGS":  true,
+		"FORMAT":    true,
+		"SELECT":    true,
+	}
+	// crossDatabaseFunctions is the functions reading the tables whose databases are given in the string arguments,
+	// or reading the other servers. The names are in upper case.
+	crossDatabaseFunctions = map[db.Type]map[string]bool{
+		db.ClickHouse: {
+			"REMOTE":             true,
+			"REMOTESECURE":       true,
+			"CLUSTER":            true,
+			"CLUSTERALLREPLICAS": true,
+			"MERGE":              true,
+			"JOINGET":            true,
+			"JOINGETORNULL":      true,
+		},
+		db.Snowflake: {
+			"IDENTIFIER": true,
+			"TABLE":      true,
+		},
+	}
+)
+
+// extractTokenDatabaseList returns the databases of the tables referenced by the ClickHouse or Snowflake statement.
+// The tables are referenced after FROM and JOIN, the top-level commas in the FROM clause and the leading DESCRIBE and EXISTS,
+// as well as after IN in ClickHouse. The qualified column references only refer to these tables, so they're skipped.
+// The current database is always accessed by the connection.
+func extractTokenDatabaseList(engineType db.Type, statement string, currentDatabase string) ([]string, error) {
+	tokenList, err := tokenize(engineType, statement)
+	if err != nil {
+		return nil, err
+	}
+	var wordList []token
+	for _, token := range tokenList {
+		if token.typ != tokenSpace && token.typ != tokenComment && token.typ != tokenSemicolon {
+			wordList = append(wordList, token)
+		}
+	}
+
+	collector := &databaseCollector{
+		currentDatabase: currentDatabase,
+		databaseMap:     make(map[string]bool),
+	}
+	collector.add(currentDatabase)
+	if len(wordList) == 0 {
+		return collector.databaseList, nil
+	}
+	keyword := func(i int) string {
+		if i < len(wordList) && wordList[i].typ == tokenWord {
+			return strings.ToUpper(wordList[i].text)
+		}
+		return ""
+	}
+	isSymbol := func(i int, symbol string) bool {
+		return i < len(wordList) && wordList[i].typ == tokenSymbol && wordList[i].text == symbol
+	}
+
+	// tableStart is the index of the table reference following the leading DESCRIBE or EXISTS.
+	tableStart := -1
+	switch keyword(0) {
+	case "SHOW":
+		return nil, fmt.Errorf("extracting the accessed databases of SHOW statement is not supported for %s", engineType)
+	case "DESC", "DESCRIBE":
+		tableStart = 1
+		if k := keyword(1); k == "TABLE" || k == "VIEW" {
+			tableStart = 2
+		}
+	case "EXISTS":
+		tableStart = 1
+		if keyword(tableStart) == "TEMPORARY" {
+			tableStart++
+		}
+		switch keyword(tableStart) {
+		case "DATABASE":
+			name, ok := identifierName(engineType, wordList, tableStart+1)
+			if !ok {
+				return nil, fmt.Errorf("failed to extract the database of %q", statement)
+			}
+			collector.add(name)
+			return collector.databaseList, nil
+		case "TABLE", "DICTIONARY", "VIEW":
+			tableStart++
+		}
+	}
+
+	// fromClauseList is whether it's in the FROM clause at each parenthesis depth.
+	fromClauseList := []bool{false}
+	for i := 0; i < len(wordList); i++ {
+		depth := len(fromClauseList) - 1
+		switch {
+		case isSymbol(i, "("):
+			fromClauseList = append(fromClauseList, false)
+			continue
+		case isSymbol(i, ")"):
+			if depth > 0 {
+				fromClauseList = fromClauseList[:depth]
+			}
+			continue
+		}
+		k := keyword(i)
+		if crossDatabaseFunctions[engineType][k] && isSymbol(i+1, "(") {
+			return nil, fmt.Errorf("extracting the accessed databases of function %s is not supported for %s", wordList[i].text, engineType)
+		}
+		if engineType == db.ClickHouse && strings.HasPrefix(k, "DICT") && isSymbol(i+1, "(") {
+			return nil, fmt.Errorf("extracting the accessed databases of function %s is not supported for %s", wordList[i].text, engineType)
+		}
+		if fromClauseEndKeywords[k] {
+			fromClauseList[depth] = false
+		}
+
+		isTableReference := false
+		switch {
+		case i == tableStart:
+			isTableReference = true
+		case k == "FROM":
+			fromClauseList[depth] = true
+			isTableReference = true
+		case k == "JOIN":
+			// ARRAY JOIN in ClickHouse joins the array columns instead of the tables.
+			isTableReference = keyword(i-1) != "ARRAY"
+		case k == "IN" && engineType == db.ClickHouse:
+			// "x IN db.t" in ClickHouse reads the table.
+			isTableReference = !isSymbol(i+1, "(")
+		case isSymbol(i, ",") && fromClauseList[depth]:
+			isTableReference = true
+		}
+		if !isTableReference {
+			continue
+		}
+
+		start := i + 1
+		if i == tableStart {
+			start = i
+		}
+		// The subquery is extracted in the following tokens.
+		if isSymbol(start, "(") {
+			continue
+		}
+		partList, end := qualifiedNamePartList(engineType, wordList, start)
+		if len(partList) == 0 {
+			return nil, fmt.Errorf("failed to extract the table referenced at offset %d of %q", wordList[i].pos, statement)
+		}
+		if isSymbol(end, "(") {
+			return nil, fmt.Errorf("extracting the accessed databases of table function %s is not supported for %s", strings.Join(partList, "."), engineType)
+		}
+		// The database of the table is qualified by "db.t" in ClickHouse, and by "db.schema.t" in Snowflake.
+		databasePartCount := 2
+		if engineType == db.Snowflake {
+			databasePartCount = 3
+		}
+		switch {
+		case len(partList) < databasePartCount:
+			collector.add(currentDatabase)
+		case len(partList) == databasePartCount:
+			collector.add(partList[0])
+		default:
+			return nil, fmt.Errorf("failed to extract the database of table %s", strings.Join(partList, "."))
+		}
+		i = end - 1
+	}
+	return collector.databaseList, nil
+}
+
+// qualifiedNamePartList returns the parts of the dot-separated name starting at the token index, e.g. "db"."t",
+// and the index of the token after the name. It returns nil if the token isn't an identifier.
+func qualifiedNamePartList(engineType db.Type, wordList []token, start int) ([]string, int) {
+	var partList []string
+	i := start
+	for {
+		name, ok := identifierName(engineType, wordList, i)
+		if !ok {
+			return nil, start
+		}
+		partList = append(partList, name)
+		i++
+		if i+1 < len(wordList) && wordList[i].typ == tokenSymbol && wordList[i].text == "." {
+			i++
+			continue
+		}
+		return partList, i
+	}
+}
+
+// identifierName returns the name of the identifier token at the index. The unquoted identifiers are case-insensitive
+// and resolved to upper case in Snowflake.
+func identifierName(engineType db.Type, wordList []token, i int) (string, bool) {
+	if i >= len(wordList) {
+		return "", false
+	}
+	text := wordList[i].text
+	switch wordList[i].typ {
+	case tokenWord:
+		// The number isn't an identifier.
+		if text[0] >= '0' && text[0] <= '9' {
+			return "", false
+		}
+		if engineType == db.Snowflake {
+			return strings.ToUpper(text), true
+		}
+		return text, true
+	case tokenQuotedIdentifier:
+		quote := text[:1]
+		return strings.ReplaceAll(text[1:len(text)-1], quote+quote, quote), true
+	}
+	return "", false
+}
+
+// databaseCollector collects the databases of the tables in the MySQL statement.
+type databaseCollector struct {
+	currentDatabase string
+	databaseMap     map[string]bool
+	databaseList    []string
+}
+
+func (c *databaseCollector) add(database string) {
+	// The unqualified table without the current database fails to execute, so it accesses no database.
+	if database == "" || c.databaseMap[database] {
+		return
+	}
+	c.databaseMap[database] = true
+	c.databaseList = append(c.databaseList, database)
+}
+
+// Enter implements ast.Visitor interface.
+func (c *databaseCollector) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.TableName:
+		if node.Schema.O != "" {
+			c.add(node.Schema.O)
+		} else {
+			c.add(c.currentDatabase)
+		}
+	case *ast.ShowStmt:
+		if node.DBName != "" {
+			c.add(node.DBName)
+		} else if node.Tp == ast.ShowTables || node.Tp == ast.ShowTableStatus {
+			c.add(c.currentDatabase)
+		}
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor interface.
+func (*databaseCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
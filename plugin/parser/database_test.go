@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+)
+
+func TestExtractDatabaseList(t *testing.T) {
+	tests := []struct {
+		engineType db.Type
+		statement  string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			engineType: db.MySQL,
+			statement:  "SELECT * FROM t1 JOIN hr.salary s ON t1.id = s.user_id WHERE t1.id IN (SELECT user_id FROM audit.log)",
+			want:       []string{"shop", "hr", "audit"},
+		},
+		{
+			// The statement referencing no table accesses the current database.
+			engineType: db.MySQL,
+			statement:  "SELECT 1",
+			want:       []string{"shop"},
+		},
+		{
+			engineType: db.MySQL,
+			statement:  "SELECT @@version",
+			want:       []string{"shop"},
+		},
+		{
+			engineType: db.MySQL,
+			statement:  "SHOW TABLES FROM hr",
+			want:       []string{"hr"},
+		},
+		{
+			engineType: db.MySQL,
+			statement:  "DESC hr.salary",
+			want:       []string{"hr"},
+		},
+		{
+			engineType: db.MySQL,
+			statement:  "SHOW DATABASES",
+			want:       []string{"shop"},
+		},
+		{
+			engineType: db.Postgres,
+			statement:  "SELECT * FROM public.t1",
+			want:       []string{"shop"},
+		},
+		{
+			engineType: db.ClickHouse,
+			statement:  "SELECT s.amount, `audit`.log.id FROM hr.salary s JOIN `audit`.log ON s.id = log.id",
+			want:       []string{"shop", "hr", "audit"},
+		},
+		{
+			engineType: db.ClickHouse,
+			statement:  "SELECT * FROM t1, (SELECT * FROM hr.salary) s, audit.log WHERE id IN finance.payment AND t1.a IN (1, 2)",
+			want:       []string{"shop", "hr", "audit", "finance"},
+		},
+		{
+			// The commas after the FROM clause don't separate the tables.
+			engineType: db.ClickHouse,
+			statement:  "SELECT a FROM t1 SAMPLE 0.1 ARRAY JOIN nested.list ORDER BY a, t1.b",
+			want:       []string{"shop"},
+		},
+		{
+			engineType: db.ClickHouse,
+			statement:  "DESCRIBE TABLE hr.salary",
+			want:       []string{"shop", "hr"},
+		},
+		{
+			engineType: db.ClickHouse,
+			statement:  "EXISTS DATABASE hr",
+			want:       []string{"shop", "hr"},
+		},
+		{
+			engineType: db.ClickHouse,
+			statement:  "SELECT * FROM remote('127.0.0.1', hr.salary)",
+			wantErr:    true,
+		},
+		{
+			engineType: db.ClickHouse,
+			statement:  "SELECT dictGet('hr.dict', 'amount', 1)",
+			wantErr:    true,
+		},
+		{
+			engineType: db.ClickHouse,
+			statement:  "SHOW TABLES FROM hr",
+			wantErr:    true,
+		},
+		{
+			// The database is qualified by the three-part name in Snowflake, and the unquoted identifiers are in upper case.
+			engineType: db.Snowflake,
+			statement:  `SELECT * FROM public.t1, hr.public.salary JOIN "audit".public.log ON salary.id = log.id`,
+			want:       []string{"shop", "HR", "audit"},
+		},
+		{
+			engineType: db.Snowflake,
+			statement:  "SELECT * FROM TABLE(hr.public.salary_func())",
+			wantErr:    true,
+		},
+		{
+			engineType: db.Snowflake,
+			statement:  "SELECT * FROM @hr.public.stage",
+			wantErr:    true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ExtractDatabaseList(test.engineType, test.statement, "shop")
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("%s %q: ExtractDatabaseList() unexpected error: %v", test.engineType, test.statement, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("%s %q: ExtractDatabaseList() expected error", test.engineType, test.statement)
+			continue
+		}
+		if diff := pretty.Diff(got, test.want); len(diff) > 0 {
+			t.Errorf("%s %q: got %+v, want %+v, diff %+v.", test.engineType, test.statement, got, test.want, diff)
+		}
+	}
+}
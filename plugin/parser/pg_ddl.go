@@ -0,0 +1,979 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// PGStmt is a Postgres statement parsed by ParsePGStatements.
+type PGStmt interface {
+	// Text returns the statement text without the trailing semicolon.
+	Text() string
+}
+
+// pgStmtText is embedded in the statements to implement PGStmt.
+type pgStmtText struct {
+	text string
+}
+
+// Text implements PGStmt.
+func (s *pgStmtText) Text() string {
+	return s.text
+}
+
+// PGTableName is the table name, which may be qualified by the schema.
+type PGTableName struct {
+	Schema string
+	Name   string
+}
+
+// PGConstraintType is the type of the Postgres constraint.
+type PGConstraintType string
+
+const (
+	// PGConstraintPrimaryKey is the PRIMARY KEY constraint.
+	PGConstraintPrimaryKey PGConstraintType = "PRIMARY KEY"
+	// PGConstraintUnique is the UNIQUE constraint.
+	PGConstraintUnique PGConstraintType = "UNIQUE"
+	// PGConstraintForeignKey is the FOREIGN KEY constraint, or the REFERENCES column constraint.
+	PGConstraintForeignKey PGConstraintType = "FOREIGN KEY"
+	// PGConstraintCheck is the CHECK constraint.
+	PGConstraintCheck PGConstraintType = "CHECK"
+	// PGConstraintExclude is the EXCLUDE constraint.
+	PGConstraintExclude PGConstraintType = "EXCLUDE"
+)
+
+// PGConstraint is the table constraint, or the column constraint other than NOT NULL, NULL and DEFAULT.
+type PGConstraint struct {
+	// Name is empty if the constraint is unnamed, whose name is generated by Postgres.
+	Name string
+	Type PGConstraintType
+	// ColumnList is the constrained columns, it's the column itself for the column constraint.
+	ColumnList []string
+	// ReferencedTable and ReferencedColumnList are only set for the foreign key.
+	// ReferencedColumnList is empty if the foreign key references the primary key.
+	ReferencedTable      *PGTableName
+	ReferencedColumnList []string
+}
+
+// PGColumnDef is the column definition of CREATE TABLE and ALTER TABLE ... ADD COLUMN.
+type PGColumnDef struct {
+	Name string
+	// Type is the data type text, e.g. "character varying(20)".
+	Type           string
+	NotNull        bool
+	HasDefault     bool
+	ConstraintList []*PGConstraint
+}
+
+// PGCreateTableStmt is the CREATE TABLE statement.
+// The column and the constraint lists are empty for CREATE TABLE ... AS, OF and PARTITION OF.
+type PGCreateTableStmt struct {
+	pgStmtText
+	Table          *PGTableName
+	IfNotExists    bool
+	ColumnList     []*PGColumnDef
+	ConstraintList []*PGConstraint
+}
+
+// PGAlterTableStmt is the ALTER TABLE statement.
+type PGAlterTableStmt struct {
+	pgStmtText
+	Table      *PGTableName
+	ActionList []PGAlterTableAction
+}
+
+// PGAlterTableAction is an action of the ALTER TABLE statement.
+type PGAlterTableAction interface {
+	// Text returns the action text.
+	Text() string
+}
+
+// PGRenameTableAction is ALTER TABLE ... RENAME TO.
+type PGRenameTableAction struct {
+	pgStmtText
+	NewName string
+}
+
+// PGRenameColumnAction is ALTER TABLE ... RENAME [COLUMN] ... TO.
+type PGRenameColumnAction struct {
+	pgStmtText
+	Column  string
+	NewName string
+}
+
+// PGRenameConstraintAction is ALTER TABLE ... RENAME CONSTRAINT ... TO.
+type PGRenameConstraintAction struct {
+	pgStmtText
+	Constraint string
+	NewName    string
+}
+
+// PGAddColumnAction is ALTER TABLE ... ADD [COLUMN].
+type PGAddColumnAction struct {
+	pgStmtText
+	IfNotExists bool
+	Column      *PGColumnDef
+}
+
+// PGAddConstraintAction is ALTER TABLE ... ADD [CONSTRAINT name] followed by the table constraint.
+type PGAddConstraintAction struct {
+	pgStmtText
+	Constraint *PGConstraint
+	// NotValid is whether the constraint is added with NOT VALID, which skips checking the existing rows.
+	NotValid bool
+}
+
+// PGDropColumnAction is ALTER TABLE ... DROP [COLUMN].
+type PGDropColumnAction struct {
+	pgStmtText
+	IfExists bool
+	Column   string
+}
+
+// PGDropConstraintAction is ALTER TABLE ... DROP CONSTRAINT.
+type PGDropConstraintAction struct {
+	pgStmtText
+	IfExists   bool
+	Constraint string
+}
+
+// PGAlterColumnTypeAction is ALTER TABLE ... ALTER [COLUMN] ... [SET DATA] TYPE.
+type PGAlterColumnTypeAction struct {
+	pgStmtText
+	Column string
+	// Type is the new data type text.
+	Type string
+}
+
+// PGSetNotNullAction is ALTER TABLE ... ALTER [COLUMN] ... SET NOT NULL.
+type PGSetNotNullAction struct {
+	pgStmtText
+	Column string
+}
+
+// PGUnknownAction is the ALTER TABLE action not recognized by the parser.
+type PGUnknownAction struct {
+	pgStmtText
+}
+
+// PGCreateIndexStmt is the CREATE INDEX statement.
+type PGCreateIndexStmt struct {
+	pgStmtText
+	// Name is empty if the index is unnamed, whose name is generated by Postgres.
+	Name         string
+	Table        *PGTableName
+	Unique       bool
+	Concurrently bool
+	IfNotExists  bool
+	// ColumnList is the indexed columns, the expressions are skipped.
+	ColumnList []string
+}
+
+// PGRenameIndexStmt is the ALTER INDEX ... RENAME TO statement.
+type PGRenameIndexStmt struct {
+	pgStmtText
+	// Index is the renamed index, which may be qualified by the schema.
+	Index    *PGTableName
+	IfExists bool
+	NewName  string
+}
+
+// PGObjectType is the type of the object dropped by the DROP statement.
+type PGObjectType string
+
+const (
+	// PGObjectDatabase is the database.
+	PGObjectDatabase PGObjectType = "DATABASE"
+	// PGObjectSchema is the schema.
+	PGObjectSchema PGObjectType = "SCHEMA"
+	// PGObjectTable is the table.
+	PGObjectTable PGObjectType = "TABLE"
+	// PGObjectView is the view.
+	PGObjectView PGObjectType = "VIEW"
+	// PGObjectMaterializedView is the materialized view.
+	PGObjectMaterializedView PGObjectType = "MATERIALIZED VIEW"
+	// PGObjectIndex is the index.
+	PGObjectIndex PGObjectType = "INDEX"
+)
+
+// PGDropStmt is the DROP statement of the database, the schema, the table, the view and the index.
+type PGDropStmt struct {
+	pgStmtText
+	ObjectType   PGObjectType
+	IfExists     bool
+	Concurrently bool
+	// NameList is the dropped objects, the schema is only set for the qualified names of the tables, the views and the indexes.
+	NameList []*PGTableName
+}
+
+// PGUnknownStmt is the statement not recognized by the parser.
+type PGUnknownStmt struct {
+	pgStmtText
+}
+
+var (
+	// pgColumnConstraintKeywords is the keywords starting the column constraints, which end the data type and the default expression.
+	pgColumnConstraintKeywords = map[string]bool{
+		"CONSTRAINT": true,
+		"NOT":        true,
+		"NULL":       true,
+		"DEFAULT":    true,
+		"PRIMARY":    true,
+		"UNIQUE":     true,
+		"REFERENCES": true,
+		"CHECK":      true,
+		"COLLATE":    true,
+		"GENERATED":  true,
+		"DEFERRABLE": true,
+		"INITIALLY":  true,
+	}
+	// pgTableConstraintKeywords is the keywords starting the table constraints.
+	pgTableConstraintKeywords = map[string]bool{
+		"CONSTRAINT": true,
+		"PRIMARY":    true,
+		"UNIQUE":     true,
+		"FOREIGN":    true,
+		"CHECK":      true,
+		"EXCLUDE":    true,
+	}
+)
+
+// ParsePGStatements parses the Postgres statements. It's a keyword-based parser built on the tokenizer, which recognizes
+// the CREATE TABLE, ALTER TABLE, CREATE INDEX, ALTER INDEX ... RENAME TO and DROP statements, and returns PGUnknownStmt
+// for the other statements.
+// It returns an error for the unterminated quoted text and comments, the unbalanced parentheses, and the malformed
+// recognized statements, but it doesn't validate the syntax of the unknown statements and the expressions.
+func ParsePGStatements(statement string) ([]PGStmt, error) {
+	tokenList, err := tokenize(db.Postgres, statement)
+	if err != nil {
+		return nil, err
+	}
+
+	var stmtList []PGStmt
+	var codeList []token
+	flush := func() error {
+		if len(codeList) == 0 {
+			return nil
+		}
+		p := &pgParser{text: statement, tokenList: codeList}
+		stmt, err := p.parseStatement()
+		if err != nil {
+			return err
+		}
+		stmtList = append(stmtList, stmt)
+		codeList = nil
+		return nil
+	}
+	for _, token := range tokenList {
+		switch token.typ {
+		case tokenSpace, tokenComment:
+		case tokenSemicolon:
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		default:
+			codeList = append(codeList, token)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return stmtList, nil
+}
+
+// IsPGConcurrentIndexStmt returns whether the statement is CREATE INDEX CONCURRENTLY or DROP INDEX CONCURRENTLY,
+// which can't run inside a transaction block.
+func IsPGConcurrentIndexStmt(stmt PGStmt) bool {
+	switch node := stmt.(type) {
+	case *PGCreateIndexStmt:
+		return node.Concurrently
+	case *PGDropStmt:
+		return node.Concurrently
+	}
+	return false
+}
+
+// pgParser parses a statement from its code tokens without the spaces, the comments and the semicolon.
+type pgParser struct {
+	text      string
+	tokenList []token
+	pos       int
+}
+
+func (p *pgParser) parseStatement() (PGStmt, error) {
+	if err := p.checkParentheses(); err != nil {
+		return nil, err
+	}
+	text := p.textOf(p.tokenList)
+	switch {
+	case p.acceptKeyword("CREATE"):
+		switch {
+		case p.peekKeyword("TABLE") || p.peekKeyword("UNLOGGED", "TABLE") || p.peekTemporaryTable():
+			for !p.acceptKeyword("TABLE") {
+				p.pos++
+			}
+			return p.parseCreateTable(text)
+		case p.peekKeyword("INDEX") || p.peekKeyword("UNIQUE", "INDEX"):
+			return p.parseCreateIndex(text)
+		}
+	case p.acceptKeyword("ALTER", "TABLE"):
+		return p.parseAlterTable(text)
+	case p.acceptKeyword("ALTER", "INDEX"):
+		return p.parseAlterIndex(text)
+	case p.acceptKeyword("DROP"):
+		for _, objectType := range []PGObjectType{PGObjectDatabase, PGObjectSchema, PGObjectTable, PGObjectView, PGObjectMaterializedView, PGObjectIndex} {
+			if p.acceptKeyword(strings.Fields(string(objectType))...) {
+				return p.parseDrop(text, objectType)
+			}
+		}
+	}
+	return &PGUnknownStmt{pgStmtText{text: text}}, nil
+}
+
+// parseDrop parses the statement after "DROP" and the object type.
+func (p *pgParser) parseDrop(text string, objectType PGObjectType) (PGStmt, error) {
+	stmt := &PGDropStmt{pgStmtText: pgStmtText{text: text}, ObjectType: objectType}
+	if objectType == PGObjectIndex {
+		stmt.Concurrently = p.acceptKeyword("CONCURRENTLY")
+	}
+	stmt.IfExists = p.acceptKeyword("IF", "EXISTS")
+	for {
+		var name *PGTableName
+		var err error
+		if objectType == PGObjectDatabase || objectType == PGObjectSchema {
+			name = &PGTableName{}
+			name.Name, err = p.parseIdentifier()
+		} else {
+			name, err = p.parseTableName()
+		}
+		if err != nil {
+			return nil, err
+		}
+		stmt.NameList = append(stmt.NameList, name)
+		// DROP DATABASE only drops one database.
+		if objectType == PGObjectDatabase || !p.acceptSymbol(",") {
+			break
+		}
+	}
+	// Skip CASCADE, RESTRICT and the options of DROP DATABASE.
+	return stmt, nil
+}
+
+// peekTemporaryTable returns whether the tokens start with "[GLOBAL | LOCAL] {TEMPORARY | TEMP} TABLE".
+func (p *pgParser) peekTemporaryTable() bool {
+	for _, prefix := range []string{"", "GLOBAL", "LOCAL"} {
+		for _, temporary := range []string{"TEMPORARY", "TEMP"} {
+			keywordList := []string{temporary, "TABLE"}
+			if prefix != "" {
+				keywordList = append([]string{prefix}, keywordList...)
+			}
+			if p.peekKeyword(keywordList...) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseCreateTable parses the statement after "CREATE ... TABLE".
+func (p *pgParser) parseCreateTable(text string) (PGStmt, error) {
+	stmt := &PGCreateTableStmt{pgStmtText: pgStmtText{text: text}}
+	stmt.IfNotExists = p.acceptKeyword("IF", "NOT", "EXISTS")
+	table, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Table = table
+	if !p.peekSymbol("(") {
+		return stmt, nil
+	}
+
+	elementList, err := p.parseParenthesizedList()
+	if err != nil {
+		return nil, err
+	}
+	for _, element := range elementList {
+		ep := &pgParser{text: p.text, tokenList: element}
+		switch {
+		case ep.peekKeyword("LIKE"):
+		case pgTableConstraintKeywords[ep.peekWord()]:
+			constraint, err := ep.parseTableConstraint()
+			if err != nil {
+				return nil, err
+			}
+			stmt.ConstraintList = append(stmt.ConstraintList, constraint)
+		default:
+			column, err := ep.parseColumnDef()
+			if err != nil {
+				return nil, err
+			}
+			stmt.ColumnList = append(stmt.ColumnList, column)
+		}
+	}
+	return stmt, nil
+}
+
+// parseAlterTable parses the statement after "ALTER TABLE".
+func (p *pgParser) parseAlterTable(text string) (PGStmt, error) {
+	stmt := &PGAlterTableStmt{pgStmtText: pgStmtText{text: text}}
+	p.acceptKeyword("IF", "EXISTS")
+	p.acceptKeyword("ONLY")
+	table, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Table = table
+	p.acceptSymbol("*")
+
+	for _, actionTokenList := range splitTopLevel(p.tokenList[p.pos:]) {
+		if len(actionTokenList) == 0 {
+			return nil, p.errorAt(p.pos)
+		}
+		ap := &pgParser{text: p.text, tokenList: actionTokenList}
+		action, err := ap.parseAlterTableAction()
+		if err != nil {
+			return nil, err
+		}
+		stmt.ActionList = append(stmt.ActionList, action)
+	}
+	if len(stmt.ActionList) == 0 {
+		return nil, p.errorAt(p.pos)
+	}
+	return stmt, nil
+}
+
+// parseAlterIndex parses the statement after "ALTER INDEX", the statements other than RENAME TO are unknown.
+func (p *pgParser) parseAlterIndex(text string) (PGStmt, error) {
+	stmt := &PGRenameIndexStmt{pgStmtText: pgStmtText{text: text}}
+	stmt.IfExists = p.acceptKeyword("IF", "EXISTS")
+	index, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	if !p.acceptKeyword("RENAME", "TO") {
+		return &PGUnknownStmt{pgStmtText{text: text}}, nil
+	}
+	stmt.Index = index
+	if stmt.NewName, err = p.parseIdentifier(); err != nil {
+		return nil, err
+	}
+	return stmt, p.expectEnd()
+}
+
+func (p *pgParser) parseAlterTableAction() (PGAlterTableAction, error) {
+	text := pgStmtText{text: p.textOf(p.tokenList)}
+	switch {
+	case p.acceptKeyword("RENAME", "TO"):
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		return &PGRenameTableAction{pgStmtText: text, NewName: name}, p.expectEnd()
+	case p.acceptKeyword("RENAME", "CONSTRAINT"):
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptKeyword("TO") {
+			return nil, p.errorAt(p.pos)
+		}
+		newName, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		return &PGRenameConstraintAction{pgStmtText: text, Constraint: name, NewName: newName}, p.expectEnd()
+	case p.acceptKeyword("RENAME"):
+		p.acceptKeyword("COLUMN")
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptKeyword("TO") {
+			return nil, p.errorAt(p.pos)
+		}
+		newName, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		return &PGRenameColumnAction{pgStmtText: text, Column: name, NewName: newName}, p.expectEnd()
+	case p.acceptKeyword("ADD"):
+		if pgTableConstraintKeywords[p.peekWord()] {
+			// NOT VALID is at the end of the constraint, which is skipped by parseTableConstraint.
+			notValid := p.hasSuffixKeyword("NOT", "VALID")
+			constraint, err := p.parseTableConstraint()
+			if err != nil {
+				return nil, err
+			}
+			return &PGAddConstraintAction{pgStmtText: text, Constraint: constraint, NotValid: notValid}, nil
+		}
+		p.acceptKeyword("COLUMN")
+		action := &PGAddColumnAction{pgStmtText: text}
+		action.IfNotExists = p.acceptKeyword("IF", "NOT", "EXISTS")
+		column, err := p.parseColumnDef()
+		if err != nil {
+			return nil, err
+		}
+		action.Column = column
+		return action, nil
+	case p.acceptKeyword("DROP", "CONSTRAINT"):
+		action := &PGDropConstraintAction{pgStmtText: text}
+		action.IfExists = p.acceptKeyword("IF", "EXISTS")
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		action.Constraint = name
+		return action, nil
+	case p.acceptKeyword("DROP"):
+		p.acceptKeyword("COLUMN")
+		action := &PGDropColumnAction{pgStmtText: text}
+		action.IfExists = p.acceptKeyword("IF", "EXISTS")
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		action.Column = name
+		return action, nil
+	case p.acceptKeyword("ALTER"):
+		p.acceptKeyword("COLUMN")
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case p.acceptKeyword("TYPE") || p.acceptKeyword("SET", "DATA", "TYPE"):
+			typeStart := p.pos
+			p.skipUntil(map[string]bool{"COLLATE": true, "USING": true})
+			if p.pos == typeStart {
+				return nil, p.errorAt(p.pos)
+			}
+			return &PGAlterColumnTypeAction{pgStmtText: text, Column: name, Type: p.textOf(p.tokenList[typeStart:p.pos])}, nil
+		case p.acceptKeyword("SET", "NOT", "NULL"):
+			return &PGSetNotNullAction{pgStmtText: text, Column: name}, p.expectEnd()
+		}
+	}
+	return &PGUnknownAction{pgStmtText: text}, nil
+}
+
+// parseCreateIndex parses the statement after "CREATE".
+func (p *pgParser) parseCreateIndex(text string) (PGStmt, error) {
+	stmt := &PGCreateIndexStmt{pgStmtText: pgStmtText{text: text}}
+	stmt.Unique = p.acceptKeyword("UNIQUE")
+	p.acceptKeyword("INDEX")
+	stmt.Concurrently = p.acceptKeyword("CONCURRENTLY")
+	stmt.IfNotExists = p.acceptKeyword("IF", "NOT", "EXISTS")
+	if !p.peekKeyword("ON") {
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Name = name
+	}
+	if !p.acceptKeyword("ON") {
+		return nil, p.errorAt(p.pos)
+	}
+	p.acceptKeyword("ONLY")
+	table, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Table = table
+	if p.acceptKeyword("USING") {
+		if _, err := p.parseIdentifier(); err != nil {
+			return nil, err
+		}
+	}
+	elementList, err := p.parseParenthesizedList()
+	if err != nil {
+		return nil, err
+	}
+	for _, element := range elementList {
+		// The expression element is either parenthesized or a function call.
+		if len(element) == 0 || element[0].typ == tokenSymbol || (len(element) > 1 && element[1].text == "(") {
+			continue
+		}
+		ep := &pgParser{text: p.text, tokenList: element}
+		column, err := ep.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		stmt.ColumnList = append(stmt.ColumnList, column)
+	}
+	return stmt, nil
+}
+
+// parseColumnDef parses the column definition, the tokens must end with the definition.
+func (p *pgParser) parseColumnDef() (*PGColumnDef, error) {
+	name, err := p.parseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	column := &PGColumnDef{Name: name}
+	typeStart := p.pos
+	p.skipUntil(pgColumnConstraintKeywords)
+	if p.pos == typeStart {
+		return nil, p.errorAt(p.pos)
+	}
+	column.Type = p.textOf(p.tokenList[typeStart:p.pos])
+
+	constraintName := ""
+	for !p.atEnd() {
+		switch {
+		case p.acceptKeyword("CONSTRAINT"):
+			if constraintName, err = p.parseIdentifier(); err != nil {
+				return nil, err
+			}
+			continue
+		case p.acceptKeyword("NOT", "NULL"):
+			column.NotNull = true
+		case p.acceptKeyword("NULL"):
+		case p.acceptKeyword("DEFAULT"):
+			column.HasDefault = true
+			p.skipUntil(pgColumnConstraintKeywords)
+		case p.acceptKeyword("PRIMARY", "KEY"):
+			column.ConstraintList = append(column.ConstraintList, &PGConstraint{Name: constraintName, Type: PGConstraintPrimaryKey, ColumnList: []string{name}})
+			p.skipUntil(pgColumnConstraintKeywords)
+		case p.acceptKeyword("UNIQUE"):
+			column.ConstraintList = append(column.ConstraintList, &PGConstraint{Name: constraintName, Type: PGConstraintUnique, ColumnList: []string{name}})
+			p.skipUntil(pgColumnConstraintKeywords)
+		case p.acceptKeyword("REFERENCES"):
+			constraint := &PGConstraint{Name: constraintName, Type: PGConstraintForeignKey, ColumnList: []string{name}}
+			if err := p.parseReferences(constraint); err != nil {
+				return nil, err
+			}
+			column.ConstraintList = append(column.ConstraintList, constraint)
+			p.skipUntil(pgColumnConstraintKeywords)
+		case p.acceptKeyword("CHECK"):
+			column.ConstraintList = append(column.ConstraintList, &PGConstraint{Name: constraintName, Type: PGConstraintCheck, ColumnList: []string{name}})
+			p.skipUntil(pgColumnConstraintKeywords)
+		case p.acceptKeyword("GENERATED"):
+			// The generated column and the identity column have the values without the DEFAULT.
+			column.HasDefault = true
+			p.skipUntil(pgColumnConstraintKeywords)
+		default:
+			// COLLATE, DEFERRABLE and INITIALLY.
+			p.pos++
+			p.skipUntil(pgColumnConstraintKeywords)
+		}
+		constraintName = ""
+	}
+	return column, nil
+}
+
+// parseTableConstraint parses the table constraint starting with either CONSTRAINT or the constraint type.
+func (p *pgParser) parseTableConstraint() (*PGConstraint, error) {
+	constraint := &PGConstraint{}
+	if p.acceptKeyword("CONSTRAINT") {
+		name, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		constraint.Name = name
+	}
+
+	var err error
+	switch {
+	case p.acceptKeyword("PRIMARY", "KEY"):
+		constraint.Type = PGConstraintPrimaryKey
+		constraint.ColumnList, err = p.parseIdentifierList()
+	case p.acceptKeyword("UNIQUE"):
+		constraint.Type = PGConstraintUnique
+		if p.acceptKeyword("NULLS") {
+			p.acceptKeyword("NOT")
+			p.acceptKeyword("DISTINCT")
+		}
+		constraint.ColumnList, err = p.parseIdentifierList()
+	case p.acceptKeyword("FOREIGN", "KEY"):
+		constraint.Type = PGConstraintForeignKey
+		if constraint.ColumnList, err = p.parseIdentifierList(); err != nil {
+			return nil, err
+		}
+		if !p.acceptKeyword("REFERENCES") {
+			return nil, p.errorAt(p.pos)
+		}
+		err = p.parseReferences(constraint)
+	case p.acceptKeyword("CHECK"):
+		constraint.Type = PGConstraintCheck
+	case p.acceptKeyword("EXCLUDE"):
+		constraint.Type = PGConstraintExclude
+	default:
+		return nil, p.errorAt(p.pos)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Skip the constraint options such as INCLUDE, DEFERRABLE and ON DELETE.
+	p.pos = len(p.tokenList)
+	return constraint, nil
+}
+
+// parseReferences parses the referenced table and columns after REFERENCES.
+func (p *pgParser) parseReferences(constraint *PGConstraint) error {
+	table, err := p.parseTableName()
+	if err != nil {
+		return err
+	}
+	constraint.ReferencedTable = table
+	if p.peekSymbol("(") {
+		if constraint.ReferencedColumnList, err = p.parseIdentifierList(); err != nil {
+			return err
+		}
+	}
+	// Skip the match type and the referential actions, where "SET NULL" and "SET DEFAULT" aren't the column constraints.
+	for {
+		switch {
+		case p.acceptKeyword("MATCH"):
+			p.pos++
+		case p.acceptKeyword("ON", "DELETE") || p.acceptKeyword("ON", "UPDATE"):
+			switch {
+			case p.acceptKeyword("SET", "NULL") || p.acceptKeyword("SET", "DEFAULT"):
+				if p.peekSymbol("(") {
+					if _, err := p.parseIdentifierList(); err != nil {
+						return err
+					}
+				}
+			case p.acceptKeyword("NO", "ACTION"), p.acceptKeyword("RESTRICT"), p.acceptKeyword("CASCADE"):
+			default:
+				return p.errorAt(p.pos)
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// parseTableName parses the table name which may be qualified by the schema.
+func (p *pgParser) parseTableName() (*PGTableName, error) {
+	name, err := p.parseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	if !p.acceptSymbol(".") {
+		return &PGTableName{Name: name}, nil
+	}
+	table, err := p.parseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	return &PGTableName{Schema: name, Name: table}, nil
+}
+
+// parseIdentifier parses the identifier. The unquoted identifier is folded to the lower case as Postgres does.
+func (p *pgParser) parseIdentifier() (string, error) {
+	if p.atEnd() {
+		return "", p.errorAt(p.pos)
+	}
+	t := p.tokenList[p.pos]
+	switch t.typ {
+	case tokenWord:
+		p.pos++
+		return strings.ToLower(t.text), nil
+	case tokenQuotedIdentifier:
+		p.pos++
+		return strings.ReplaceAll(t.text[1:len(t.text)-1], `""`, `"`), nil
+	}
+	return "", p.errorAt(p.pos)
+}
+
+// parseIdentifierList parses the parenthesized identifier list, e.g. "(a, b)".
+// The element is taken by its leading identifier, e.g. "a" of "a DESC".
+func (p *pgParser) parseIdentifierList() ([]string, error) {
+	elementList, err := p.parseParenthesizedList()
+	if err != nil {
+		return nil, err
+	}
+	var identifierList []string
+	for _, element := range elementList {
+		ep := &pgParser{text: p.text, tokenList: element}
+		identifier, err := ep.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		identifierList = append(identifierList, identifier)
+	}
+	return identifierList, nil
+}
+
+// parseParenthesizedList parses the parenthesized list, and returns the tokens of the elements separated by the top-level commas.
+func (p *pgParser) parseParenthesizedList() ([][]token, error) {
+	if !p.peekSymbol("(") {
+		return nil, p.errorAt(p.pos)
+	}
+	start := p.pos + 1
+	depth := 0
+	for ; p.pos < len(p.tokenList); p.pos++ {
+		switch p.tokenList[p.pos].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	elementList := splitTopLevel(p.tokenList[start:p.pos])
+	p.pos++
+	// The empty element is either the empty list or the element between the extra commas.
+	i := start
+	for _, element := range elementList {
+		if len(element) == 0 {
+			return nil, p.errorAt(i)
+		}
+		i += len(element) + 1
+	}
+	return elementList, nil
+}
+
+// skipUntil skips the tokens until the keyword in the set at the top level.
+func (p *pgParser) skipUntil(keywordSet map[string]bool) {
+	depth := 0
+	for ; p.pos < len(p.tokenList); p.pos++ {
+		t := p.tokenList[p.pos]
+		switch {
+		case t.typ == tokenSymbol && t.text == "(":
+			depth++
+		case t.typ == tokenSymbol && t.text == ")":
+			depth--
+		case depth == 0 && t.typ == tokenWord && keywordSet[strings.ToUpper(t.text)]:
+			return
+		}
+	}
+}
+
+// peekKeyword returns whether the following tokens are the keywords.
+func (p *pgParser) peekKeyword(keywordList ...string) bool {
+	if p.pos+len(keywordList) > len(p.tokenList) {
+		return false
+	}
+	for i, keyword := range keywordList {
+		t := p.tokenList[p.pos+i]
+		if t.typ != tokenWord || !strings.EqualFold(t.text, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptKeyword consumes the following tokens if they are the keywords.
+func (p *pgParser) acceptKeyword(keywordList ...string) bool {
+	if !p.peekKeyword(keywordList...) {
+		return false
+	}
+	p.pos += len(keywordList)
+	return true
+}
+
+// hasSuffixKeyword returns whether the tokens end with the keywords.
+func (p *pgParser) hasSuffixKeyword(keywordList ...string) bool {
+	if len(p.tokenList) < len(keywordList) {
+		return false
+	}
+	sp := &pgParser{text: p.text, tokenList: p.tokenList[len(p.tokenList)-len(keywordList):]}
+	return sp.peekKeyword(keywordList...)
+}
+
+// peekWord returns the upper-case text of the following token if it's a word.
+func (p *pgParser) peekWord() string {
+	if p.atEnd() || p.tokenList[p.pos].typ != tokenWord {
+		return ""
+	}
+	return strings.ToUpper(p.tokenList[p.pos].text)
+}
+
+func (p *pgParser) peekSymbol(symbol string) bool {
+	return !p.atEnd() && p.tokenList[p.pos].typ == tokenSymbol && p.tokenList[p.pos].text == symbol
+}
+
+func (p *pgParser) acceptSymbol(symbol string) bool {
+	if !p.peekSymbol(symbol) {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *pgParser) atEnd() bool {
+	return p.pos >= len(p.tokenList)
+}
+
+func (p *pgParser) expectEnd() error {
+	if !p.atEnd() {
+		return p.errorAt(p.pos)
+	}
+	return nil
+}
+
+// errorAt returns the syntax error at the token in the format of Postgres.
+func (p *pgParser) errorAt(i int) error {
+	if i >= len(p.tokenList) {
+		offset := 0
+		if n := len(p.tokenList); n > 0 {
+			offset = p.tokenList[n-1].pos + len(p.tokenList[n-1].text)
+		}
+		return &SyntaxError{Offset: offset, Message: "syntax error at end of input"}
+	}
+	return &SyntaxError{Offset: p.tokenList[i].pos, Message: fmt.Sprintf("syntax error at or near %q", p.tokenList[i].text)}
+}
+
+// textOf returns the source text from the first token to the last token.
+func (p *pgParser) textOf(tokenList []token) string {
+	if len(tokenList) == 0 {
+		return ""
+	}
+	last := tokenList[len(tokenList)-1]
+	return p.text[tokenList[0].pos : last.pos+len(last.text)]
+}
+
+// checkParentheses checks the parentheses of the tokens are balanced.
+func (p *pgParser) checkParentheses() error {
+	depth := 0
+	for i, t := range p.tokenList {
+		if t.typ != tokenSymbol {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth < 0 {
+				return p.errorAt(i)
+			}
+		}
+	}
+	if depth > 0 {
+		return p.errorAt(len(p.tokenList))
+	}
+	return nil
+}
+
+// splitTopLevel splits the tokens by the commas outside the parentheses.
+func splitTopLevel(tokenList []token) [][]token {
+	if len(tokenList) == 0 {
+		return nil
+	}
+	var result [][]token
+	depth := 0
+	start := 0
+	for i, t := range tokenList {
+		if t.typ != tokenSymbol {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				result = append(result, tokenList[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, tokenList[start:])
+}
@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kr/pretty"
+)
+
+func TestParsePGStatements(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      []PGStmt
+		wantErr   string
+		// wantErrOffset is the offset of the syntax error.
+		wantErrOffset int
+	}{
+		{
+			statement: `CREATE TABLE IF NOT EXISTS public."UserInfo" (
+  id serial PRIMARY KEY,
+  name character varying(20) NOT NULL DEFAULT '',
+  org_id int CONSTRAINT fk_org REFERENCES org (id) ON DELETE SET NULL,
+  CONSTRAINT uk_user_name UNIQUE (name, org_id),
+  FOREIGN KEY (org_id) REFERENCES "Org"
+);
+-- comment;
+SELECT 1`,
+			want: []PGStmt{
+				&PGCreateTableStmt{
+					pgStmtText:  pgStmtText{text: "CREATE TABLE IF NOT EXISTS public.\"UserInfo\" (\n  id serial PRIMARY KEY,\n  name character varying(20) NOT NULL DEFAULT '',\n  org_id int CONSTRAINT fk_org REFERENCES org (id) ON DELETE SET NULL,\n  CONSTRAINT uk_user_name UNIQUE (name, org_id),\n  FOREIGN KEY (org_id) REFERENCES \"Org\"\n)"},
+					Table:       &PGTableName{Schema: "public", Name: "UserInfo"},
+					IfNotExists: true,
+					ColumnList: []*PGColumnDef{
+						{Name: "id", Type: "serial", ConstraintList: []*PGConstraint{{Type: PGConstraintPrimaryKey, ColumnList: []string{"id"}}}},
+						{Name: "name", Type: "character varying(20)", NotNull: true, HasDefault: true},
+						{Name: "org_id", Type: "int", ConstraintList: []*PGConstraint{{Name: "fk_org", Type: PGConstraintForeignKey, ColumnList: []string{"org_id"}, ReferencedTable: &PGTableName{Name: "org"}, ReferencedColumnList: []string{"id"}}}},
+					},
+					ConstraintList: []*PGConstraint{
+						{Name: "uk_user_name", Type: PGConstraintUnique, ColumnList: []string{"name", "org_id"}},
+						{Type: PGConstraintForeignKey, ColumnList: []string{"org_id"}, ReferencedTable: &PGTableName{Name: "Org"}},
+					},
+				},
+				&PGUnknownStmt{pgStmtText{text: "SELECT 1"}},
+			},
+		},
+		{
+			statement: "ALTER TABLE ONLY t RENAME TO t1; ALTER TABLE t1 ADD COLUMN IF NOT EXISTS a int, ADD CONSTRAINT uk_t1_a UNIQUE (a), ALTER COLUMN b SET NOT NULL; ALTER TABLE t1 RENAME b TO \"B\"",
+			want: []PGStmt{
+				&PGAlterTableStmt{
+					pgStmtText: pgStmtText{text: "ALTER TABLE ONLY t RENAME TO t1"},
+					Table:      &PGTableName{Name: "t"},
+					ActionList: []PGAlterTableAction{
+						&PGRenameTableAction{pgStmtText: pgStmtText{text: "RENAME TO t1"}, NewName: "t1"},
+					},
+				},
+				&PGAlterTableStmt{
+					pgStmtText: pgStmtText{text: "ALTER TABLE t1 ADD COLUMN IF NOT EXISTS a int, ADD CONSTRAINT uk_t1_a UNIQUE (a), ALTER COLUMN b SET NOT NULL"},
+					Table:      &PGTableName{Name: "t1"},
+					ActionList: []PGAlterTableAction{
+						&PGAddColumnAction{pgStmtText: pgStmtText{text: "ADD COLUMN IF NOT EXISTS a int"}, IfNotExists: true, Column: &PGColumnDef{Name: "a", Type: "int"}},
+						&PGAddConstraintAction{pgStmtText: pgStmtText{text: "ADD CONSTRAINT uk_t1_a UNIQUE (a)"}, Constraint: &PGConstraint{Name: "uk_t1_a", Type: PGConstraintUnique, ColumnList: []string{"a"}}},
+						&PGSetNotNullAction{pgStmtText: pgStmtText{text: "ALTER COLUMN b SET NOT NULL"}, Column: "b"},
+					},
+				},
+				&PGAlterTableStmt{
+					pgStmtText: pgStmtText{text: "ALTER TABLE t1 RENAME b TO \"B\""},
+					Table:      &PGTableName{Name: "t1"},
+					ActionList: []PGAlterTableAction{
+						&PGRenameColumnAction{pgStmtText: pgStmtText{text: "RENAME b TO \"B\""}, Column: "b", NewName: "B"},
+					},
+				},
+			},
+		},
+		{
+			statement: "CREATE UNIQUE INDEX CONCURRENTLY idx_t_a ON t USING btree (a DESC, lower(b), (c + 1));\nCREATE INDEX ON t (a)",
+			want: []PGStmt{
+				&PGCreateIndexStmt{
+					pgStmtText:   pgStmtText{text: "CREATE UNIQUE INDEX CONCURRENTLY idx_t_a ON t USING btree (a DESC, lower(b), (c + 1))"},
+					Name:         "idx_t_a",
+					Table:        &PGTableName{Name: "t"},
+					Unique:       true,
+					Concurrently: true,
+					ColumnList:   []string{"a"},
+				},
+				&PGCreateIndexStmt{
+					pgStmtText: pgStmtText{text: "CREATE INDEX ON t (a)"},
+					Table:      &PGTableName{Name: "t"},
+					ColumnList: []string{"a"},
+				},
+			},
+		},
+		{
+			statement: "DROP INDEX CONCURRENTLY IF EXISTS public.idx_a, idx_b CASCADE; DROP DATABASE \"Db\" WITH (FORCE); DROP MATERIALIZED VIEW v; DROP FUNCTION f",
+			want: []PGStmt{
+				&PGDropStmt{
+					pgStmtText:   pgStmtText{text: "DROP INDEX CONCURRENTLY IF EXISTS public.idx_a, idx_b CASCADE"},
+					ObjectType:   PGObjectIndex,
+					IfExists:     true,
+					Concurrently: true,
+					NameList:     []*PGTableName{{Schema: "public", Name: "idx_a"}, {Name: "idx_b"}},
+				},
+				&PGDropStmt{
+					pgStmtText: pgStmtText{text: "DROP DATABASE \"Db\" WITH (FORCE)"},
+					ObjectType: PGObjectDatabase,
+					NameList:   []*PGTableName{{Name: "Db"}},
+				},
+				&PGDropStmt{
+					pgStmtText: pgStmtText{text: "DROP MATERIALIZED VIEW v"},
+					ObjectType: PGObjectMaterializedView,
+					NameList:   []*PGTableName{{Name: "v"}},
+				},
+				&PGUnknownStmt{pgStmtText{text: "DROP FUNCTION f"}},
+			},
+		},
+		{
+			statement: "ALTER INDEX IF EXISTS public.idx_a RENAME TO \"Idx_A\"; ALTER INDEX idx_b SET TABLESPACE s",
+			want: []PGStmt{
+				&PGRenameIndexStmt{
+					pgStmtText: pgStmtText{text: "ALTER INDEX IF EXISTS public.idx_a RENAME TO \"Idx_A\""},
+					Index:      &PGTableName{Schema: "public", Name: "idx_a"},
+					IfExists:   true,
+					NewName:    "Idx_A",
+				},
+				&PGUnknownStmt{pgStmtText{text: "ALTER INDEX idx_b SET TABLESPACE s"}},
+			},
+		},
+		{
+			statement: "ALTER TABLE t DROP COLUMN IF EXISTS a CASCADE, DROP CONSTRAINT t_b_key, ALTER COLUMN c SET DATA TYPE bigint USING c::bigint, ALTER d TYPE varchar(20), ALTER COLUMN e SET NOT NULL, ALTER COLUMN e DROP DEFAULT, ADD CHECK (f > 0) NOT VALID",
+			want: []PGStmt{
+				&PGAlterTableStmt{
+					pgStmtText: pgStmtText{text: "ALTER TABLE t DROP COLUMN IF EXISTS a CASCADE, DROP CONSTRAINT t_b_key, ALTER COLUMN c SET DATA TYPE bigint USING c::bigint, ALTER d TYPE varchar(20), ALTER COLUMN e SET NOT NULL, ALTER COLUMN e DROP DEFAULT, ADD CHECK (f > 0) NOT VALID"},
+					Table:      &PGTableName{Name: "t"},
+					ActionList: []PGAlterTableAction{
+						&PGDropColumnAction{pgStmtText: pgStmtText{text: "DROP COLUMN IF EXISTS a CASCADE"}, IfExists: true, Column: "a"},
+						&PGDropConstraintAction{pgStmtText: pgStmtText{text: "DROP CONSTRAINT t_b_key"}, Constraint: "t_b_key"},
+						&PGAlterColumnTypeAction{pgStmtText: pgStmtText{text: "ALTER COLUMN c SET DATA TYPE bigint USING c::bigint"}, Column: "c", Type: "bigint"},
+						&PGAlterColumnTypeAction{pgStmtText: pgStmtText{text: "ALTER d TYPE varchar(20)"}, Column: "d", Type: "varchar(20)"},
+						&PGSetNotNullAction{pgStmtText: pgStmtText{text: "ALTER COLUMN e SET NOT NULL"}, Column: "e"},
+						&PGUnknownAction{pgStmtText: pgStmtText{text: "ALTER COLUMN e DROP DEFAULT"}},
+						&PGAddConstraintAction{pgStmtText: pgStmtText{text: "ADD CHECK (f > 0) NOT VALID"}, Constraint: &PGConstraint{Type: PGConstraintCheck}, NotValid: true},
+					},
+				},
+			},
+		},
+		{
+			statement:     "CREATE TABLE t (id int,)",
+			wantErr:       "syntax error at or near \")\"",
+			wantErrOffset: 23,
+		},
+		{
+			statement:     "CREATE TABLE t (id int",
+			wantErr:       "syntax error at end of input",
+			wantErrOffset: 22,
+		},
+		{
+			statement:     "SELECT 1;\nALTER TABLE t RENAME TO;",
+			wantErr:       "syntax error at end of input",
+			wantErrOffset: 33,
+		},
+		{
+			statement:     "SELECT 1; SELECT 'a",
+			wantErr:       "unterminated quoted text ' at offset 17",
+			wantErrOffset: 17,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParsePGStatements(test.statement)
+		if err != nil {
+			if test.wantErr == "" || err.Error() != test.wantErr {
+				t.Errorf("%q: ParsePGStatements() got error %q, want error %q", test.statement, err, test.wantErr)
+			}
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Offset != test.wantErrOffset {
+				t.Errorf("%q: ParsePGStatements() got error %#v, want the syntax error at offset %d", test.statement, err, test.wantErrOffset)
+			}
+			continue
+		}
+		if test.wantErr != "" {
+			t.Errorf("%q: ParsePGStatements() expected error %q", test.statement, test.wantErr)
+			continue
+		}
+		if diff := pretty.Diff(got, test.want); len(diff) > 0 {
+			t.Errorf("%q: got %# v, want %# v, diff %+v.", test.statement, pretty.Formatter(got), pretty.Formatter(test.want), diff)
+		}
+	}
+}
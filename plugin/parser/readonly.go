@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/pingcap/parser/ast"
+)
+
+var (
+	// readOnlyLeadingKeywords is the keywords that the read-only statements of the engine start with.
+	readOnlyLeadingKeywords = map[db.Type]map[string]bool{
+		db.Postgres:   {"SELECT": true, "WITH": true, "SHOW": true, "EXPLAIN": true, "VALUES": true, "TABLE": true},
+		db.SQLite:     {"SELECT": true, "WITH": true, "EXPLAIN": true, "VALUES": true},
+		db.ClickHouse: {"SELECT": true, "WITH": true, "SHOW": true, "EXPLAIN": true, "DESCRIBE": true, "DESC": true, "EXISTS": true},
+		db.Snowflake:  {"SELECT": true, "WITH": true, "SHOW": true, "EXPLAIN": true, "DESCRIBE": true, "DESC": true},
+	}
+	// writeKeywords is the keywords making the statement not read-only wherever they appear, e.g. the data-modifying
+	// WITH query "WITH t AS (DELETE FROM t1 RETURNING *) SELECT * FROM t" in Postgres, "SELECT ... INTO" creating
+	// the table, "SELECT ... FOR UPDATE" locking the rows, and "EXPLAIN ANALYZE" executing the statement.
+	writeKeywords = map[string]bool{
+		"INSERT":   true,
+		"UPDATE":   true,
+		"DELETE":   true,
+		"MERGE":    true,
+		"UPSERT":   true,
+		"INTO":     true,
+		"CREATE":   true,
+		"ALTER":    true,
+		"DROP":     true,
+		"TRUNCATE": true,
+		"GRANT":    true,
+		"REVOKE":   true,
+		"COPY":     true,
+		"CALL":     true,
+		"LOCK":     true,
+		"ANALYZE":  true,
+	}
+)
+
+// ValidateReadOnlySQL validates the single statement of the engine is read-only, such as SELECT, SHOW and EXPLAIN.
+// MySQL and TiDB statements are validated on the syntax tree of the MySQL parser, while the statements of the other
+// engines are validated on the keywords of the tokens, which is conservative and may reject some read-only statements.
+// It's only the first line of defense, the statements should still be executed in the read-only transaction.
+func ValidateReadOnlySQL(engineType db.Type, statement string) error {
+	switch engineType {
+	case db.MySQL, db.TiDB:
+		nodeList, err := parseMySQL(statement)
+		if err != nil {
+			return err
+		}
+		if len(nodeList) != 1 {
+			return fmt.Errorf("expect one statement, got %d", len(nodeList))
+		}
+		if !isMySQLReadOnlyNode(nodeList[0]) {
+			return fmt.Errorf("only SELECT, SHOW, DESCRIBE and EXPLAIN statements are allowed, got %q", statement)
+		}
+		return nil
+	}
+
+	leadingKeywords, ok := readOnlyLeadingKeywords[engineType]
+	if !ok {
+		return fmt.Errorf("engine %s is not supported", engineType)
+	}
+	tokenList, err := tokenize(engineType, statement)
+	if err != nil {
+		return err
+	}
+	var wordList []string
+	for _, token := range tokenList {
+		switch token.typ {
+		case tokenSemicolon:
+			return fmt.Errorf("expect one statement, got multiple statements")
+		case tokenWord:
+			wordList = append(wordList, strings.ToUpper(token.text))
+		}
+	}
+	if len(wordList) == 0 || !leadingKeywords[wordList[0]] {
+		return fmt.Errorf("only read-only statements are allowed, got %q", statement)
+	}
+	for _, word := range wordList {
+		if writeKeywords[word] {
+			return fmt.Errorf("only read-only statements are allowed, got %s in %q", word, statement)
+		}
+	}
+	return nil
+}
+
+// isMySQLReadOnlyNode returns whether the MySQL statement is read-only.
+func isMySQLReadOnlyNode(node ast.StmtNode) bool {
+	switch node := node.(type) {
+	case *ast.SelectStmt:
+		return node.SelectIntoOpt == nil && node.LockTp == ast.SelectLockNone
+	case *ast.UnionStmt:
+		for _, selectStmt := range node.SelectList.Selects {
+			if !isMySQLReadOnlyNode(selectStmt) {
+				return false
+			}
+		}
+		return true
+	case *ast.ShowStmt:
+		return true
+	case *ast.ExplainStmt:
+		// EXPLAIN ANALYZE executes the statement. DESCRIBE table is parsed as EXPLAIN on the SHOW COLUMNS statement.
+		return !node.Analyze && isMySQLReadOnlyNode(node.Stmt)
+	}
+	return false
+}
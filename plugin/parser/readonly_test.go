@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestValidateReadOnlySQL(t *testing.T) {
+	tests := []struct {
+		engineType db.Type
+		statement  string
+		want       bool
+	}{
+		{engineType: db.MySQL, statement: "  seLeCT * FROM test", want: true},
+		{engineType: db.MySQL, statement: "SELECT\n*\nFROM\ntest", want: true},
+		{engineType: db.MySQL, statement: "SELECT a FROM t1 UNION SELECT b FROM t2", want: true},
+		{engineType: db.MySQL, statement: "explain select * from test", want: true},
+		{engineType: db.MySQL, statement: "SHOW DATABASES", want: true},
+		{engineType: db.MySQL, statement: "DESCRIBE test", want: true},
+		{engineType: db.MySQL, statement: "EXPLAIN ANALYZE SELECT * FROM test", want: false},
+		{engineType: db.MySQL, statement: "SELECT * FROM test FOR UPDATE", want: false},
+		{engineType: db.MySQL, statement: "SELECT * FROM test INTO OUTFILE '/tmp/test'", want: false},
+		{engineType: db.MySQL, statement: "SELECT 1 UNION SELECT * FROM test FOR UPDATE", want: false},
+		{engineType: db.MySQL, statement: "insert into test values (1)", want: false},
+		{engineType: db.MySQL, statement: "SELECTfoo", want: false},
+		{engineType: db.MySQL, statement: "SELECT 1; DELETE FROM test", want: false},
+		{engineType: db.MySQL, statement: "", want: false},
+		{engineType: db.Postgres, statement: "  \n \r SELEct * from test ", want: true},
+		{engineType: db.Postgres, statement: "select", want: true},
+		{engineType: db.Postgres, statement: "\n explain \n \r  select", want: true},
+		{engineType: db.Postgres, statement: "WITH t AS (SELECT 1) SELECT * FROM t", want: true},
+		{engineType: db.Postgres, statement: "SELECT 'delete', \"update\" FROM test -- insert", want: true},
+		{engineType: db.Postgres, statement: "SHOW search_path", want: true},
+		{engineType: db.Postgres, statement: "WITH t AS (DELETE FROM t1 RETURNING *) SELECT * FROM t", want: false},
+		{engineType: db.Postgres, statement: "SELECT * INTO t2 FROM t1", want: false},
+		{engineType: db.Postgres, statement: "EXPLAIN (ANALYZE) SELECT 1", want: false},
+		{engineType: db.Postgres, statement: "asd  explain selectasd ", want: false},
+		{engineType: db.Postgres, statement: "SETEST * FROM test", want: false},
+		{engineType: db.Postgres, statement: "SELECT 1; SELECT 2", want: false},
+		{engineType: db.Postgres, statement: "", want: false},
+		{engineType: db.SQLite, statement: "SELECT * FROM test", want: true},
+		{engineType: db.SQLite, statement: "PRAGMA user_version = 1", want: false},
+		{engineType: db.ClickHouse, statement: "DESCRIBE TABLE test", want: true},
+		{engineType: db.Snowflake, statement: "SHOW TABLES", want: true},
+		{engineType: db.Snowflake, statement: "CREATE TABLE t (id INT)", want: false},
+	}
+
+	for _, test := range tests {
+		err := ValidateReadOnlySQL(test.engineType, test.statement)
+		if got := err == nil; got != test.want {
+			t.Errorf("%s %q: got read-only %v, want %v, error: %v", test.engineType, test.statement, got, test.want, err)
+		}
+	}
+}
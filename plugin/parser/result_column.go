@@ -0,0 +1,329 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/pingcap/parser/ast"
+)
+
+// ColumnResource is a column of a table in a database.
+type ColumnResource struct {
+	Database string
+	Table    string
+	Column   string
+}
+
+// ColumnCatalog is the catalog of the table columns for resolving the result columns of the queries.
+type ColumnCatalog interface {
+	// ListColumn returns the column names of the table in the column order, or nil if the table isn't in the catalog.
+	ListColumn(ctx context.Context, database string, table string) ([]string, error)
+}
+
+// IsResultColumnSourceSupported returns true if resolving the result columns is supported for the engine.
+func IsResultColumnSourceSupported(engineType db.Type) bool {
+	return engineType == db.MySQL || engineType == db.TiDB
+}
+
+// ExtractResultColumnSource returns the source columns of each result column of the readonly query, in the result column order.
+// The result column derived from an expression has all the source columns referenced in the expression, including
+// those in the scalar subqueries, so it's an over-approximation if the column can't be told apart, e.g. the unqualified
+// column of a join whose tables aren't in the catalog. It returns nil if the statement returns no table data, such as SHOW.
+// Only MySQL and TiDB are supported.
+func ExtractResultColumnSource(ctx context.Context, engineType db.Type, statement string, currentDatabase string, catalog ColumnCatalog) ([][]ColumnResource, error) {
+	if !IsResultColumnSourceSupported(engineType) {
+		return nil, fmt.Errorf("resolving the result columns is not supported for %s", engineType)
+	}
+	nodeList, err := parseMySQL(statement)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodeList) != 1 {
+		return nil, fmt.Errorf("expect one statement, got %d", len(nodeList))
+	}
+
+	var resultSet ast.ResultSetNode
+	switch node := nodeList[0].(type) {
+	case *ast.SelectStmt:
+		resultSet = node
+	case *ast.UnionStmt:
+		resultSet = node
+	default:
+		return nil, nil
+	}
+
+	r := &mysqlColumnResolver{
+		ctx:             ctx,
+		currentDatabase: currentDatabase,
+		catalog:         catalog,
+	}
+	columnList, err := r.resolveResultSet(resultSet, nil /* outer */)
+	if err != nil {
+		return nil, err
+	}
+	sourceList := make([][]ColumnResource, len(columnList))
+	for i, column := range columnList {
+		sourceList[i] = column.sourceList
+	}
+	return sourceList, nil
+}
+
+// scopeColumn is a column of the table in the scope, or a result column of the query.
+type scopeColumn struct {
+	name       string
+	sourceList []ColumnResource
+}
+
+// scopeTable is a table in the FROM clause, which is either a physical table or a derived table of the subquery.
+type scopeTable struct {
+	// name is the alias of the table, or the table name if there is no alias.
+	name string
+	// database and table are the physical table, which are empty for the derived table.
+	database string
+	table    string
+	// columnList is nil if the physical table isn't in the catalog.
+	columnList []scopeColumn
+}
+
+// scope is the tables visible to the expressions of a query, the parent is the scope of the outer query
+// for the correlated subqueries.
+type scope struct {
+	tableList []*scopeTable
+	parent    *scope
+}
+
+// mysqlColumnResolver resolves the result columns of the MySQL query to the source columns.
+type mysqlColumnResolver struct {
+	ctx             context.Context
+	currentDatabase string
+	catalog         ColumnCatalog
+}
+
+func (r *mysqlColumnResolver) resolveResultSet(node ast.ResultSetNode, outer *scope) ([]scopeColumn, error) {
+	switch node := node.(type) {
+	case *ast.SelectStmt:
+		return r.resolveSelect(node, outer)
+	case *ast.UnionStmt:
+		var columnList []scopeColumn
+		for i, selectStmt := range node.SelectList.Selects {
+			selectColumnList, err := r.resolveSelect(selectStmt, outer)
+			if err != nil {
+				return nil, err
+			}
+			// The union result columns are named after the first SELECT, and derived from the columns of every SELECT.
+			if i == 0 {
+				columnList = selectColumnList
+				continue
+			}
+			if len(selectColumnList) != len(columnList) {
+				return nil, fmt.Errorf("the SELECT statements of the UNION have different numbers of columns")
+			}
+			for j := range columnList {
+				columnList[j].sourceList = append(columnList[j].sourceList, selectColumnList[j].sourceList...)
+			}
+		}
+		return columnList, nil
+	}
+	return nil, fmt.Errorf("unsupported result set %T", node)
+}
+
+func (r *mysqlColumnResolver) resolveSelect(node *ast.SelectStmt, outer *scope) ([]scopeColumn, error) {
+	sc := &scope{parent: outer}
+	if node.From != nil {
+		if err := r.collectTable(node.From.TableRefs, sc); err != nil {
+			return nil, err
+		}
+	}
+
+	var columnList []scopeColumn
+	for _, field := range node.Fields.Fields {
+		if field.WildCard != nil {
+			expanded := false
+			for _, table := range sc.tableList {
+				if field.WildCard.Table.O != "" && !matchTable(table, field.WildCard.Schema.O, field.WildCard.Table.O) {
+					continue
+				}
+				if table.columnList == nil {
+					return nil, fmt.Errorf("failed to expand * since the columns of table %q are unknown", table.name)
+				}
+				columnList = append(columnList, table.columnList...)
+				expanded = true
+			}
+			if !expanded {
+				return nil, fmt.Errorf("failed to expand %s.* since the table is unknown", field.WildCard.Table.O)
+			}
+			continue
+		}
+
+		sourceList, err := r.resolveExpr(field.Expr, sc)
+		if err != nil {
+			return nil, err
+		}
+		name := field.AsName.O
+		if name == "" {
+			if columnNameExpr, ok := field.Expr.(*ast.ColumnNameExpr); ok {
+				name = columnNameExpr.Name.Name.O
+			} else {
+				name = field.Text()
+			}
+		}
+		columnList = append(columnList, scopeColumn{name: name, sourceList: sourceList})
+	}
+	return columnList, nil
+}
+
+// collectTable collects the tables in the FROM clause into the scope.
+func (r *mysqlColumnResolver) collectTable(node ast.ResultSetNode, sc *scope) error {
+	switch node := node.(type) {
+	case nil:
+		return nil
+	case *ast.Join:
+		if err := r.collectTable(node.Left, sc); err != nil {
+			return err
+		}
+		return r.collectTable(node.Right, sc)
+	case *ast.TableSource:
+		switch source := node.Source.(type) {
+		case *ast.TableName:
+			database := source.Schema.O
+			if database == "" {
+				database = r.currentDatabase
+			}
+			table := &scopeTable{
+				name:     source.Name.O,
+				database: database,
+				table:    source.Name.O,
+			}
+			if node.AsName.O != "" {
+				table.name = node.AsName.O
+			}
+			nameList, err := r.catalog.ListColumn(r.ctx, database, source.Name.O)
+			if err != nil {
+				return err
+			}
+			if nameList != nil {
+				table.columnList = []scopeColumn{}
+				for _, name := range nameList {
+					table.columnList = append(table.columnList, scopeColumn{
+						name:       name,
+						sourceList: []ColumnResource{{Database: database, Table: source.Name.O, Column: name}},
+					})
+				}
+			}
+			sc.tableList = append(sc.tableList, table)
+			return nil
+		case *ast.SelectStmt, *ast.UnionStmt:
+			// The derived table can't reference the other tables in the same FROM clause.
+			columnList, err := r.resolveResultSet(source, sc.parent)
+			if err != nil {
+				return err
+			}
+			sc.tableList = append(sc.tableList, &scopeTable{
+				name:       node.AsName.O,
+				columnList: append([]scopeColumn{}, columnList...),
+			})
+			return nil
+		case *ast.Join:
+			return r.collectTable(source, sc)
+		}
+		return fmt.Errorf("unsupported table source %T", node.Source)
+	}
+	return fmt.Errorf("unsupported table reference %T", node)
+}
+
+// resolveExpr returns the source columns referenced in the expression.
+func (r *mysqlColumnResolver) resolveExpr(expr ast.ExprNode, sc *scope) ([]ColumnResource, error) {
+	collector := &columnReferenceCollector{resolver: r, scope: sc}
+	expr.Accept(collector)
+	if collector.err != nil {
+		return nil, collector.err
+	}
+	return collector.sourceList, nil
+}
+
+// resolveColumnName returns the source columns of the column reference, looking up from the innermost scope.
+func (r *mysqlColumnResolver) resolveColumnName(name *ast.ColumnName, sc *scope) []ColumnResource {
+	var guessList []ColumnResource
+	for s := sc; s != nil; s = s.parent {
+		var sourceList []ColumnResource
+		found := false
+		for _, table := range s.tableList {
+			if name.Table.O != "" && !matchTable(table, name.Schema.O, name.Table.O) {
+				continue
+			}
+			if table.columnList == nil {
+				// The column may come from the table not in the catalog.
+				guessList = append(guessList, ColumnResource{Database: table.database, Table: table.table, Column: name.Name.O})
+				continue
+			}
+			for _, column := range table.columnList {
+				if strings.EqualFold(column.name, name.Name.O) {
+					sourceList = append(sourceList, column.sourceList...)
+					found = true
+				}
+			}
+		}
+		if found {
+			return append(sourceList, guessList...)
+		}
+	}
+	if len(guessList) > 0 {
+		return guessList
+	}
+
+	// The column isn't found in the catalog, which may be out of date, so it may come from any physical table in the scopes.
+	for s := sc; s != nil; s = s.parent {
+		for _, table := range s.tableList {
+			if table.table != "" {
+				guessList = append(guessList, ColumnResource{Database: table.database, Table: table.table, Column: name.Name.O})
+			}
+		}
+	}
+	return guessList
+}
+
+// matchTable returns whether the table is referenced by the qualifier, the database is optional.
+func matchTable(table *scopeTable, database string, name string) bool {
+	if !strings.EqualFold(table.name, name) {
+		return false
+	}
+	return database == "" || (table.table != "" && strings.EqualFold(table.database, database))
+}
+
+// columnReferenceCollector collects the source columns of the column references in the expression.
+type columnReferenceCollector struct {
+	resolver   *mysqlColumnResolver
+	scope      *scope
+	sourceList []ColumnResource
+	err        error
+}
+
+// Enter implements ast.Visitor interface.
+func (c *columnReferenceCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if c.err != nil {
+		return in, true
+	}
+	switch node := in.(type) {
+	case *ast.ColumnNameExpr:
+		c.sourceList = append(c.sourceList, c.resolver.resolveColumnName(node.Name, c.scope)...)
+		return in, true
+	case *ast.SubqueryExpr:
+		columnList, err := c.resolver.resolveResultSet(node.Query, c.scope)
+		if err != nil {
+			c.err = err
+			return in, true
+		}
+		for _, column := range columnList {
+			c.sourceList = append(c.sourceList, column.sourceList...)
+		}
+		return in, true
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor interface.
+func (*columnReferenceCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+)
+
+// testColumnCatalog is the column catalog keyed by "database.table".
+type testColumnCatalog map[string][]string
+
+func (c testColumnCatalog) ListColumn(_ context.Context, database string, table string) ([]string, error) {
+	return c[database+"."+table], nil
+}
+
+func TestExtractResultColumnSource(t *testing.T) {
+	catalog := testColumnCatalog{
+		"shop.user":  {"id", "name", "email"},
+		"shop.order": {"id", "user_id", "amount"},
+		"hr.salary":  {"user_id", "salary"},
+	}
+	col := func(database, table, column string) ColumnResource {
+		return ColumnResource{Database: database, Table: table, Column: column}
+	}
+	tests := []struct {
+		statement string
+		want      [][]ColumnResource
+		wantErr   bool
+	}{
+		{
+			statement: "SELECT * FROM user",
+			want:      [][]ColumnResource{{col("shop", "user", "id")}, {col("shop", "user", "name")}, {col("shop", "user", "email")}},
+		},
+		{
+			statement: "SELECT u.email AS e, CONCAT(name, '@'), 1, o.* FROM user u JOIN `order` o ON u.id = o.user_id",
+			want: [][]ColumnResource{
+				{col("shop", "user", "email")},
+				{col("shop", "user", "name")},
+				nil,
+				{col("shop", "order", "id")},
+				{col("shop", "order", "user_id")},
+				{col("shop", "order", "amount")},
+			},
+		},
+		{
+			statement: "SELECT x.s FROM (SELECT salary + 1 AS s FROM hr.salary) x",
+			want:      [][]ColumnResource{{col("hr", "salary", "salary")}},
+		},
+		{
+			statement: "SELECT id, (SELECT salary FROM hr.salary s WHERE s.user_id = u.id) FROM user u",
+			want:      [][]ColumnResource{{col("shop", "user", "id")}, {col("hr", "salary", "salary")}},
+		},
+		{
+			statement: "SELECT name FROM user UNION SELECT amount FROM `order`",
+			want:      [][]ColumnResource{{col("shop", "user", "name"), col("shop", "order", "amount")}},
+		},
+		{
+			// The unqualified column may come from the table not in the catalog.
+			statement: "SELECT name, secret FROM user JOIN unknown",
+			want: [][]ColumnResource{
+				{col("shop", "user", "name"), col("shop", "unknown", "name")},
+				{col("shop", "unknown", "secret")},
+			},
+		},
+		{
+			// The column not in the catalog may come from any table.
+			statement: "SELECT phone FROM user",
+			want:      [][]ColumnResource{{col("shop", "user", "phone")}},
+		},
+		{
+			statement: "SHOW TABLES",
+			want:      nil,
+		},
+		{
+			statement: "SELECT * FROM unknown",
+			wantErr:   true,
+		},
+		{
+			statement: "SELECT 1; SELECT 2",
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ExtractResultColumnSource(context.Background(), db.MySQL, test.statement, "shop", catalog)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("%q: ExtractResultColumnSource() unexpected error: %v", test.statement, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("%q: ExtractResultColumnSource() expected error", test.statement)
+			continue
+		}
+		if diff := pretty.Diff(got, test.want); len(diff) > 0 {
+			t.Errorf("%q: got %+v, want %+v, diff %+v.", test.statement, got, test.want, diff)
+		}
+	}
+
+	if _, err := ExtractResultColumnSource(context.Background(), db.Postgres, "SELECT 1", "shop", catalog); err == nil {
+		t.Errorf("ExtractResultColumnSource() expected error for Postgres")
+	}
+}
@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// defaultDelimiter is the default delimiter between the statements.
+	defaultDelimiter = ";"
+	// delimiterCommand is the MySQL client command changing the delimiter, e.g. "DELIMITER ;;" in the dump with routines.
+	delimiterCommand = "DELIMITER"
+)
+
+// SingleSQL is a statement split from the SQL script.
+type SingleSQL struct {
+	// Text is the statement text without the leading comments and the trailing delimiter.
+	Text string
+	// Line is the 1-based line number of the first line of the statement in the script.
+	Line int
+	// Delimiter is the delimiter in effect for the statement, which is changed by the DELIMITER command of MySQL.
+	Delimiter string
+}
+
+// ApplyMultiStatements splits the SQL script of the engine read line by line from the scanner into the statements,
+// and applies f to each statement in order. The statements are split by the tokenizer of the engine, so the delimiters
+// in the string literals, the quoted identifiers, the comments and the Postgres dollar-quoted function bodies don't
+// split the statement. The statements consisting of only comments are skipped. For MySQL and TiDB, the DELIMITER
+// command at the start of a statement changes the delimiter of the following statements, and the executable comments
+// such as "/*!40101 SET NAMES utf8 */" are statements.
+// The error returned by f is wrapped with the line number of the statement.
+func ApplyMultiStatements(engineType db.Type, sc *bufio.Scanner, f func(*SingleSQL) error) error {
+	s := &scriptSplitter{
+		dialect:   getDialect(engineType),
+		isMySQL:   engineType == db.MySQL || engineType == db.TiDB,
+		delimiter: defaultDelimiter,
+		line:      1,
+		start:     -1,
+		f:         f,
+	}
+	for sc.Scan() {
+		s.feed(sc.Text())
+		if err := s.split(false /* eof */); err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return s.split(true /* eof */)
+}
+
+// scriptSplitter splits the SQL script fed line by line into the statements.
+// Each byte of the script is scanned once, and the text split already is dropped as the script is fed.
+type scriptSplitter struct {
+	dialect dialect
+	// isMySQL is whether the script supports the DELIMITER command and the executable comments "/*! ... */" of MySQL.
+	isMySQL   bool
+	delimiter string
+	// text is the script text fed so far, except the text dropped before the current statement.
+	text strings.Builder
+	// line is the line number at lineOffset in text.
+	line       int
+	lineOffset int
+	// pos is the offset in text tokenized so far. The token starting at pos may be unterminated until more lines are fed,
+	// and scanState records how far it has been scanned.
+	pos       int
+	scanState scanState
+	// start is the offset of the first code token of the current statement, or -1 if there is none yet,
+	// and end is the offset after the last code token.
+	start int
+	end   int
+	f     func(*SingleSQL) error
+}
+
+// feed appends the line to the text. The text before the current statement is dropped once it's no shorter than
+// the rest, so each byte is copied a constant number of times on average.
+func (s *scriptSplitter) feed(line string) {
+	text := s.text.String()
+	keep := s.pos
+	if s.start >= 0 {
+		keep = s.start
+	}
+	if keep > 0 && keep >= len(text)-keep {
+		s.line += strings.Count(text[s.lineOffset:keep], "\n")
+		s.text.Reset()
+		s.text.Grow(len(text) - keep + len(line) + 1)
+		s.text.WriteString(text[keep:])
+		s.lineOffset = 0
+		s.pos -= keep
+		if s.start >= 0 {
+			s.start -= keep
+			s.end -= keep
+		}
+		if s.scanState.from > 0 {
+			s.scanState.from -= keep
+		}
+	}
+	s.text.WriteString(line)
+	s.text.WriteByte('\n')
+}
+
+// split splits the statements ending in the text fed so far, the remaining text is the statement at the end of the script if eof.
+func (s *scriptSplitter) split(eof bool) error {
+	text := s.text.String()
+	for s.pos < len(text) {
+		if s.start < 0 && s.isMySQL {
+			delimiter, n, ok, err := scanDelimiterCommand(text[s.pos:])
+			if err != nil {
+				return fmt.Errorf("invalid statement at line %d: %w", s.lineAt(text, s.pos), err)
+			}
+			if ok {
+				s.delimiter = delimiter
+				s.skip(text, s.pos+n)
+				continue
+			}
+		}
+		if strings.HasPrefix(text[s.pos:], s.delimiter) {
+			if err := s.apply(text); err != nil {
+				return err
+			}
+			s.skip(text, s.pos+len(s.delimiter))
+			continue
+		}
+
+		typ, end, err := s.dialect.resumeScan(text, s.pos, &s.scanState)
+		if err != nil {
+			if eof {
+				return fmt.Errorf("invalid statement at line %d: %w", s.lineAt(text, s.pos), err)
+			}
+			// Wait for the lines ending the quoted text or the comment, and resume scanning from the end of this line.
+			return nil
+		}
+		s.scanState = scanState{}
+		// The custom delimiter may follow the word without the space, e.g. "END$$".
+		if typ == tokenWord && s.delimiter != defaultDelimiter {
+			if i := strings.Index(text[s.pos:end], s.delimiter); i > 0 {
+				end = s.pos + i
+			}
+		}
+		isCode := typ != tokenSpace && typ != tokenComment
+		if typ == tokenComment && s.isMySQL && strings.HasPrefix(text[s.pos:], "/*!") {
+			isCode = true
+		}
+		if isCode {
+			if s.start < 0 {
+				s.start = s.pos
+			}
+			s.end = end
+		}
+		s.pos = end
+	}
+	if eof {
+		return s.apply(text)
+	}
+	return nil
+}
+
+// apply applies f to the current statement if it has any code token.
+func (s *scriptSplitter) apply(text string) error {
+	if s.start < 0 {
+		return nil
+	}
+	line := s.lineAt(text, s.start)
+	if err := s.f(&SingleSQL{
+		Text:      text[s.start:s.end],
+		Line:      line,
+		Delimiter: s.delimiter,
+	}); err != nil {
+		return fmt.Errorf("failed to execute the statement at line %d: %w", line, err)
+	}
+	return nil
+}
+
+// skip moves past the text before the offset, and starts a new statement.
+func (s *scriptSplitter) skip(text string, offset int) {
+	s.line += strings.Count(text[s.lineOffset:offset], "\n")
+	s.lineOffset = offset
+	s.pos, s.start, s.end = offset, -1, 0
+}
+
+// lineAt returns the line number of the offset in text, which is no less than lineOffset.
+func (s *scriptSplitter) lineAt(text string, offset int) int {
+	return s.line + strings.Count(text[s.lineOffset:offset], "\n")
+}
+
+// scanDelimiterCommand scans the DELIMITER command at the start of the text, and returns the new delimiter and the length
+// of the command line. The command ends at the end of the line, so it requires the whole line in the text.
+func scanDelimiterCommand(text string) (string, int, bool, error) {
+	if len(text) <= len(delimiterCommand) || !strings.EqualFold(text[:len(delimiterCommand)], delimiterCommand) || !isSpace(text[len(delimiterCommand)]) {
+		return "", 0, false, nil
+	}
+	n := strings.IndexByte(text, '\n')
+	if n < 0 {
+		n = len(text)
+	} else {
+		n++
+	}
+	fields := strings.Fields(text[len(delimiterCommand):n])
+	if len(fields) == 0 {
+		return "", 0, false, fmt.Errorf("DELIMITER must be followed by the delimiter")
+	}
+	return fields[0], n, true, nil
+}
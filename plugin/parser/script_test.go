@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+)
+
+func TestApplyMultiStatements(t *testing.T) {
+	tests := []struct {
+		engineType db.Type
+		statement  string
+		want       []SingleSQL
+		wantErr    string
+	}{
+		{
+			engineType: db.Postgres,
+			statement: `-- Function
+  /* indented
+     comment; */
+CREATE FUNCTION f() RETURNS trigger AS $$
+BEGIN
+  NEW.updated_ts = extract(epoch from now());
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+INSERT INTO t VALUES ('a;
+b'); SELECT 1;`,
+			want: []SingleSQL{
+				{Text: "CREATE FUNCTION f() RETURNS trigger AS $$\nBEGIN\n  NEW.updated_ts = extract(epoch from now());\n  RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql", Line: 4, Delimiter: ";"},
+				{Text: "INSERT INTO t VALUES ('a;\nb')", Line: 10, Delimiter: ";"},
+				{Text: "SELECT 1", Line: 11, Delimiter: ";"},
+			},
+		},
+		{
+			engineType: db.MySQL,
+			statement: `SET sql_mode = 'a;b';
+/*!40101 SET NAMES utf8 */;
+DELIMITER ;;
+CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END ;;
+DELIMITER ;
+delimiter $$
+CREATE TRIGGER t1 BEFORE INSERT ON t FOR EACH ROW BEGIN SET NEW.a = 1; END$$
+DELIMITER ;
+SELECT "x;" # comment;
+`,
+			want: []SingleSQL{
+				{Text: "SET sql_mode = 'a;b'", Line: 1, Delimiter: ";"},
+				{Text: "/*!40101 SET NAMES utf8 */", Line: 2, Delimiter: ";"},
+				{Text: "CREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END", Line: 4, Delimiter: ";;"},
+				{Text: "CREATE TRIGGER t1 BEFORE INSERT ON t FOR EACH ROW BEGIN SET NEW.a = 1; END", Line: 7, Delimiter: "$$"},
+				{Text: `SELECT "x;"`, Line: 9, Delimiter: ";"},
+			},
+		},
+		{
+			engineType: db.MySQL,
+			statement:  "DELIMITER\nSELECT 1;",
+			wantErr:    "invalid statement at line 1: DELIMITER must be followed by the delimiter",
+		},
+		{
+			engineType: db.SQLite,
+			statement:  "SELECT 1;\n\nSELECT [a;\nb] FROM t;\n-- only comment;",
+			want: []SingleSQL{
+				{Text: "SELECT 1", Line: 1, Delimiter: ";"},
+				{Text: "SELECT [a;\nb] FROM t", Line: 3, Delimiter: ";"},
+			},
+		},
+		{
+			engineType: db.ClickHouse,
+			statement:  "SELECT 1;\nSELECT 'a\\';\nb",
+			wantErr:    "invalid statement at line 2: unterminated quoted text '",
+		},
+		{
+			engineType: db.Postgres,
+			statement:  "/* outer\n/* inner; */\n; */ SELECT 'a\n''b;\n' /* c */;\nSELECT \"x\n;\"",
+			want: []SingleSQL{
+				{Text: "SELECT 'a\n''b;\n'", Line: 3, Delimiter: ";"},
+				{Text: "SELECT \"x\n;\"", Line: 6, Delimiter: ";"},
+			},
+		},
+		{
+			engineType: db.MySQL,
+			statement:  "SELECT 'a\\\n;b', `c\n;`;\nSELECT 1",
+			want: []SingleSQL{
+				{Text: "SELECT 'a\\\n;b', `c\n;`", Line: 1, Delimiter: ";"},
+				{Text: "SELECT 1", Line: 4, Delimiter: ";"},
+			},
+		},
+		{
+			engineType: db.Snowflake,
+			statement:  "SELECT $$a;\nb$$; // comment;\nSELECT 2",
+			want: []SingleSQL{
+				{Text: "SELECT $$a;\nb$$", Line: 1, Delimiter: ";"},
+				{Text: "SELECT 2", Line: 3, Delimiter: ";"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var got []SingleSQL
+		sc := bufio.NewScanner(strings.NewReader(test.statement))
+		err := ApplyMultiStatements(test.engineType, sc, func(stmt *SingleSQL) error {
+			got = append(got, *stmt)
+			return nil
+		})
+		if err != nil {
+			if test.wantErr == "" || err.Error() != test.wantErr {
+				t.Errorf("%s %q: ApplyMultiStatements() got error %q, want error %q", test.engineType, test.statement, err, test.wantErr)
+			}
+			continue
+		}
+		if test.wantErr != "" {
+			t.Errorf("%s %q: ApplyMultiStatements() expected error %q", test.engineType, test.statement, test.wantErr)
+			continue
+		}
+		if diff := pretty.Diff(got, test.want); len(diff) > 0 {
+			t.Errorf("%s %q: got %+v, want %+v, diff %+v.", test.engineType, test.statement, got, test.want, diff)
+		}
+	}
+}
+
+func TestApplyMultiStatementsError(t *testing.T) {
+	statement := "CREATE TABLE t (id INT);\n\n-- Bad statement\nINSERT INTO t\nVALUES ('x');\nSELECT 1;"
+	sc := bufio.NewScanner(strings.NewReader(statement))
+	count := 0
+	err := ApplyMultiStatements(db.Postgres, sc, func(stmt *SingleSQL) error {
+		count++
+		if strings.HasPrefix(stmt.Text, "INSERT") {
+			return fmt.Errorf("invalid input syntax")
+		}
+		return nil
+	})
+	want := "failed to execute the statement at line 4: invalid input syntax"
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want error %q", err, want)
+	}
+	if count != 2 {
+		t.Errorf("got %d statements applied, want 2", count)
+	}
+}
+
+func TestApplyMultiStatementsLongScript(t *testing.T) {
+	// The text split already is dropped as the script is fed, which must keep the statements and the line numbers intact.
+	var sb strings.Builder
+	var want []SingleSQL
+	line := 1
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&sb, "INSERT INTO t VALUES (%d,\n'%d');\n", i, i)
+		want = append(want, SingleSQL{Text: fmt.Sprintf("INSERT INTO t VALUES (%d,\n'%d')", i, i), Line: line, Delimiter: ";"})
+		line += 2
+	}
+	sb.WriteString("CREATE FUNCTION f() RETURNS int AS $$\n")
+	for i := 0; i < 1000; i++ {
+		sb.WriteString("SELECT 1;\n")
+	}
+	sb.WriteString("$$ LANGUAGE sql;\n")
+	want = append(want, SingleSQL{Text: "CREATE FUNCTION f() RETURNS int AS $$\n" + strings.Repeat("SELECT 1;\n", 1000) + "$$ LANGUAGE sql", Line: line, Delimiter: ";"})
+
+	var got []SingleSQL
+	sc := bufio.NewScanner(strings.NewReader(sb.String()))
+	if err := ApplyMultiStatements(db.Postgres, sc, func(stmt *SingleSQL) error {
+		got = append(got, *stmt)
+		return nil
+	}); err != nil {
+		t.Fatalf("ApplyMultiStatements() got error: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d statements, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("statement %d got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	tidbparser "github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+)
+
+// newTiDBParser returns the MySQL parser of TiDB.
+func newTiDBParser() *tidbparser.Parser {
+	p := tidbparser.New()
+
+	// To support MySQL8 window function syntax.
+	// See https://github.com/bytebase/bytebase/issues/175.
+	p.EnableWindowFunc(true)
+
+	return p
+}
+
+// parseMySQL parses the MySQL statements.
+func parseMySQL(statement string) ([]ast.StmtNode, error) {
+	nodeList, _, err := newTiDBParser().Parse(statement, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the statement: %w", err)
+	}
+	return nodeList, nil
+}
+
+// SplitMultiSQL splits the multi-statement SQL script of the engine into the statements, without the trailing semicolons.
+// MySQL and TiDB statements are split by the MySQL parser, while the statements of the other engines are split
+// by the tokenizer of the engine, so the semicolons in the string literals, the quoted identifiers and the comments
+// don't split the statement. The statements consisting of only comments are skipped.
+func SplitMultiSQL(engineType db.Type, statement string) ([]string, error) {
+	var list []string
+	switch engineType {
+	case db.MySQL, db.TiDB:
+		nodeList, err := parseMySQL(statement)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodeList {
+			text, err := trimMySQLStatement(node.Text())
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, text)
+		}
+	default:
+		tokenList, err := tokenize(engineType, statement)
+		if err != nil {
+			return nil, err
+		}
+		start, hasCode := 0, false
+		for _, token := range tokenList {
+			switch token.typ {
+			case tokenSpace, tokenComment:
+			case tokenSemicolon:
+				if hasCode {
+					list = append(list, strings.TrimSpace(statement[start:token.pos]))
+				}
+				start, hasCode = token.pos+len(token.text), false
+			default:
+				hasCode = true
+			}
+		}
+		if hasCode {
+			list = append(list, strings.TrimSpace(statement[start:]))
+		}
+	}
+	return list, nil
+}
+
+// trimMySQLStatement trims the spaces and the trailing semicolon of the statement text returned by the MySQL parser.
+func trimMySQLStatement(statement string) (string, error) {
+	tokenList, err := tokenize(db.MySQL, statement)
+	if err != nil {
+		return "", err
+	}
+	for i := len(tokenList) - 1; i >= 0; i-- {
+		switch tokenList[i].typ {
+		case tokenSpace:
+			continue
+		case tokenSemicolon:
+			return strings.TrimSpace(statement[:tokenList[i].pos]), nil
+		}
+		break
+	}
+	return strings.TrimSpace(statement), nil
+}
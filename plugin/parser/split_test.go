@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+
+	// Register the TiDB parser driver for parsing the literals.
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+func TestSplitMultiSQL(t *testing.T) {
+	tests := []struct {
+		engineType db.Type
+		statement  string
+		want       []string
+		wantErr    bool
+	}{
+		{
+			engineType: db.MySQL,
+			statement:  "SELECT 1;\n  -- comment\nSHOW TABLES ;\nDESC t; SELECT ';' FROM t WHERE a = \"x;\"",
+			want:       []string{"SELECT 1", "-- comment\nSHOW TABLES", "DESC t", "SELECT ';' FROM t WHERE a = \"x;\""},
+		},
+		{
+			engineType: db.MySQL,
+			statement:  "SELECT * FROM",
+			wantErr:    true,
+		},
+		{
+			engineType: db.Postgres,
+			statement: `SELECT 'a;''b' AS "c;d"; -- trailing; comment
+/* block /* nested; */ comment; */ SELECT $$x;y$$, $body$;$body$, $1;
+;;  -- only comment;
+SELECT 1`,
+			want: []string{`SELECT 'a;''b' AS "c;d"`, "-- trailing; comment\n/* block /* nested; */ comment; */ SELECT $$x;y$$, $body$;$body$, $1", "-- only comment;\nSELECT 1"},
+		},
+		{
+			engineType: db.Postgres,
+			statement:  "SELECT 'unterminated;",
+			wantErr:    true,
+		},
+		{
+			engineType: db.SQLite,
+			statement:  "SELECT [a;b], `c;d` FROM t; SELECT 2;",
+			want:       []string{"SELECT [a;b], `c;d` FROM t", "SELECT 2"},
+		},
+		{
+			engineType: db.ClickHouse,
+			statement:  "SELECT 'a\\';b'; SELECT 2",
+			want:       []string{"SELECT 'a\\';b'", "SELECT 2"},
+		},
+		{
+			engineType: db.Snowflake,
+			statement:  "SELECT 1 // comment;\n; SELECT $$a;b$$",
+			want:       []string{"SELECT 1 // comment;", "SELECT $$a;b$$"},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := SplitMultiSQL(test.engineType, test.statement)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("%s %q: SplitMultiSQL() unexpected error: %v", test.engineType, test.statement, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("%s %q: SplitMultiSQL() expected error", test.engineType, test.statement)
+			continue
+		}
+		if diff := pretty.Diff(got, test.want); len(diff) > 0 {
+			t.Errorf("%s %q: got %+v, want %+v, diff %+v.", test.engineType, test.statement, got, test.want, diff)
+		}
+	}
+}
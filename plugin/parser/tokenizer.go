@@ -0,0 +1,252 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// tokenType is the type of the lexical token.
+type tokenType int
+
+const (
+	tokenSpace tokenType = iota
+	tokenComment
+	// tokenWord is a keyword, an unquoted identifier or a number.
+	tokenWord
+	// tokenString is a string literal, including the dollar-quoted string of Postgres and Snowflake.
+	tokenString
+	tokenQuotedIdentifier
+	tokenSemicolon
+	// tokenSymbol is a single character of the operators and the punctuations.
+	tokenSymbol
+)
+
+// token is the lexical token of the SQL text.
+type token struct {
+	typ tokenType
+	// text is the source text of the token.
+	text string
+	// pos is the byte offset of the token in the SQL text.
+	pos int
+}
+
+// SyntaxError is the syntax error found by the tokenizer or the parser.
+type SyntaxError struct {
+	// Offset is the byte offset of the SQL text where the error is.
+	Offset  int
+	Message string
+}
+
+// Error implements error.
+func (e *SyntaxError) Error() string {
+	return e.Message
+}
+
+// dialect is the lexical rules of an engine, which determine where the quoted text and the comments end,
+// so the semicolons inside them aren't taken as the statement delimiter.
+type dialect struct {
+	// backslashEscape is whether the backslash escapes the next character in the quoted text.
+	backslashEscape bool
+	// dashCommentNeedsSpace is whether the "--" comment must be followed by a whitespace, e.g. "1--1" is "1 - (-1)" in MySQL.
+	dashCommentNeedsSpace bool
+	// doubleQuotedString is whether the double-quoted text is a string literal instead of a quoted identifier.
+	doubleQuotedString  bool
+	backquoteIdentifier bool
+	bracketIdentifier   bool
+	hashComment         bool
+	doubleSlashComment  bool
+	nestedBlockComment  bool
+	dollarQuote         bool
+}
+
+func getDialect(engineType db.Type) dialect {
+	switch engineType {
+	case db.MySQL, db.TiDB:
+		return dialect{backslashEscape: true, dashCommentNeedsSpace: true, doubleQuotedString: true, backquoteIdentifier: true, hashComment: true}
+	case db.Postgres:
+		return dialect{nestedBlockComment: true, dollarQuote: true}
+	case db.SQLite:
+		return dialect{backquoteIdentifier: true, bracketIdentifier: true}
+	case db.ClickHouse:
+		return dialect{backslashEscape: true, backquoteIdentifier: true}
+	case db.Snowflake:
+		return dialect{backslashEscape: true, doubleSlashComment: true, dollarQuote: true}
+	}
+	return dialect{}
+}
+
+// tokenize splits the SQL text into the lexical tokens of the engine.
+func tokenize(engineType db.Type, text string) ([]token, error) {
+	d := getDialect(engineType)
+	var tokenList []token
+	pos := 0
+	for pos < len(text) {
+		typ, end, err := d.scan(text, pos)
+		if err != nil {
+			return nil, &SyntaxError{Offset: pos, Message: fmt.Sprintf("%v at offset %d", err, pos)}
+		}
+		tokenList = append(tokenList, token{typ: typ, text: text[pos:end], pos: pos})
+		pos = end
+	}
+	return tokenList, nil
+}
+
+// scanState is the progress of scanning the quoted text or the block comment unterminated at the end of the text,
+// so the scanning resumes there once more text is appended instead of starting over from the opening quote.
+type scanState struct {
+	// from is the offset to resume scanning from, or 0 if the scanning starts from the token start.
+	from int
+	// depth is the nesting depth of the unterminated block comment.
+	depth int
+}
+
+// scan scans the token starting at pos, and returns the token type and the end offset.
+// It returns an error if the quoted text or the comment is unterminated.
+func (d dialect) scan(text string, pos int) (tokenType, int, error) {
+	return d.resumeScan(text, pos, &scanState{})
+}
+
+// resumeScan is scan resuming from the state, and records the progress in the state if the token is unterminated.
+// The text must end with a newline to be resumed later, so no escape sequence or closing sequence spans the end.
+func (d dialect) resumeScan(text string, pos int, state *scanState) (tokenType, int, error) {
+	c := text[pos]
+	switch {
+	case isSpace(c):
+		end := pos + 1
+		for end < len(text) && isSpace(text[end]) {
+			end++
+		}
+		return tokenSpace, end, nil
+	case c == '-' && strings.HasPrefix(text[pos:], "--") && (!d.dashCommentNeedsSpace || pos+2 == len(text) || isSpace(text[pos+2])),
+		c == '#' && d.hashComment,
+		c == '/' && d.doubleSlashComment && strings.HasPrefix(text[pos:], "//"):
+		end := strings.IndexByte(text[pos:], '\n')
+		if end < 0 {
+			return tokenComment, len(text), nil
+		}
+		return tokenComment, pos + end + 1, nil
+	case c == '/' && strings.HasPrefix(text[pos:], "/*"):
+		end, err := d.scanBlockComment(text, pos, state)
+		return tokenComment, end, err
+	case c == '\'':
+		end, err := d.scanQuoted(text, pos, state, '\'', d.backslashEscape)
+		return tokenString, end, err
+	case c == '"':
+		if d.doubleQuotedString {
+			end, err := d.scanQuoted(text, pos, state, '"', d.backslashEscape)
+			return tokenString, end, err
+		}
+		end, err := d.scanQuoted(text, pos, state, '"', false)
+		return tokenQuotedIdentifier, end, err
+	case c == '`' && d.backquoteIdentifier:
+		end, err := d.scanQuoted(text, pos, state, '`', false)
+		return tokenQuotedIdentifier, end, err
+	case c == '[' && d.bracketIdentifier:
+		from := state.resumeFrom(pos + 1)
+		end := strings.IndexByte(text[from:], ']')
+		if end < 0 {
+			state.from = len(text)
+			return 0, 0, fmt.Errorf("unterminated quoted identifier")
+		}
+		return tokenQuotedIdentifier, from + end + 1, nil
+	case c == '$' && d.dollarQuote:
+		if tag, ok := scanDollarQuoteTag(text, pos); ok {
+			from := state.resumeFrom(pos + len(tag))
+			end := strings.Index(text[from:], tag)
+			if end < 0 {
+				state.from = len(text)
+				return 0, 0, fmt.Errorf("unterminated dollar-quoted string")
+			}
+			return tokenString, from + end + len(tag), nil
+		}
+		return tokenSymbol, pos + 1, nil
+	case c == ';':
+		return tokenSemicolon, pos + 1, nil
+	case isWordChar(c):
+		end := pos + 1
+		// "$" is allowed in the identifiers after the first character, e.g. "a$b" in Postgres.
+		for end < len(text) && (isWordChar(text[end]) || text[end] == '$') {
+			end++
+		}
+		return tokenWord, end, nil
+	}
+	return tokenSymbol, pos + 1, nil
+}
+
+// resumeFrom returns the offset to resume scanning from, which is start if the scanning hasn't started.
+func (s *scanState) resumeFrom(start int) int {
+	if s.from > start {
+		return s.from
+	}
+	return start
+}
+
+// scanQuoted scans the text quoted by the quote character, where the quote is escaped by doubling it,
+// or by the backslash if backslashEscape is true. It returns the offset after the closing quote.
+func (dialect) scanQuoted(text string, pos int, state *scanState, quote byte, backslashEscape bool) (int, error) {
+	for i := state.resumeFrom(pos + 1); i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			if backslashEscape {
+				i++
+			}
+		case quote:
+			if i+1 < len(text) && text[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, nil
+		}
+	}
+	state.from = len(text)
+	return 0, fmt.Errorf("unterminated quoted text %c", quote)
+}
+
+// scanBlockComment scans the /* */ comment, which can be nested in Postgres.
+func (d dialect) scanBlockComment(text string, pos int, state *scanState) (int, error) {
+	depth := state.depth
+	for i := state.resumeFrom(pos); i+1 < len(text); i++ {
+		switch {
+		case text[i] == '/' && text[i+1] == '*':
+			if depth == 0 || d.nestedBlockComment {
+				depth++
+			}
+			i++
+		case text[i] == '*' && text[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	state.from, state.depth = len(text), depth
+	return 0, fmt.Errorf("unterminated comment")
+}
+
+// scanDollarQuoteTag returns the opening tag of the dollar-quoted string at pos, e.g. "$$" or "$body$".
+// The tag can't start with a digit, so the positional parameters such as "$1" aren't taken as the tag.
+func scanDollarQuoteTag(text string, pos int) (string, bool) {
+	for i := pos + 1; i < len(text); i++ {
+		c := text[i]
+		if c == '$' {
+			return text[pos : i+1], true
+		}
+		if !isWordChar(c) || (i == pos+1 && c >= '0' && c <= '9') {
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// isWordChar returns whether the byte is part of the keywords, the unquoted identifiers and the numbers.
+// The bytes of the multi-byte UTF-8 characters are taken as the word characters.
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c >= 0x80
+}
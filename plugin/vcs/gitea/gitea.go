@@ -0,0 +1,950 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"go.uber.org/zap"
+)
+
+const (
+	// SecretTokenLength is the length of secret token.
+	SecretTokenLength = 16
+
+	// SignatureHeader is the header of the HMAC-SHA256 signature of the webhook payload.
+	SignatureHeader = "X-Gitea-Signature"
+	// EventHeader is the header of the webhook event type.
+	EventHeader = "X-Gitea-Event"
+
+	maxRetries = 3
+
+	// apiPath is the API path.
+	apiPath = "api/v1"
+	// pullRequestFilePageSize is the page size of listing the pull request files, which is the default max page size of Gitea.
+	pullRequestFilePageSize = 50
+	// treePageSize is the page size of listing the git tree, which is the default max page size of Gitea.
+	treePageSize = 1000
+)
+
+var (
+	_ vcs.Provider = (*Provider)(nil)
+)
+
+// WebhookType is the Gitea webhook event type, sent in the X-Gitea-Event header.
+type WebhookType string
+
+const (
+	// WebhookPush is the webhook type for push.
+	WebhookPush WebhookType = "push"
+	// WebhookPullRequest is the webhook type for pull request.
+	WebhookPullRequest WebhookType = "pull_request"
+)
+
+func (e WebhookType) String() string {
+	switch e {
+	case WebhookPush:
+		return "push"
+	case WebhookPullRequest:
+		return "pull_request"
+	}
+	return "UNKNOWN"
+}
+
+// WebhookInfo is the API message for webhook info.
+type WebhookInfo struct {
+	ID int `json:"id"`
+}
+
+// WebhookConfig is the API message for webhook config.
+type WebhookConfig struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Secret      string `json:"secret"`
+}
+
+// WebhookPost is the API message for webhook POST.
+type WebhookPost struct {
+	// Type is always "gitea", which is the native webhook type of Gitea.
+	Type   string        `json:"type"`
+	Config WebhookConfig `json:"config"`
+	Events []string      `json:"events"`
+	// BranchFilter is the glob pattern of the branches whose push events are sent.
+	BranchFilter string `json:"branch_filter"`
+	Active       bool   `json:"active"`
+}
+
+// WebhookPatch is the API message for webhook PATCH.
+type WebhookPatch struct {
+	Config       WebhookConfig `json:"config"`
+	Events       []string      `json:"events"`
+	BranchFilter string        `json:"branch_filter"`
+}
+
+// WebhookRepository is the API message for webhook repository.
+type WebhookRepository struct {
+	ID       int    `json:"id"`
+	FullName string `json:"full_name"`
+	HTMLURL  string `json:"html_url"`
+}
+
+// WebhookCommitAuthor is the API message for webhook commit author.
+type WebhookCommitAuthor struct {
+	Name string `json:"name"`
+}
+
+// WebhookCommit is the API message for webhook commit.
+type WebhookCommit struct {
+	ID           string              `json:"id"`
+	Message      string              `json:"message"`
+	Timestamp    string              `json:"timestamp"`
+	URL          string              `json:"url"`
+	Author       WebhookCommitAuthor `json:"author"`
+	AddedList    []string            `json:"added"`
+	ModifiedList []string            `json:"modified"`
+	RemovedList  []string            `json:"removed"`
+}
+
+// Title returns the commit title, which is the first line of the commit message.
+func (c WebhookCommit) Title() string {
+	return strings.SplitN(c.Message, "\n", 2)[0]
+}
+
+// WebhookPusher is the API message for webhook pusher.
+type WebhookPusher struct {
+	Login    string `json:"login"`
+	FullName string `json:"full_name"`
+}
+
+// WebhookPushEvent is the API message for webhook push event.
+type WebhookPushEvent struct {
+	Ref        string            `json:"ref"`
+	Repository WebhookRepository `json:"repository"`
+	Pusher     WebhookPusher     `json:"pusher"`
+	CommitList []WebhookCommit   `json:"commits"`
+}
+
+// PullRequestBranch is the API message for the head or base branch of the pull request.
+type PullRequestBranch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+// PullRequest is the API message for pull request.
+type PullRequest struct {
+	Number  int               `json:"number"`
+	Title   string            `json:"title"`
+	HTMLURL string            `json:"html_url"`
+	Head    PullRequestBranch `json:"head"`
+	Base    PullRequestBranch `json:"base"`
+}
+
+// WebhookPullRequestEvent is the API message for webhook pull request event.
+type WebhookPullRequestEvent struct {
+	// Action is one of opened, synchronized, reopened, closed, edited and etc.
+	Action      string            `json:"action"`
+	Number      int               `json:"number"`
+	PullRequest PullRequest       `json:"pull_request"`
+	Repository  WebhookRepository `json:"repository"`
+}
+
+// Signature returns the X-Gitea-Signature header of the payload, which is the hex encoded HMAC-SHA256
+// of the payload using the webhook secret as the key.
+func Signature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	// Write never returns an error for hash.Hash.
+	_, _ = mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateWebhookSignature validates the X-Gitea-Signature header of the payload.
+func ValidateWebhookSignature(signature, secret string, payload []byte) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	want, err := hex.DecodeString(Signature(secret, payload))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, want)
+}
+
+// FileCommit is the API message for file commit.
+type FileCommit struct {
+	Message string `json:"message"`
+	// Content is base64 encoded.
+	Content string `json:"content"`
+	Branch  string `json:"branch"`
+	// SHA is the blob SHA of the file being replaced.
+	SHA string `json:"sha,omitempty"`
+}
+
+// File is the API message for file contents.
+type File struct {
+	SHA           string `json:"sha"`
+	LastCommitSHA string `json:"last_commit_sha"`
+}
+
+// PullRequestFile is the API message for pull request file.
+type PullRequestFile struct {
+	Filename string `json:"filename"`
+	// Status is one of added, deleted, changed, renamed and etc.
+	Status string `json:"status"`
+}
+
+// IssueComment is the API message for issue comment. A pull request is also an issue in Gitea.
+type IssueComment struct {
+	Body string `json:"body"`
+}
+
+// CommitStatus is the API message for commit status.
+type CommitStatus struct {
+	// State is one of pending, success, error, failure and warning.
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// BranchCreate is the API message for creating a branch.
+type BranchCreate struct {
+	NewBranchName string `json:"new_branch_name"`
+	OldBranchName string `json:"old_branch_name"`
+}
+
+// PullRequestCreate is the API message for creating a pull request.
+type PullRequestCreate struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+}
+
+// Tree is the API message for a page of the git tree.
+type Tree struct {
+	TreeNodeList []TreeNode `json:"tree"`
+	TotalCount   int        `json:"total_count"`
+}
+
+// TreeNode is the API message for a node of the git tree.
+type TreeNode struct {
+	Path string `json:"path"`
+	// Type is either "blob" for a file or "tree" for a directory.
+	Type string `json:"type"`
+}
+
+// User is the API message for user.
+type User struct {
+	Login         string `json:"login"`
+	FullName      string `json:"full_name"`
+	Email         string `json:"email"`
+	ProhibitLogin bool   `json:"prohibit_login"`
+}
+
+func init() {
+	vcs.Register(vcs.Gitea, newProvider)
+}
+
+// Provider is the Gitea provider, which also works with Forgejo.
+// The repositoryID is the full name of the repository, e.g. "bytebase/bytebase".
+type Provider struct {
+	l *zap.Logger
+}
+
+func newProvider(config vcs.ProviderConfig) vcs.Provider {
+	return &Provider{
+		l: config.Logger,
+	}
+}
+
+// APIURL returns the API URL path of a Gitea instance.
+func (provider *Provider) APIURL(instanceURL string) string {
+	return fmt.Sprintf("%s/%s", instanceURL, apiPath)
+}
+
+// TryLogin will try to login Gitea.
+func (provider *Provider) TryLogin(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) (*vcs.UserInfo, error) {
+	code, body, err := httpGet(
+		instanceURL,
+		"user",
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if code == 404 {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to fetch user info from Gitea instance %s", instanceURL))
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to read user info from Gitea instance %s, status code: %d",
+			instanceURL,
+			code,
+		)
+	}
+
+	user := &User{}
+	if err := json.Unmarshal([]byte(body), user); err != nil {
+		return nil, err
+	}
+
+	name := user.FullName
+	if name == "" {
+		name = user.Login
+	}
+	state := vcs.UserStateActive
+	if user.ProhibitLogin {
+		state = vcs.UserStateArchived
+	}
+	return &vcs.UserInfo{
+		Email: user.Email,
+		Name:  name,
+		State: state,
+	}, nil
+}
+
+// CreateFile creates a file.
+func (provider *Provider) CreateFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	body, err := json.Marshal(FileCommit{
+		Message: fileCommitCreate.CommitMessage,
+		Content: base64.StdEncoding.EncodeToString([]byte(fileCommitCreate.Content)),
+		Branch:  fileCommitCreate.Branch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal file commit: %w", err)
+	}
+
+	code, _, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("repos/%s/contents/%s", repositoryID, escapePath(filePath)),
+		&oauthCtx.AccessToken,
+		body,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s on Gitea instance %s, err: %w", filePath, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to create file %s on Gitea instance %s, status code: %d",
+			filePath,
+			instanceURL,
+			code,
+		)
+	}
+	return nil
+}
+
+// OverwriteFile overwrite the content of a file.
+// Gitea detects conflicting writes by the blob SHA instead of the commit ID, so we look up the blob SHA of the file
+// at the last commit. Gitea rejects the write if the file has changed since then.
+func (provider *Provider) OverwriteFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	file, err := provider.readFileContents(oauthCtx, instanceURL, repositoryID, filePath, fileCommitCreate.LastCommitID)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(FileCommit{
+		Message: fileCommitCreate.CommitMessage,
+		Content: base64.StdEncoding.EncodeToString([]byte(fileCommitCreate.Content)),
+		Branch:  fileCommitCreate.Branch,
+		SHA:     file.SHA,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal file commit: %w", err)
+	}
+
+	code, _, err := httpPut(
+		instanceURL,
+		fmt.Sprintf("repos/%s/contents/%s", repositoryID, escapePath(filePath)),
+		&oauthCtx.AccessToken,
+		body,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s on Gitea instance %s, error: %w", filePath, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to create file %s on Gitea instance %s, status code: %d",
+			filePath,
+			instanceURL,
+			code,
+		)
+	}
+	return nil
+}
+
+// ReadFile reads the content of a file.
+func (provider *Provider) ReadFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, commitID string) (string, error) {
+	code, body, err := httpGet(
+		instanceURL,
+		fmt.Sprintf("repos/%s/raw/%s?ref=%s", repositoryID, escapePath(filePath), url.QueryEscape(commitID)),
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s from Gitea instance %s: %w", filePath, instanceURL, err)
+	}
+
+	if code == 404 {
+		return "", common.Errorf(common.NotFound, fmt.Errorf("failed to read file %s from Gitea instance %s, file not found", filePath, instanceURL))
+	} else if code >= 300 {
+		return "", fmt.Errorf("failed to read file %s from Gitea instance %s, status code: %d",
+			filePath,
+			instanceURL,
+			code,
+		)
+	}
+
+	return body, nil
+}
+
+// ReadFileMeta reads the metadata of a file.
+func (provider *Provider) ReadFileMeta(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, branch string) (*vcs.FileMeta, error) {
+	file, err := provider.readFileContents(oauthCtx, instanceURL, repositoryID, filePath, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vcs.FileMeta{
+		LastCommitID: file.LastCommitSHA,
+	}, nil
+}
+
+// readFileContents reads the file contents metadata at the ref, which is a branch or a commit ID.
+func (provider *Provider) readFileContents(oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, ref string) (*File, error) {
+	code, body, err := httpGet(
+		instanceURL,
+		fmt.Sprintf("repos/%s/contents/%s?ref=%s", repositoryID, escapePath(filePath), url.QueryEscape(ref)),
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file meta %s from Gitea instance %s: %w", filePath, instanceURL, err)
+	}
+
+	if code == 404 {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to read file meta %s from Gitea instance %s, file not found", filePath, instanceURL))
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to read file meta %s from Gitea instance %s, status code: %d",
+			filePath,
+			instanceURL,
+			code,
+		)
+	}
+
+	file := &File{}
+	if err := json.Unmarshal([]byte(body), file); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal file meta from Gitea instance %s: %w", instanceURL, err)
+	}
+	return file, nil
+}
+
+// CreateWebhook creates a webhook in a Gitea repository.
+func (provider *Provider) CreateWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, payload []byte) (string, error) {
+	resourcePath := fmt.Sprintf("repos/%s/hooks", repositoryID)
+	code, body, err := httpPost(
+		instanceURL,
+		resourcePath,
+		&oauthCtx.AccessToken,
+		payload,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create webhook for repository %s from Gitea instance %s: %w", repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return "", fmt.Errorf("failed to create webhook for repository %s from Gitea instance %s, status code: %d, body: %s",
+			repositoryID,
+			instanceURL,
+			code,
+			body,
+		)
+	}
+
+	webhookInfo := &WebhookInfo{}
+	if err := json.Unmarshal([]byte(body), webhookInfo); err != nil {
+		return "", fmt.Errorf("failed to unmarshal create webhook response for repository %s from Gitea instance %s: %w", repositoryID, instanceURL, err)
+	}
+	return strconv.Itoa(webhookInfo.ID), nil
+}
+
+// PatchWebhook patches a webhook in a Gitea repository.
+func (provider *Provider) PatchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, webhookID string, payload []byte) error {
+	resourcePath := fmt.Sprintf("repos/%s/hooks/%s", repositoryID, webhookID)
+	code, _, err := httpPatch(
+		instanceURL,
+		resourcePath,
+		&oauthCtx.AccessToken,
+		payload,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to patch webhook ID %s for repository %s from Gitea instance %s: %w", webhookID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to patch webhook ID %s for repository %s from Gitea instance %s, status code: %d", webhookID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// DeleteWebhook deletes a webhook in a Gitea repository.
+func (provider *Provider) DeleteWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, webhookID string) error {
+	resourcePath := fmt.Sprintf("repos/%s/hooks/%s", repositoryID, webhookID)
+	code, _, err := httpDelete(
+		instanceURL,
+		resourcePath,
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook ID %s for repository %s from Gitea instance %s: %w", webhookID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to delete webhook ID %s for repository %s from Gitea instance %s, status code: %d", webhookID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// ListMergeRequestFiles lists the files changed in a Gitea pull request.
+func (provider *Provider) ListMergeRequestFiles(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestID string) ([]vcs.MergeRequestFile, error) {
+	var fileList []vcs.MergeRequestFile
+	for page := 1; ; page++ {
+		code, body, err := httpGet(
+			instanceURL,
+			fmt.Sprintf("repos/%s/pulls/%s/files?limit=%d&page=%d", repositoryID, mergeRequestID, pullRequestFilePageSize, page),
+			&oauthCtx.AccessToken,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull request %s files for repository %s from Gitea instance %s: %w", mergeRequestID, repositoryID, instanceURL, err)
+		}
+
+		if code == 404 {
+			return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to list pull request %s files for repository %s from Gitea instance %s, pull request not found", mergeRequestID, repositoryID, instanceURL))
+		} else if code >= 300 {
+			return nil, fmt.Errorf("failed to list pull request %s files for repository %s from Gitea instance %s, status code: %d", mergeRequestID, repositoryID, instanceURL, code)
+		}
+
+		var files []PullRequestFile
+		if err := json.Unmarshal([]byte(body), &files); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pull request files from Gitea instance %s: %w", instanceURL, err)
+		}
+		for _, file := range files {
+			fileList = append(fileList, vcs.MergeRequestFile{
+				Path:    file.Filename,
+				Deleted: file.Status == "deleted",
+			})
+		}
+		if len(files) < pullRequestFilePageSize {
+			break
+		}
+	}
+	return fileList, nil
+}
+
+// CreateMergeRequestComment creates a comment on a Gitea pull request.
+func (provider *Provider) CreateMergeRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestID string, content string) error {
+	body, err := json.Marshal(IssueComment{
+		Body: content,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue comment: %w", err)
+	}
+
+	code, _, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("repos/%s/issues/%s/comments", repositoryID, mergeRequestID),
+		&oauthCtx.AccessToken,
+		body,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pull request %s comment for repository %s on Gitea instance %s: %w", mergeRequestID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to create pull request %s comment for repository %s on Gitea instance %s, status code: %d", mergeRequestID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// SetCommitStatus sets the status of a commit in a Gitea repository.
+func (provider *Provider) SetCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, commitID string, status vcs.CommitStatus) error {
+	body, err := json.Marshal(CommitStatus{
+		State:       string(status.State),
+		Context:     status.Context,
+		Description: status.Description,
+		TargetURL:   status.TargetURL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal commit status: %w", err)
+	}
+
+	code, _, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("repos/%s/statuses/%s", repositoryID, commitID),
+		&oauthCtx.AccessToken,
+		body,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set commit %s status for repository %s on Gitea instance %s: %w", commitID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to set commit %s status for repository %s on Gitea instance %s, status code: %d", commitID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// CreateBranch creates a branch in a Gitea repository.
+func (provider *Provider) CreateBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, branch string, baseBranch string) error {
+	payload, err := json.Marshal(BranchCreate{
+		NewBranchName: branch,
+		OldBranchName: baseBranch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal branch: %w", err)
+	}
+
+	code, _, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("repos/%s/branches", repositoryID),
+		&oauthCtx.AccessToken,
+		payload,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s from %s for repository %s on Gitea instance %s: %w", branch, baseBranch, repositoryID, instanceURL, err)
+	}
+
+	if code == 409 {
+		return common.Errorf(common.Conflict, fmt.Errorf("failed to create branch %s from %s for repository %s on Gitea instance %s, branch already exists", branch, baseBranch, repositoryID, instanceURL))
+	} else if code >= 300 {
+		return fmt.Errorf("failed to create branch %s from %s for repository %s on Gitea instance %s, status code: %d", branch, baseBranch, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// CreateMergeRequest creates a pull request in a Gitea repository.
+// Gitea removes the head branch after merge by the repository setting, so RemoveSourceBranch is ignored.
+func (provider *Provider) CreateMergeRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestCreate vcs.MergeRequestCreate) (*vcs.MergeRequest, error) {
+	payload, err := json.Marshal(PullRequestCreate{
+		Title: mergeRequestCreate.Title,
+		Body:  mergeRequestCreate.Description,
+		Head:  mergeRequestCreate.SourceBranch,
+		Base:  mergeRequestCreate.TargetBranch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pull request: %w", err)
+	}
+
+	code, body, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("repos/%s/pulls", repositoryID),
+		&oauthCtx.AccessToken,
+		payload,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request from %s to %s for repository %s on Gitea instance %s: %w", mergeRequestCreate.SourceBranch, mergeRequestCreate.TargetBranch, repositoryID, instanceURL, err)
+	}
+
+	if code == 409 {
+		return nil, common.Errorf(common.Conflict, fmt.Errorf("failed to create pull request from %s to %s for repository %s on Gitea instance %s, pull request already exists", mergeRequestCreate.SourceBranch, mergeRequestCreate.TargetBranch, repositoryID, instanceURL))
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to create pull request from %s to %s for repository %s on Gitea instance %s, status code: %d", mergeRequestCreate.SourceBranch, mergeRequestCreate.TargetBranch, repositoryID, instanceURL, code)
+	}
+
+	pullRequest := &PullRequest{}
+	if err := json.Unmarshal([]byte(body), pullRequest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pull request from Gitea instance %s: %w", instanceURL, err)
+	}
+	return &vcs.MergeRequest{
+		ID:  strconv.Itoa(pullRequest.Number),
+		URL: pullRequest.HTMLURL,
+	}, nil
+}
+
+// ListRepositoryFiles lists the paths of the files under the directory recursively at the Git ref.
+func (provider *Provider) ListRepositoryFiles(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, directory string, ref string) ([]string, error) {
+	// The git tree API lists the whole repository, so we filter the files under the directory here.
+	prefix := ""
+	if directory != "" {
+		prefix = strings.TrimSuffix(directory, "/") + "/"
+	}
+	var fileList []string
+	for page := 1; ; page++ {
+		code, body, err := httpGet(
+			instanceURL,
+			fmt.Sprintf("repos/%s/git/trees/%s?recursive=true&per_page=%d&page=%d", repositoryID, url.PathEscape(ref), treePageSize, page),
+			&oauthCtx.AccessToken,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files of directory %q at %s for repository %s from Gitea instance %s: %w", directory, ref, repositoryID, instanceURL, err)
+		}
+
+		if code == 404 {
+			return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to list files of directory %q at %s for repository %s from Gitea instance %s, ref not found", directory, ref, repositoryID, instanceURL))
+		} else if code >= 300 {
+			return nil, fmt.Errorf("failed to list files of directory %q at %s for repository %s from Gitea instance %s, status code: %d", directory, ref, repositoryID, instanceURL, code)
+		}
+
+		tree := &Tree{}
+		if err := json.Unmarshal([]byte(body), tree); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal git tree from Gitea instance %s: %w", instanceURL, err)
+		}
+		for _, node := range tree.TreeNodeList {
+			if node.Type == "blob" && strings.HasPrefix(node.Path, prefix) {
+				fileList = append(fileList, node.Path)
+			}
+		}
+		if len(tree.TreeNodeList) < treePageSize || page*treePageSize >= tree.TotalCount {
+			break
+		}
+	}
+	return fileList, nil
+}
+
+// escapePath escapes each segment of the file path, the contents API takes the path as is.
+func escapePath(filePath string) string {
+	segmentList := strings.Split(filePath, "/")
+	for i, segment := range segmentList {
+		segmentList[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segmentList, "/")
+}
+
+// httpPost sends a POST request.
+func httpPost(instanceURL string, resourcePath string, token *string, body []byte, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("POST", instanceURL, resourcePath, token, body, oauthContext, refresher)
+}
+
+// httpGet sends a GET request.
+func httpGet(instanceURL string, resourcePath string, token *string, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("GET", instanceURL, resourcePath, token, nil, oauthContext, refresher)
+}
+
+// httpPut sends a PUT request.
+func httpPut(instanceURL string, resourcePath string, token *string, body []byte, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("PUT", instanceURL, resourcePath, token, body, oauthContext, refresher)
+}
+
+// httpPatch sends a PATCH request.
+func httpPatch(instanceURL string, resourcePath string, token *string, body []byte, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("PATCH", instanceURL, resourcePath, token, body, oauthContext, refresher)
+}
+
+// httpDelete sends a DELETE request.
+func httpDelete(instanceURL string, resourcePath string, token *string, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("DELETE", instanceURL, resourcePath, token, nil, oauthContext, refresher)
+}
+
+func httpRequest(method string, instanceURL string, resourcePath string, token *string, body []byte, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return retry(instanceURL, token, oauthContext, refresher, func() (*http.Response, error) {
+		url := fmt.Sprintf("%s/%s/%s", instanceURL, apiPath, resourcePath)
+		// The body is read on each retry.
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequest(method, url, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct %s %v (%w)", method, url, err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", *token))
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed %s %v (%w)", method, url, err)
+		}
+		return resp, nil
+	})
+}
+
+func retry(instanceURL string, token *string, oauthContext oauthContext, refresher common.TokenRefresher, f func() (*http.Response, error)) (code int, respBody string, err error) {
+	retries := 0
+RETRY:
+	retries++
+
+	resp, err := f()
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read gitea response body, code %v, error: %v", resp.StatusCode, err)
+	}
+
+	// Gitea returns 401 without the OAuth error details when the access token expires.
+	if resp.StatusCode == http.StatusUnauthorized && oauthContext.RefreshToken != "" {
+		if retries < maxRetries {
+			// Refresh and store the token.
+			if err := refreshToken(instanceURL, token, oauthContext, refresher); err != nil {
+				return 0, "", err
+			}
+			goto RETRY
+		}
+		return 0, "", fmt.Errorf("retries exceeded for oauth refresher; original code %v body %s", resp.StatusCode, string(body))
+	}
+
+	return resp.StatusCode, string(body), nil
+}
+
+// oauthContext is the request context for refreshing oauth token.
+type oauthContext struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+	GrantType    string `json:"grant_type"`
+}
+
+type refreshOauthResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	// token_type is not used.
+}
+
+func refreshToken(instanceURL string, oldToken *string, oauthContext oauthContext, refresher common.TokenRefresher) error {
+	url := fmt.Sprintf("%s/login/oauth/access_token", instanceURL)
+	oauthContext.GrantType = "refresh_token"
+	body, err := json.Marshal(oauthContext)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to construct refresh token POST %v (%w)", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send refresh token POST %v (%w)", url, err)
+	}
+	defer resp.Body.Close()
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read body from refresh token POST %v (%w)", url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch refresh token, response code %v body %s", resp.StatusCode, body)
+	}
+
+	var r refreshOauthResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return fmt.Errorf("failed to unmarshal body from refresh token POST %v (%w)", url, err)
+	}
+
+	// Update the old token to new value for retries.
+	*oldToken = r.AccessToken
+
+	var expireAt int64
+	if r.ExpiresIn != 0 {
+		expireAt = time.Now().Unix() + r.ExpiresIn
+	}
+	if err := refresher(r.AccessToken, r.RefreshToken, expireAt); err != nil {
+		return err
+	}
+
+	return nil
+}
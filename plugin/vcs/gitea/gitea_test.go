@@ -0,0 +1,43 @@
+package gitea
+
+import (
+	"testing"
+)
+
+func TestValidateWebhookSignature(t *testing.T) {
+	payload := []byte(`{"ref":"refs/heads/main"}`)
+	tests := []struct {
+		signature string
+		secret    string
+		want      bool
+	}{
+		{
+			signature: Signature("secret", payload),
+			secret:    "secret",
+			want:      true,
+		},
+		{
+			signature: Signature("secret", payload),
+			secret:    "another",
+			want:      false,
+		},
+		{
+			// Unlike GitHub, the signature doesn't have the algorithm prefix.
+			signature: "sha256=" + Signature("secret", payload),
+			secret:    "secret",
+			want:      false,
+		},
+		{
+			signature: "",
+			secret:    "",
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		got := ValidateWebhookSignature(test.signature, test.secret, payload)
+		if got != test.want {
+			t.Errorf("ValidateWebhookSignature(%q, %q) = %v, want %v", test.signature, test.secret, got, test.want)
+		}
+	}
+}
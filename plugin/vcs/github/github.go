@@ -0,0 +1,1047 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"go.uber.org/zap"
+)
+
+const (
+	// SecretTokenLength is the length of secret token.
+	SecretTokenLength = 16
+
+	// SignatureHeader is the header of the HMAC-SHA256 signature of the webhook payload.
+	SignatureHeader = "X-Hub-Signature-256"
+	// EventHeader is the header of the webhook event type.
+	EventHeader = "X-GitHub-Event"
+
+	maxRetries = 3
+
+	// cloudInstanceURL is the instance URL of github.com.
+	cloudInstanceURL = "https://github.com"
+	// cloudAPIURL is the API URL of github.com.
+	cloudAPIURL = "https://api.github.com"
+	// enterpriseAPIPath is the API path of GitHub Enterprise Server.
+	enterpriseAPIPath = "api/v3"
+
+	// pullRequestFilePageSize is the max page size of listing the pull request files.
+	pullRequestFilePageSize = 100
+
+	mediaTypeJSON = "application/vnd.github.v3+json"
+	mediaTypeRaw  = "application/vnd.github.v3.raw"
+)
+
+var (
+	_ vcs.Provider = (*Provider)(nil)
+)
+
+// WebhookType is the GitHub webhook event type, sent in the X-GitHub-Event header.
+type WebhookType string
+
+const (
+	// WebhookPush is the webhook type for push.
+	WebhookPush WebhookType = "push"
+	// WebhookPing is the webhook type sent by GitHub when the webhook is created.
+	WebhookPing WebhookType = "ping"
+	// WebhookPullRequest is the webhook type for pull request.
+	WebhookPullRequest WebhookType = "pull_request"
+)
+
+func (e WebhookType) String() string {
+	switch e {
+	case WebhookPush:
+		return "push"
+	case WebhookPing:
+		return "ping"
+	case WebhookPullRequest:
+		return "pull_request"
+	}
+	return "UNKNOWN"
+}
+
+// WebhookInfo is the API message for webhook info.
+type WebhookInfo struct {
+	ID int `json:"id"`
+}
+
+// WebhookConfig is the API message for webhook config.
+type WebhookConfig struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Secret      string `json:"secret"`
+	// InsecureSSL is "1" to skip the SSL verification, "0" otherwise.
+	// This is set to "1" to be consistent with GitLab, where the SSL verification isn't enabled.
+	InsecureSSL string `json:"insecure_ssl"`
+}
+
+// WebhookCreateOrUpdate is the API message for webhook POST and PATCH.
+// Unlike GitLab, GitHub doesn't filter the push events by branch, so we check the branch when receiving the event.
+type WebhookCreateOrUpdate struct {
+	// Name must be "web" on creation, and is omitted on update.
+	Name   string        `json:"name,omitempty"`
+	Config WebhookConfig `json:"config"`
+	Events []string      `json:"events"`
+	Active bool          `json:"active"`
+}
+
+// WebhookRepository is the API message for webhook repository.
+type WebhookRepository struct {
+	ID       int    `json:"id"`
+	FullName string `json:"full_name"`
+	HTMLURL  string `json:"html_url"`
+}
+
+// WebhookCommitAuthor is the API message for webhook commit author.
+type WebhookCommitAuthor struct {
+	Name string `json:"name"`
+}
+
+// WebhookCommit is the API message for webhook commit.
+type WebhookCommit struct {
+	ID           string              `json:"id"`
+	Message      string              `json:"message"`
+	Timestamp    string              `json:"timestamp"`
+	URL          string              `json:"url"`
+	Author       WebhookCommitAuthor `json:"author"`
+	AddedList    []string            `json:"added"`
+	ModifiedList []string            `json:"modified"`
+	RemovedList  []string            `json:"removed"`
+}
+
+// Title returns the commit title, which is the first line of the commit message.
+func (c WebhookCommit) Title() string {
+	return strings.SplitN(c.Message, "\n", 2)[0]
+}
+
+// WebhookPusher is the API message for webhook pusher.
+type WebhookPusher struct {
+	Name string `json:"name"`
+}
+
+// WebhookPushEvent is the API message for webhook push event.
+type WebhookPushEvent struct {
+	Ref        string            `json:"ref"`
+	Repository WebhookRepository `json:"repository"`
+	Pusher     WebhookPusher     `json:"pusher"`
+	CommitList []WebhookCommit   `json:"commits"`
+}
+
+// PullRequestBranch is the API message for the head or base branch of the pull request.
+type PullRequestBranch struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+// PullRequest is the API message for pull request.
+type PullRequest struct {
+	Number  int               `json:"number"`
+	Title   string            `json:"title"`
+	HTMLURL string            `json:"html_url"`
+	Head    PullRequestBranch `json:"head"`
+	Base    PullRequestBranch `json:"base"`
+}
+
+// WebhookPullRequestEvent is the API message for webhook pull request event.
+type WebhookPullRequestEvent struct {
+	// Action is one of opened, synchronize, reopened, closed, edited and etc.
+	Action      string            `json:"action"`
+	Number      int               `json:"number"`
+	PullRequest PullRequest       `json:"pull_request"`
+	Repository  WebhookRepository `json:"repository"`
+}
+
+// ValidateWebhookSignature validates the X-Hub-Signature-256 header, which is "sha256=" followed by
+// the hex encoded HMAC-SHA256 of the payload using the webhook secret as the key.
+func ValidateWebhookSignature(signature, secret string, payload []byte) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	if _, err := mac.Write(payload); err != nil {
+		return false
+	}
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// FileCommit is the API message for file commit.
+type FileCommit struct {
+	Message string `json:"message"`
+	// Content is base64 encoded.
+	Content string `json:"content"`
+	Branch  string `json:"branch"`
+	// SHA is the blob SHA of the file being replaced.
+	SHA string `json:"sha,omitempty"`
+}
+
+// File is the API message for file contents.
+type File struct {
+	SHA string `json:"sha"`
+}
+
+// Commit is the API message for commit.
+type Commit struct {
+	SHA string `json:"sha"`
+}
+
+// PullRequestFile is the API message for pull request file.
+type PullRequestFile struct {
+	Filename string `json:"filename"`
+	// Status is one of added, removed, modified, renamed, copied, changed and unchanged.
+	Status string `json:"status"`
+}
+
+// IssueComment is the API message for issue comment. A pull request is also an issue in GitHub.
+type IssueComment struct {
+	Body string `json:"body"`
+}
+
+// CommitStatus is the API message for commit status.
+type CommitStatus struct {
+	// State is one of error, failure, pending and success.
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// Reference is the API message for git reference.
+type Reference struct {
+	Ref    string          `json:"ref"`
+	Object ReferenceObject `json:"object"`
+}
+
+// ReferenceObject is the API message for the object the git reference points to.
+type ReferenceObject struct {
+	SHA string `json:"sha"`
+}
+
+// ReferenceCreate is the API message for creating a git reference.
+type ReferenceCreate struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+// PullRequestCreate is the API message for creating a pull request.
+type PullRequestCreate struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+}
+
+// Tree is the API message for git tree.
+type Tree struct {
+	TreeNodeList []TreeNode `json:"tree"`
+	// Truncated is true if the number of the nodes exceeds the limit of the recursive listing.
+	Truncated bool `json:"truncated"`
+}
+
+// TreeNode is the API message for a node of the git tree.
+type TreeNode struct {
+	Path string `json:"path"`
+	// Type is either "blob" for a file or "tree" for a directory.
+	Type string `json:"type"`
+}
+
+// User is the API message for user.
+type User struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// UserEmail is the API message for user email.
+type UserEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+func init() {
+	vcs.Register(vcs.GitHub, newProvider)
+}
+
+// Provider is the GitHub provider for both github.com and GitHub Enterprise Server.
+// The repositoryID is the full name of the repository, e.g. "bytebase/bytebase".
+type Provider struct {
+	l *zap.Logger
+}
+
+func newProvider(config vcs.ProviderConfig) vcs.Provider {
+	return &Provider{
+		l: config.Logger,
+	}
+}
+
+// APIURL returns the API URL path of a GitHub instance.
+func (provider *Provider) APIURL(instanceURL string) string {
+	return apiURL(instanceURL)
+}
+
+func apiURL(instanceURL string) string {
+	if instanceURL == cloudInstanceURL {
+		return cloudAPIURL
+	}
+	return fmt.Sprintf("%s/%s", instanceURL, enterpriseAPIPath)
+}
+
+// TryLogin will try to login GitHub.
+func (provider *Provider) TryLogin(ctx context.Context, oauthCtx common.OauthContext, instanceURL string) (*vcs.UserInfo, error) {
+	code, body, err := httpGet(
+		instanceURL,
+		"user",
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if code == 404 {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to fetch user info from GitHub instance %s", instanceURL))
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to read user info from GitHub instance %s, status code: %d",
+			instanceURL,
+			code,
+		)
+	}
+
+	user := &User{}
+	if err := json.Unmarshal([]byte(body), user); err != nil {
+		return nil, err
+	}
+
+	// The public email is empty if the user keeps the email private, we fall back to the primary verified email.
+	if user.Email == "" {
+		code, body, err := httpGet(
+			instanceURL,
+			"user/emails",
+			&oauthCtx.AccessToken,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if code >= 300 {
+			return nil, fmt.Errorf("failed to read user emails from GitHub instance %s, status code: %d",
+				instanceURL,
+				code,
+			)
+		}
+		var emailList []UserEmail
+		if err := json.Unmarshal([]byte(body), &emailList); err != nil {
+			return nil, err
+		}
+		for _, email := range emailList {
+			if email.Primary && email.Verified {
+				user.Email = email.Email
+				break
+			}
+		}
+		if user.Email == "" {
+			return nil, fmt.Errorf("failed to find the verified primary email of user %s from GitHub instance %s", user.Login, instanceURL)
+		}
+	}
+
+	name := user.Name
+	if name == "" {
+		name = user.Login
+	}
+	// GitHub doesn't expose the account state, suspended users can't be authenticated at all.
+	return &vcs.UserInfo{
+		Email: user.Email,
+		Name:  name,
+		State: vcs.UserStateActive,
+	}, nil
+}
+
+// CreateFile creates a file.
+func (provider *Provider) CreateFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	return provider.putFile(oauthCtx, instanceURL, repositoryID, filePath, fileCommitCreate, "")
+}
+
+// OverwriteFile overwrite the content of a file.
+// GitHub detects conflicting writes by the blob SHA instead of the commit ID, so we look up the blob SHA of the file
+// at the last commit. GitHub rejects the write if the file has changed since then.
+func (provider *Provider) OverwriteFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, fileCommitCreate vcs.FileCommitCreate) error {
+	code, body, err := httpGet(
+		instanceURL,
+		fmt.Sprintf("repos/%s/contents/%s?ref=%s", repositoryID, escapePath(filePath), url.QueryEscape(fileCommitCreate.LastCommitID)),
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to read file %s from GitHub instance %s: %w", filePath, instanceURL, err)
+	}
+
+	if code == 404 {
+		return common.Errorf(common.NotFound, fmt.Errorf("failed to read file %s from GitHub instance %s, file not found", filePath, instanceURL))
+	} else if code >= 300 {
+		return fmt.Errorf("failed to read file %s from GitHub instance %s, status code: %d",
+			filePath,
+			instanceURL,
+			code,
+		)
+	}
+
+	file := &File{}
+	if err := json.Unmarshal([]byte(body), file); err != nil {
+		return fmt.Errorf("failed to unmarshal file from GitHub instance %s: %w", instanceURL, err)
+	}
+
+	return provider.putFile(oauthCtx, instanceURL, repositoryID, filePath, fileCommitCreate, file.SHA)
+}
+
+// putFile creates or updates a file via the contents API. The sha is the blob SHA of the file being replaced, or empty for creating a file.
+func (provider *Provider) putFile(oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, fileCommitCreate vcs.FileCommitCreate, sha string) error {
+	body, err := json.Marshal(FileCommit{
+		Message: fileCommitCreate.CommitMessage,
+		Content: base64.StdEncoding.EncodeToString([]byte(fileCommitCreate.Content)),
+		Branch:  fileCommitCreate.Branch,
+		SHA:     sha,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal file commit: %w", err)
+	}
+
+	code, _, err := httpPut(
+		instanceURL,
+		fmt.Sprintf("repos/%s/contents/%s", repositoryID, escapePath(filePath)),
+		&oauthCtx.AccessToken,
+		body,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s on GitHub instance %s, err: %w", filePath, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to create file %s on GitHub instance %s, status code: %d",
+			filePath,
+			instanceURL,
+			code,
+		)
+	}
+	return nil
+}
+
+// ReadFile reads the content of a file.
+func (provider *Provider) ReadFile(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, commitID string) (string, error) {
+	code, body, err := httpGetRaw(
+		instanceURL,
+		fmt.Sprintf("repos/%s/contents/%s?ref=%s", repositoryID, escapePath(filePath), url.QueryEscape(commitID)),
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s from GitHub instance %s: %w", filePath, instanceURL, err)
+	}
+
+	if code == 404 {
+		return "", common.Errorf(common.NotFound, fmt.Errorf("failed to read file %s from GitHub instance %s, file not found", filePath, instanceURL))
+	} else if code >= 300 {
+		return "", fmt.Errorf("failed to read file %s from GitHub instance %s, status code: %d",
+			filePath,
+			instanceURL,
+			code,
+		)
+	}
+
+	return body, nil
+}
+
+// ReadFileMeta reads the metadata of a file.
+// The contents API doesn't return the last commit of the file, so we list the commits touching the file on the branch.
+func (provider *Provider) ReadFileMeta(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, filePath string, branch string) (*vcs.FileMeta, error) {
+	code, body, err := httpGet(
+		instanceURL,
+		fmt.Sprintf("repos/%s/commits?path=%s&sha=%s&per_page=1", repositoryID, url.QueryEscape(filePath), url.QueryEscape(branch)),
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file meta %s from GitHub instance %s: %w", filePath, instanceURL, err)
+	}
+
+	if code == 404 {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to read file meta %s from GitHub instance %s, file not found", filePath, instanceURL))
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to read file meta %s from GitHub instance %s, status code: %d",
+			filePath,
+			instanceURL,
+			code,
+		)
+	}
+
+	var commitList []Commit
+	if err := json.Unmarshal([]byte(body), &commitList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal file meta from GitHub instance %s: %w", instanceURL, err)
+	}
+	// There is no commit touching the file if the file doesn't exist.
+	if len(commitList) == 0 {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to read file meta %s from GitHub instance %s, file not found", filePath, instanceURL))
+	}
+
+	return &vcs.FileMeta{
+		LastCommitID: commitList[0].SHA,
+	}, nil
+}
+
+// CreateWebhook creates a webhook in a GitHub repository.
+func (provider *Provider) CreateWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, payload []byte) (string, error) {
+	resourcePath := fmt.Sprintf("repos/%s/hooks", repositoryID)
+	code, body, err := httpPost(
+		instanceURL,
+		resourcePath,
+		&oauthCtx.AccessToken,
+		payload,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to create webhook for repository %s from GitHub instance %s: %w", repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return "", fmt.Errorf("failed to create webhook for repository %s from GitHub instance %s, status code: %d, body: %s",
+			repositoryID,
+			instanceURL,
+			code,
+			body,
+		)
+	}
+
+	webhookInfo := &WebhookInfo{}
+	if err := json.Unmarshal([]byte(body), webhookInfo); err != nil {
+		return "", fmt.Errorf("failed to unmarshal create webhook response for repository %s from GitHub instance %s: %w", repositoryID, instanceURL, err)
+	}
+	return strconv.Itoa(webhookInfo.ID), nil
+}
+
+// PatchWebhook patches a webhook in a GitHub repository.
+func (provider *Provider) PatchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, webhookID string, payload []byte) error {
+	resourcePath := fmt.Sprintf("repos/%s/hooks/%s", repositoryID, webhookID)
+	code, _, err := httpPatch(
+		instanceURL,
+		resourcePath,
+		&oauthCtx.AccessToken,
+		payload,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to patch webhook ID %s for repository %s from GitHub instance %s: %w", webhookID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to patch webhook ID %s for repository %s from GitHub instance %s, status code: %d", webhookID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// DeleteWebhook deletes a webhook in a GitHub repository.
+func (provider *Provider) DeleteWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, webhookID string) error {
+	resourcePath := fmt.Sprintf("repos/%s/hooks/%s", repositoryID, webhookID)
+	code, _, err := httpDelete(
+		instanceURL,
+		resourcePath,
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook ID %s for repository %s from GitHub instance %s: %w", webhookID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to delete webhook ID %s for repository %s from GitHub instance %s, status code: %d", webhookID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// ListMergeRequestFiles lists the files changed in a GitHub pull request.
+func (provider *Provider) ListMergeRequestFiles(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestID string) ([]vcs.MergeRequestFile, error) {
+	var fileList []vcs.MergeRequestFile
+	// The API is paginated and returns at most 3000 files.
+	for page := 1; ; page++ {
+		code, body, err := httpGet(
+			instanceURL,
+			fmt.Sprintf("repos/%s/pulls/%s/files?per_page=%d&page=%d", repositoryID, mergeRequestID, pullRequestFilePageSize, page),
+			&oauthCtx.AccessToken,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull request %s files for repository %s from GitHub instance %s: %w", mergeRequestID, repositoryID, instanceURL, err)
+		}
+
+		if code == 404 {
+			return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to list pull request %s files for repository %s from GitHub instance %s, pull request not found", mergeRequestID, repositoryID, instanceURL))
+		} else if code >= 300 {
+			return nil, fmt.Errorf("failed to list pull request %s files for repository %s from GitHub instance %s, status code: %d", mergeRequestID, repositoryID, instanceURL, code)
+		}
+
+		var files []PullRequestFile
+		if err := json.Unmarshal([]byte(body), &files); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pull request files from GitHub instance %s: %w", instanceURL, err)
+		}
+		for _, file := range files {
+			fileList = append(fileList, vcs.MergeRequestFile{
+				Path:    file.Filename,
+				Deleted: file.Status == "removed",
+			})
+		}
+		if len(files) < pullRequestFilePageSize {
+			break
+		}
+	}
+	return fileList, nil
+}
+
+// CreateMergeRequestComment creates a comment on a GitHub pull request.
+func (provider *Provider) CreateMergeRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestID string, content string) error {
+	body, err := json.Marshal(IssueComment{
+		Body: content,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue comment: %w", err)
+	}
+
+	code, _, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("repos/%s/issues/%s/comments", repositoryID, mergeRequestID),
+		&oauthCtx.AccessToken,
+		body,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pull request %s comment for repository %s on GitHub instance %s: %w", mergeRequestID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to create pull request %s comment for repository %s on GitHub instance %s, status code: %d", mergeRequestID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// SetCommitStatus sets the status of a commit in a GitHub repository.
+func (provider *Provider) SetCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, commitID string, status vcs.CommitStatus) error {
+	body, err := json.Marshal(CommitStatus{
+		State:       string(status.State),
+		Context:     status.Context,
+		Description: status.Description,
+		TargetURL:   status.TargetURL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal commit status: %w", err)
+	}
+
+	code, _, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("repos/%s/statuses/%s", repositoryID, commitID),
+		&oauthCtx.AccessToken,
+		body,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set commit %s status for repository %s on GitHub instance %s: %w", commitID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to set commit %s status for repository %s on GitHub instance %s, status code: %d", commitID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// CreateBranch creates a branch in a GitHub repository.
+// GitHub creates the branch as a git reference to a commit, so we look up the head commit of the base branch first.
+func (provider *Provider) CreateBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, branch string, baseBranch string) error {
+	code, body, err := httpGet(
+		instanceURL,
+		fmt.Sprintf("repos/%s/git/ref/heads/%s", repositoryID, escapePath(baseBranch)),
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to read branch %s for repository %s from GitHub instance %s: %w", baseBranch, repositoryID, instanceURL, err)
+	}
+
+	if code == 404 {
+		return common.Errorf(common.NotFound, fmt.Errorf("failed to read branch %s for repository %s from GitHub instance %s, branch not found", baseBranch, repositoryID, instanceURL))
+	} else if code >= 300 {
+		return fmt.Errorf("failed to read branch %s for repository %s from GitHub instance %s, status code: %d", baseBranch, repositoryID, instanceURL, code)
+	}
+
+	reference := &Reference{}
+	if err := json.Unmarshal([]byte(body), reference); err != nil {
+		return fmt.Errorf("failed to unmarshal branch reference from GitHub instance %s: %w", instanceURL, err)
+	}
+
+	payload, err := json.Marshal(ReferenceCreate{
+		Ref: fmt.Sprintf("refs/heads/%s", branch),
+		SHA: reference.Object.SHA,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal branch reference: %w", err)
+	}
+
+	code, body, err = httpPost(
+		instanceURL,
+		fmt.Sprintf("repos/%s/git/refs", repositoryID),
+		&oauthCtx.AccessToken,
+		payload,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s from %s for repository %s on GitHub instance %s: %w", branch, baseBranch, repositoryID, instanceURL, err)
+	}
+
+	// GitHub responds 422 with the "Reference already exists" message if the branch exists.
+	if code == 422 && strings.Contains(body, "already exists") {
+		return common.Errorf(common.Conflict, fmt.Errorf("failed to create branch %s from %s for repository %s on GitHub instance %s, branch already exists", branch, baseBranch, repositoryID, instanceURL))
+	} else if code >= 300 {
+		return fmt.Errorf("failed to create branch %s from %s for repository %s on GitHub instance %s, status code: %d", branch, baseBranch, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// CreateMergeRequest creates a pull request in a GitHub repository.
+// GitHub removes the head branch after merge by the repository setting, so RemoveSourceBranch is ignored.
+func (provider *Provider) CreateMergeRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestCreate vcs.MergeRequestCreate) (*vcs.MergeRequest, error) {
+	payload, err := json.Marshal(PullRequestCreate{
+		Title: mergeRequestCreate.Title,
+		Body:  mergeRequestCreate.Description,
+		Head:  mergeRequestCreate.SourceBranch,
+		Base:  mergeRequestCreate.TargetBranch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pull request: %w", err)
+	}
+
+	code, body, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("repos/%s/pulls", repositoryID),
+		&oauthCtx.AccessToken,
+		payload,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request from %s to %s for repository %s on GitHub instance %s: %w", mergeRequestCreate.SourceBranch, mergeRequestCreate.TargetBranch, repositoryID, instanceURL, err)
+	}
+
+	// GitHub responds 422 with the "A pull request already exists" message if an open pull request from the head branch exists.
+	if code == 422 && strings.Contains(body, "already exists") {
+		return nil, common.Errorf(common.Conflict, fmt.Errorf("failed to create pull request from %s to %s for repository %s on GitHub instance %s, pull request already exists", mergeRequestCreate.SourceBranch, mergeRequestCreate.TargetBranch, repositoryID, instanceURL))
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to create pull request from %s to %s for repository %s on GitHub instance %s, status code: %d", mergeRequestCreate.SourceBranch, mergeRequestCreate.TargetBranch, repositoryID, instanceURL, code)
+	}
+
+	pullRequest := &PullRequest{}
+	if err := json.Unmarshal([]byte(body), pullRequest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pull request from GitHub instance %s: %w", instanceURL, err)
+	}
+	return &vcs.MergeRequest{
+		ID:  strconv.Itoa(pullRequest.Number),
+		URL: pullRequest.HTMLURL,
+	}, nil
+}
+
+// ListRepositoryFiles lists the paths of the files under the directory recursively at the Git ref.
+func (provider *Provider) ListRepositoryFiles(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, directory string, ref string) ([]string, error) {
+	code, body, err := httpGet(
+		instanceURL,
+		fmt.Sprintf("repos/%s/git/trees/%s?recursive=1", repositoryID, url.PathEscape(ref)),
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files of directory %q at %s for repository %s from GitHub instance %s: %w", directory, ref, repositoryID, instanceURL, err)
+	}
+
+	if code == 404 {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to list files of directory %q at %s for repository %s from GitHub instance %s, ref not found", directory, ref, repositoryID, instanceURL))
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to list files of directory %q at %s for repository %s from GitHub instance %s, status code: %d", directory, ref, repositoryID, instanceURL, code)
+	}
+
+	tree := &Tree{}
+	if err := json.Unmarshal([]byte(body), tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal git tree from GitHub instance %s: %w", instanceURL, err)
+	}
+	if tree.Truncated {
+		return nil, fmt.Errorf("failed to list files of directory %q at %s for repository %s from GitHub instance %s, too many files in the repository", directory, ref, repositoryID, instanceURL)
+	}
+
+	// The git tree API lists the whole repository, so we filter the files under the directory here.
+	prefix := ""
+	if directory != "" {
+		prefix = strings.TrimSuffix(directory, "/") + "/"
+	}
+	var fileList []string
+	for _, node := range tree.TreeNodeList {
+		if node.Type == "blob" && strings.HasPrefix(node.Path, prefix) {
+			fileList = append(fileList, node.Path)
+		}
+	}
+	return fileList, nil
+}
+
+// escapePath escapes each segment of the file path, the contents API takes the path as is.
+func escapePath(filePath string) string {
+	segmentList := strings.Split(filePath, "/")
+	for i, segment := range segmentList {
+		segmentList[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segmentList, "/")
+}
+
+// httpPost sends a POST request.
+func httpPost(instanceURL string, resourcePath string, token *string, body []byte, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("POST", instanceURL, resourcePath, token, body, mediaTypeJSON, oauthContext, refresher)
+}
+
+// httpGet sends a GET request.
+func httpGet(instanceURL string, resourcePath string, token *string, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("GET", instanceURL, resourcePath, token, nil, mediaTypeJSON, oauthContext, refresher)
+}
+
+// httpGetRaw sends a GET request for the raw content of a file.
+func httpGetRaw(instanceURL string, resourcePath string, token *string, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("GET", instanceURL, resourcePath, token, nil, mediaTypeRaw, oauthContext, refresher)
+}
+
+// httpPut sends a PUT request.
+func httpPut(instanceURL string, resourcePath string, token *string, body []byte, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("PUT", instanceURL, resourcePath, token, body, mediaTypeJSON, oauthContext, refresher)
+}
+
+// httpPatch sends a PATCH request.
+func httpPatch(instanceURL string, resourcePath string, token *string, body []byte, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("PATCH", instanceURL, resourcePath, token, body, mediaTypeJSON, oauthContext, refresher)
+}
+
+// httpDelete sends a DELETE request.
+func httpDelete(instanceURL string, resourcePath string, token *string, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return httpRequest("DELETE", instanceURL, resourcePath, token, nil, mediaTypeJSON, oauthContext, refresher)
+}
+
+func httpRequest(method string, instanceURL string, resourcePath string, token *string, body []byte, mediaType string, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
+	return retry(instanceURL, token, oauthContext, refresher, func() (*http.Response, error) {
+		url := fmt.Sprintf("%s/%s", apiURL(instanceURL), resourcePath)
+		// The body is read on each retry.
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequest(method, url, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct %s %v (%w)", method, url, err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", mediaType)
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", *token))
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed %s %v (%w)", method, url, err)
+		}
+		return resp, nil
+	})
+}
+
+func retry(instanceURL string, token *string, oauthContext oauthContext, refresher common.TokenRefresher, f func() (*http.Response, error)) (code int, respBody string, err error) {
+	retries := 0
+RETRY:
+	retries++
+
+	resp, err := f()
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read github response body, code %v, error: %v", resp.StatusCode, err)
+	}
+
+	// Only the expiring user-to-server tokens of GitHub Apps have the refresh token, OAuth App tokens don't expire.
+	if resp.StatusCode == http.StatusUnauthorized && oauthContext.RefreshToken != "" {
+		if retries < maxRetries {
+			// Refresh and store the token.
+			if err := refreshToken(instanceURL, token, oauthContext, refresher); err != nil {
+				return 0, "", err
+			}
+			goto RETRY
+		}
+		return 0, "", fmt.Errorf("retries exceeded for oauth refresher; original code %v body %s", resp.StatusCode, string(body))
+	}
+
+	return resp.StatusCode, string(body), nil
+}
+
+// oauthContext is the request context for refreshing oauth token.
+type oauthContext struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+	GrantType    string `json:"grant_type"`
+}
+
+type refreshOauthResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	// GitHub returns 200 with the error in the body if the refresh fails.
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	// token_type, scope, refresh_token_expires_in are not used.
+}
+
+func refreshToken(instanceURL string, oldToken *string, oauthContext oauthContext, refresher common.TokenRefresher) error {
+	url := fmt.Sprintf("%s/login/oauth/access_token", instanceURL)
+	oauthContext.GrantType = "refresh_token"
+	body, err := json.Marshal(oauthContext)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to construct refresh token POST %v (%w)", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send refresh token POST %v (%w)", url, err)
+	}
+	defer resp.Body.Close()
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read body from refresh token POST %v (%w)", url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch refresh token, response code %v body %s", resp.StatusCode, body)
+	}
+
+	var r refreshOauthResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return fmt.Errorf("failed to unmarshal body from refresh token POST %v (%w)", url, err)
+	}
+	if r.Error != "" {
+		return fmt.Errorf("failed to fetch refresh token, error %q description %q", r.Error, r.ErrorDescription)
+	}
+
+	// Update the old token to new value for retries.
+	*oldToken = r.AccessToken
+
+	var expireAt int64
+	if r.ExpiresIn != 0 {
+		expireAt = time.Now().Unix() + r.ExpiresIn
+	}
+	if err := refresher(r.AccessToken, r.RefreshToken, expireAt); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestValidateWebhookSignature(t *testing.T) {
+	payload := []byte(`{"ref":"refs/heads/main"}`)
+	tests := []struct {
+		signature string
+		secret    string
+		want      bool
+	}{
+		{
+			signature: "sha256=2de9fd1d1d1f2a1cc3e9b0e2d6ecf5db7aa4b8b3c50b3e88e1b5c16c3b10ffc2",
+			secret:    "secret",
+			want:      false,
+		},
+		{
+			signature: signature("secret", payload),
+			secret:    "secret",
+			want:      true,
+		},
+		{
+			signature: signature("secret", payload),
+			secret:    "another",
+			want:      false,
+		},
+		{
+			// The legacy SHA-1 signature isn't accepted.
+			signature: "sha1=0123456789abcdef",
+			secret:    "secret",
+			want:      false,
+		},
+		{
+			signature: "sha256=not-hex",
+			secret:    "secret",
+			want:      false,
+		},
+		{
+			signature: "",
+			secret:    "secret",
+			want:      false,
+		},
+	}
+
+	for _, test := range tests {
+		got := ValidateWebhookSignature(test.signature, test.secret, payload)
+		if got != test.want {
+			t.Errorf("ValidateWebhookSignature(%q, %q) = %v, want %v", test.signature, test.secret, got, test.want)
+		}
+	}
+}
+
+func TestAPIURL(t *testing.T) {
+	tests := []struct {
+		instanceURL string
+		want        string
+	}{
+		{
+			instanceURL: "https://github.com",
+			want:        "https://api.github.com",
+		},
+		{
+			instanceURL: "https://github.example.com",
+			want:        "https://github.example.com/api/v3",
+		},
+	}
+
+	for _, test := range tests {
+		got := apiURL(test.instanceURL)
+		if got != test.want {
+			t.Errorf("apiURL(%q) = %q, want %q", test.instanceURL, got, test.want)
+		}
+	}
+}
+
+func signature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
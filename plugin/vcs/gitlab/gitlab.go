@@ -24,6 +24,8 @@ const (
 
 	// apiPath is the API path.
 	apiPath = "api/v4"
+	// repositoryTreePageSize is the max page size of listing the repository tree.
+	repositoryTreePageSize = 100
 )
 
 var (
@@ -36,12 +38,20 @@ type WebhookType string
 const (
 	// WebhookPush is the webhook type for push.
 	WebhookPush WebhookType = "push"
+	// WebhookMergeRequest is the webhook type for merge request.
+	WebhookMergeRequest WebhookType = "merge_request"
+	// WebhookTagPush is the webhook type for tag push.
+	WebhookTagPush WebhookType = "tag_push"
 )
 
 func (e WebhookType) String() string {
 	switch e {
 	case WebhookPush:
 		return "push"
+	case WebhookMergeRequest:
+		return "merge_request"
+	case WebhookTagPush:
+		return "tag_push"
 	}
 	return "UNKNOWN"
 }
@@ -57,12 +67,12 @@ type WebhookPost struct {
 	SecretToken string `json:"token"`
 	// This is set to true
 	PushEvents bool `json:"push_events"`
-	// For now, there is no native dry run DDL support in mysql/postgres. One may wonder if we could wrap the DDL
-	// in a transaction and just not commit at the end, unfortunately there are side effects which are hard to control.
-	// See https://www.postgresql.org/message-id/CAMsr%2BYGiYQ7PYvYR2Voio37YdCpp79j5S%2BcmgVJMOLM2LnRQcA%40mail.gmail.com
-	// So we can't possibly display useful info when reviewing a MR, thus we don't enable this event.
-	// Saying that, delivering a souding dry run solution would be great and hopefully we can achieve that one day.
-	// MergeRequestsEvents  bool   `json:"merge_requests_events"`
+	// This is set to true to review the SQL of the changed migration files in the merge request.
+	// There is no native dry run DDL support in mysql/postgres, so the review is done by the statement advisors
+	// instead of running the DDL.
+	MergeRequestsEvents bool `json:"merge_requests_events"`
+	// This is set to true to deploy the migration files from the tags matching the branch filter.
+	TagPushEvents          bool   `json:"tag_push_events"`
 	PushEventsBranchFilter string `json:"push_events_branch_filter"`
 	// TODO(tianzhou): This is set to false, be lax to not enable_ssl_verification
 	EnableSSLVerification bool `json:"enable_ssl_verification"`
@@ -71,6 +81,8 @@ type WebhookPost struct {
 // WebhookPut is the API message for webhook PUT.
 type WebhookPut struct {
 	URL                    string `json:"url"`
+	MergeRequestsEvents    bool   `json:"merge_requests_events"`
+	TagPushEvents          bool   `json:"tag_push_events"`
 	PushEventsBranchFilter string `json:"push_events_branch_filter"`
 }
 
@@ -88,22 +100,101 @@ type WebhookCommitAuthor struct {
 
 // WebhookCommit is the API message for webhook commit.
 type WebhookCommit struct {
-	ID        string              `json:"id"`
-	Title     string              `json:"title"`
-	Message   string              `json:"message"`
-	Timestamp string              `json:"timestamp"`
-	URL       string              `json:"url"`
-	Author    WebhookCommitAuthor `json:"author"`
-	AddedList []string            `json:"added"`
+	ID           string              `json:"id"`
+	Title        string              `json:"title"`
+	Message      string              `json:"message"`
+	Timestamp    string              `json:"timestamp"`
+	URL          string              `json:"url"`
+	Author       WebhookCommitAuthor `json:"author"`
+	AddedList    []string            `json:"added"`
+	ModifiedList []string            `json:"modified"`
+	RemovedList  []string            `json:"removed"`
 }
 
 // WebhookPushEvent is the API message for webhook push event.
+// The tag push event shares the same message, whose commit list is empty.
 type WebhookPushEvent struct {
-	ObjectKind WebhookType     `json:"object_kind"`
-	Ref        string          `json:"ref"`
-	AuthorName string          `json:"user_name"`
-	Project    WebhookProject  `json:"project"`
-	CommitList []WebhookCommit `json:"commits"`
+	ObjectKind WebhookType `json:"object_kind"`
+	Ref        string      `json:"ref"`
+	// CheckoutSHA is the commit the ref points to after the push, it's empty if the ref is deleted.
+	CheckoutSHA string          `json:"checkout_sha"`
+	Message     string          `json:"message"`
+	AuthorName  string          `json:"user_name"`
+	Project     WebhookProject  `json:"project"`
+	CommitList  []WebhookCommit `json:"commits"`
+}
+
+// WebhookMergeRequestLastCommit is the API message for the last commit of the webhook merge request.
+type WebhookMergeRequestLastCommit struct {
+	ID string `json:"id"`
+}
+
+// WebhookMergeRequestAttributes is the API message for webhook merge request attributes.
+type WebhookMergeRequestAttributes struct {
+	IID   int    `json:"iid"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	// Action is one of open, close, reopen, update, approved, unapproved and merge.
+	Action string `json:"action"`
+	// OldRev is only set if the update action pushes new commits.
+	OldRev       string                        `json:"oldrev"`
+	SourceBranch string                        `json:"source_branch"`
+	TargetBranch string                        `json:"target_branch"`
+	LastCommit   WebhookMergeRequestLastCommit `json:"last_commit"`
+}
+
+// WebhookMergeRequestEvent is the API message for webhook merge request event.
+type WebhookMergeRequestEvent struct {
+	ObjectKind       WebhookType                   `json:"object_kind"`
+	Project          WebhookProject                `json:"project"`
+	ObjectAttributes WebhookMergeRequestAttributes `json:"object_attributes"`
+}
+
+// MergeRequestChange is the API message for merge request change.
+type MergeRequestChange struct {
+	NewPath     string `json:"new_path"`
+	DeletedFile bool   `json:"deleted_file"`
+}
+
+// MergeRequestChanges is the API message for merge request changes.
+type MergeRequestChanges struct {
+	Changes []MergeRequestChange `json:"changes"`
+}
+
+// MergeRequestNote is the API message for merge request note.
+type MergeRequestNote struct {
+	Body string `json:"body"`
+}
+
+// CommitStatus is the API message for commit status.
+type CommitStatus struct {
+	// State is one of pending, running, success, failed and canceled.
+	State       string `json:"state"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// MergeRequestPost is the API message for merge request POST.
+type MergeRequestPost struct {
+	SourceBranch       string `json:"source_branch"`
+	TargetBranch       string `json:"target_branch"`
+	Title              string `json:"title"`
+	Description        string `json:"description"`
+	RemoveSourceBranch bool   `json:"remove_source_branch"`
+}
+
+// MergeRequest is the API message for merge request.
+type MergeRequest struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+// RepositoryTreeNode is the API message for a node of the repository tree.
+type RepositoryTreeNode struct {
+	Path string `json:"path"`
+	// Type is either "blob" for a file or "tree" for a directory.
+	Type string `json:"type"`
 }
 
 // FileCommit is the API message for file commit.
@@ -407,6 +498,229 @@ func (provider *Provider) DeleteWebhook(ctx context.Context, oauthCtx common.Oau
 	return nil
 }
 
+// ListMergeRequestFiles lists the files changed in a GitLab merge request.
+func (provider *Provider) ListMergeRequestFiles(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestID string) ([]vcs.MergeRequestFile, error) {
+	code, body, err := httpGet(
+		instanceURL,
+		fmt.Sprintf("projects/%s/merge_requests/%s/changes", repositoryID, mergeRequestID),
+		&oauthCtx.AccessToken,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merge request %s files for repository %s from GitLab instance %s: %w", mergeRequestID, repositoryID, instanceURL, err)
+	}
+
+	if code == 404 {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to list merge request %s files for repository %s from GitLab instance %s, merge request not found", mergeRequestID, repositoryID, instanceURL))
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to list merge request %s files for repository %s from GitLab instance %s, status code: %d", mergeRequestID, repositoryID, instanceURL, code)
+	}
+
+	changes := &MergeRequestChanges{}
+	if err := json.Unmarshal([]byte(body), changes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merge request changes from GitLab instance %s: %w", instanceURL, err)
+	}
+
+	var fileList []vcs.MergeRequestFile
+	for _, change := range changes.Changes {
+		fileList = append(fileList, vcs.MergeRequestFile{
+			Path:    change.NewPath,
+			Deleted: change.DeletedFile,
+		})
+	}
+	return fileList, nil
+}
+
+// CreateMergeRequestComment creates a note on a GitLab merge request.
+func (provider *Provider) CreateMergeRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestID string, content string) error {
+	body, err := json.Marshal(MergeRequestNote{
+		Body: content,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal merge request note: %w", err)
+	}
+
+	code, _, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("projects/%s/merge_requests/%s/notes", repositoryID, mergeRequestID),
+		&oauthCtx.AccessToken,
+		bytes.NewBuffer(body),
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create merge request %s note for repository %s on GitLab instance %s: %w", mergeRequestID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to create merge request %s note for repository %s on GitLab instance %s, status code: %d", mergeRequestID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// SetCommitStatus sets the status of a commit in a GitLab project.
+func (provider *Provider) SetCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, commitID string, status vcs.CommitStatus) error {
+	state := "pending"
+	switch status.State {
+	case vcs.CommitStateSuccess:
+		state = "success"
+	case vcs.CommitStateFailure:
+		state = "failed"
+	}
+	body, err := json.Marshal(CommitStatus{
+		State:       state,
+		Name:        status.Context,
+		Description: status.Description,
+		TargetURL:   status.TargetURL,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal commit status: %w", err)
+	}
+
+	code, _, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("projects/%s/statuses/%s", repositoryID, commitID),
+		&oauthCtx.AccessToken,
+		bytes.NewBuffer(body),
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set commit %s status for repository %s on GitLab instance %s: %w", commitID, repositoryID, instanceURL, err)
+	}
+
+	if code >= 300 {
+		return fmt.Errorf("failed to set commit %s status for repository %s on GitLab instance %s, status code: %d", commitID, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// CreateBranch creates a branch in a GitLab project.
+func (provider *Provider) CreateBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, branch string, baseBranch string) error {
+	code, body, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("projects/%s/repository/branches?branch=%s&ref=%s", repositoryID, url.QueryEscape(branch), url.QueryEscape(baseBranch)),
+		&oauthCtx.AccessToken,
+		nil,
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create branch %s from %s for repository %s on GitLab instance %s: %w", branch, baseBranch, repositoryID, instanceURL, err)
+	}
+
+	// GitLab responds 400 with the "Branch already exists" message if the branch exists.
+	if code == 400 && strings.Contains(body, "already exists") {
+		return common.Errorf(common.Conflict, fmt.Errorf("failed to create branch %s from %s for repository %s on GitLab instance %s, branch already exists", branch, baseBranch, repositoryID, instanceURL))
+	} else if code >= 300 {
+		return fmt.Errorf("failed to create branch %s from %s for repository %s on GitLab instance %s, status code: %d", branch, baseBranch, repositoryID, instanceURL, code)
+	}
+	return nil
+}
+
+// CreateMergeRequest creates a merge request in a GitLab project.
+func (provider *Provider) CreateMergeRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestCreate vcs.MergeRequestCreate) (*vcs.MergeRequest, error) {
+	body, err := json.Marshal(MergeRequestPost{
+		SourceBranch:       mergeRequestCreate.SourceBranch,
+		TargetBranch:       mergeRequestCreate.TargetBranch,
+		Title:              mergeRequestCreate.Title,
+		Description:        mergeRequestCreate.Description,
+		RemoveSourceBranch: mergeRequestCreate.RemoveSourceBranch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merge request: %w", err)
+	}
+
+	code, respBody, err := httpPost(
+		instanceURL,
+		fmt.Sprintf("projects/%s/merge_requests", repositoryID),
+		&oauthCtx.AccessToken,
+		bytes.NewBuffer(body),
+		oauthContext{
+			ClientID:     oauthCtx.ClientID,
+			ClientSecret: oauthCtx.ClientSecret,
+			RefreshToken: oauthCtx.RefreshToken,
+		},
+		oauthCtx.Refresher,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create merge request from %s to %s for repository %s on GitLab instance %s: %w", mergeRequestCreate.SourceBranch, mergeRequestCreate.TargetBranch, repositoryID, instanceURL, err)
+	}
+
+	if code == 409 {
+		return nil, common.Errorf(common.Conflict, fmt.Errorf("failed to create merge request from %s to %s for repository %s on GitLab instance %s, merge request already exists", mergeRequestCreate.SourceBranch, mergeRequestCreate.TargetBranch, repositoryID, instanceURL))
+	} else if code >= 300 {
+		return nil, fmt.Errorf("failed to create merge request from %s to %s for repository %s on GitLab instance %s, status code: %d", mergeRequestCreate.SourceBranch, mergeRequestCreate.TargetBranch, repositoryID, instanceURL, code)
+	}
+
+	mergeRequest := &MergeRequest{}
+	if err := json.Unmarshal([]byte(respBody), mergeRequest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal merge request from GitLab instance %s: %w", instanceURL, err)
+	}
+	return &vcs.MergeRequest{
+		ID:  strconv.Itoa(mergeRequest.IID),
+		URL: mergeRequest.WebURL,
+	}, nil
+}
+
+// ListRepositoryFiles lists the paths of the files under the directory recursively at the Git ref.
+func (provider *Provider) ListRepositoryFiles(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, directory string, ref string) ([]string, error) {
+	var fileList []string
+	for page := 1; ; page++ {
+		code, body, err := httpGet(
+			instanceURL,
+			fmt.Sprintf("projects/%s/repository/tree?path=%s&ref=%s&recursive=true&per_page=%d&page=%d", repositoryID, url.QueryEscape(directory), url.QueryEscape(ref), repositoryTreePageSize, page),
+			&oauthCtx.AccessToken,
+			oauthContext{
+				ClientID:     oauthCtx.ClientID,
+				ClientSecret: oauthCtx.ClientSecret,
+				RefreshToken: oauthCtx.RefreshToken,
+			},
+			oauthCtx.Refresher,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files of directory %q at %s for repository %s from GitLab instance %s: %w", directory, ref, repositoryID, instanceURL, err)
+		}
+
+		if code == 404 {
+			return nil, common.Errorf(common.NotFound, fmt.Errorf("failed to list files of directory %q at %s for repository %s from GitLab instance %s, directory not found", directory, ref, repositoryID, instanceURL))
+		} else if code >= 300 {
+			return nil, fmt.Errorf("failed to list files of directory %q at %s for repository %s from GitLab instance %s, status code: %d", directory, ref, repositoryID, instanceURL, code)
+		}
+
+		var nodeList []RepositoryTreeNode
+		if err := json.Unmarshal([]byte(body), &nodeList); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal repository tree from GitLab instance %s: %w", instanceURL, err)
+		}
+		for _, node := range nodeList {
+			if node.Type == "blob" {
+				fileList = append(fileList, node.Path)
+			}
+		}
+		if len(nodeList) < repositoryTreePageSize {
+			break
+		}
+	}
+	return fileList, nil
+}
+
 // httpPost sends a POST request.
 func httpPost(instanceURL string, resourcePath string, token *string, body io.Reader, oauthContext oauthContext, refresher common.TokenRefresher) (code int, respBody string, err error) {
 	return retry(instanceURL, token, oauthContext, refresher, func() (*http.Response, error) {
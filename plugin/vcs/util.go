@@ -15,3 +15,12 @@ func Branch(ref string) (string, error) {
 
 	return "", fmt.Errorf("invalid Git ref: %s", ref)
 }
+
+// Tag is the helper function returns the tag name from reference name.
+func Tag(ref string) (string, error) {
+	if strings.HasPrefix(ref, "refs/tags/") {
+		return strings.TrimPrefix(ref, "refs/tags/"), nil
+	}
+
+	return "", fmt.Errorf("invalid Git tag ref: %s", ref)
+}
@@ -42,3 +42,42 @@ func TestBranch(t *testing.T) {
 		}
 	}
 }
+
+func TestTag(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{
+			ref:     "refs/tags/v1.0.0",
+			want:    "v1.0.0",
+			wantErr: false,
+		},
+		{
+			ref:     "refs/tags/release/v1.0.0",
+			want:    "release/v1.0.0",
+			wantErr: false,
+		},
+		{
+			ref:     "refs/heads/master",
+			want:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		result, err := Tag(test.ref)
+		if err != nil && !test.wantErr {
+			t.Errorf("Tag %q: got error %v, want OK.", test.ref, test.wantErr)
+		}
+
+		if err == nil && test.wantErr {
+			t.Errorf("Tag %q: got OK, want error", test.ref)
+		}
+
+		if result != test.want {
+			t.Errorf("Tag %q: got result %v, want %v.", test.ref, result, test.want)
+		}
+	}
+}
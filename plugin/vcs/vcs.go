@@ -14,12 +14,20 @@ type Type string
 const (
 	// GitLabSelfHost is the VCS type for gitlab self host.
 	GitLabSelfHost Type = "GITLAB_SELF_HOST"
+	// GitHub is the VCS type for github.com and GitHub Enterprise Server.
+	GitHub Type = "GITHUB"
+	// Gitea is the VCS type for Gitea and its fork Forgejo.
+	Gitea Type = "GITEA"
 )
 
 func (e Type) String() string {
 	switch e {
 	case GitLabSelfHost:
 		return "GITLAB_SELF_HOST"
+	case GitHub:
+		return "GITHUB"
+	case Gitea:
+		return "GITEA"
 	}
 	return "UNKNOWN"
 }
@@ -64,6 +72,51 @@ type PushEvent struct {
 	FileCommit         FileCommit `json:"fileCommit"`
 }
 
+// MergeRequestFile is a file changed in a merge request.
+type MergeRequestFile struct {
+	Path string
+	// Deleted is true if the file is deleted in the merge request.
+	Deleted bool
+}
+
+// MergeRequestCreate is the payload for creating a merge request.
+type MergeRequestCreate struct {
+	Title        string
+	Description  string
+	SourceBranch string
+	TargetBranch string
+	// RemoveSourceBranch is true to remove the source branch after the merge request is merged, if the VCS supports it.
+	RemoveSourceBranch bool
+}
+
+// MergeRequest is the API message for a created merge request.
+type MergeRequest struct {
+	// ID is the merge request ID scoped to the repository.
+	ID  string
+	URL string
+}
+
+// CommitState is the state of a commit status.
+type CommitState string
+
+const (
+	// CommitStatePending is the commit state for the pending checks.
+	CommitStatePending CommitState = "pending"
+	// CommitStateSuccess is the commit state for the passed checks.
+	CommitStateSuccess CommitState = "success"
+	// CommitStateFailure is the commit state for the failed checks.
+	CommitStateFailure CommitState = "failure"
+)
+
+// CommitStatus is the status of a commit reported by an external system such as Bytebase.
+type CommitStatus struct {
+	State CommitState
+	// Context identifies the reporter of the status, the status with the same context is overwritten.
+	Context     string
+	Description string
+	TargetURL   string
+}
+
 // UserState is the state of a VCS user account.
 type UserState string
 
@@ -129,6 +182,51 @@ type Provider interface {
 	PatchWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, webhookID string, payload []byte) error
 	// Deletes a webhook.
 	DeleteWebhook(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, webhookID string) error
+
+	// Lists the files changed in a merge request.
+	//
+	// oauthCtx: OAuth context to read the merge request
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// mergeRequestID: the merge request ID scoped to the repository, e.g. the IID of the GitLab merge request or the number of the GitHub pull request
+	ListMergeRequestFiles(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestID string) ([]MergeRequestFile, error)
+	// Creates a comment on a merge request.
+	//
+	// Similar to ListMergeRequestFiles, the content is in the Markdown format.
+	CreateMergeRequestComment(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestID string, content string) error
+	// Sets the status of a commit, which is shown on the merge request containing the commit.
+	//
+	// oauthCtx: OAuth context to set the commit status
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// commitID: the commit SHA
+	// status: the commit status
+	SetCommitStatus(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, commitID string, status CommitStatus) error
+
+	// Creates a branch from the head of the base branch.
+	//
+	// oauthCtx: OAuth context to create the branch
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// branch: the name of the new branch
+	// baseBranch: the branch to create the new branch from
+	//
+	// Returns a common.Conflict error if the branch already exists.
+	CreateBranch(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, branch string, baseBranch string) error
+	// Creates a merge request. Returns the created merge request on success.
+	//
+	// Similar to CreateBranch, the mergeRequestCreate specifies the source and target branches.
+	// Returns a common.Conflict error if an open merge request from the source branch already exists.
+	CreateMergeRequest(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, mergeRequestCreate MergeRequestCreate) (*MergeRequest, error)
+
+	// Lists the paths of the files under the directory recursively at the Git ref.
+	//
+	// oauthCtx: OAuth context to read the repository tree
+	// instanceURL: VCS instance URL
+	// repositoryID: the repository ID from the external VCS system (note this is NOT the ID of Bytebase's own repository resource)
+	// directory: the directory relative to the repository root, empty means the repository root
+	// ref: the branch, tag or commit SHA
+	ListRepositoryFiles(ctx context.Context, oauthCtx common.OauthContext, instanceURL string, repositoryID string, directory string, ref string) ([]string, error)
 }
 
 var (
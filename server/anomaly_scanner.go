@@ -11,6 +11,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/differ"
 	"go.uber.org/zap"
 )
 
@@ -331,6 +332,18 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 					Expect:  list[0].Schema,
 					Actual:  schemaBuf.String(),
 				}
+				if instance.Engine == db.MySQL || instance.Engine == db.TiDB {
+					diff, err := diffDumpedSchema(instance.Engine, anomalyPayload.Actual, anomalyPayload.Expect)
+					if err != nil {
+						s.l.Debug("Failed to diff drifted schema",
+							zap.String("instance", instance.Name),
+							zap.String("database", database.Name),
+							zap.String("type", string(api.AnomalyDatabaseSchemaDrift)),
+							zap.Error(err))
+					} else {
+						anomalyPayload.Diff = diff
+					}
+				}
 				payload, err := json.Marshal(anomalyPayload)
 				if err != nil {
 					s.l.Error("Failed to marshal anomaly payload",
@@ -357,7 +370,7 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 			} else {
 				err := s.server.AnomalyService.ArchiveAnomaly(ctx, &api.AnomalyArchive{
 					DatabaseID: &database.ID,
-					Type:       api.AnomalyDatabaseConnection,
+					Type:       api.AnomalyDatabaseSchemaDrift,
 				})
 				if err != nil && common.ErrorCode(err) != common.NotFound {
 					s.l.Error("Failed to close anomaly",
@@ -372,6 +385,19 @@ func (s *AnomalyScanner) checkDatabaseAnomaly(ctx context.Context, instance *api
 SchemaDriftEnd:
 }
 
+// diffDumpedSchema returns the DDL statements migrating the actual schema dump to the expected schema dump.
+func diffDumpedSchema(dbType db.Type, actual, expect string) (string, error) {
+	actualSchema, err := differ.ParseSchema(dbType, actual)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the actual schema: %w", err)
+	}
+	expectSchema, err := differ.ParseSchema(dbType, expect)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the expected schema: %w", err)
+	}
+	return differ.SchemaDiff(dbType, actualSchema, expectSchema)
+}
+
 func (s *AnomalyScanner) checkBackupAnomaly(ctx context.Context, instance *api.Instance, database *api.Database, policyMap map[int]*api.BackupPlanPolicy) {
 	schedule := api.BackupPlanPolicyScheduleUnset
 	backupSettingFind := &api.BackupSettingFind{
@@ -16,7 +16,7 @@ import (
 
 func (s *Server) registerAuthRoutes(g *echo.Group) {
 
-	// for now, we only support Gitlab
+	// for now, we only support GitLab, GitHub and Gitea
 	g.GET("/auth/provider", func(c echo.Context) error {
 		ctx := context.Background()
 		vcsFind := &api.VCSFind{}
@@ -75,13 +75,14 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 					return echo.NewHTTPError(http.StatusUnauthorized, "Incorrect password").SetInternal(err)
 				}
 			}
-		case api.PrincipalAuthProviderGitlabSelfHost:
+		case api.PrincipalAuthProviderGitlabSelfHost, api.PrincipalAuthProviderGitHub, api.PrincipalAuthProviderGitea:
 			{
+				// The login message is the same for VCS auth providers.
 				gitlabLogin := &api.GitlabLogin{}
 				if err := jsonapi.UnmarshalPayload(c.Request().Body, gitlabLogin); err != nil {
-					return echo.NewHTTPError(http.StatusBadRequest, "Malformatted gitlab login request").SetInternal(err)
+					return echo.NewHTTPError(http.StatusBadRequest, "Malformatted VCS login request").SetInternal(err)
 				}
-				GitlabUserInfo, err := vcsPlugin.Get("GITLAB_SELF_HOST", vcsPlugin.ProviderConfig{Logger: s.l}).TryLogin(ctx,
+				GitlabUserInfo, err := vcsPlugin.Get(vcsPlugin.Type(authProvider), vcsPlugin.ProviderConfig{Logger: s.l}).TryLogin(ctx,
 					common.OauthContext{
 						ClientID:     gitlabLogin.ApplicationID,
 						ClientSecret: gitlabLogin.Secret,
@@ -92,7 +93,7 @@ func (s *Server) registerAuthRoutes(g *echo.Group) {
 					gitlabLogin.InstanceURL,
 				)
 				if err != nil {
-					return echo.NewHTTPError(http.StatusInternalServerError, "Fail to fetch user info from gitlab").SetInternal(err)
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Fail to fetch user info from %s", authProvider)).SetInternal(err)
 				}
 
 				// we only allow active user to login via gitlab
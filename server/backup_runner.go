@@ -95,6 +95,19 @@ func (s *BackupRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
 					backupSetting.Database = database
 
 					backupName := fmt.Sprintf("%s-%s-%s-autobackup", api.ProjectShortSlug(database.Project), api.EnvSlug(database.Instance.Environment), t.Format("20060102T030405"))
+					if !database.Instance.Capability.DumpWithData {
+						// The backup setting may be enabled before the engine is gated, so the skipped backup is recorded
+						// as failed to show the user why the database isn't backed up.
+						if err := s.recordUnsupportedBackup(ctx, database, backupName); err != nil {
+							s.l.Error("Failed to record unsupported automatic backup for database",
+								zap.Int("databaseID", database.ID),
+								zap.Error(err))
+						}
+						mu.Lock()
+						delete(runningTasks, backupSetting.ID)
+						mu.Unlock()
+						continue
+					}
 					go func(database *api.Database, backupSettingID int, backupName string, hookURL string) {
 						s.l.Debug("Schedule auto backup",
 							zap.String("database", database.Name),
@@ -132,6 +145,33 @@ func (s *BackupRunner) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// recordUnsupportedBackup records the automatic backup of the database as failed since the engine doesn't support backup.
+func (s *BackupRunner) recordUnsupportedBackup(ctx context.Context, database *api.Database, backupName string) error {
+	backup, err := s.server.BackupService.CreateBackup(ctx, &api.BackupCreate{
+		CreatorID:      api.SystemBotID,
+		DatabaseID:     database.ID,
+		Name:           backupName,
+		Type:           api.BackupTypeAutomatic,
+		StorageBackend: api.BackupStorageBackendLocal,
+	})
+	if err != nil {
+		if common.ErrorCode(err) == common.Conflict {
+			// Automatic backup already exists.
+			return nil
+		}
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	if _, err := s.server.BackupService.PatchBackup(ctx, &api.BackupPatch{
+		ID:        backup.ID,
+		UpdaterID: api.SystemBotID,
+		Status:    string(api.BackupStatusFailed),
+		Comment:   fmt.Sprintf("Backup is not supported for %s since the dump doesn't contain the data", database.Instance.Engine),
+	}); err != nil {
+		return fmt.Errorf("failed to patch backup: %w", err)
+	}
+	return nil
+}
+
 func (s *BackupRunner) scheduleBackupTask(ctx context.Context, database *api.Database, backupName string) error {
 	path, err := getAndCreateBackupPath(s.server.dataDir, database, backupName)
 	if err != nil {
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+)
+
+// fakeBackupService has the backups keyed by the name.
+type fakeBackupService struct {
+	api.BackupService
+	backupMap map[string]*api.Backup
+}
+
+func (s *fakeBackupService) CreateBackup(_ context.Context, create *api.BackupCreate) (*api.Backup, error) {
+	if _, ok := s.backupMap[create.Name]; ok {
+		return nil, &common.Error{Code: common.Conflict}
+	}
+	backup := &api.Backup{
+		ID:         len(s.backupMap) + 1,
+		DatabaseID: create.DatabaseID,
+		Name:       create.Name,
+		Status:     api.BackupStatusPendingCreate,
+		Type:       create.Type,
+	}
+	s.backupMap[create.Name] = backup
+	return backup, nil
+}
+
+func (s *fakeBackupService) PatchBackup(_ context.Context, patch *api.BackupPatch) (*api.Backup, error) {
+	for _, backup := range s.backupMap {
+		if backup.ID == patch.ID {
+			backup.Status = api.BackupStatus(patch.Status)
+			backup.Comment = patch.Comment
+			return backup, nil
+		}
+	}
+	return nil, &common.Error{Code: common.NotFound}
+}
+
+func TestRecordUnsupportedBackup(t *testing.T) {
+	backupService := &fakeBackupService{backupMap: make(map[string]*api.Backup)}
+	runner := NewBackupRunner(zap.NewNop(), &Server{BackupService: backupService}, 0)
+	database := &api.Database{ID: 1, Name: "shop", Instance: &api.Instance{Engine: db.ClickHouse}}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		// The backup of the same name is recorded once.
+		if err := runner.recordUnsupportedBackup(ctx, database, "shop-autobackup"); err != nil {
+			t.Fatalf("recordUnsupportedBackup() got error: %v", err)
+		}
+	}
+	if len(backupService.backupMap) != 1 {
+		t.Fatalf("got %d backups, want 1", len(backupService.backupMap))
+	}
+	backup := backupService.backupMap["shop-autobackup"]
+	if backup.Status != api.BackupStatusFailed || backup.Type != api.BackupTypeAutomatic {
+		t.Errorf("got backup status %s and type %s, want %s and %s", backup.Status, backup.Type, api.BackupStatusFailed, api.BackupTypeAutomatic)
+	}
+	want := "Backup is not supported for CLICKHOUSE since the dump doesn't contain the data"
+	if backup.Comment != want {
+		t.Errorf("got backup comment %q, want %q", backup.Comment, want)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/differ"
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
@@ -516,6 +517,10 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if database == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
 		}
+		// The backup is restored from the dump, so it's useless if the dump doesn't contain the data.
+		if !database.Instance.Capability.DumpWithData {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Backup is not supported for %s", database.Instance.Engine))
+		}
 
 		backupCreate.Path, err = getAndCreateBackupPath(s.dataDir, database, backupCreate.Name)
 		if err != nil {
@@ -655,6 +660,9 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if db == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", id))
 		}
+		if backupSettingUpsert.Enabled && !db.Instance.Capability.DumpWithData {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Backup is not supported for %s", db.Instance.Engine))
+		}
 		backupSettingUpsert.EnvironmentID = db.Instance.Environment.ID
 
 		backupSetting, err := s.BackupService.UpsertBackupSetting(ctx, backupSettingUpsert)
@@ -953,6 +961,51 @@ func getDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName
 	return driver, nil
 }
 
+// getDataSourceDatabaseDriver returns the driver connecting with the data source instead of the instance admin data source.
+// The host and port of the instance are overridden by the data source ones if set.
+func getDataSourceDatabaseDriver(ctx context.Context, instance *api.Instance, dataSource *api.DataSource, databaseName string, logger *zap.Logger) (db.Driver, error) {
+	connectionInstance := *instance
+	connectionInstance.Username = dataSource.Username
+	connectionInstance.Password = dataSource.Password
+	if dataSource.HostOverride != "" {
+		connectionInstance.Host = dataSource.HostOverride
+	}
+	if dataSource.PortOverride != "" {
+		connectionInstance.Port = dataSource.PortOverride
+	}
+	return getDatabaseDriver(ctx, &connectionInstance, databaseName, logger)
+}
+
+// getDatabaseSchema returns the schema of the database for schema diffs.
+// For MySQL and TiDB, the schema is parsed from the schema dump so that the view definitions are comparable across databases.
+func getDatabaseSchema(ctx context.Context, instance *api.Instance, databaseName string, logger *zap.Logger) (*db.Schema, error) {
+	driver, err := getDatabaseDriver(ctx, instance, databaseName, logger)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+
+	switch instance.Engine {
+	case db.MySQL, db.TiDB:
+		var schemaBuf bytes.Buffer
+		if err := driver.Dump(ctx, databaseName, &schemaBuf, true /*schemaOnly*/); err != nil {
+			return nil, err
+		}
+		return differ.ParseSchema(instance.Engine, schemaBuf.String())
+	}
+
+	_, schemaList, err := driver.SyncSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, schema := range schemaList {
+		if schema.Name == databaseName {
+			return schema, nil
+		}
+	}
+	return nil, common.Errorf(common.NotFound, fmt.Errorf("database %q not found in instance %q", databaseName, instance.Name))
+}
+
 func validateDatabaseLabelList(labelList []*api.DatabaseLabel, labelKeyList []*api.LabelKey, environmentName string) error {
 	keyValueList := make(map[string]map[string]bool)
 	for _, labelKey := range labelKeyList {
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// executionRegistry is the registry of the running SQL editor queries and task executions, so they can be canceled on request.
+// Canceling the context of the execution cancels the running statement on the database server as well.
+type executionRegistry struct {
+	mu       sync.Mutex
+	queryMap map[queryExecutionKey]context.CancelFunc
+	taskMap  map[int]context.CancelFunc
+}
+
+// queryExecutionKey is the key of a running query. The query ID is generated by the client, so it's scoped by the principal.
+type queryExecutionKey struct {
+	principalID int
+	queryID     string
+}
+
+func newExecutionRegistry() *executionRegistry {
+	return &executionRegistry{
+		queryMap: make(map[queryExecutionKey]context.CancelFunc),
+		taskMap:  make(map[int]context.CancelFunc),
+	}
+}
+
+// registerQuery registers the running query with the cancel function of its context.
+// The returned unregister function must be called once the query finishes.
+func (r *executionRegistry) registerQuery(principalID int, queryID string, cancel context.CancelFunc) (unregister func(), err error) {
+	key := queryExecutionKey{principalID: principalID, queryID: queryID}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.queryMap[key]; ok {
+		return nil, fmt.Errorf("query %q is already running", queryID)
+	}
+	r.queryMap[key] = cancel
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.queryMap, key)
+	}, nil
+}
+
+// cancelQuery cancels the running query of the principal, and returns false if there is no such running query.
+func (r *executionRegistry) cancelQuery(principalID int, queryID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cancel, ok := r.queryMap[queryExecutionKey{principalID: principalID, queryID: queryID}]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// registerTask registers the running task execution with the cancel function of its context.
+// The returned unregister function must be called once the execution finishes.
+func (r *executionRegistry) registerTask(taskID int, cancel context.CancelFunc) (unregister func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.taskMap[taskID] = cancel
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.taskMap, taskID)
+	}
+}
+
+// cancelTask cancels the running task execution, and returns false if the task isn't being executed.
+func (r *executionRegistry) cancelTask(taskID int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cancel, ok := r.taskMap[taskID]
+	if ok {
+		cancel()
+	}
+	return ok
+}
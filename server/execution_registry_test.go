@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExecutionRegistryQuery(t *testing.T) {
+	r := newExecutionRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	unregister, err := r.registerQuery(101, "q1", cancel)
+	if err != nil {
+		t.Fatalf("registerQuery() unexpected error: %v", err)
+	}
+	if _, err := r.registerQuery(101, "q1", func() {}); err == nil {
+		t.Errorf("registerQuery() expected error on registering the running query again")
+	}
+	// The query ID is scoped by the principal.
+	otherUnregister, err := r.registerQuery(102, "q1", func() {})
+	if err != nil {
+		t.Fatalf("registerQuery() unexpected error: %v", err)
+	}
+	defer otherUnregister()
+
+	if r.cancelQuery(102, "q2") {
+		t.Errorf("cancelQuery() canceled the query not running")
+	}
+	if !r.cancelQuery(101, "q1") {
+		t.Errorf("cancelQuery() didn't find the running query")
+	}
+	if ctx.Err() != context.Canceled {
+		t.Errorf("the query context got error %v, want %v", ctx.Err(), context.Canceled)
+	}
+
+	unregister()
+	if r.cancelQuery(101, "q1") {
+		t.Errorf("cancelQuery() canceled the unregistered query")
+	}
+}
+
+func TestExecutionRegistryTask(t *testing.T) {
+	r := newExecutionRegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	unregister := r.registerTask(1001, cancel)
+
+	if r.cancelTask(1002) {
+		t.Errorf("cancelTask() canceled the task not running")
+	}
+	if !r.cancelTask(1001) {
+		t.Errorf("cancelTask() didn't find the running task")
+	}
+	if ctx.Err() != context.Canceled {
+		t.Errorf("the task context got error %v, want %v", ctx.Err(), context.Canceled)
+	}
+
+	unregister()
+	if r.cancelTask(1001) {
+		t.Errorf("cancelTask() canceled the unregistered task")
+	}
+}
@@ -213,6 +213,156 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 		return nil
 	})
 
+	// The admin data source is created along with the instance, so only the read-only data source can be created here.
+	g.POST("/instance/:instanceID/datasource", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		dataSourceCreate := &api.DataSourceCreate{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, dataSourceCreate); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted create data source request").SetInternal(err)
+		}
+		if dataSourceCreate.Type != api.RO {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted create data source request, only %s data source can be created for the instance", api.RO))
+		}
+
+		instance, err := s.composeInstanceByID(ctx, id)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", id))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", id)).SetInternal(err)
+		}
+
+		dataSourceType := api.RO
+		existing, err := s.DataSourceService.FindDataSource(ctx, &api.DataSourceFind{
+			InstanceID: &instance.ID,
+			Type:       &dataSourceType,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch data source for instance: %v", instance.Name)).SetInternal(err)
+		}
+		if existing != nil {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Instance %q already has the %s data source", instance.Name, api.RO))
+		}
+
+		// Like the admin data source, the read-only data source belongs to the * database of the instance.
+		allDatabaseName := api.AllDatabaseName
+		allDatabase, err := s.DatabaseService.FindDatabase(ctx, &api.DatabaseFind{
+			InstanceID:         &instance.ID,
+			Name:               &allDatabaseName,
+			IncludeAllDatabase: true,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch * database for instance: %v", instance.Name)).SetInternal(err)
+		}
+		if allDatabase == nil {
+			err := fmt.Errorf("* database not found for instance ID %v, name %q", instance.ID, instance.Name)
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
+		}
+
+		dataSourceCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
+		dataSourceCreate.InstanceID = instance.ID
+		dataSourceCreate.DatabaseID = allDatabase.ID
+		if dataSourceCreate.Name == "" {
+			dataSourceCreate.Name = api.ReadOnlyDataSourceName
+		}
+		dataSource, err := s.DataSourceService.CreateDataSource(ctx, dataSourceCreate)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create data source for instance: %v", instance.Name)).SetInternal(err)
+		}
+		// We do not transfer the password back to client.
+		dataSource.Password = ""
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dataSource); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create data source response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.GET("/instance/:instanceID/datasource", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+
+		list, err := s.DataSourceService.FindDataSourceList(ctx, &api.DataSourceFind{
+			InstanceID: &id,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch data source list for instance: %v", id)).SetInternal(err)
+		}
+		// We do not transfer the password back to client.
+		// The developers may query with the read-only data source, but don't need to know the admin username.
+		role := c.Get(getRoleContextKey()).(api.Role)
+		for _, dataSource := range list {
+			dataSource.Password = ""
+			if dataSource.Type == api.Admin && role == api.Developer {
+				dataSource.Username = ""
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, list); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal data source list response for instance: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	// The admin data source is updated along with the instance, so only the read-only data source can be updated here.
+	g.PATCH("/instance/:instanceID/datasource/:dataSourceID", func(c echo.Context) error {
+		ctx := context.Background()
+		instanceID, err := strconv.Atoi(c.Param("instanceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Instance ID is not a number: %s", c.Param("instanceID"))).SetInternal(err)
+		}
+		id, err := strconv.Atoi(c.Param("dataSourceID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Data source ID is not a number: %s", c.Param("dataSourceID"))).SetInternal(err)
+		}
+
+		dataSourceType := api.RO
+		dataSource, err := s.DataSourceService.FindDataSource(ctx, &api.DataSourceFind{
+			InstanceID: &instanceID,
+			Type:       &dataSourceType,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch data source for instance: %v", instanceID)).SetInternal(err)
+		}
+		if dataSource == nil || dataSource.ID != id {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("%s data source ID not found for instance %d: %d", api.RO, instanceID, id))
+		}
+
+		dataSourcePatch := &api.DataSourcePatch{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, dataSourcePatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted patch data source request").SetInternal(err)
+		}
+		// The data source ID in the body must not patch another data source than the checked one in the path.
+		if dataSourcePatch.ID != 0 && dataSourcePatch.ID != id {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted patch data source request, data source ID %d mismatches the path ID %d", dataSourcePatch.ID, id))
+		}
+		dataSourcePatch.ID = id
+		dataSourcePatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
+
+		dataSource, err = s.DataSourceService.PatchDataSource(ctx, dataSourcePatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch data source ID: %v", id)).SetInternal(err)
+		}
+		// We do not transfer the password back to client.
+		dataSource.Password = ""
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, dataSource); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal patch data source response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
 	g.GET("/instance/:instanceID/user", func(c echo.Context) error {
 		ctx := context.Background()
 		id, err := strconv.Atoi(c.Param("instanceID"))
@@ -479,6 +629,11 @@ func (s *Server) composeInstanceByID(ctx context.Context, id int) (*api.Instance
 func (s *Server) composeInstanceRelationship(ctx context.Context, instance *api.Instance) error {
 	var err error
 
+	instance.Capability, err = db.GetCapability(instance.Engine)
+	if err != nil {
+		return err
+	}
+
 	instance.Creator, err = s.composePrincipalByID(ctx, instance.CreatorID)
 	if err != nil {
 		return err
@@ -535,6 +690,38 @@ func (s *Server) composeInstanceAdminDataSource(ctx context.Context, instance *a
 	return nil
 }
 
+// getReadOnlyDatabaseDriver returns the driver for the readonly queries of the instance, which connects with the read-only data source.
+// If the instance has no read-only data source, it connects with the admin data source only if the query data source policy
+// of the instance environment allows. It also returns the type of the data source connected with.
+func (s *Server) getReadOnlyDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName string) (db.Driver, api.DataSourceType, error) {
+	dataSourceType := api.RO
+	dataSource, err := s.DataSourceService.FindDataSource(ctx, &api.DataSourceFind{
+		InstanceID: &instance.ID,
+		Type:       &dataSourceType,
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch the %s data source for instance %q: %w", api.RO, instance.Name, err)
+	}
+	if dataSource != nil {
+		driver, err := getDataSourceDatabaseDriver(ctx, instance, dataSource, databaseName, s.l)
+		return driver, api.RO, err
+	}
+
+	policy, err := s.PolicyService.GetQueryDataSourcePolicy(ctx, instance.EnvironmentID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch the query data source policy for environment ID %v: %w", instance.EnvironmentID, err)
+	}
+	if policy.AdminFallback != api.QueryDataSourceAdminFallbackAllow {
+		return nil, "", common.Errorf(common.NotFound, fmt.Errorf("instance %q has no %s data source, and querying with the %s data source is disallowed by the environment policy", instance.Name, api.RO, api.Admin))
+	}
+	s.l.Info("Fell back to the admin data source for the readonly query, since the instance has no read-only data source",
+		zap.String("instance", instance.Name),
+		zap.String("database", databaseName),
+	)
+	driver, err := getDatabaseDriver(ctx, instance, databaseName, s.l)
+	return driver, api.Admin, err
+}
+
 func (s *Server) findInstanceAdminPasswordByID(ctx context.Context, instanceID int) (string, error) {
 	dataSourceFind := &api.DataSourceFind{
 		InstanceID: &instanceID,
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"go.uber.org/zap"
+
+	// Register the SQLite driver, which connects to the databases in a local directory.
+	_ "github.com/bytebase/bytebase/plugin/db/sqlite"
+	// Register the sqlite3 driver.
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// fakeDataSourceService is the data source service of an instance with the given read-only data source.
+type fakeDataSourceService struct {
+	api.DataSourceService
+	roDataSource *api.DataSource
+}
+
+func (s *fakeDataSourceService) FindDataSource(_ context.Context, find *api.DataSourceFind) (*api.DataSource, error) {
+	if find.Type != nil && *find.Type == api.RO {
+		return s.roDataSource, nil
+	}
+	return nil, nil
+}
+
+// fakePolicyService is the policy service of the environments with the given query data source policy.
+type fakePolicyService struct {
+	api.PolicyService
+	adminFallback api.QueryDataSourceAdminFallback
+}
+
+func (s *fakePolicyService) GetQueryDataSourcePolicy(_ context.Context, _ int) (*api.QueryDataSourcePolicy, error) {
+	return &api.QueryDataSourcePolicy{AdminFallback: s.adminFallback}, nil
+}
+
+// newSQLiteTestInstanceDir returns the SQLite instance directory whose database db1 has the table t with the value.
+func newSQLiteTestInstanceDir(t *testing.T, value string) string {
+	t.Helper()
+	dir := t.TempDir()
+	database, err := sql.Open("sqlite3", filepath.Join(dir, "db1.db"))
+	if err != nil {
+		t.Fatalf("failed to open database db1 in %q: %v", dir, err)
+	}
+	defer database.Close()
+	if _, err := database.Exec("CREATE TABLE t (v TEXT); INSERT INTO t VALUES (?)", value); err != nil {
+		t.Fatalf("failed to set up database db1 in %q: %v", dir, err)
+	}
+	return dir
+}
+
+func TestGetReadOnlyDatabaseDriver(t *testing.T) {
+	instance := &api.Instance{
+		ID:            101,
+		EnvironmentID: 101,
+		Environment:   &api.Environment{Name: "Test"},
+		Name:          "sqlite",
+		Engine:        db.SQLite,
+		Host:          newSQLiteTestInstanceDir(t, "admin"),
+	}
+	replicaDir := newSQLiteTestInstanceDir(t, "replica")
+	tests := []struct {
+		name          string
+		roDataSource  *api.DataSource
+		adminFallback api.QueryDataSourceAdminFallback
+		// wantValue is the value queried from the connected database, or empty if the connection is disallowed.
+		wantValue          string
+		wantDataSourceType api.DataSourceType
+	}{
+		{
+			name:               "read-only data source with host override",
+			roDataSource:       &api.DataSource{Type: api.RO, HostOverride: replicaDir},
+			adminFallback:      api.QueryDataSourceAdminFallbackDisallow,
+			wantValue:          "replica",
+			wantDataSourceType: api.RO,
+		},
+		{
+			name:               "read-only data source without host override",
+			roDataSource:       &api.DataSource{Type: api.RO},
+			adminFallback:      api.QueryDataSourceAdminFallbackDisallow,
+			wantValue:          "admin",
+			wantDataSourceType: api.RO,
+		},
+		{
+			name:               "admin fallback allowed",
+			adminFallback:      api.QueryDataSourceAdminFallbackAllow,
+			wantValue:          "admin",
+			wantDataSourceType: api.Admin,
+		},
+		{
+			name:          "admin fallback disallowed",
+			adminFallback: api.QueryDataSourceAdminFallbackDisallow,
+		},
+	}
+
+	for _, test := range tests {
+		s := &Server{
+			l:                 zap.NewNop(),
+			DataSourceService: &fakeDataSourceService{roDataSource: test.roDataSource},
+			PolicyService:     &fakePolicyService{adminFallback: test.adminFallback},
+		}
+		ctx := context.Background()
+		driver, dataSourceType, err := s.getReadOnlyDatabaseDriver(ctx, instance, "db1")
+		if test.wantValue == "" {
+			if common.ErrorCode(err) != common.NotFound {
+				t.Errorf("%s: getReadOnlyDatabaseDriver() got error %v, want the not found error", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: getReadOnlyDatabaseDriver() got error: %v", test.name, err)
+			continue
+		}
+		if dataSourceType != test.wantDataSourceType {
+			t.Errorf("%s: getReadOnlyDatabaseDriver() got data source type %q, want %q", test.name, dataSourceType, test.wantDataSourceType)
+		}
+		value, err := queryTestValue(ctx, driver)
+		driver.Close(ctx)
+		if err != nil {
+			t.Errorf("%s: failed to query the connected database: %v", test.name, err)
+			continue
+		}
+		if value != test.wantValue {
+			t.Errorf("%s: getReadOnlyDatabaseDriver() connected to the database with value %q, want %q", test.name, value, test.wantValue)
+		}
+	}
+}
+
+// queryTestValue returns the value in the table t of the connected database.
+func queryTestValue(ctx context.Context, driver db.Driver) (string, error) {
+	cursor, err := driver.OpenCursor(ctx, "SELECT v FROM t", 1)
+	if err != nil {
+		return "", err
+	}
+	defer cursor.Close()
+	rowList, err := cursor.Next(1)
+	if err == io.EOF {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	value, _ := rowList[0][0].(string)
+	return value, nil
+}
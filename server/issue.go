@@ -28,6 +28,9 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 
 		issue, err := s.createIssue(ctx, issueCreate, c.Get(getPrincipalIDContextKey()).(int))
 		if err != nil {
+			if httpErr, ok := err.(*echo.HTTPError); ok {
+				return httpErr
+			}
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create issue").SetInternal(err)
 		}
 
@@ -236,6 +239,8 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusNotFound).SetInternal(err)
 			} else if common.ErrorCode(err) == common.Conflict {
 				return echo.NewHTTPError(http.StatusConflict).SetInternal(err)
+			} else if common.ErrorCode(err) == common.NotAuthorized {
+				return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
 			}
 			return echo.NewHTTPError(http.StatusInternalServerError).SetInternal(err)
 		}
@@ -250,6 +255,117 @@ func (s *Server) registerIssueRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	g.POST("/issue/:issueID/rollback", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("issueID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("issueID"))).SetInternal(err)
+		}
+
+		issue, err := s.composeIssueByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue ID: %v", id)).SetInternal(err)
+		}
+		if issue == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue ID not found: %d", id))
+		}
+
+		issueCreate, err := composeRollbackIssueCreate(issue)
+		if err != nil {
+			return err
+		}
+		rollbackIssue, err := s.createIssue(ctx, issueCreate, c.Get(getPrincipalIDContextKey()).(int))
+		if err != nil {
+			if httpErr, ok := err.(*echo.HTTPError); ok {
+				return httpErr
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create rollback issue for issue ID: %v", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, rollbackIssue); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal create rollback issue response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// composeRollbackIssueCreate composes the issue reverting the closed schema or data update issue, which executes the rollback
+// statements of the applied tasks against the same databases, in the reverse order.
+// The returned error is an *echo.HTTPError.
+func composeRollbackIssueCreate(issue *api.Issue) (*api.IssueCreate, error) {
+	if issue.Type != api.IssueDatabaseSchemaUpdate && issue.Type != api.IssueDatabaseDataUpdate {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only schema update or data update issue can be rolled back, issue %d is %s", issue.ID, issue.Type))
+	}
+	if issue.Status != api.IssueDone && issue.Status != api.IssueCanceled {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only closed issue can be rolled back, issue %d is %s", issue.ID, issue.Status))
+	}
+	if issue.Project.TenantMode == api.TenantModeTenant {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Rollback issue is not supported in tenant mode project")
+	}
+
+	migrationType := db.Migrate
+	if issue.Type == api.IssueDatabaseDataUpdate {
+		migrationType = db.Data
+	}
+	var detailList []*api.UpdateSchemaDetail
+	for i := len(issue.Pipeline.StageList) - 1; i >= 0; i-- {
+		stage := issue.Pipeline.StageList[i]
+		for j := len(stage.TaskList) - 1; j >= 0; j-- {
+			task := stage.TaskList[j]
+			if task.Status != api.TaskDone || task.DatabaseID == nil {
+				continue
+			}
+			var statement, rollbackStatement string
+			switch task.Type {
+			case api.TaskDatabaseSchemaUpdate:
+				payload := &api.TaskDatabaseSchemaUpdatePayload{}
+				if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Invalid database schema update payload of task %d", task.ID)).SetInternal(err)
+				}
+				statement, rollbackStatement = payload.Statement, payload.RollbackStatement
+			case api.TaskDatabaseDataUpdate:
+				payload := &api.TaskDatabaseDataUpdatePayload{}
+				if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Invalid database data update payload of task %d", task.ID)).SetInternal(err)
+				}
+				statement, rollbackStatement = payload.Statement, payload.RollbackStatement
+			}
+			if rollbackStatement == "" {
+				continue
+			}
+			detailList = append(detailList, &api.UpdateSchemaDetail{
+				DatabaseID:        *task.DatabaseID,
+				Statement:         rollbackStatement,
+				RollbackStatement: statement,
+			})
+		}
+	}
+	if len(detailList) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Issue %d has no applied task with rollback statement", issue.ID))
+	}
+
+	createContext, err := json.Marshal(&api.UpdateSchemaContext{
+		MigrationType:          migrationType,
+		UpdateSchemaDetailList: detailList,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal rollback issue create context").SetInternal(err)
+	}
+	description := ""
+	if issue.Description != "" {
+		description = fmt.Sprintf("====Original issue description BEGIN====\n\n%s\n\n====Original issue description END====\n\n", issue.Description)
+	}
+	return &api.IssueCreate{
+		ProjectID:       issue.ProjectID,
+		Name:            fmt.Sprintf("[Rollback] issue/%d - %s", issue.ID, issue.Name),
+		Type:            issue.Type,
+		Description:     description,
+		AssigneeID:      issue.AssigneeID,
+		CreateContext:   string(createContext),
+		RollbackIssueID: &issue.ID,
+	}, nil
 }
 
 func (s *Server) composeIssueByID(ctx context.Context, id int) (*api.Issue, error) {
@@ -332,6 +448,11 @@ func (s *Server) createIssue(ctx context.Context, issueCreate *api.IssueCreate,
 	if issueCreate.AssigneeID == api.UnknownID {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, assignee missing")
 	}
+	if issueCreate.Type == api.IssueDataSourceRequest {
+		if err := s.validateDataSourceRequest(ctx, issueCreate); err != nil {
+			return nil, err
+		}
+	}
 
 	var pipeline *api.Pipeline
 	// If frontend does not pass the stageList, we will generate it from backend.
@@ -468,8 +589,11 @@ func (s *Server) createIssue(ctx context.Context, issueCreate *api.IssueCreate,
 		return nil, fmt.Errorf("failed to schedule task after creating the issue: %v. Error %w", issue.Name, err)
 	}
 	// We need to re-compose task relationship because the one in issue is modified by ScheduleNextTaskIfNeeded.
-	if err := s.composeTaskRelationship(ctx, task); err != nil {
-		return nil, fmt.Errorf("failed to compose task %v, error %w", task.Name, err)
+	// There is no task to schedule for the issue without tasks, e.g. the data source request.
+	if task != nil {
+		if err := s.composeTaskRelationship(ctx, task); err != nil {
+			return nil, fmt.Errorf("failed to compose task %v, error %w", task.Name, err)
+		}
 	}
 
 	createActivityPayload := api.ActivityIssueCreatePayload{
@@ -614,49 +738,12 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 			return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
 		}
 
-		switch instance.Engine {
-		case db.ClickHouse:
-			// ClickHouse does not support character set and collation at the database level.
-			if m.CharacterSet != "" {
-				return nil, echo.NewHTTPError(
-					http.StatusBadRequest,
-					fmt.Sprintf("Failed to create issue, ClickHouse does not support character set, got %s\n", m.CharacterSet),
-				)
-			}
-			if m.Collation != "" {
-				return nil, echo.NewHTTPError(
-					http.StatusBadRequest,
-					fmt.Sprintf("Failed to create issue, ClickHouse does not support collation, got %s\n", m.Collation),
-				)
-			}
-		case db.Snowflake:
-			if m.CharacterSet != "" {
-				return nil, echo.NewHTTPError(
-					http.StatusBadRequest,
-					fmt.Sprintf("Failed to create issue, Snowflake does not support character set, got %s\n", m.CharacterSet),
-				)
-			}
-			if m.Collation != "" {
-				return nil, echo.NewHTTPError(
-					http.StatusBadRequest,
-					fmt.Sprintf("Failed to create issue, Snowflake does not support collation, got %s\n", m.Collation),
-				)
-			}
-
-			// Snowflake needs to use upper case of DatabaseName.
+		if err := validateCreateDatabaseCollation(instance, &m); err != nil {
+			return nil, err
+		}
+		// Snowflake needs to use upper case of DatabaseName.
+		if instance.Engine == db.Snowflake {
 			m.DatabaseName = strings.ToUpper(m.DatabaseName)
-		case db.SQLite:
-			// no-op.
-		default:
-			if m.CharacterSet == "" {
-				return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, character set missing")
-			}
-			// For postgres, we don't explicitly specify a default since the default might be UNSET (denoted by "C").
-			// If that's the case, setting an explicit default such as "en_US.UTF-8" might fail if the instance doesn't
-			// install it.
-			if instance.Engine != db.Postgres && m.Collation == "" {
-				return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, collation missing")
-			}
 		}
 
 		// Validate the labels. Labels are set upon task completion.
@@ -784,6 +871,13 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 				}
 			}
 		}
+		for _, detail := range m.UpdateSchemaDetailList {
+			switch detail.MigrationType {
+			case "", db.Baseline, db.Migrate, db.Data:
+			default:
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid migration type %q", detail.MigrationType))
+			}
+		}
 		pc := &api.PipelineCreate{}
 		switch m.MigrationType {
 		case db.Baseline:
@@ -808,12 +902,20 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 			if m.MigrationType != db.Migrate {
 				return nil, echo.NewHTTPError(http.StatusBadRequest, "Only Migrate type migration can be performed on tenant mode project")
 			}
-			if len(m.UpdateSchemaDetailList) != 1 {
-				return nil, echo.NewHTTPError(http.StatusBadRequest, "Tenant mode project should have exactly one update schema detail")
+			if len(m.UpdateSchemaDetailList) == 0 {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Tenant mode project should have at least one update schema detail")
 			}
 			d := m.UpdateSchemaDetailList[0]
-			if d.Statement == "" {
-				return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, sql statement missing")
+			for _, detail := range m.UpdateSchemaDetailList {
+				if detail.MigrationType != "" && detail.MigrationType != db.Migrate {
+					return nil, echo.NewHTTPError(http.StatusBadRequest, "Only Migrate type migration can be performed on tenant mode project")
+				}
+				if detail.DatabaseName != d.DatabaseName {
+					return nil, echo.NewHTTPError(http.StatusBadRequest, "Update schema details of tenant mode project should have the same database name")
+				}
+				if detail.Statement == "" {
+					return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, sql statement missing")
+				}
 			}
 
 			databaseList, err := s.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{
@@ -847,11 +949,13 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 					if policy.Value == api.PipelineApprovalValueManualNever {
 						taskStatus = api.TaskPending
 					}
-					taskCreate, err := getUpdateTask(database, m.MigrationType, m.VCSPushEvent, d, taskStatus)
-					if err != nil {
-						return nil, err
+					for _, detail := range m.UpdateSchemaDetailList {
+						taskCreate, err := getUpdateTask(database, m.MigrationType, m.VCSPushEvent, detail, taskStatus)
+						if err != nil {
+							return nil, err
+						}
+						taskCreateList = append(taskCreateList, *taskCreate)
 					}
-					taskCreateList = append(taskCreateList, *taskCreate)
 				}
 				if len(environmentSet) != 1 {
 					var environments []string
@@ -870,8 +974,13 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 			}
 			pipelineCreate = pc
 		} else {
+			stageIndexByDatabase := make(map[int]int)
 			for _, d := range m.UpdateSchemaDetailList {
-				if m.MigrationType == db.Migrate && d.Statement == "" {
+				migrationType := m.MigrationType
+				if d.MigrationType != "" {
+					migrationType = d.MigrationType
+				}
+				if migrationType == db.Migrate && d.Statement == "" {
 					return nil, echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, sql statement missing")
 				}
 				databaseFind := &api.DatabaseFind{
@@ -899,6 +1008,13 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 					return nil, err
 				}
 
+				// The tasks of the same database are performed in the detail order in one stage,
+				// so a failed task blocks the later ones.
+				if index, ok := stageIndexByDatabase[database.ID]; ok {
+					pc.StageList[index].TaskList = append(pc.StageList[index].TaskList, *taskCreate)
+					continue
+				}
+				stageIndexByDatabase[database.ID] = len(pc.StageList)
 				pc.StageList = append(pc.StageList, api.StageCreate{
 					Name:          fmt.Sprintf("%s %s", database.Instance.Environment.Name, database.Name),
 					EnvironmentID: database.Instance.Environment.ID,
@@ -907,6 +1023,11 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 			}
 			pipelineCreate = pc
 		}
+	case issueCreate.Type == api.IssueDataSourceRequest:
+		// The request is approved by resolving the issue, so the pipeline has no stage.
+		pipelineCreate = &api.PipelineCreate{
+			Name: fmt.Sprintf("Pipeline - %s", issueCreate.Name),
+		}
 	default:
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid issue type %q", issueCreate.Type))
 	}
@@ -942,7 +1063,43 @@ func (s *Server) createPipelineFromIssue(ctx context.Context, issueCreate *api.I
 	return createdPipeline, nil
 }
 
+// validateCreateDatabaseCollation validates the character set and the collation of the database to create on the instance.
+func validateCreateDatabaseCollation(instance *api.Instance, m *api.CreateDatabaseContext) error {
+	if instance.Capability.Collation {
+		if m.CharacterSet == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, character set missing")
+		}
+		// For postgres, we don't explicitly specify a default since the default might be UNSET (denoted by "C").
+		// If that's the case, setting an explicit default such as "en_US.UTF-8" might fail if the instance doesn't
+		// install it.
+		if instance.Engine != db.Postgres && m.Collation == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, collation missing")
+		}
+	} else if instance.Engine != db.SQLite {
+		// SQLite ignores the character set and the collation instead of rejecting them.
+		if m.CharacterSet != "" {
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				fmt.Sprintf("Failed to create issue, %s does not support character set, got %s\n", instance.Engine, m.CharacterSet),
+			)
+		}
+		if m.Collation != "" {
+			return echo.NewHTTPError(
+				http.StatusBadRequest,
+				fmt.Sprintf("Failed to create issue, %s does not support collation, got %s\n", instance.Engine, m.Collation),
+			)
+		}
+	}
+	return nil
+}
+
 func getUpdateTask(database *api.Database, migrationType db.MigrationType, vcsPushEvent *vcs.PushEvent, d *api.UpdateSchemaDetail, taskStatus api.TaskStatus) (*api.TaskCreate, error) {
+	if d.MigrationType != "" {
+		migrationType = d.MigrationType
+	}
+	if d.VCSPushEvent != nil {
+		vcsPushEvent = d.VCSPushEvent
+	}
 	taskName := fmt.Sprintf("Establish %q baseline", database.Name)
 	if migrationType == db.Migrate {
 		taskName = fmt.Sprintf("Update %q schema", database.Name)
@@ -1028,6 +1185,8 @@ func getDatabaseNameAndStatement(dbType db.Type, databaseName, characterSet, col
 
 func (s *Server) changeIssueStatus(ctx context.Context, issue *api.Issue, newStatus api.IssueStatus, updaterID int, comment string) (*api.Issue, error) {
 	var pipelineStatus api.PipelineStatus
+	// dataSourceRequest is the approved data source request to grant the query access.
+	var dataSourceRequest *api.DataSourceRequestContext
 	switch newStatus {
 	case api.IssueOpen:
 		pipelineStatus = api.PipelineOpen
@@ -1040,6 +1199,13 @@ func (s *Server) changeIssueStatus(ctx context.Context, issue *api.Issue, newSta
 				}
 			}
 		}
+		if issue.Type == api.IssueDataSourceRequest {
+			requestContext, err := s.validateDataSourceRequestApproval(ctx, issue, updaterID)
+			if err != nil {
+				return nil, err
+			}
+			dataSourceRequest = requestContext
+		}
 		pipelineStatus = api.PipelineDone
 	case api.IssueCanceled:
 		// If we want to cancel the issue, we find the current running tasks, mark each of them CANCELED.
@@ -1079,6 +1245,18 @@ func (s *Server) changeIssueStatus(ctx context.Context, issue *api.Issue, newSta
 		return nil, fmt.Errorf("failed update issue status: %v, error: %w", issue.Name, err)
 	}
 
+	if dataSourceRequest != nil {
+		if _, err := s.QueryGrantService.CreateQueryGrant(ctx, &api.QueryGrantCreate{
+			CreatorID:   updaterID,
+			DatabaseID:  dataSourceRequest.DatabaseID,
+			PrincipalID: issue.CreatorID,
+			IssueID:     issue.ID,
+			ExpireTs:    dataSourceRequest.ExpireTs,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to grant the query access requested by issue: %v, error: %w", issue.Name, err)
+		}
+	}
+
 	payload, err := json.Marshal(api.ActivityIssueStatusUpdatePayload{
 		OldStatus: issue.Status,
 		NewStatus: newStatus,
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/kr/pretty"
 )
 
@@ -71,3 +72,127 @@ func TestGetPeerTenantDatabase(t *testing.T) {
 		}
 	}
 }
+
+func TestComposeRollbackIssueCreate(t *testing.T) {
+	db1, db2 := 1, 2
+	issue := &api.Issue{
+		ID:         10,
+		ProjectID:  100,
+		Project:    &api.Project{TenantMode: api.TenantModeDisabled},
+		Name:       "Add column",
+		Status:     api.IssueDone,
+		Type:       api.IssueDatabaseSchemaUpdate,
+		AssigneeID: 200,
+		Pipeline: &api.Pipeline{
+			StageList: []*api.Stage{
+				{
+					TaskList: []*api.Task{
+						{
+							ID:         1,
+							Status:     api.TaskDone,
+							Type:       api.TaskDatabaseSchemaUpdate,
+							DatabaseID: &db1,
+							Payload:    `{"statement":"ALTER TABLE t ADD COLUMN c INT;","rollbackStatement":"ALTER TABLE t DROP COLUMN c;"}`,
+						},
+					},
+				},
+				{
+					TaskList: []*api.Task{
+						{
+							ID:         2,
+							Status:     api.TaskDone,
+							Type:       api.TaskDatabaseSchemaUpdate,
+							DatabaseID: &db2,
+							Payload:    `{"statement":"ALTER TABLE t ADD COLUMN c INT;"}`,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := composeRollbackIssueCreate(issue)
+	if err != nil {
+		t.Fatalf("composeRollbackIssueCreate() unexpected error: %v", err)
+	}
+	want := &api.IssueCreate{
+		ProjectID:       100,
+		Name:            "[Rollback] issue/10 - Add column",
+		Type:            api.IssueDatabaseSchemaUpdate,
+		AssigneeID:      200,
+		CreateContext:   `{"migrationType":"MIGRATE","updateSchemaDetailList":[{"databaseId":1,"databaseName":"","statement":"ALTER TABLE t DROP COLUMN c;","rollbackStatement":"ALTER TABLE t ADD COLUMN c INT;","EarliestAllowedTs":0}],"VCSPushEvent":null}`,
+		RollbackIssueID: &issue.ID,
+	}
+	if diff := pretty.Diff(got, want); len(diff) > 0 {
+		t.Errorf("composeRollbackIssueCreate() got %+v, want %+v, diff %+v.", got, want, diff)
+	}
+
+	issue.Status = api.IssueOpen
+	if _, err := composeRollbackIssueCreate(issue); err == nil {
+		t.Errorf("composeRollbackIssueCreate() expected error for open issue")
+	}
+	issue.Status = api.IssueDone
+	issue.Pipeline.StageList = issue.Pipeline.StageList[1:]
+	if _, err := composeRollbackIssueCreate(issue); err == nil {
+		t.Errorf("composeRollbackIssueCreate() expected error for issue without rollback statement")
+	}
+}
+
+func TestValidateCreateDatabaseCollation(t *testing.T) {
+	mysql := &api.Instance{Engine: db.MySQL, Capability: db.Capability{Collation: true}}
+	postgres := &api.Instance{Engine: db.Postgres, Capability: db.Capability{Collation: true}}
+	clickHouse := &api.Instance{Engine: db.ClickHouse}
+	sqlite := &api.Instance{Engine: db.SQLite}
+	tests := []struct {
+		name     string
+		instance *api.Instance
+		m        *api.CreateDatabaseContext
+		wantErr  bool
+	}{
+		{
+			name:     "MySQL",
+			instance: mysql,
+			m:        &api.CreateDatabaseContext{CharacterSet: "utf8mb4", Collation: "utf8mb4_general_ci"},
+		},
+		{
+			name:     "MySQL without collation",
+			instance: mysql,
+			m:        &api.CreateDatabaseContext{CharacterSet: "utf8mb4"},
+			wantErr:  true,
+		},
+		{
+			name:     "Postgres without collation",
+			instance: postgres,
+			m:        &api.CreateDatabaseContext{CharacterSet: "UTF8"},
+		},
+		{
+			name:     "Postgres without character set",
+			instance: postgres,
+			m:        &api.CreateDatabaseContext{},
+			wantErr:  true,
+		},
+		{
+			name:     "ClickHouse",
+			instance: clickHouse,
+			m:        &api.CreateDatabaseContext{},
+		},
+		{
+			name:     "ClickHouse with character set",
+			instance: clickHouse,
+			m:        &api.CreateDatabaseContext{CharacterSet: "utf8"},
+			wantErr:  true,
+		},
+		{
+			name:     "SQLite with character set and collation",
+			instance: sqlite,
+			m:        &api.CreateDatabaseContext{CharacterSet: "UTF8", Collation: "BINARY"},
+		},
+	}
+
+	for _, test := range tests {
+		err := validateCreateDatabaseCollation(test.instance, test.m)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: validateCreateDatabaseCollation() got error %v, want error %v", test.name, err, test.wantErr)
+		}
+	}
+}
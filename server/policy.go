@@ -55,6 +55,11 @@ func (s *Server) registerPolicyRoutes(g *echo.Group) {
 		if err := s.hasAccessToUpsertPolicy(policyUpsert); err != nil {
 			return echo.NewHTTPError(http.StatusForbidden, err.Error()).SetInternal(err)
 		}
+		if pType == api.PolicyTypeDataMasking {
+			if err := s.validateDataMaskingPolicy(ctx, environmentID, policyUpsert.Payload); err != nil {
+				return err
+			}
+		}
 
 		policy, err := s.PolicyService.UpsertPolicy(ctx, policyUpsert)
 		if err != nil {
@@ -12,6 +12,8 @@ import (
 	"github.com/bytebase/bytebase/common"
 
 	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/gitea"
+	"github.com/bytebase/bytebase/plugin/vcs/github"
 	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
 	"github.com/google/jsonapi"
 	"github.com/google/uuid"
@@ -187,6 +189,18 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		if err := api.ValidateRepositorySchemaPathTemplate(repositoryCreate.SchemaPathTemplate, project.TenantMode); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted create linked repository request: %s", err.Error()))
 		}
+		if repositoryCreate.SchemaPathTemplate != "" {
+			if err := s.validateRepositorySchemaPathTemplateDatabase(ctx, projectID); err != nil {
+				return err
+			}
+		}
+
+		if repositoryCreate.WriteBackMode == "" {
+			repositoryCreate.WriteBackMode = api.RepositoryWriteBackCommit
+		}
+		if err := api.ValidateRepositoryWriteBackMode(repositoryCreate.WriteBackMode); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted create linked repository request: %s", err.Error()))
+		}
 
 		vcsFind := &api.VCSFind{
 			ID: &repositoryCreate.VCSID,
@@ -199,6 +213,10 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("VCS ID not found: %d", repositoryCreate.VCSID))
 		}
 
+		if err := validateBranchFilter(vcs.Type, repositoryCreate.BranchFilter); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted create linked repository request: %s", err.Error()))
+		}
+
 		repositoryCreate.WebhookURLHost = fmt.Sprintf("%s:%d", s.host, s.port)
 		repositoryCreate.WebhookEndpointID = uuid.New().String()
 		repositoryCreate.WebhookSecretToken = common.RandomString(gitlab.SecretTokenLength)
@@ -206,18 +224,52 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		// Create webhook and retrieve the created webhook id
 		var webhookCreatePayload []byte
 		switch vcs.Type {
-		case "GITLAB_SELF_HOST":
+		case vcsPlugin.GitLabSelfHost:
 			webhookPost := gitlab.WebhookPost{
 				URL:                    fmt.Sprintf("%s:%d/%s/%s", s.host, s.port, gitLabWebhookPath, repositoryCreate.WebhookEndpointID),
 				SecretToken:            repositoryCreate.WebhookSecretToken,
 				PushEvents:             true,
-				PushEventsBranchFilter: repositoryCreate.BranchFilter,
+				MergeRequestsEvents:    true,
+				TagPushEvents:          true,
+				PushEventsBranchFilter: webhookBranchFilter(repositoryCreate.BranchFilter),
 				EnableSSLVerification:  false,
 			}
 			webhookCreatePayload, err = json.Marshal(webhookPost)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal post request for creating webhook for project ID: %v", repositoryCreate.ProjectID)).SetInternal(err)
 			}
+		case vcsPlugin.GitHub:
+			webhookPost := github.WebhookCreateOrUpdate{
+				Name: "web",
+				Config: github.WebhookConfig{
+					URL:         fmt.Sprintf("%s:%d/%s/%s", s.host, s.port, gitHubWebhookPath, repositoryCreate.WebhookEndpointID),
+					ContentType: "json",
+					Secret:      repositoryCreate.WebhookSecretToken,
+					InsecureSSL: "1",
+				},
+				Events: []string{string(github.WebhookPush), string(github.WebhookPullRequest)},
+				Active: true,
+			}
+			webhookCreatePayload, err = json.Marshal(webhookPost)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal post request for creating webhook for project ID: %v", repositoryCreate.ProjectID)).SetInternal(err)
+			}
+		case vcsPlugin.Gitea:
+			webhookPost := gitea.WebhookPost{
+				Type: "gitea",
+				Config: gitea.WebhookConfig{
+					URL:         fmt.Sprintf("%s:%d/%s/%s", s.host, s.port, giteaWebhookPath, repositoryCreate.WebhookEndpointID),
+					ContentType: "json",
+					Secret:      repositoryCreate.WebhookSecretToken,
+				},
+				Events:       []string{string(gitea.WebhookPush), string(gitea.WebhookPullRequest)},
+				BranchFilter: webhookBranchFilter(repositoryCreate.BranchFilter),
+				Active:       true,
+			}
+			webhookCreatePayload, err = json.Marshal(webhookPost)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal post request for creating webhook for project ID: %v", repositoryCreate.ProjectID)).SetInternal(err)
+			}
 		}
 
 		webhookID, err := vcsPlugin.Get(vcs.Type, vcsPlugin.ProviderConfig{Logger: s.l}).CreateWebhook(
@@ -325,6 +377,17 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 			if err := api.ValidateRepositorySchemaPathTemplate(*repositoryPatch.SchemaPathTemplate, project.TenantMode); err != nil {
 				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted create linked repository request: %s", err.Error()))
 			}
+			if *repositoryPatch.SchemaPathTemplate != "" {
+				if err := s.validateRepositorySchemaPathTemplateDatabase(ctx, projectID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if repositoryPatch.WriteBackMode != nil {
+			if err := api.ValidateRepositoryWriteBackMode(*repositoryPatch.WriteBackMode); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted patch linked repository request: %s", err.Error()))
+			}
 		}
 
 		// Remove enclosing /
@@ -349,17 +412,12 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 		}
 
 		repository := list[0]
-		repositoryPatch.ID = repository.ID
-		updatedRepository, err := s.RepositoryService.PatchRepository(ctx, repositoryPatch)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update repository for project ID: %d", projectID)).SetInternal(err)
-		}
-
+		var vcs *api.VCS
 		if repositoryPatch.BranchFilter != nil {
 			vcsFind := &api.VCSFind{
 				ID: &repository.VCSID,
 			}
-			vcs, err := s.VCSService.FindVCS(ctx, vcsFind)
+			vcs, err = s.VCSService.FindVCS(ctx, vcsFind)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update repository for project ID: %d", projectID)).SetInternal(err)
 			}
@@ -367,20 +425,66 @@ func (s *Server) registerProjectRoutes(g *echo.Group) {
 				err := fmt.Errorf("failed to find VCS configuration for ID: %d", repository.VCSID)
 				return echo.NewHTTPError(http.StatusInternalServerError, err.Error()).SetInternal(err)
 			}
+			if err := validateBranchFilter(vcs.Type, *repositoryPatch.BranchFilter); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted patch linked repository request: %s", err.Error()))
+			}
+		}
+
+		repositoryPatch.ID = repository.ID
+		updatedRepository, err := s.RepositoryService.PatchRepository(ctx, repositoryPatch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update repository for project ID: %d", projectID)).SetInternal(err)
+		}
+
+		if repositoryPatch.BranchFilter != nil {
 			// Update the webhook after we successfully update the repository.
 			// This is because in case the webhook update fails, we can still have a reconcile process to reconcile the webhook state.
 			// If we update it before we update the repository, then if the repository update fails, then the reconcile process will reconcile the webhook to the pre-update state which is likely not intended.
 			var webhookPatchPayload []byte
 			switch vcs.Type {
-			case "GITLAB_SELF_HOST":
+			case vcsPlugin.GitLabSelfHost:
 				webhookPut := gitlab.WebhookPut{
 					URL:                    fmt.Sprintf("%s:%d/%s/%s", s.host, s.port, gitLabWebhookPath, updatedRepository.WebhookEndpointID),
-					PushEventsBranchFilter: *repositoryPatch.BranchFilter,
+					MergeRequestsEvents:    true,
+					TagPushEvents:          true,
+					PushEventsBranchFilter: webhookBranchFilter(*repositoryPatch.BranchFilter),
 				}
 				webhookPatchPayload, err = json.Marshal(webhookPut)
 				if err != nil {
 					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal put request for updating webhook %s for project ID: %v", repository.ExternalWebhookID, projectID)).SetInternal(err)
 				}
+			case vcsPlugin.GitHub:
+				// GitHub doesn't filter the push events by branch, the branch filter is checked when receiving the push event.
+				// The config is replaced as a whole, so we need to send the secret as well.
+				webhookPatch := github.WebhookCreateOrUpdate{
+					Config: github.WebhookConfig{
+						URL:         fmt.Sprintf("%s:%d/%s/%s", s.host, s.port, gitHubWebhookPath, updatedRepository.WebhookEndpointID),
+						ContentType: "json",
+						Secret:      updatedRepository.WebhookSecretToken,
+						InsecureSSL: "1",
+					},
+					Events: []string{string(github.WebhookPush), string(github.WebhookPullRequest)},
+					Active: true,
+				}
+				webhookPatchPayload, err = json.Marshal(webhookPatch)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal patch request for updating webhook %s for project ID: %v", repository.ExternalWebhookID, projectID)).SetInternal(err)
+				}
+			case vcsPlugin.Gitea:
+				// The config is replaced as a whole, so we need to send the secret as well.
+				webhookPatch := gitea.WebhookPatch{
+					Config: gitea.WebhookConfig{
+						URL:         fmt.Sprintf("%s:%d/%s/%s", s.host, s.port, giteaWebhookPath, updatedRepository.WebhookEndpointID),
+						ContentType: "json",
+						Secret:      updatedRepository.WebhookSecretToken,
+					},
+					Events:       []string{string(gitea.WebhookPush), string(gitea.WebhookPullRequest)},
+					BranchFilter: webhookBranchFilter(*repositoryPatch.BranchFilter),
+				}
+				webhookPatchPayload, err = json.Marshal(webhookPatch)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal patch request for updating webhook %s for project ID: %v", repository.ExternalWebhookID, projectID)).SetInternal(err)
+				}
 			}
 
 			err = vcsPlugin.Get(vcs.Type, vcsPlugin.ProviderConfig{Logger: s.l}).PatchWebhook(
@@ -640,3 +744,46 @@ func (s *Server) refreshTokenNoop() common.TokenRefresher {
 		return nil
 	}
 }
+
+// validateBranchFilter validates the branch filter rules of the repository.
+// Only GitLab sends the tag push events to deploy from, so the tag rules aren't supported by other VCS for now.
+func validateBranchFilter(vcsType vcsPlugin.Type, branchFilter string) error {
+	ruleList, err := api.ParseBranchFilter(branchFilter)
+	if err != nil {
+		return err
+	}
+	if vcsType == vcsPlugin.GitLabSelfHost {
+		return nil
+	}
+	for _, rule := range ruleList {
+		if strings.HasPrefix(rule.RefPattern, "refs/tags/") {
+			return fmt.Errorf("tag ref pattern %q isn't supported for %s yet", rule.RefPattern, vcsType)
+		}
+	}
+	return nil
+}
+
+// webhookBranchFilter returns the branch pattern for the VCS to filter the push events, which only supports a single branch pattern.
+// Returns empty to receive the push events of all branches if there are multiple rules, the rules are checked on receiving the push event anyway.
+func webhookBranchFilter(branchFilter string) string {
+	ruleList, err := api.ParseBranchFilter(branchFilter)
+	if err != nil || len(ruleList) != 1 || strings.HasPrefix(ruleList[0].RefPattern, "refs/") {
+		return ""
+	}
+	return ruleList[0].RefPattern
+}
+
+// validateRepositorySchemaPathTemplateDatabase validates the project databases support the declarative schema migration,
+// since the pushed schema files matching the schema path template are applied as the declarative schema migration.
+func (s *Server) validateRepositorySchemaPathTemplateDatabase(ctx context.Context, projectID int) error {
+	databaseList, err := s.composeDatabaseListByFind(ctx, &api.DatabaseFind{
+		ProjectID: &projectID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find the databases of project ID: %v", projectID)).SetInternal(err)
+	}
+	if err := validateDeclarativeSchemaDatabaseList(databaseList); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid schema path template, the pushed schema files are applied as declarative schema migration: %s", err.Error()))
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) registerQueryGrantRoutes(g *echo.Group) {
+	g.GET("/database/:id/querygrant", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+
+		// Only the effective grants are returned.
+		now := time.Now().Unix()
+		grantFind := &api.QueryGrantFind{
+			DatabaseID:    &id,
+			ExpireTsAfter: &now,
+		}
+		grantList, err := s.QueryGrantService.FindQueryGrantList(ctx, grantFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch query grant list for database ID: %d", id)).SetInternal(err)
+		}
+
+		for _, grant := range grantList {
+			if err := s.composeQueryGrantRelationship(ctx, grant); err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to compose query grant relationship").SetInternal(err)
+			}
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, grantList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to marshal fetch query grant list response: %v", id)).SetInternal(err)
+		}
+		return nil
+	})
+
+	g.DELETE("/database/:id/querygrant/:grantID", func(c echo.Context) error {
+		ctx := context.Background()
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("id"))).SetInternal(err)
+		}
+		grantID, err := strconv.Atoi(c.Param("grantID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Grant ID is not a number: %s", c.Param("grantID"))).SetInternal(err)
+		}
+
+		grant, err := s.QueryGrantService.FindQueryGrant(ctx, &api.QueryGrantFind{
+			ID:         &grantID,
+			DatabaseID: &id,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch query grant ID: %v", grantID)).SetInternal(err)
+		}
+		if grant == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Query grant ID not found: %d", grantID))
+		}
+
+		grantDelete := &api.QueryGrantDelete{
+			ID:        grantID,
+			DeleterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := s.QueryGrantService.DeleteQueryGrant(ctx, grantDelete); err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Query grant ID not found: %d", grantID))
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to delete query grant ID: %v", grantID)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+func (s *Server) composeQueryGrantRelationship(ctx context.Context, grant *api.QueryGrant) error {
+	var err error
+
+	grant.Creator, err = s.composePrincipalByID(ctx, grant.CreatorID)
+	if err != nil {
+		return err
+	}
+
+	grant.Updater, err = s.composePrincipalByID(ctx, grant.UpdaterID)
+	if err != nil {
+		return err
+	}
+
+	grant.Principal, err = s.composePrincipalByID(ctx, grant.PrincipalID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateQueryPermission validates the principal can query the databases of the instance accessed by the readonly statements.
+// The workspace owners and DBAs can query all the databases. The other members can query the databases of the projects
+// they are members of, or the databases granted to them by the resolved data source request issues until the grants expire.
+// The returned error is an *echo.HTTPError.
+func (s *Server) validateQueryPermission(ctx context.Context, principalID int, role api.Role, instance *api.Instance, databaseName string, statementList []string) error {
+	if role == api.Owner || role == api.DBA {
+		return nil
+	}
+
+	databaseNameMap := make(map[string]bool)
+	var databaseNameList []string
+	for _, statement := range statementList {
+		list, err := parser.ExtractDatabaseList(instance.Engine, statement, databaseName)
+		// The statement may access any database of the instance if the accessed databases can't be extracted,
+		// or if it accesses no table without the current database, e.g. "SHOW DATABASES".
+		if err != nil || len(list) == 0 {
+			databaseList, err := s.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{
+				InstanceID: &instance.ID,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch databases for instance ID: %v", instance.ID)).SetInternal(err)
+			}
+			list = []string{databaseName}
+			for _, database := range databaseList {
+				list = append(list, database.Name)
+			}
+		}
+		for _, name := range list {
+			if name != "" && !databaseNameMap[name] {
+				databaseNameMap[name] = true
+				databaseNameList = append(databaseNameList, name)
+			}
+		}
+	}
+
+	for _, name := range databaseNameList {
+		database, err := s.DatabaseService.FindDatabase(ctx, &api.DatabaseFind{
+			InstanceID: &instance.ID,
+			Name:       &name,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database %q for instance ID: %v", name, instance.ID)).SetInternal(err)
+		}
+		if database == nil {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("Only the Owner and DBA can query the database %q not managed by Bytebase", name))
+		}
+
+		memberList, err := s.ProjectMemberService.FindProjectMemberList(ctx, &api.ProjectMemberFind{
+			ProjectID: &database.ProjectID,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch members for project ID: %v", database.ProjectID)).SetInternal(err)
+		}
+		isProjectMember := false
+		for _, member := range memberList {
+			if member.PrincipalID == principalID {
+				isProjectMember = true
+				break
+			}
+		}
+		if isProjectMember {
+			continue
+		}
+
+		now := time.Now().Unix()
+		grantList, err := s.QueryGrantService.FindQueryGrantList(ctx, &api.QueryGrantFind{
+			DatabaseID:    &database.ID,
+			PrincipalID:   &principalID,
+			ExpireTsAfter: &now,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch query grants for database ID: %v", database.ID)).SetInternal(err)
+		}
+		if len(grantList) == 0 {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("No permission to query database %q, request the query access through an issue first", name))
+		}
+	}
+	return nil
+}
+
+// validateDataSourceRequest validates the issue create of requesting the query access to a database, and persists the create context
+// as the issue payload. The returned error is an *echo.HTTPError.
+func (s *Server) validateDataSourceRequest(ctx context.Context, issueCreate *api.IssueCreate) error {
+	if len(issueCreate.Pipeline.StageList) > 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, data source request has no pipeline stage")
+	}
+	requestContext := &api.DataSourceRequestContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), requestContext); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Malformatted data source request create context").SetInternal(err)
+	}
+	if requestContext.ExpireTs <= time.Now().Unix() {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, the query access must expire in the future")
+	}
+
+	database, err := s.DatabaseService.FindDatabase(ctx, &api.DatabaseFind{
+		ID: &requestContext.DatabaseID,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", requestContext.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database ID not found: %d", requestContext.DatabaseID))
+	}
+	if database.ProjectID != issueCreate.ProjectID {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database %q doesn't belong to project ID %d", database.Name, issueCreate.ProjectID))
+	}
+
+	// The request is approved by the assignee resolving the issue.
+	isAdmin, err := s.isWorkspaceAdmin(ctx, issueCreate.AssigneeID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the role of assignee ID: %v", issueCreate.AssigneeID)).SetInternal(err)
+	}
+	if !isAdmin {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to create issue, the assignee of data source request must be an Owner or DBA")
+	}
+
+	payload, err := json.Marshal(requestContext)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal data source request payload").SetInternal(err)
+	}
+	issueCreate.Payload = string(payload)
+	return nil
+}
+
+// validateDataSourceRequestApproval validates the data source request issue can be resolved by the updater,
+// who must be the assignee and a workspace owner or DBA.
+func (s *Server) validateDataSourceRequestApproval(ctx context.Context, issue *api.Issue, updaterID int) (*api.DataSourceRequestContext, error) {
+	requestContext := &api.DataSourceRequestContext{}
+	if err := json.Unmarshal([]byte(issue.Payload), requestContext); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data source request payload of issue %d, error: %w", issue.ID, err)
+	}
+	if updaterID != issue.AssigneeID {
+		return nil, &common.Error{Code: common.NotAuthorized, Err: fmt.Errorf("only the assignee can resolve the data source request issue %d", issue.ID)}
+	}
+	isAdmin, err := s.isWorkspaceAdmin(ctx, updaterID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdmin {
+		return nil, &common.Error{Code: common.NotAuthorized, Err: fmt.Errorf("only the Owner or DBA can resolve the data source request issue %d", issue.ID)}
+	}
+	if requestContext.ExpireTs <= time.Now().Unix() {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("the requested query access of issue %d has expired", issue.ID)}
+	}
+	return requestContext, nil
+}
+
+// isWorkspaceAdmin returns whether the principal is a workspace owner or DBA. Everyone is the owner if RBAC is not enabled.
+func (s *Server) isWorkspaceAdmin(ctx context.Context, principalID int) (bool, error) {
+	if !s.feature(api.FeatureRBAC) {
+		return true, nil
+	}
+	member, err := s.MemberService.FindMember(ctx, &api.MemberFind{
+		PrincipalID: &principalID,
+	})
+	if err != nil {
+		return false, err
+	}
+	if member == nil || member.RowStatus == api.Archived {
+		return false, nil
+	}
+	return member.Role == api.Owner || member.Role == api.DBA, nil
+}
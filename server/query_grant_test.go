@@ -0,0 +1,289 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	enterprise "github.com/bytebase/bytebase/enterprise/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// fakeProjectMemberService has the members of the projects keyed by the project ID.
+type fakeProjectMemberService struct {
+	api.ProjectMemberService
+	memberMap map[int][]*api.ProjectMember
+}
+
+func (s *fakeProjectMemberService) FindProjectMemberList(_ context.Context, find *api.ProjectMemberFind) ([]*api.ProjectMember, error) {
+	return s.memberMap[*find.ProjectID], nil
+}
+
+// fakeQueryGrantService has the grants in the grant list.
+type fakeQueryGrantService struct {
+	api.QueryGrantService
+	grantList []*api.QueryGrant
+}
+
+func (s *fakeQueryGrantService) FindQueryGrantList(_ context.Context, find *api.QueryGrantFind) ([]*api.QueryGrant, error) {
+	var grantList []*api.QueryGrant
+	for _, grant := range s.grantList {
+		if find.DatabaseID != nil && *find.DatabaseID != grant.DatabaseID {
+			continue
+		}
+		if find.PrincipalID != nil && *find.PrincipalID != grant.PrincipalID {
+			continue
+		}
+		if find.ExpireTsAfter != nil && grant.ExpireTs <= *find.ExpireTsAfter {
+			continue
+		}
+		grantList = append(grantList, grant)
+	}
+	return grantList, nil
+}
+
+// fakeMemberService has the workspace members keyed by the principal ID.
+type fakeMemberService struct {
+	api.MemberService
+	memberMap map[int]*api.Member
+}
+
+func (s *fakeMemberService) FindMember(_ context.Context, find *api.MemberFind) (*api.Member, error) {
+	return s.memberMap[*find.PrincipalID], nil
+}
+
+func TestValidateQueryPermission(t *testing.T) {
+	const (
+		projectMemberID = 101
+		grantedID       = 102
+		otherID         = 103
+	)
+	s := &Server{
+		l: zap.NewNop(),
+		DatabaseService: &fakeDatabaseService{
+			databaseList: []*api.Database{
+				{ID: 1, InstanceID: 1, ProjectID: 1, Name: "shop"},
+				{ID: 2, InstanceID: 1, ProjectID: 2, Name: "hr"},
+				{ID: 3, InstanceID: 2, ProjectID: 1, Name: "shop"},
+				{ID: 4, InstanceID: 2, ProjectID: 2, Name: "hr"},
+			},
+		},
+		ProjectMemberService: &fakeProjectMemberService{
+			memberMap: map[int][]*api.ProjectMember{
+				1: {{ProjectID: 1, PrincipalID: projectMemberID}},
+			},
+		},
+		QueryGrantService: &fakeQueryGrantService{
+			grantList: []*api.QueryGrant{
+				{DatabaseID: 1, PrincipalID: grantedID, ExpireTs: time.Now().Add(time.Hour).Unix()},
+				{DatabaseID: 2, PrincipalID: grantedID, ExpireTs: time.Now().Add(-time.Hour).Unix()},
+				{DatabaseID: 3, PrincipalID: grantedID, ExpireTs: time.Now().Add(time.Hour).Unix()},
+			},
+		},
+	}
+	mysql := &api.Instance{ID: 1, Engine: db.MySQL}
+	clickHouse := &api.Instance{ID: 2, Engine: db.ClickHouse}
+	tests := []struct {
+		name         string
+		principalID  int
+		role         api.Role
+		instance     *api.Instance
+		databaseName string
+		statement    string
+		// wantCode is the HTTP status code of the error, or 0 if the query is permitted.
+		wantCode int
+	}{
+		{
+			name:         "DBA",
+			principalID:  otherID,
+			role:         api.DBA,
+			instance:     mysql,
+			databaseName: "hr",
+			statement:    "SELECT * FROM salary",
+		},
+		{
+			name:         "project member",
+			principalID:  projectMemberID,
+			role:         api.Developer,
+			instance:     mysql,
+			databaseName: "shop",
+			statement:    "SELECT * FROM t",
+		},
+		{
+			name:         "project member querying database of another project",
+			principalID:  projectMemberID,
+			role:         api.Developer,
+			instance:     mysql,
+			databaseName: "shop",
+			statement:    "SELECT * FROM t JOIN hr.salary s ON t.id = s.id",
+			wantCode:     http.StatusForbidden,
+		},
+		{
+			name:         "granted",
+			principalID:  grantedID,
+			role:         api.Developer,
+			instance:     mysql,
+			databaseName: "shop",
+			statement:    "SELECT * FROM t",
+		},
+		{
+			name:         "grant expired",
+			principalID:  grantedID,
+			role:         api.Developer,
+			instance:     mysql,
+			databaseName: "hr",
+			statement:    "SELECT * FROM salary",
+			wantCode:     http.StatusForbidden,
+		},
+		{
+			name:         "statement referencing no table",
+			principalID:  otherID,
+			role:         api.Developer,
+			instance:     mysql,
+			databaseName: "hr",
+			statement:    "SELECT @@version",
+			wantCode:     http.StatusForbidden,
+		},
+		{
+			name:         "statement referencing no table without current database",
+			principalID:  projectMemberID,
+			role:         api.Developer,
+			instance:     mysql,
+			databaseName: "",
+			statement:    "SHOW DATABASES",
+			wantCode:     http.StatusForbidden,
+		},
+		{
+			name:         "database not managed",
+			principalID:  projectMemberID,
+			role:         api.Developer,
+			instance:     mysql,
+			databaseName: "shop",
+			statement:    "SELECT * FROM mysql.user",
+			wantCode:     http.StatusForbidden,
+		},
+		{
+			// Only the referenced databases are checked instead of all the databases of the instance.
+			name:         "ClickHouse granted",
+			principalID:  grantedID,
+			role:         api.Developer,
+			instance:     clickHouse,
+			databaseName: "shop",
+			statement:    "SELECT * FROM t",
+		},
+		{
+			name:         "ClickHouse querying database of another project",
+			principalID:  grantedID,
+			role:         api.Developer,
+			instance:     clickHouse,
+			databaseName: "shop",
+			statement:    "SELECT * FROM t JOIN hr.salary s ON t.id = s.id",
+			wantCode:     http.StatusForbidden,
+		},
+		{
+			// The accessed databases of SHOW can't be extracted, so all the databases of the instance are checked.
+			name:         "ClickHouse SHOW",
+			principalID:  grantedID,
+			role:         api.Developer,
+			instance:     clickHouse,
+			databaseName: "shop",
+			statement:    "SHOW TABLES FROM shop",
+			wantCode:     http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		err := s.validateQueryPermission(context.Background(), test.principalID, test.role, test.instance, test.databaseName, []string{test.statement})
+		code := 0
+		if err != nil {
+			httpErr, ok := err.(*echo.HTTPError)
+			if !ok {
+				t.Errorf("%s: validateQueryPermission() got error %v, want *echo.HTTPError", test.name, err)
+				continue
+			}
+			code = httpErr.Code
+		}
+		if code != test.wantCode {
+			t.Errorf("%s: validateQueryPermission() got error %v, want status code %d", test.name, err, test.wantCode)
+		}
+	}
+}
+
+func TestValidateDataSourceRequestApproval(t *testing.T) {
+	const (
+		dbaID       = 101
+		developerID = 102
+	)
+	s := &Server{
+		l:            zap.NewNop(),
+		subscription: &enterprise.Subscription{Plan: api.TEAM, ExpiresTs: time.Now().AddDate(1, 0, 0).Unix()},
+		MemberService: &fakeMemberService{
+			memberMap: map[int]*api.Member{
+				dbaID:       {PrincipalID: dbaID, Role: api.DBA, RowStatus: api.Normal},
+				developerID: {PrincipalID: developerID, Role: api.Developer, RowStatus: api.Normal},
+			},
+		},
+	}
+	newIssue := func(assigneeID int, expireTs int64) *api.Issue {
+		payload, err := json.Marshal(&api.DataSourceRequestContext{DatabaseID: 1, ExpireTs: expireTs})
+		if err != nil {
+			t.Fatalf("failed to marshal data source request payload: %v", err)
+		}
+		return &api.Issue{ID: 1, AssigneeID: assigneeID, Payload: string(payload)}
+	}
+	future := time.Now().Add(time.Hour).Unix()
+	tests := []struct {
+		name      string
+		issue     *api.Issue
+		updaterID int
+		// wantCode is the error code, or 0 if the approval is valid.
+		wantCode common.Code
+	}{
+		{
+			name:      "DBA assignee",
+			issue:     newIssue(dbaID, future),
+			updaterID: dbaID,
+		},
+		{
+			name:      "not assignee",
+			issue:     newIssue(dbaID, future),
+			updaterID: developerID,
+			wantCode:  common.NotAuthorized,
+		},
+		{
+			name:      "Developer assignee",
+			issue:     newIssue(developerID, future),
+			updaterID: developerID,
+			wantCode:  common.NotAuthorized,
+		},
+		{
+			name:      "expired",
+			issue:     newIssue(dbaID, time.Now().Add(-time.Hour).Unix()),
+			updaterID: dbaID,
+			wantCode:  common.Conflict,
+		},
+	}
+
+	for _, test := range tests {
+		requestContext, err := s.validateDataSourceRequestApproval(context.Background(), test.issue, test.updaterID)
+		if test.wantCode == 0 {
+			if err != nil {
+				t.Errorf("%s: validateDataSourceRequestApproval() got error: %v", test.name, err)
+				continue
+			}
+			if requestContext.DatabaseID != 1 {
+				t.Errorf("%s: validateDataSourceRequestApproval() got database ID %d, want 1", test.name, requestContext.DatabaseID)
+			}
+			continue
+		}
+		if common.ErrorCode(err) != test.wantCode {
+			t.Errorf("%s: validateDataSourceRequestApproval() got error %v, want code %v", test.name, err, test.wantCode)
+		}
+	}
+}
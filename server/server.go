@@ -35,6 +35,8 @@ type Server struct {
 
 	ActivityManager *ActivityManager
 
+	executionRegistry *executionRegistry
+
 	CacheService api.CacheService
 
 	SettingService          api.SettingService
@@ -70,6 +72,7 @@ type Server struct {
 	DeploymentConfigService api.DeploymentConfigService
 	LicenseService          enterprise.LicenseService
 	SheetService            api.SheetService
+	QueryGrantService       api.QueryGrantService
 
 	e *echo.Echo
 
@@ -116,21 +119,22 @@ func NewServer(logger *zap.Logger, loggerLevel *zap.AtomicLevel, version string,
 	embedFrontend(logger, e)
 
 	s := &Server{
-		l:            logger,
-		lvl:          loggerLevel,
-		CacheService: NewCacheService(logger),
-		e:            e,
-		version:      version,
-		mode:         mode,
-		host:         host,
-		port:         port,
-		frontendHost: frontendHost,
-		frontendPort: frontendPort,
-		startedTs:    time.Now().Unix(),
-		secret:       secret,
-		readonly:     readonly,
-		demo:         demo,
-		dataDir:      dataDir,
+		l:                 logger,
+		lvl:               loggerLevel,
+		CacheService:      NewCacheService(logger),
+		executionRegistry: newExecutionRegistry(),
+		e:                 e,
+		version:           version,
+		mode:              mode,
+		host:              host,
+		port:              port,
+		frontendHost:      frontendHost,
+		frontendPort:      frontendPort,
+		startedTs:         time.Now().Unix(),
+		secret:            secret,
+		readonly:          readonly,
+		demo:              demo,
+		dataDir:           dataDir,
 	}
 
 	if !readonly {
@@ -164,6 +168,7 @@ func NewServer(logger *zap.Logger, loggerLevel *zap.AtomicLevel, version string,
 		taskCheckScheduler.Register(string(api.TaskCheckDatabaseStatementFakeAdvise), statementExecutor)
 		taskCheckScheduler.Register(string(api.TaskCheckDatabaseStatementSyntax), statementExecutor)
 		taskCheckScheduler.Register(string(api.TaskCheckDatabaseStatementCompatibility), statementExecutor)
+		taskCheckScheduler.Register(string(api.TaskCheckDatabaseStatementSQLReview), statementExecutor)
 
 		databaseConnectExecutor := NewTaskCheckDatabaseConnectExecutor(logger)
 		taskCheckScheduler.Register(string(api.TaskCheckDatabaseConnect), databaseConnectExecutor)
@@ -235,6 +240,7 @@ func NewServer(logger *zap.Logger, loggerLevel *zap.AtomicLevel, version string,
 	s.registerEnvironmentRoutes(apiGroup)
 	s.registerInstanceRoutes(apiGroup)
 	s.registerDatabaseRoutes(apiGroup)
+	s.registerQueryGrantRoutes(apiGroup)
 	s.registerIssueRoutes(apiGroup)
 	s.registerIssueSubscriberRoutes(apiGroup)
 	s.registerTaskRoutes(apiGroup)
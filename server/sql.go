@@ -4,19 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
-	"regexp"
-	"strings"
 	"time"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/differ"
+	"github.com/bytebase/bytebase/plugin/parser"
 	"github.com/google/jsonapi"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 )
 
+const (
+	// sqlResultStreamBatchSize is the maximum row count of a batch in the streaming SQL result.
+	sqlResultStreamBatchSize = 100
+	// sqlExportMaxRowCount is the maximum row count of the exported SQL results.
+	sqlExportMaxRowCount = 100000
+)
+
 func (s *Server) registerSQLRoutes(g *echo.Group) {
 	g.POST("/sql/ping", func(c echo.Context) error {
 		ctx := context.Background()
@@ -96,103 +104,150 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		return nil
 	})
 
-	g.POST("/sql/execute", func(c echo.Context) error {
+	g.POST("/sql/schemadiff", func(c echo.Context) error {
 		ctx := context.Background()
-		exec := &api.SQLExecute{}
-		if err := jsonapi.UnmarshalPayload(c.Request().Body, exec); err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request").SetInternal(err)
+		schemaDiff := &api.SQLSchemaDiff{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, schemaDiff); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql schema diff request").SetInternal(err)
 		}
 
-		if exec.InstanceID == 0 {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, missing instanceId")
+		sourceDatabase, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: &schemaDiff.SourceDatabaseID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", schemaDiff.SourceDatabaseID)).SetInternal(err)
 		}
-		if len(exec.Statement) == 0 {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, missing sql statement")
+		if sourceDatabase == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", schemaDiff.SourceDatabaseID))
 		}
-		if !exec.Readonly {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, only support readonly sql statement")
+		targetDatabase, err := s.composeDatabaseByFind(ctx, &api.DatabaseFind{ID: &schemaDiff.TargetDatabaseID})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", schemaDiff.TargetDatabaseID)).SetInternal(err)
 		}
-		if !validateSQLSelectStatement(exec.Statement) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, only support SELECT sql statement")
+		if targetDatabase == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", schemaDiff.TargetDatabaseID))
 		}
-
-		instance, err := s.composeInstanceByID(ctx, exec.InstanceID)
-		if err != nil {
-			if common.ErrorCode(err) == common.NotFound {
-				return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exec.InstanceID))
-			}
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", exec.InstanceID)).SetInternal(err)
+		if sourceDatabase.Instance.Engine != targetDatabase.Instance.Engine {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Cannot diff the schema of %s database %q and %s database %q", sourceDatabase.Instance.Engine, sourceDatabase.Name, targetDatabase.Instance.Engine, targetDatabase.Name))
 		}
 
-		start := time.Now().UnixNano()
-
-		bytes, err := func() ([]byte, error) {
-			driver, err := getDatabaseDriver(ctx, instance, exec.DatabaseName, s.l)
+		result := &api.SQLSchemaDiffResult{}
+		statement, err := func() (string, error) {
+			sourceSchema, err := getDatabaseSchema(ctx, sourceDatabase.Instance, sourceDatabase.Name, s.l)
 			if err != nil {
-				return nil, err
+				return "", err
 			}
-			defer driver.Close(ctx)
-
-			rowSet, err := driver.Query(ctx, exec.Statement, exec.Limit)
+			targetSchema, err := getDatabaseSchema(ctx, targetDatabase.Instance, targetDatabase.Name, s.l)
 			if err != nil {
-				return nil, err
+				return "", err
 			}
-
-			return json.Marshal(rowSet)
+			return differ.SchemaDiff(sourceDatabase.Instance.Engine, sourceSchema, targetSchema)
 		}()
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Statement = statement
+		}
 
-		{
-			errMessage := ""
-			activityLevel := api.ActivityInfo
-			if err != nil {
-				errMessage = err.Error()
-				activityLevel = api.ActivityError
-			}
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, result); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql schema diff response").SetInternal(err)
+		}
+		return nil
+	})
 
-			bytes, err := json.Marshal(api.ActivitySQLEditorQueryPayload{
-				Statement:    exec.Statement,
-				DurationNs:   time.Now().UnixNano() - start,
-				InstanceName: instance.Name,
-				DatabaseName: exec.DatabaseName,
-				Error:        errMessage,
-			})
+	g.POST("/sql/execute", func(c echo.Context) error {
+		// Use the request context so the query is canceled once the client disconnects.
+		ctx := c.Request().Context()
+		exec := &api.SQLExecute{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, exec); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request").SetInternal(err)
+		}
+
+		instance, statementList, err := s.validateSQLExecute(ctx, exec)
+		if err != nil {
+			return err
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if err := s.validateQueryPermission(ctx, principalID, c.Get(getRoleContextKey()).(api.Role), instance, exec.DatabaseName, statementList); err != nil {
+			return err
+		}
+		maskingRuleList, err := s.getMaskingRuleList(ctx, instance)
+		if err != nil {
+			return err
+		}
+
+		queryCtx, finishQuery, err := s.beginSQLEditorQuery(ctx, principalID, exec.QueryID, instance)
+		if err != nil {
+			return err
+		}
+		defer finishQuery()
 
+		start := time.Now().UnixNano()
+
+		var dataSourceType api.DataSourceType
+		resultList := []*api.SingleSQLResult{}
+		err = func() error {
+			driver, driverDataSourceType, err := s.getReadOnlyDatabaseDriver(queryCtx, instance, exec.DatabaseName)
 			if err != nil {
-				s.l.Warn("Failed to marshal activity after executing sql statement",
-					zap.String("database_name", exec.DatabaseName),
-					zap.String("instance_name", instance.Name),
-					zap.String("statement", exec.Statement),
-					zap.Error(err))
-				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+				return err
 			}
+			dataSourceType = driverDataSourceType
+			defer driver.Close(queryCtx)
 
-			activityCreate := &api.ActivityCreate{
-				CreatorID:   c.Get(getPrincipalIDContextKey()).(int),
-				Type:        api.ActivitySQLEditorQuery,
-				ContainerID: exec.InstanceID,
-				Level:       activityLevel,
-				Comment: fmt.Sprintf("Executed `%q` in database %q of instance %q.",
-					exec.Statement, exec.DatabaseName, instance.Name),
-				Payload: string(bytes),
+			// The statements are read-only, so the following statements are still executed if one fails.
+			for _, statement := range statementList {
+				statementStart := time.Now().UnixNano()
+				var rowSet []interface{}
+				masker, err := s.newSQLResultMasker(queryCtx, instance, exec.DatabaseName, statement, maskingRuleList)
+				if err == nil {
+					rowSet, err = queryWithMasker(queryCtx, driver, statement, exec.Limit, masker)
+				}
+				result := &api.SingleSQLResult{
+					Statement:  statement,
+					Data:       rowSet,
+					RowCount:   len(rowSet),
+					DurationNs: time.Now().UnixNano() - statementStart,
+				}
+				if err != nil {
+					result.Error = formatQueryError(queryCtx, err).Error()
+				}
+				resultList = append(resultList, result)
 			}
+			return nil
+		}()
+		if err != nil {
+			err = formatQueryError(queryCtx, err)
+		}
 
-			_, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{})
-
-			if err != nil {
-				s.l.Warn("Failed to create activity after executing sql statement",
-					zap.String("database_name", exec.DatabaseName),
-					zap.String("instance_name", instance.Name),
-					zap.String("statement", exec.Statement),
-					zap.Error(err))
+		// Record the error of the first failed statement in the activity.
+		activityErr := err
+		for i, result := range resultList {
+			if activityErr == nil && result.Error != "" {
+				activityErr = fmt.Errorf("statement #%d failed: %s", i+1, result.Error)
 			}
 		}
+		if err := s.createSQLEditorQueryActivity(context.Background(), principalID, exec, instance, dataSourceType, time.Now().UnixNano()-start, activityErr); err != nil {
+			return err
+		}
 
 		resultSet := &api.SQLResultSet{}
 		if err == nil {
-			resultSet.Data = string(bytes)
+			bytes, err := json.Marshal(resultList)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result list").SetInternal(err)
+			}
+			resultSet.ResultList = string(bytes)
+			lastResult := resultList[len(resultList)-1]
+			if lastResult.Error == "" {
+				bytes, err := json.Marshal(lastResult.Data)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set").SetInternal(err)
+				}
+				resultSet.Data = string(bytes)
+			}
+			resultSet.Error = lastResult.Error
 			s.l.Debug("Query result",
 				zap.String("statement", exec.Statement),
-				zap.String("data", resultSet.Data),
+				zap.String("result", resultSet.ResultList),
 			)
 		} else {
 			resultSet.Error = err.Error()
@@ -215,6 +270,479 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		}
 		return nil
 	})
+
+	g.POST("/sql/explain", func(c echo.Context) error {
+		// Use the request context so the EXPLAIN is canceled once the client disconnects.
+		ctx := c.Request().Context()
+		exec := &api.SQLExecute{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, exec); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql explain request").SetInternal(err)
+		}
+
+		instance, err := s.validateSingleSQLExecute(ctx, exec)
+		if err != nil {
+			return err
+		}
+		if !instance.Capability.Explain {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Explaining the query plan is not supported for %s", instance.Engine))
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if err := s.validateQueryPermission(ctx, principalID, c.Get(getRoleContextKey()).(api.Role), instance, exec.DatabaseName, []string{exec.Statement}); err != nil {
+			return err
+		}
+
+		queryCtx, finishQuery, err := s.beginSQLEditorQuery(ctx, principalID, exec.QueryID, instance)
+		if err != nil {
+			return err
+		}
+		defer finishQuery()
+
+		bytes, err := func() ([]byte, error) {
+			driver, _, err := s.getReadOnlyDatabaseDriver(queryCtx, instance, exec.DatabaseName)
+			if err != nil {
+				return nil, err
+			}
+			defer driver.Close(queryCtx)
+
+			plan, err := driver.Explain(queryCtx, exec.Statement)
+			if err != nil {
+				return nil, err
+			}
+
+			return json.Marshal(plan)
+		}()
+
+		result := &api.SQLExplainResult{}
+		if err == nil {
+			result.Plan = string(bytes)
+		} else {
+			result.Error = formatQueryError(queryCtx, err).Error()
+			s.l.Debug("Failed to explain query",
+				zap.Error(err),
+				zap.String("statement", exec.Statement),
+			)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, result); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql explain response").SetInternal(err)
+		}
+		return nil
+	})
+
+	// Unlike /sql/execute, the result is streamed in row batches as newline delimited JSON without holding all the rows in memory.
+	g.POST("/sql/execute/stream", func(c echo.Context) error {
+		// Use the request context so the query is canceled once the client disconnects.
+		ctx := c.Request().Context()
+		exec := &api.SQLExecute{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, exec); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request").SetInternal(err)
+		}
+
+		instance, err := s.validateSingleSQLExecute(ctx, exec)
+		if err != nil {
+			return err
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if err := s.validateQueryPermission(ctx, principalID, c.Get(getRoleContextKey()).(api.Role), instance, exec.DatabaseName, []string{exec.Statement}); err != nil {
+			return err
+		}
+		maskingRuleList, err := s.getMaskingRuleList(ctx, instance)
+		if err != nil {
+			return err
+		}
+
+		queryCtx, finishQuery, err := s.beginSQLEditorQuery(ctx, principalID, exec.QueryID, instance)
+		if err != nil {
+			return err
+		}
+		defer finishQuery()
+
+		start := time.Now().UnixNano()
+		// The cursor is opened before the response starts, so the failure to connect or to run the query is returned with the error status.
+		driver, dataSourceType, err := s.getReadOnlyDatabaseDriver(queryCtx, instance, exec.DatabaseName)
+		if err != nil {
+			err = formatQueryError(queryCtx, err)
+			_ = s.createSQLEditorQueryActivity(context.Background(), principalID, exec, instance, dataSourceType, time.Now().UnixNano()-start, err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to execute query: %s", err.Error())).SetInternal(err)
+		}
+		defer driver.Close(queryCtx)
+		cursor, err := func() (db.QueryCursor, error) {
+			masker, err := s.newSQLResultMasker(queryCtx, instance, exec.DatabaseName, exec.Statement, maskingRuleList)
+			if err != nil {
+				return nil, err
+			}
+			cursor, err := driver.OpenCursor(queryCtx, exec.Statement, exec.Limit)
+			if err != nil {
+				return nil, err
+			}
+			return masker.wrapCursor(cursor)
+		}()
+		if err != nil {
+			err = formatQueryError(queryCtx, err)
+			_ = s.createSQLEditorQueryActivity(context.Background(), principalID, exec, instance, dataSourceType, time.Now().UnixNano()-start, err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to execute query: %s", err.Error())).SetInternal(err)
+		}
+		defer cursor.Close()
+
+		c.Response().Header().Set(echo.HeaderContentType, "application/x-ndjson")
+		c.Response().WriteHeader(http.StatusOK)
+		encoder := json.NewEncoder(c.Response())
+		writeMessage := func(message *api.SQLResultStreamMessage) error {
+			if err := encoder.Encode(message); err != nil {
+				return err
+			}
+			c.Response().Flush()
+			return nil
+		}
+
+		rowCount, err := func() (int, error) {
+			if err := writeMessage(&api.SQLResultStreamMessage{ColumnList: cursor.Columns()}); err != nil {
+				return 0, err
+			}
+			rowCount := 0
+			for {
+				rowList, err := cursor.Next(sqlResultStreamBatchSize)
+				if err == io.EOF {
+					return rowCount, nil
+				}
+				if err != nil {
+					return rowCount, err
+				}
+				if err := writeMessage(&api.SQLResultStreamMessage{RowList: rowList}); err != nil {
+					return rowCount, err
+				}
+				rowCount += len(rowList)
+			}
+		}()
+		if err != nil {
+			err = formatQueryError(queryCtx, err)
+		}
+
+		// The response has started, so failing to create the activity is only logged.
+		_ = s.createSQLEditorQueryActivity(context.Background(), principalID, exec, instance, dataSourceType, time.Now().UnixNano()-start, err)
+
+		lastMessage := &api.SQLResultStreamMessage{
+			Done:     true,
+			RowCount: rowCount,
+		}
+		if err != nil {
+			s.l.Debug("Failed to execute query",
+				zap.Error(err),
+				zap.String("statement", exec.Statement),
+			)
+			lastMessage = &api.SQLResultStreamMessage{
+				Error: err.Error(),
+			}
+		}
+		if err := writeMessage(lastMessage); err != nil {
+			s.l.Debug("Failed to write the last message of sql result stream", zap.Error(err))
+		}
+		return nil
+	})
+
+	g.POST("/sql/export", func(c echo.Context) error {
+		// Use the request context so the query is canceled once the client disconnects.
+		ctx := c.Request().Context()
+		export := &api.SQLExport{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, export); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql export request").SetInternal(err)
+		}
+
+		instance, err := s.validateSingleSQLExecute(ctx, &api.SQLExecute{
+			InstanceID:   export.InstanceID,
+			DatabaseName: export.DatabaseName,
+			Statement:    export.Statement,
+			Readonly:     true,
+		})
+		if err != nil {
+			return err
+		}
+		extension := getSQLExportFileExtension(export.Format)
+		if extension == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted sql export request, invalid format %q", export.Format))
+		}
+		limit := export.Limit
+		if limit <= 0 || limit > sqlExportMaxRowCount {
+			limit = sqlExportMaxRowCount
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		if err := s.validateQueryPermission(ctx, principalID, c.Get(getRoleContextKey()).(api.Role), instance, export.DatabaseName, []string{export.Statement}); err != nil {
+			return err
+		}
+		maskingRuleList, err := s.getMaskingRuleList(ctx, instance)
+		if err != nil {
+			return err
+		}
+
+		queryCtx, finishQuery, err := s.beginSQLEditorQuery(ctx, principalID, export.QueryID, instance)
+		if err != nil {
+			return err
+		}
+		defer finishQuery()
+
+		start := time.Now().UnixNano()
+		driver, dataSourceType, err := s.getReadOnlyDatabaseDriver(queryCtx, instance, export.DatabaseName)
+		if err != nil {
+			err = formatQueryError(queryCtx, err)
+			s.createSQLEditorExportActivity(context.Background(), principalID, export, instance, dataSourceType, time.Now().UnixNano()-start, 0, err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to export query results: %s", err.Error())).SetInternal(err)
+		}
+		defer driver.Close(queryCtx)
+		cursor, err := func() (db.QueryCursor, error) {
+			masker, err := s.newSQLResultMasker(queryCtx, instance, export.DatabaseName, export.Statement, maskingRuleList)
+			if err != nil {
+				return nil, err
+			}
+			cursor, err := driver.OpenCursor(queryCtx, export.Statement, limit)
+			if err != nil {
+				return nil, err
+			}
+			return masker.wrapCursor(cursor)
+		}()
+		if err != nil {
+			err = formatQueryError(queryCtx, err)
+			s.createSQLEditorExportActivity(context.Background(), principalID, export, instance, dataSourceType, time.Now().UnixNano()-start, 0, err)
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to export query results: %s", err.Error())).SetInternal(err)
+		}
+		defer cursor.Close()
+
+		c.Response().Header().Set(echo.HeaderContentType, getSQLExportContentType(export.Format))
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", "export."+extension))
+		c.Response().WriteHeader(http.StatusOK)
+		rowCount, err := func() (int, error) {
+			exporter, err := newSQLResultExporter(export.Format, c.Response())
+			if err != nil {
+				return 0, err
+			}
+			if err := exporter.WriteColumns(cursor.Columns()); err != nil {
+				return 0, err
+			}
+			rowCount := 0
+			for {
+				rowList, err := cursor.Next(sqlResultStreamBatchSize)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return rowCount, err
+				}
+				if err := exporter.WriteRows(rowList); err != nil {
+					return rowCount, err
+				}
+				c.Response().Flush()
+				rowCount += len(rowList)
+			}
+			return rowCount, exporter.Close()
+		}()
+		if err != nil {
+			err = formatQueryError(queryCtx, err)
+		}
+
+		// The response has started, so the error can only be recorded in the activity.
+		s.createSQLEditorExportActivity(context.Background(), principalID, export, instance, dataSourceType, time.Now().UnixNano()-start, rowCount, err)
+		if err != nil {
+			s.l.Debug("Failed to export query results",
+				zap.Error(err),
+				zap.String("statement", export.Statement),
+			)
+		}
+		return nil
+	})
+
+	g.POST("/sql/cancel", func(c echo.Context) error {
+		sqlCancel := &api.SQLCancel{}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, sqlCancel); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql cancel request").SetInternal(err)
+		}
+		if sqlCancel.QueryID == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql cancel request, missing queryId")
+		}
+
+		// Only the principal running the query can cancel it.
+		if !s.executionRegistry.cancelQuery(c.Get(getPrincipalIDContextKey()).(int), sqlCancel.QueryID) {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Running query not found: %s", sqlCancel.QueryID))
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		c.Response().WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+// beginSQLEditorQuery returns the context to execute the SQL editor query with, which is canceled on the cancel request of the query if the engine supports it,
+// or once the query exceeds the max execution time policy of the instance environment.
+// The returned finish function must be called once the query finishes. The returned error is an *echo.HTTPError.
+func (s *Server) beginSQLEditorQuery(ctx context.Context, principalID int, queryID string, instance *api.Instance) (context.Context, func(), error) {
+	policy, err := s.PolicyService.GetMaxExecutionTimePolicy(ctx, instance.EnvironmentID)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the max execution time policy of environment ID: %v", instance.EnvironmentID)).SetInternal(err)
+	}
+
+	var cancel context.CancelFunc
+	if policy.QuerySeconds > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(policy.QuerySeconds)*time.Second)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	// The query can't be canceled by the request if the engine keeps running the statement once its context is canceled.
+	if queryID == "" || !instance.Capability.Cancel {
+		return ctx, cancel, nil
+	}
+	unregister, err := s.executionRegistry.registerQuery(principalID, queryID, cancel)
+	if err != nil {
+		cancel()
+		return nil, nil, echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return ctx, func() {
+		unregister()
+		cancel()
+	}, nil
+}
+
+// formatQueryError explains the error of the query interrupted by the cancel request or the max execution time.
+func formatQueryError(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("query exceeded the max execution time of the environment: %w", err)
+	case context.Canceled:
+		return fmt.Errorf("query canceled: %w", err)
+	}
+	return err
+}
+
+// validateSQLExecute validates the readonly SQL execute request, and returns the instance to execute the statements
+// and the statements split from the multi-statement script. Each statement must be read-only.
+// The returned error is an *echo.HTTPError.
+func (s *Server) validateSQLExecute(ctx context.Context, exec *api.SQLExecute) (*api.Instance, []string, error) {
+	if exec.InstanceID == 0 {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, missing instanceId")
+	}
+	if len(exec.Statement) == 0 {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, missing sql statement")
+	}
+	if !exec.Readonly {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Malformatted sql execute request, only support readonly sql statement")
+	}
+
+	instance, err := s.composeInstanceByID(ctx, exec.InstanceID)
+	if err != nil {
+		if common.ErrorCode(err) == common.NotFound {
+			return nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exec.InstanceID))
+		}
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %v", exec.InstanceID)).SetInternal(err)
+	}
+
+	statementList, err := validateReadOnlyStatementList(instance.Engine, exec.Statement)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted sql execute request, %s", err.Error())).SetInternal(err)
+	}
+	return instance, statementList, nil
+}
+
+// validateSingleSQLExecute validates the readonly SQL execute request of a single statement, and returns the instance to execute the statement.
+// The returned error is an *echo.HTTPError.
+func (s *Server) validateSingleSQLExecute(ctx context.Context, exec *api.SQLExecute) (*api.Instance, error) {
+	instance, statementList, err := s.validateSQLExecute(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	if len(statementList) != 1 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformatted sql execute request, only support a single sql statement, got %d statements", len(statementList)))
+	}
+	return instance, nil
+}
+
+// createSQLEditorQueryActivity records the execution of the SQL editor query, queryErr is the error of the query if failed.
+// Failing to create the activity is only logged, the returned error is an *echo.HTTPError on failing to construct the activity.
+func (s *Server) createSQLEditorQueryActivity(ctx context.Context, creatorID int, exec *api.SQLExecute, instance *api.Instance, dataSourceType api.DataSourceType, durationNs int64, queryErr error) error {
+	errMessage := ""
+	activityLevel := api.ActivityInfo
+	if queryErr != nil {
+		errMessage = queryErr.Error()
+		activityLevel = api.ActivityError
+	}
+
+	bytes, err := json.Marshal(api.ActivitySQLEditorQueryPayload{
+		Statement:      exec.Statement,
+		DurationNs:     durationNs,
+		InstanceName:   instance.Name,
+		DatabaseName:   exec.DatabaseName,
+		DataSourceType: dataSourceType,
+		Error:          errMessage,
+	})
+	if err != nil {
+		s.l.Warn("Failed to marshal activity after executing sql statement",
+			zap.String("database_name", exec.DatabaseName),
+			zap.String("instance_name", instance.Name),
+			zap.String("statement", exec.Statement),
+			zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   creatorID,
+		Type:        api.ActivitySQLEditorQuery,
+		ContainerID: exec.InstanceID,
+		Level:       activityLevel,
+		Comment: fmt.Sprintf("Executed `%q` in database %q of instance %q.",
+			exec.Statement, exec.DatabaseName, instance.Name),
+		Payload: string(bytes),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		s.l.Warn("Failed to create activity after executing sql statement",
+			zap.String("database_name", exec.DatabaseName),
+			zap.String("instance_name", instance.Name),
+			zap.String("statement", exec.Statement),
+			zap.Error(err))
+	}
+	return nil
+}
+
+// createSQLEditorExportActivity records the export of the SQL editor query results, exportErr is the error of the export if failed.
+func (s *Server) createSQLEditorExportActivity(ctx context.Context, creatorID int, export *api.SQLExport, instance *api.Instance, dataSourceType api.DataSourceType, durationNs int64, rowCount int, exportErr error) {
+	errMessage := ""
+	activityLevel := api.ActivityInfo
+	if exportErr != nil {
+		errMessage = exportErr.Error()
+		activityLevel = api.ActivityError
+	}
+
+	bytes, err := json.Marshal(api.ActivitySQLEditorExportPayload{
+		Statement:      export.Statement,
+		DurationNs:     durationNs,
+		InstanceName:   instance.Name,
+		DatabaseName:   export.DatabaseName,
+		DataSourceType: dataSourceType,
+		Format:         export.Format,
+		RowCount:       rowCount,
+		Error:          errMessage,
+	})
+	if err != nil {
+		s.l.Warn("Failed to marshal activity after exporting query results",
+			zap.String("database_name", export.DatabaseName),
+			zap.String("instance_name", instance.Name),
+			zap.String("statement", export.Statement),
+			zap.Error(err))
+		return
+	}
+
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   creatorID,
+		Type:        api.ActivitySQLEditorExport,
+		ContainerID: export.InstanceID,
+		Level:       activityLevel,
+		Comment: fmt.Sprintf("Exported %d rows of `%q` in database %q of instance %q as %s.",
+			rowCount, export.Statement, export.DatabaseName, instance.Name, export.Format),
+		Payload: string(bytes),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		s.l.Warn("Failed to create activity after exporting query results",
+			zap.String("database_name", export.DatabaseName),
+			zap.String("instance_name", instance.Name),
+			zap.String("statement", export.Statement),
+			zap.Error(err))
+	}
 }
 
 func (s *Server) syncEngineVersionAndSchema(ctx context.Context, instance *api.Instance) (rs *api.SQLResultSet) {
@@ -343,6 +871,7 @@ func (s *Server) syncEngineVersionAndSchema(ctx context.Context, instance *api.I
 							CharacterSet: column.CharacterSet,
 							Collation:    column.Collation,
 							Comment:      column.Comment,
+							Extra:        column.Extra,
 						}
 						if err := createColumn(database, upsertedTable, columnCreate); err != nil {
 							return err
@@ -402,45 +931,48 @@ func (s *Server) syncEngineVersionAndSchema(ctx context.Context, instance *api.I
 				return nil
 			}
 
-			instanceUserFind := &api.InstanceUserFind{
-				InstanceID: instance.ID,
-			}
-			instanceUserList, err := s.InstanceUserService.FindInstanceUserList(ctx, instanceUserFind)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch user list for instance: %v", instance.ID)).SetInternal(err)
-			}
-
-			// Upsert user found in the instance
-			for _, user := range userList {
-				userUpsert := &api.InstanceUserUpsert{
-					CreatorID:  api.SystemBotID,
+			// The users are kept as is if the engine doesn't sync them, instead of being deleted as not found.
+			if driver.GetCapability(instance.Engine).UserGrant {
+				instanceUserFind := &api.InstanceUserFind{
 					InstanceID: instance.ID,
-					Name:       user.Name,
-					Grant:      user.Grant,
 				}
-				_, err := s.InstanceUserService.UpsertInstanceUser(ctx, userUpsert)
+				instanceUserList, err := s.InstanceUserService.FindInstanceUserList(ctx, instanceUserFind)
 				if err != nil {
-					return fmt.Errorf("failed to sync user for instance: %s. Failed to upsert user. Error %w", instance.Name, err)
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch user list for instance: %v", instance.ID)).SetInternal(err)
 				}
-			}
 
-			// Delete user no longer found in the instance
-			for _, user := range instanceUserList {
-				found := false
-				for _, dbUser := range userList {
-					if user.Name == dbUser.Name {
-						found = true
-						break
+				// Upsert user found in the instance
+				for _, user := range userList {
+					userUpsert := &api.InstanceUserUpsert{
+						CreatorID:  api.SystemBotID,
+						InstanceID: instance.ID,
+						Name:       user.Name,
+						Grant:      user.Grant,
+					}
+					_, err := s.InstanceUserService.UpsertInstanceUser(ctx, userUpsert)
+					if err != nil {
+						return fmt.Errorf("failed to sync user for instance: %s. Failed to upsert user. Error %w", instance.Name, err)
 					}
 				}
 
-				if !found {
-					userDelete := &api.InstanceUserDelete{
-						ID: user.ID,
+				// Delete user no longer found in the instance
+				for _, user := range instanceUserList {
+					found := false
+					for _, dbUser := range userList {
+						if user.Name == dbUser.Name {
+							found = true
+							break
+						}
 					}
-					err := s.InstanceUserService.DeleteInstanceUser(ctx, userDelete)
-					if err != nil {
-						return fmt.Errorf("failed to sync user for instance: %s. Failed to delete user: %s. Error %w", instance.Name, user.Name, err)
+
+					if !found {
+						userDelete := &api.InstanceUserDelete{
+							ID: user.ID,
+						}
+						err := s.InstanceUserService.DeleteInstanceUser(ctx, userDelete)
+						if err != nil {
+							return fmt.Errorf("failed to sync user for instance: %s. Failed to delete user: %s. Error %w", instance.Name, user.Name, err)
+						}
 					}
 				}
 			}
@@ -616,14 +1148,19 @@ func getLatestSchemaVersion(ctx context.Context, driver db.Driver, databaseName
 	return schemaVersion, nil
 }
 
-func validateSQLSelectStatement(sqlStatement string) bool {
-	whiteListRegs := []string{`^SELECT$`, `^EXPLAIN\s+?SELECT$`, `^(EXPLAIN\s+?)?SELECT\s`}
-	formatedStr := strings.ToUpper(strings.TrimSpace(sqlStatement))
-	for _, reg := range whiteListRegs {
-		matchResult, _ := regexp.MatchString(reg, formatedStr)
-		if matchResult {
-			return true
+// validateReadOnlyStatementList splits the multi-statement script of the engine, and validates each statement is read-only.
+func validateReadOnlyStatementList(engineType db.Type, statement string) ([]string, error) {
+	statementList, err := parser.SplitMultiSQL(engineType, statement)
+	if err != nil {
+		return nil, err
+	}
+	if len(statementList) == 0 {
+		return nil, fmt.Errorf("missing sql statement")
+	}
+	for i, statement := range statementList {
+		if err := parser.ValidateReadOnlySQL(engineType, statement); err != nil {
+			return nil, fmt.Errorf("statement #%d: %w", i+1, err)
 		}
 	}
-	return false
+	return statementList, nil
 }
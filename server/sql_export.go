@@ -0,0 +1,238 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// sqlResultExporter writes the SQL results in the export format as the rows are fetched,
+// so the exported rows aren't held in memory.
+type sqlResultExporter interface {
+	// WriteColumns writes the columns, it's called once before writing any rows.
+	WriteColumns(columnList []*db.QueryColumn) error
+	// WriteRows writes the rows, whose values are in the column order.
+	WriteRows(rowList [][]interface{}) error
+	// Close writes the remaining content, it doesn't close the underlying writer.
+	Close() error
+}
+
+// newSQLResultExporter returns the exporter writing to w in the export format.
+func newSQLResultExporter(format api.SQLExportFormat, w io.Writer) (sqlResultExporter, error) {
+	switch format {
+	case api.SQLExportFormatCSV:
+		return &csvExporter{w: csv.NewWriter(w)}, nil
+	case api.SQLExportFormatJSONL:
+		return &jsonlExporter{w: w}, nil
+	case api.SQLExportFormatXLSX:
+		return &xlsxExporter{w: zip.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("invalid export format %q", format)
+}
+
+// getSQLExportFileExtension returns the file extension of the export format.
+func getSQLExportFileExtension(format api.SQLExportFormat) string {
+	switch format {
+	case api.SQLExportFormatCSV:
+		return "csv"
+	case api.SQLExportFormatJSONL:
+		return "jsonl"
+	case api.SQLExportFormatXLSX:
+		return "xlsx"
+	}
+	return ""
+}
+
+// getSQLExportContentType returns the MIME type of the export format.
+func getSQLExportContentType(format api.SQLExportFormat) string {
+	switch format {
+	case api.SQLExportFormatCSV:
+		return "text/csv; charset=UTF-8"
+	case api.SQLExportFormatJSONL:
+		return "application/x-ndjson"
+	case api.SQLExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return ""
+}
+
+// formatExportValue formats the value as the text in the exported file.
+func formatExportValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) WriteColumns(columnList []*db.QueryColumn) error {
+	var record []string
+	for _, column := range columnList {
+		record = append(record, column.Name)
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExporter) WriteRows(rowList [][]interface{}) error {
+	for _, row := range rowList {
+		var record []string
+		for _, value := range row {
+			record = append(record, formatExportValue(value))
+		}
+		if err := e.w.Write(record); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlExporter struct {
+	w          io.Writer
+	columnList []*db.QueryColumn
+}
+
+func (e *jsonlExporter) WriteColumns(columnList []*db.QueryColumn) error {
+	e.columnList = columnList
+	return nil
+}
+
+func (e *jsonlExporter) WriteRows(rowList [][]interface{}) error {
+	for _, row := range rowList {
+		// Compose the object by hand to keep the keys in the column order, json.Marshal sorts the map keys.
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for i, value := range row {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(e.columnList[i].Name)
+			if err != nil {
+				return err
+			}
+			val, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(val)
+		}
+		buf.WriteString("}\n")
+		if _, err := e.w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *jsonlExporter) Close() error {
+	return nil
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+	xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	xlsxSheetFooter = `</sheetData></worksheet>`
+)
+
+// xlsxExporter writes a minimal workbook with a single sheet, whose rows are streamed into the sheet part of the zip archive.
+// The strings are written as inline strings so there is no shared string table to hold in memory.
+type xlsxExporter struct {
+	w     *zip.Writer
+	sheet io.Writer
+}
+
+func (e *xlsxExporter) WriteColumns(columnList []*db.QueryColumn) error {
+	for _, part := range []struct {
+		name    string
+		content string
+	}{
+		{name: "[Content_Types].xml", content: xlsxContentTypes},
+		{name: "_rels/.rels", content: xlsxRels},
+		{name: "xl/workbook.xml", content: xlsxWorkbook},
+		{name: "xl/_rels/workbook.xml.rels", content: xlsxWorkbookRels},
+	} {
+		w, err := e.w.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := e.w.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	e.sheet = sheet
+	if _, err := io.WriteString(e.sheet, xlsxSheetHeader); err != nil {
+		return err
+	}
+	var header []interface{}
+	for _, column := range columnList {
+		header = append(header, column.Name)
+	}
+	return e.WriteRows([][]interface{}{header})
+}
+
+func (e *xlsxExporter) WriteRows(rowList [][]interface{}) error {
+	var buf bytes.Buffer
+	for _, row := range rowList {
+		buf.WriteString("<row>")
+		for _, value := range row {
+			switch v := value.(type) {
+			case int64, int32, float64:
+				fmt.Fprintf(&buf, "<c><v>%v</v></c>", v)
+			case bool:
+				b := 0
+				if v {
+					b = 1
+				}
+				fmt.Fprintf(&buf, `<c t="b"><v>%d</v></c>`, b)
+			default:
+				buf.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+				if err := xml.EscapeText(&buf, []byte(formatExportValue(value))); err != nil {
+					return err
+				}
+				buf.WriteString("</t></is></c>")
+			}
+		}
+		buf.WriteString("</row>")
+	}
+	_, err := e.sheet.Write(buf.Bytes())
+	return err
+}
+
+func (e *xlsxExporter) Close() error {
+	if e.sheet != nil {
+		if _, err := io.WriteString(e.sheet, xlsxSheetFooter); err != nil {
+			return err
+		}
+	}
+	return e.w.Close()
+}
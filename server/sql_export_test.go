@@ -0,0 +1,109 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestSQLResultExporter(t *testing.T) {
+	columnList := []*db.QueryColumn{
+		{Name: "id", Type: "INT4"},
+		{Name: "name", Type: "TEXT"},
+		{Name: "active", Type: "BOOL"},
+	}
+	rowList := [][]interface{}{
+		{int64(1), "alice", true},
+		{int64(2), "bob, \"jr\"", false},
+	}
+
+	tests := []struct {
+		format api.SQLExportFormat
+		want   string
+	}{
+		{
+			format: api.SQLExportFormatCSV,
+			want:   "id,name,active\n1,alice,true\n2,\"bob, \"\"jr\"\"\",false\n",
+		},
+		{
+			format: api.SQLExportFormatJSONL,
+			want:   "{\"id\":1,\"name\":\"alice\",\"active\":true}\n{\"id\":2,\"name\":\"bob, \\\"jr\\\"\",\"active\":false}\n",
+		},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		exporter, err := newSQLResultExporter(test.format, &buf)
+		if err != nil {
+			t.Fatalf("newSQLResultExporter(%q) unexpected error: %v", test.format, err)
+		}
+		if err := exporter.WriteColumns(columnList); err != nil {
+			t.Fatalf("%q: WriteColumns() unexpected error: %v", test.format, err)
+		}
+		for _, row := range rowList {
+			if err := exporter.WriteRows([][]interface{}{row}); err != nil {
+				t.Fatalf("%q: WriteRows() unexpected error: %v", test.format, err)
+			}
+		}
+		if err := exporter.Close(); err != nil {
+			t.Fatalf("%q: Close() unexpected error: %v", test.format, err)
+		}
+		if got := buf.String(); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.format, got, test.want)
+		}
+	}
+
+	if _, err := newSQLResultExporter("PDF", &bytes.Buffer{}); err == nil {
+		t.Errorf("newSQLResultExporter(%q) expected error", "PDF")
+	}
+}
+
+func TestXLSXExporter(t *testing.T) {
+	var buf bytes.Buffer
+	exporter, err := newSQLResultExporter(api.SQLExportFormatXLSX, &buf)
+	if err != nil {
+		t.Fatalf("newSQLResultExporter() unexpected error: %v", err)
+	}
+	if err := exporter.WriteColumns([]*db.QueryColumn{{Name: "id"}, {Name: "name"}}); err != nil {
+		t.Fatalf("WriteColumns() unexpected error: %v", err)
+	}
+	if err := exporter.WriteRows([][]interface{}{{int64(1), "a<b"}}); err != nil {
+		t.Fatalf("WriteRows() unexpected error: %v", err)
+	}
+	if err := exporter.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to read the exported xlsx as zip: %v", err)
+	}
+	partMap := map[string]string{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open %q: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to read %q: %v", file.Name, err)
+		}
+		partMap[file.Name] = string(content)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		if _, ok := partMap[name]; !ok {
+			t.Errorf("missing part %q in the exported xlsx", name)
+		}
+	}
+	wantSheetData := `<sheetData><row><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c><c t="inlineStr"><is><t xml:space="preserve">name</t></is></c></row>` +
+		`<row><c><v>1</v></c><c t="inlineStr"><is><t xml:space="preserve">a&lt;b</t></is></c></row></sheetData></worksheet>`
+	if sheet := partMap["xl/worksheets/sheet1.xml"]; !strings.HasSuffix(sheet, wantSheetData) {
+		t.Errorf("got sheet %q, want suffix %q", sheet, wantSheetData)
+	}
+}
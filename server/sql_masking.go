@@ -0,0 +1,304 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// fullMaskValue is the value of the fully masked column.
+	fullMaskValue = "******"
+)
+
+// maskingTypeStrictness is the strictness of the masking types, the strictest one is applied
+// if the result column is derived from multiple masked columns.
+var maskingTypeStrictness = map[api.MaskingType]int{
+	api.MaskingTypePartial: 1,
+	api.MaskingTypeHash:    2,
+	api.MaskingTypeFull:    3,
+}
+
+// getMaskingRuleList returns the data masking rules of the instance environment that apply to the databases of the instance.
+// The returned error is an *echo.HTTPError.
+func (s *Server) getMaskingRuleList(ctx context.Context, instance *api.Instance) ([]*api.MaskingRule, error) {
+	policy, err := s.PolicyService.GetDataMaskingPolicy(ctx, instance.EnvironmentID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch the data masking policy of environment ID: %v", instance.EnvironmentID)).SetInternal(err)
+	}
+	if len(policy.RuleList) == 0 {
+		return nil, nil
+	}
+
+	databaseList, err := s.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{
+		InstanceID: &instance.ID,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch databases for instance ID: %v", instance.ID)).SetInternal(err)
+	}
+	var ruleList []*api.MaskingRule
+	for _, rule := range policy.RuleList {
+		for _, database := range databaseList {
+			if strings.EqualFold(rule.DatabaseName, database.Name) {
+				ruleList = append(ruleList, rule)
+				break
+			}
+		}
+	}
+	return ruleList, nil
+}
+
+// validateDataMaskingPolicy validates the data masking rules don't apply to any database in the environment whose engine
+// doesn't support resolving the result columns, since the query results of those databases can't be masked.
+// The returned error is an *echo.HTTPError.
+func (s *Server) validateDataMaskingPolicy(ctx context.Context, environmentID int, payload string) error {
+	policy, err := api.UnmarshalDataMaskingPolicy(payload)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid data masking policy").SetInternal(err)
+	}
+	if len(policy.RuleList) == 0 {
+		return nil
+	}
+
+	rowStatus := api.Normal
+	instanceList, err := s.InstanceService.FindInstanceList(ctx, &api.InstanceFind{
+		RowStatus: &rowStatus,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch instance list").SetInternal(err)
+	}
+	for _, instance := range instanceList {
+		if instance.EnvironmentID != environmentID || parser.IsResultColumnSourceSupported(instance.Engine) {
+			continue
+		}
+		for _, rule := range policy.RuleList {
+			databaseName := rule.DatabaseName
+			databaseList, err := s.DatabaseService.FindDatabaseList(ctx, &api.DatabaseFind{
+				InstanceID: &instance.ID,
+				Name:       &databaseName,
+			})
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch databases for instance ID: %v", instance.ID)).SetInternal(err)
+			}
+			if len(databaseList) > 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid data masking rule, data masking isn't supported for database %q on %s instance %q", rule.DatabaseName, instance.Engine, instance.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// newSQLResultMasker returns the masker of the readonly query result, or nil if no result column is masked.
+// The result columns are resolved back to the source columns to match the masking rules, and it fails closed
+// if the result columns can't be resolved. The masking rules are rejected for the databases on the engines not supported
+// by the resolver, but the databases may still be created or transferred afterwards, whose queries are rejected here.
+func (s *Server) newSQLResultMasker(ctx context.Context, instance *api.Instance, databaseName string, statement string, ruleList []*api.MaskingRule) (*sqlResultMasker, error) {
+	if len(ruleList) == 0 {
+		return nil, nil
+	}
+
+	if !parser.IsResultColumnSourceSupported(instance.Engine) {
+		return nil, fmt.Errorf("data masking isn't supported for %s, querying instance %q with masked databases is disallowed", instance.Engine, instance.Name)
+	}
+	catalog := &columnCatalog{server: s, instanceID: instance.ID}
+	sourceList, err := parser.ExtractResultColumnSource(ctx, instance.Engine, statement, databaseName, catalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the result columns for data masking: %w", err)
+	}
+
+	masker := &sqlResultMasker{
+		maskingTypeList: make([]api.MaskingType, len(sourceList)),
+	}
+	masked := false
+	for i, columnSourceList := range sourceList {
+		for _, source := range columnSourceList {
+			for _, rule := range ruleList {
+				if !strings.EqualFold(rule.DatabaseName, source.Database) ||
+					!strings.EqualFold(rule.TableName, source.Table) ||
+					!strings.EqualFold(rule.ColumnName, source.Column) {
+					continue
+				}
+				if maskingTypeStrictness[rule.Type] > maskingTypeStrictness[masker.maskingTypeList[i]] {
+					masker.maskingTypeList[i] = rule.Type
+					masked = true
+				}
+			}
+		}
+	}
+	if !masked {
+		return nil, nil
+	}
+	return masker, nil
+}
+
+// columnCatalog is the column catalog of an instance backed by the synced schema.
+type columnCatalog struct {
+	server     *Server
+	instanceID int
+}
+
+// ListColumn implements parser.ColumnCatalog interface.
+func (c *columnCatalog) ListColumn(ctx context.Context, databaseName string, tableName string) ([]string, error) {
+	database, err := c.server.DatabaseService.FindDatabase(ctx, &api.DatabaseFind{
+		InstanceID: &c.instanceID,
+		Name:       &databaseName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch database %q, error: %w", databaseName, err)
+	}
+	if database == nil {
+		return nil, nil
+	}
+
+	table, err := c.server.TableService.FindTable(ctx, &api.TableFind{
+		DatabaseID: &database.ID,
+		Name:       &tableName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch table %q, error: %w", tableName, err)
+	}
+	if table == nil {
+		return nil, nil
+	}
+
+	columnList, err := c.server.ColumnService.FindColumnList(ctx, &api.ColumnFind{
+		DatabaseID: &database.ID,
+		TableID:    &table.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch columns of table %q, error: %w", tableName, err)
+	}
+	nameList := []string{}
+	for _, column := range columnList {
+		nameList = append(nameList, column.Name)
+	}
+	return nameList, nil
+}
+
+// sqlResultMasker masks the values of the result columns derived from the masked columns.
+// The nil masker masks nothing.
+type sqlResultMasker struct {
+	// maskingTypeList is the masking type of each result column, which is empty if the column isn't masked.
+	maskingTypeList []api.MaskingType
+}
+
+// validateColumns validates the result columns are the ones resolved by the masker.
+func (m *sqlResultMasker) validateColumns(columnList []*db.QueryColumn) error {
+	if m == nil {
+		return nil
+	}
+	if len(columnList) != len(m.maskingTypeList) {
+		return fmt.Errorf("failed to mask the query result, expect %d columns, got %d", len(m.maskingTypeList), len(columnList))
+	}
+	return nil
+}
+
+// maskRows masks the row values in place, the values are in the result column order.
+func (m *sqlResultMasker) maskRows(rowList [][]interface{}) {
+	if m == nil {
+		return
+	}
+	for _, row := range rowList {
+		for i, maskingType := range m.maskingTypeList {
+			if maskingType != "" && i < len(row) {
+				row[i] = maskValue(maskingType, row[i])
+			}
+		}
+	}
+}
+
+// wrapCursor returns the cursor whose rows are masked. The cursor is closed on error.
+func (m *sqlResultMasker) wrapCursor(cursor db.QueryCursor) (db.QueryCursor, error) {
+	if m == nil {
+		return cursor, nil
+	}
+	if err := m.validateColumns(cursor.Columns()); err != nil {
+		cursor.Close()
+		return nil, err
+	}
+	return &maskingCursor{QueryCursor: cursor, masker: m}, nil
+}
+
+// maskingCursor is the query cursor masking the rows of the underlying cursor.
+type maskingCursor struct {
+	db.QueryCursor
+	masker *sqlResultMasker
+}
+
+// Next implements db.QueryCursor interface.
+func (c *maskingCursor) Next(batchSize int) ([][]interface{}, error) {
+	rowList, err := c.QueryCursor.Next(batchSize)
+	if err != nil {
+		return nil, err
+	}
+	c.masker.maskRows(rowList)
+	return rowList, nil
+}
+
+// maskValue masks the value by the masking type, the NULL value is kept.
+func maskValue(maskingType api.MaskingType, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	text := fmt.Sprint(value)
+	switch maskingType {
+	case api.MaskingTypeFull:
+		return fullMaskValue
+	case api.MaskingTypePartial:
+		// Keep the first and last quarters of the characters.
+		runeList := []rune(text)
+		keep := len(runeList) / 4
+		return string(runeList[:keep]) + strings.Repeat("*", len(runeList)-2*keep) + string(runeList[len(runeList)-keep:])
+	case api.MaskingTypeHash:
+		sum := sha256.Sum256([]byte(text))
+		return hex.EncodeToString(sum[:])
+	}
+	return fullMaskValue
+}
+
+// queryWithMasker queries the readonly statement, and returns the rows keyed by the column name as driver.Query does,
+// the rows are masked by the masker if not nil.
+func queryWithMasker(ctx context.Context, driver db.Driver, statement string, limit int, masker *sqlResultMasker) ([]interface{}, error) {
+	if masker == nil {
+		return driver.Query(ctx, statement, limit)
+	}
+
+	cursor, err := driver.OpenCursor(ctx, statement, limit)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err = masker.wrapCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	columnList := cursor.Columns()
+	resultSet := []interface{}{}
+	for {
+		rowList, err := cursor.Next(0)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rowList {
+			rowData := map[string]interface{}{}
+			for i, column := range columnList {
+				rowData[column.Name] = row[i]
+			}
+			resultSet = append(resultSet, rowData)
+		}
+	}
+	return resultSet, nil
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+	"go.uber.org/zap"
+)
+
+func TestMaskValue(t *testing.T) {
+	tests := []struct {
+		maskingType api.MaskingType
+		value       interface{}
+		want        interface{}
+	}{
+		{
+			maskingType: api.MaskingTypeFull,
+			value:       "alice@example.com",
+			want:        "******",
+		},
+		{
+			maskingType: api.MaskingTypeFull,
+			value:       nil,
+			want:        nil,
+		},
+		{
+			maskingType: api.MaskingTypePartial,
+			value:       "13812345678",
+			want:        "13*******78",
+		},
+		{
+			maskingType: api.MaskingTypePartial,
+			value:       "张三丰先生",
+			want:        "张***生",
+		},
+		{
+			maskingType: api.MaskingTypePartial,
+			value:       "abc",
+			want:        "***",
+		},
+		{
+			maskingType: api.MaskingTypeHash,
+			value:       int64(42),
+			want:        "73475cb40a568e8da8a045ced110137e159f890ac4da883b6b17dc651b3a8049",
+		},
+	}
+
+	for _, test := range tests {
+		got := maskValue(test.maskingType, test.value)
+		if got != test.want {
+			t.Errorf("maskValue(%s, %v): got %v, want %v.", test.maskingType, test.value, got, test.want)
+		}
+	}
+}
+
+func TestSQLResultMaskerMaskRows(t *testing.T) {
+	masker := &sqlResultMasker{
+		maskingTypeList: []api.MaskingType{"", api.MaskingTypeFull},
+	}
+	rowList := [][]interface{}{{int64(1), "secret"}, {int64(2), nil}}
+	masker.maskRows(rowList)
+	want := [][]interface{}{{int64(1), "******"}, {int64(2), nil}}
+	if diff := pretty.Diff(rowList, want); len(diff) > 0 {
+		t.Errorf("got %+v, want %+v, diff %+v.", rowList, want, diff)
+	}
+
+	// The nil masker masks nothing.
+	var nilMasker *sqlResultMasker
+	nilMasker.maskRows(rowList)
+	if diff := pretty.Diff(rowList, want); len(diff) > 0 {
+		t.Errorf("got %+v, want %+v, diff %+v.", rowList, want, diff)
+	}
+}
+
+// fakeInstanceService has the instances in the instance list.
+type fakeInstanceService struct {
+	api.InstanceService
+	instanceList []*api.Instance
+}
+
+func (s *fakeInstanceService) FindInstanceList(_ context.Context, _ *api.InstanceFind) ([]*api.Instance, error) {
+	return s.instanceList, nil
+}
+
+func (s *fakeInstanceService) FindInstance(_ context.Context, find *api.InstanceFind) (*api.Instance, error) {
+	for _, instance := range s.instanceList {
+		if instance.ID == *find.ID {
+			return instance, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestValidateDataMaskingPolicy(t *testing.T) {
+	s := &Server{
+		l: zap.NewNop(),
+		InstanceService: &fakeInstanceService{
+			instanceList: []*api.Instance{
+				{ID: 1, EnvironmentID: 1, Name: "mysql", Engine: db.MySQL},
+				{ID: 2, EnvironmentID: 1, Name: "pg", Engine: db.Postgres},
+				{ID: 3, EnvironmentID: 2, Name: "pg prod", Engine: db.Postgres},
+			},
+		},
+		DatabaseService: &fakeDatabaseService{
+			databaseList: []*api.Database{
+				{InstanceID: 1, Name: "shop"},
+				{InstanceID: 2, Name: "crm"},
+				{InstanceID: 3, Name: "shop"},
+			},
+		},
+	}
+	tests := []struct {
+		name          string
+		environmentID int
+		ruleList      []*api.MaskingRule
+		wantErr       bool
+	}{
+		{
+			name:          "no rule",
+			environmentID: 1,
+		},
+		{
+			name:          "MySQL database",
+			environmentID: 1,
+			ruleList:      []*api.MaskingRule{{DatabaseName: "shop", TableName: "user", ColumnName: "email", Type: api.MaskingTypeFull}},
+		},
+		{
+			name:          "Postgres database",
+			environmentID: 1,
+			ruleList:      []*api.MaskingRule{{DatabaseName: "crm", TableName: "user", ColumnName: "email", Type: api.MaskingTypeFull}},
+			wantErr:       true,
+		},
+		{
+			name:          "Postgres database of the same name in the environment",
+			environmentID: 2,
+			ruleList:      []*api.MaskingRule{{DatabaseName: "shop", TableName: "user", ColumnName: "email", Type: api.MaskingTypeFull}},
+			wantErr:       true,
+		},
+		{
+			name:          "database not found",
+			environmentID: 2,
+			ruleList:      []*api.MaskingRule{{DatabaseName: "crm", TableName: "user", ColumnName: "email", Type: api.MaskingTypeFull}},
+		},
+	}
+
+	for _, test := range tests {
+		payload, err := api.DataMaskingPolicy{RuleList: test.ruleList}.String()
+		if err != nil {
+			t.Fatalf("failed to marshal data masking policy: %v", err)
+		}
+		err = s.validateDataMaskingPolicy(context.Background(), test.environmentID, payload)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: validateDataMaskingPolicy() got error %v, want error %v", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestNewSQLResultMaskerUnsupportedEngine(t *testing.T) {
+	s := &Server{l: zap.NewNop()}
+	instance := &api.Instance{ID: 1, Name: "pg", Engine: db.Postgres}
+	ruleList := []*api.MaskingRule{{DatabaseName: "crm", TableName: "user", ColumnName: "email", Type: api.MaskingTypeFull}}
+	// The query results can't be masked, so the query is rejected instead of returning the unmasked values.
+	if _, err := s.newSQLResultMasker(context.Background(), instance, "crm", "SELECT email FROM user", ruleList); err == nil {
+		t.Errorf("newSQLResultMasker() on %s got no error, want the unsupported error", instance.Engine)
+	}
+	// Nothing is masked without the masking rules.
+	masker, err := s.newSQLResultMasker(context.Background(), instance, "crm", "SELECT email FROM user", nil)
+	if err != nil || masker != nil {
+		t.Errorf("newSQLResultMasker() without masking rules got masker %+v and error %v, want neither", masker, err)
+	}
+}
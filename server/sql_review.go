@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// sqlReviewStatusContext is the context of the commit status set by the SQL review, which identifies the status among other CI checks.
+const sqlReviewStatusContext = "bytebase/sql-review"
+
+// sqlReviewFileResult is the SQL review result of a migration file changed in the merge request.
+type sqlReviewFileResult struct {
+	file     string
+	database string
+	// skippedReason is set if the file isn't reviewed.
+	skippedReason string
+	adviceList    []advisor.Advice
+}
+
+// reviewMergeRequest runs the statement advisors against the migration files changed in the merge request,
+// then posts the advice list back to the merge request as a comment and sets the status of the commit.
+// Returns the message of the review, the returned error is an *echo.HTTPError.
+func (s *Server) reviewMergeRequest(ctx context.Context, repository *api.Repository, mergeRequestID string, commitID string) (string, error) {
+	provider := vcs.Get(repository.VCS.Type, vcs.ProviderConfig{Logger: s.l})
+	oauthCtx := common.OauthContext{
+		ClientID:     repository.VCS.ApplicationID,
+		ClientSecret: repository.VCS.Secret,
+		AccessToken:  repository.AccessToken,
+		RefreshToken: repository.RefreshToken,
+		Refresher:    s.refreshToken(ctx, repository.ID),
+	}
+
+	fileList, err := provider.ListMergeRequestFiles(ctx, oauthCtx, repository.VCS.InstanceURL, repository.ExternalID, mergeRequestID)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list files of merge request %s", mergeRequestID)).SetInternal(err)
+	}
+
+	var resultList []sqlReviewFileResult
+	for _, file := range fileList {
+		if file.Deleted || !strings.HasPrefix(file.Path, repository.BaseDirectory) {
+			continue
+		}
+		// The schema file is the desired schema instead of a statement to run, so there is nothing to review.
+		if isSchemaFile(repository, file.Path) {
+			continue
+		}
+		mi, err := db.ParseMigrationInfo(file.Path, filepath.Join(repository.BaseDirectory, repository.FilePathTemplate))
+		if err != nil {
+			s.l.Debug("Ignored merge request file, not a migration file.", zap.String("file", file.Path), zap.Error(err))
+			continue
+		}
+
+		result := sqlReviewFileResult{
+			file:     file.Path,
+			database: mi.Database,
+		}
+		statement, err := provider.ReadFile(ctx, oauthCtx, repository.VCS.InstanceURL, repository.ExternalID, file.Path, commitID)
+		if err != nil {
+			s.l.Warn("Failed to read merge request file for SQL review.", zap.String("file", file.Path), zap.String("commit", commitID), zap.Error(err))
+			result.skippedReason = "failed to read the file"
+		} else {
+			result.adviceList, err = s.adviseMigrationFile(ctx, repository, mi, statement)
+			if err != nil {
+				result.skippedReason = err.Error()
+			}
+		}
+		resultList = append(resultList, result)
+	}
+	if len(resultList) == 0 {
+		return fmt.Sprintf("Ignored merge request %s, no migration file changed", mergeRequestID), nil
+	}
+
+	if err := provider.CreateMergeRequestComment(ctx, oauthCtx, repository.VCS.InstanceURL, repository.ExternalID, mergeRequestID, formatSQLReviewComment(resultList)); err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to comment on merge request %s", mergeRequestID)).SetInternal(err)
+	}
+
+	state, description := sqlReviewCommitStatus(resultList)
+	if err := provider.SetCommitStatus(ctx, oauthCtx, repository.VCS.InstanceURL, repository.ExternalID, commitID, vcs.CommitStatus{
+		State:       state,
+		Context:     sqlReviewStatusContext,
+		Description: description,
+		TargetURL:   fmt.Sprintf("%s:%d/project/%s", s.frontendHost, s.frontendPort, api.ProjectSlug(repository.Project)),
+	}); err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to set status of commit %s", commitID)).SetInternal(err)
+	}
+
+	return fmt.Sprintf("Reviewed %d migration file(s) in merge request %s, %s", len(resultList), mergeRequestID, description), nil
+}
+
+// adviseMigrationFile runs the statement advisors against the migration file using the database it targets.
+// Like the push event, the database is matched by the database name and the optional environment name in the file path.
+func (s *Server) adviseMigrationFile(ctx context.Context, repository *api.Repository, mi *db.MigrationInfo, statement string) ([]advisor.Advice, error) {
+	databaseFind := &api.DatabaseFind{
+		ProjectID: &repository.ProjectID,
+		Name:      &mi.Database,
+	}
+	databaseList, err := s.composeDatabaseListByFind(ctx, databaseFind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find database %q", mi.Database)
+	}
+	var database *api.Database
+	for _, item := range databaseList {
+		// Environment name comparison is case insensitive
+		if mi.Environment == "" || strings.EqualFold(item.Instance.Environment.Name, mi.Environment) {
+			database = item
+			break
+		}
+	}
+	if database == nil {
+		if mi.Environment != "" {
+			return nil, fmt.Errorf("project does not contain database %q for environment %q", mi.Database, mi.Environment)
+		}
+		return nil, fmt.Errorf("project does not contain database %q", mi.Database)
+	}
+
+	syntaxAdvisorType, ok := advisor.GetSyntaxAdvisorType(database.Instance.Engine)
+	if !ok {
+		return nil, fmt.Errorf("SQL review isn't supported for %s yet", database.Instance.Engine)
+	}
+	advisorTypeList := []advisor.Type{syntaxAdvisorType}
+	if compatibilityAdvisorType, ok := advisor.GetMigrationCompatibilityAdvisorType(database.Instance.Engine); ok && s.feature(api.FeatureBackwardCompatibilty) {
+		advisorTypeList = append(advisorTypeList, compatibilityAdvisorType)
+	}
+
+	catalog, err := s.getAdvisorCatalog(ctx, database.ID)
+	if err != nil {
+		s.l.Warn("Failed to get the catalog for SQL review.", zap.String("database", database.Name), zap.Error(err))
+		return nil, fmt.Errorf("failed to get the schema of database %q", database.Name)
+	}
+
+	var adviceList []advisor.Advice
+	for _, advisorType := range advisorTypeList {
+		list, err := advisor.Check(
+			database.Instance.Engine,
+			advisorType,
+			advisor.Context{
+				Logger:    s.l,
+				Charset:   database.CharacterSet,
+				Collation: database.Collation,
+				Catalog:   catalog,
+			},
+			statement,
+		)
+		if err != nil {
+			s.l.Warn("Failed to check statement for SQL review.", zap.String("advisor", string(advisorType)), zap.Error(err))
+			return nil, fmt.Errorf("failed to check statement")
+		}
+		adviceList = append(adviceList, list...)
+	}
+	return adviceList, nil
+}
+
+// sqlReviewCommitStatus returns the commit state and description of the SQL review.
+// The review fails if there is any error advice, while the warnings and the skipped files don't block the merge request.
+func sqlReviewCommitStatus(resultList []sqlReviewFileResult) (vcs.CommitState, string) {
+	errorCount, warnCount := 0, 0
+	for _, result := range resultList {
+		for _, advice := range result.adviceList {
+			switch advice.Status {
+			case advisor.Error:
+				errorCount++
+			case advisor.Warn:
+				warnCount++
+			}
+		}
+	}
+	description := fmt.Sprintf("%d error(s), %d warning(s)", errorCount, warnCount)
+	if errorCount > 0 {
+		return vcs.CommitStateFailure, description
+	}
+	return vcs.CommitStateSuccess, description
+}
+
+// formatSQLReviewComment formats the SQL review result as a markdown comment.
+// The successful advices are omitted to keep the comment short.
+func formatSQLReviewComment(resultList []sqlReviewFileResult) string {
+	// Table cells can't contain the column separator or line breaks.
+	cellReplacer := strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+	var buf strings.Builder
+	buf.WriteString("## Bytebase SQL Review\n")
+	for _, result := range resultList {
+		fmt.Fprintf(&buf, "\n### `%s`\n\n", result.file)
+		if result.skippedReason != "" {
+			fmt.Fprintf(&buf, "Skipped reviewing against database `%s`, %s.\n", result.database, result.skippedReason)
+			continue
+		}
+
+		var rowList []string
+		for _, advice := range result.adviceList {
+			if advice.Status == advisor.Success {
+				continue
+			}
+			// The line is empty if the advice isn't about a statement, e.g. the syntax error.
+			line := ""
+			if advice.Position != nil {
+				line = strconv.Itoa(advice.Position.Line)
+			}
+			rowList = append(rowList, fmt.Sprintf("| %s | %d | %s | %s | %s |", advice.Status.String(), advice.Code, line, cellReplacer.Replace(advice.Title), cellReplacer.Replace(advice.Content)))
+		}
+		if len(rowList) == 0 {
+			fmt.Fprintf(&buf, "No issue found against database `%s`.\n", result.database)
+			continue
+		}
+		fmt.Fprintf(&buf, "Reviewed against database `%s`.\n\n", result.database)
+		buf.WriteString("| Status | Code | Line | Title | Content |\n")
+		buf.WriteString("| --- | --- | --- | --- | --- |\n")
+		buf.WriteString(strings.Join(rowList, "\n"))
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
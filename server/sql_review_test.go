@@ -0,0 +1,125 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestSQLReviewCommitStatus(t *testing.T) {
+	tests := []struct {
+		resultList      []sqlReviewFileResult
+		wantState       vcs.CommitState
+		wantDescription string
+	}{
+		{
+			resultList: []sqlReviewFileResult{
+				{
+					file:     "bytebase/db1__v1__create_table.sql",
+					database: "db1",
+					adviceList: []advisor.Advice{
+						{Status: advisor.Success, Code: common.Ok, Title: "OK"},
+					},
+				},
+			},
+			wantState:       vcs.CommitStateSuccess,
+			wantDescription: "0 error(s), 0 warning(s)",
+		},
+		{
+			resultList: []sqlReviewFileResult{
+				{
+					file:     "bytebase/db1__v1__drop_column.sql",
+					database: "db1",
+					adviceList: []advisor.Advice{
+						{Status: advisor.Warn, Code: common.CompatibilityDropColumn, Title: "Potential incompatible migration"},
+					},
+				},
+				{
+					file:          "bytebase/db2__v1__create_table.sql",
+					database:      "db2",
+					skippedReason: "project does not contain database \"db2\"",
+				},
+			},
+			wantState:       vcs.CommitStateSuccess,
+			wantDescription: "0 error(s), 1 warning(s)",
+		},
+		{
+			resultList: []sqlReviewFileResult{
+				{
+					file:     "bytebase/db1__v1__create_table.sql",
+					database: "db1",
+					adviceList: []advisor.Advice{
+						{Status: advisor.Error, Code: common.DbStatementSyntaxError, Title: "Syntax error"},
+						{Status: advisor.Warn, Code: common.CompatibilityDropColumn, Title: "Potential incompatible migration"},
+					},
+				},
+			},
+			wantState:       vcs.CommitStateFailure,
+			wantDescription: "1 error(s), 1 warning(s)",
+		},
+	}
+
+	for _, test := range tests {
+		state, description := sqlReviewCommitStatus(test.resultList)
+		if state != test.wantState || description != test.wantDescription {
+			t.Errorf("sqlReviewCommitStatus(%+v) = (%q, %q), want (%q, %q)", test.resultList, state, description, test.wantState, test.wantDescription)
+		}
+	}
+}
+
+func TestFormatSQLReviewComment(t *testing.T) {
+	tests := []struct {
+		resultList []sqlReviewFileResult
+		want       string
+	}{
+		{
+			resultList: []sqlReviewFileResult{
+				{
+					file:     "bytebase/db1__v1__create_table.sql",
+					database: "db1",
+					adviceList: []advisor.Advice{
+						{Status: advisor.Success, Code: common.Ok, Title: "OK"},
+					},
+				},
+				{
+					file:          "bytebase/db2__v1__create_table.sql",
+					database:      "db2",
+					skippedReason: "SQL review isn't supported for POSTGRES yet",
+				},
+			},
+			want: "## Bytebase SQL Review\n" +
+				"\n### `bytebase/db1__v1__create_table.sql`\n\n" +
+				"No issue found against database `db1`.\n" +
+				"\n### `bytebase/db2__v1__create_table.sql`\n\n" +
+				"Skipped reviewing against database `db2`, SQL review isn't supported for POSTGRES yet.\n",
+		},
+		{
+			resultList: []sqlReviewFileResult{
+				{
+					file:     "bytebase/db1__v2__alter_table.sql",
+					database: "db1",
+					adviceList: []advisor.Advice{
+						{Status: advisor.Error, Code: common.DbStatementSyntaxError, Title: "Syntax error", Content: "line 1 column 5 near \"a | b\"\nfoo"},
+						{Status: advisor.Warn, Code: common.CompatibilityDropColumn, Title: "Potential incompatible migration", Content: "\"ALTER TABLE t DROP COLUMN a\" may cause incompatibility with the existing data and code", Position: &advisor.Position{StatementIndex: 1, Line: 3, Column: 1, Snippet: "ALTER TABLE t DROP COLUMN a"}},
+					},
+				},
+			},
+			want: "## Bytebase SQL Review\n" +
+				"\n### `bytebase/db1__v2__alter_table.sql`\n\n" +
+				"Reviewed against database `db1`.\n\n" +
+				"| Status | Code | Line | Title | Content |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| ERROR | 102 |  | Syntax error | line 1 column 5 near \"a \\| b\"<br>foo |\n" +
+				"| WARN | 10005 | 3 | Potential incompatible migration | \"ALTER TABLE t DROP COLUMN a\" may cause incompatibility with the existing data and code |\n",
+		},
+	}
+
+	for _, test := range tests {
+		got := formatSQLReviewComment(test.resultList)
+		if got != test.want {
+			t.Errorf("formatSQLReviewComment(%+v) = %q, want %q", test.resultList, got, test.want)
+		}
+	}
+}
@@ -2,9 +2,16 @@ package server
 
 import (
 	"testing"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/kr/pretty"
+
+	// Register the TiDB parser driver for parsing the literals.
+	_ "github.com/pingcap/tidb/types/parser_driver"
 )
 
-func TestValidateSQLSelectStatement(t *testing.T) {
+func TestValidateReadOnlyStatementList(t *testing.T) {
+	// The statements of the engines other than MySQL and TiDB are validated on the keywords of the tokens.
 	tests := []struct {
 		sqlStatement string
 		want         bool
@@ -84,9 +91,61 @@ func TestValidateSQLSelectStatement(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result := validateSQLSelectStatement(test.sqlStatement)
-		if result != test.want {
-			t.Errorf("Validate SQLStatement %q: got result %v, want %v.", test.sqlStatement, result, test.want)
+		_, err := validateReadOnlyStatementList(db.Postgres, test.sqlStatement)
+		if result := err == nil; result != test.want {
+			t.Errorf("Validate SQLStatement %q: got result %v, want %v, error: %v.", test.sqlStatement, result, test.want, err)
+		}
+	}
+}
+
+func TestValidateReadOnlyMultiStatementList(t *testing.T) {
+	tests := []struct {
+		engineType   db.Type
+		sqlStatement string
+		want         []string
+		wantErr      bool
+	}{
+		{
+			engineType:   db.MySQL,
+			sqlStatement: "SELECT * FROM t1;\nSHOW TABLES;\nDESCRIBE t1;",
+			want:         []string{"SELECT * FROM t1", "SHOW TABLES", "DESCRIBE t1"},
+		},
+		{
+			engineType:   db.MySQL,
+			sqlStatement: "SELECT * FROM t1; DELETE FROM t1;",
+			wantErr:      true,
+		},
+		{
+			engineType:   db.Postgres,
+			sqlStatement: "SELECT ';'; SHOW search_path;",
+			want:         []string{"SELECT ';'", "SHOW search_path"},
+		},
+		{
+			engineType:   db.Postgres,
+			sqlStatement: "SELECT 1; UPDATE t1 SET a = 1",
+			wantErr:      true,
+		},
+		{
+			engineType:   db.Postgres,
+			sqlStatement: " ; -- comment only",
+			wantErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := validateReadOnlyStatementList(test.engineType, test.sqlStatement)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("%s %q: unexpected error: %v", test.engineType, test.sqlStatement, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("%s %q: expected error", test.engineType, test.sqlStatement)
+			continue
+		}
+		if diff := pretty.Diff(got, test.want); len(diff) > 0 {
+			t.Errorf("%s %q: got %+v, want %+v, diff %+v.", test.engineType, test.sqlStatement, got, test.want, diff)
 		}
 	}
 }
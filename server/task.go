@@ -168,51 +168,7 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create activity after updating task statement: %v", updatedTask.Name)).SetInternal(err)
 				}
 
-				// For now, we supported MySQL and TiDB dialect check
-				if updatedTask.Database.Instance.Engine == db.MySQL || updatedTask.Database.Instance.Engine == db.TiDB {
-					payload, err := json.Marshal(api.TaskCheckDatabaseStatementAdvisePayload{
-						Statement: *taskPatch.Statement,
-						DbType:    updatedTask.Database.Instance.Engine,
-						Charset:   updatedTask.Database.CharacterSet,
-						Collation: updatedTask.Database.Collation,
-					})
-					if err != nil {
-						return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to marshal statement advise payload: %v, err: %w", task.Name, err))
-					}
-					_, err = s.TaskCheckRunService.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
-						CreatorID:               api.SystemBotID,
-						TaskID:                  task.ID,
-						Type:                    api.TaskCheckDatabaseStatementSyntax,
-						Payload:                 string(payload),
-						SkipIfAlreadyTerminated: false,
-					})
-					if err != nil {
-						// It's OK if we failed to trigger a check, just emit an error log
-						s.l.Error("Failed to trigger syntax check after changing task statement",
-							zap.Int("task_id", task.ID),
-							zap.String("task_name", task.Name),
-							zap.Error(err),
-						)
-					}
-
-					if s.feature(api.FeatureBackwardCompatibilty) {
-						_, err = s.TaskCheckRunService.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
-							CreatorID:               api.SystemBotID,
-							TaskID:                  task.ID,
-							Type:                    api.TaskCheckDatabaseStatementCompatibility,
-							Payload:                 string(payload),
-							SkipIfAlreadyTerminated: false,
-						})
-						if err != nil {
-							// It's OK if we failed to trigger a check, just emit an error log
-							s.l.Error("Failed to trigger compatibility check after changing task statement",
-								zap.Int("task_id", task.ID),
-								zap.String("task_name", task.Name),
-								zap.Error(err),
-							)
-						}
-					}
-				}
+				s.triggerTaskStatementCheck(ctx, updatedTask, *taskPatch.Statement)
 			}
 
 		}
@@ -312,6 +268,25 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Task ID not found: %d", taskID))
 		}
 
+		if taskStatusPatch.Status == api.TaskRunning && (task.Type == api.TaskDatabaseSchemaUpdate || task.Type == api.TaskDatabaseDataUpdate) {
+			pass, err := s.passSQLReviewCheck(ctx, task)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check SQL review result of task \"%v\"", task.Name)).SetInternal(err)
+			}
+			if !pass {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The statement of task \"%v\" doesn't pass the SQL review check", task.Name))
+			}
+		}
+
+		if taskStatusPatch.Status == api.TaskCanceled {
+			if err := s.validateTaskCancel(ctx, task); err != nil {
+				if common.ErrorCode(err) == common.Invalid {
+					return echo.NewHTTPError(http.StatusBadRequest, common.ErrorMessage(err))
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to check whether task \"%v\" can be canceled", task.Name)).SetInternal(err)
+			}
+		}
+
 		updatedTask, err := s.changeTaskStatusWithPatch(ctx, task, taskStatusPatch)
 		if err != nil {
 			if common.ErrorCode(err) == common.Invalid {
@@ -368,6 +343,95 @@ func (s *Server) registerTaskRoutes(g *echo.Group) {
 	})
 }
 
+// validateTaskCancel returns the error if the task can't be canceled, since its running statement keeps running on the database
+// server of the engine without the cancel capability.
+func (s *Server) validateTaskCancel(ctx context.Context, task *api.Task) error {
+	instance, err := s.InstanceService.FindInstance(ctx, &api.InstanceFind{ID: &task.InstanceID})
+	if err != nil {
+		return fmt.Errorf("failed to find instance %d: %w", task.InstanceID, err)
+	}
+	if instance == nil {
+		return fmt.Errorf("instance ID not found %v", task.InstanceID)
+	}
+	capability, err := db.GetCapability(instance.Engine)
+	if err != nil {
+		return err
+	}
+	if !capability.Cancel {
+		return &common.Error{
+			Code: common.Invalid,
+			Err:  fmt.Errorf("task %q can't be canceled, since canceling the running statement isn't supported for %s", task.Name, instance.Engine)}
+	}
+	return nil
+}
+
+// triggerTaskStatementCheck triggers the statement checks of the task after its statement changes.
+func (s *Server) triggerTaskStatementCheck(ctx context.Context, task *api.Task, statement string) {
+	payload, err := json.Marshal(api.TaskCheckDatabaseStatementAdvisePayload{
+		Statement: statement,
+		DbType:    task.Database.Instance.Engine,
+		Charset:   task.Database.CharacterSet,
+		Collation: task.Database.Collation,
+	})
+	if err != nil {
+		s.l.Error("Failed to marshal statement advise payload",
+			zap.Int("task_id", task.ID),
+			zap.String("task_name", task.Name),
+			zap.Error(err),
+		)
+		return
+	}
+
+	for _, checkType := range []api.TaskCheckType{api.TaskCheckDatabaseStatementSyntax, api.TaskCheckDatabaseStatementCompatibility} {
+		if !s.isStatementCheckApplicable(checkType, task.Database.Instance) {
+			continue
+		}
+		_, err = s.TaskCheckRunService.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+			CreatorID:               api.SystemBotID,
+			TaskID:                  task.ID,
+			Type:                    checkType,
+			Payload:                 string(payload),
+			SkipIfAlreadyTerminated: false,
+		})
+		if err != nil {
+			// It's OK if we failed to trigger a check, just emit an error log
+			s.l.Error("Failed to trigger statement check after changing task statement",
+				zap.Int("task_id", task.ID),
+				zap.String("task_name", task.Name),
+				zap.String("task_check_type", string(checkType)),
+				zap.Error(err),
+			)
+		}
+	}
+
+	supported, err := s.isSQLReviewSupported(ctx, task.Database.Instance)
+	if err != nil {
+		s.l.Error("Failed to check SQL review support after changing task statement",
+			zap.Int("task_id", task.ID),
+			zap.String("task_name", task.Name),
+			zap.Error(err),
+		)
+		return
+	}
+	if supported {
+		_, err = s.TaskCheckRunService.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+			CreatorID:               api.SystemBotID,
+			TaskID:                  task.ID,
+			Type:                    api.TaskCheckDatabaseStatementSQLReview,
+			Payload:                 string(payload),
+			SkipIfAlreadyTerminated: false,
+		})
+		if err != nil {
+			// It's OK if we failed to trigger a check, just emit an error log
+			s.l.Error("Failed to trigger SQL review check after changing task statement",
+				zap.Int("task_id", task.ID),
+				zap.String("task_name", task.Name),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
 func (s *Server) composeTaskListByPipelineAndStageID(ctx context.Context, pipelineID int, stageID int) ([]*api.Task, error) {
 	taskFind := &api.TaskFind{
 		PipelineID: &pipelineID,
@@ -484,6 +548,11 @@ func (s *Server) changeTaskStatusWithPatch(ctx context.Context, task *api.Task,
 		return nil, fmt.Errorf("failed to change task %v(%v) status: %w", task.ID, task.Name, err)
 	}
 
+	// Cancel the running execution of the canceled task, which cancels the running statement on the database server as well.
+	if updatedTask.Status == api.TaskCanceled {
+		s.executionRegistry.cancelTask(task.ID)
+	}
+
 	// Most tasks belong to a pipeline which in turns belongs to an issue. The followup code
 	// behaves differently depending on whether the task is wrapped in an issue.
 	// TODO(tianzhou): Refactor the followup code into chained onTaskStatusChange hook.
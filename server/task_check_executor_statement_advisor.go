@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
 	"go.uber.org/zap"
 )
 
@@ -30,17 +32,48 @@ func (exec *TaskCheckStatementAdvisorExecutor) Run(ctx context.Context, server *
 		return []api.TaskCheckResult{}, common.Errorf(common.Invalid, fmt.Errorf("invalid check statement advise payload: %w", err))
 	}
 
+	if taskCheckRun.Type == api.TaskCheckDatabaseStatementSQLReview {
+		adviceList, err := exec.sqlReviewCheck(ctx, server, taskCheckRun, payload)
+		if err != nil {
+			return []api.TaskCheckResult{}, err
+		}
+		return convertAdviceList(adviceList), nil
+	}
+
 	var advisorType advisor.Type
 	switch taskCheckRun.Type {
 	case api.TaskCheckDatabaseStatementFakeAdvise:
 		advisorType = advisor.Fake
 	case api.TaskCheckDatabaseStatementSyntax:
-		advisorType = advisor.MySQLSyntax
+		var ok bool
+		if advisorType, ok = advisor.GetSyntaxAdvisorType(payload.DbType); !ok {
+			return []api.TaskCheckResult{}, common.Errorf(common.Invalid, fmt.Errorf("syntax check isn't supported for %s", payload.DbType))
+		}
 	case api.TaskCheckDatabaseStatementCompatibility:
 		if !server.feature(api.FeatureBackwardCompatibilty) {
 			return []api.TaskCheckResult{}, common.Errorf(common.NotAuthorized, fmt.Errorf(api.FeatureBackwardCompatibilty.AccessErrorMessage()))
 		}
-		advisorType = advisor.MySQLMigrationCompatibility
+		capability, err := db.GetCapability(payload.DbType)
+		if err != nil || !isStatementCheckSupported(taskCheckRun.Type, payload.DbType, capability) {
+			return []api.TaskCheckResult{}, common.Errorf(common.Invalid, fmt.Errorf("compatibility check isn't supported for %s", payload.DbType))
+		}
+		advisorType, _ = advisor.GetMigrationCompatibilityAdvisorType(payload.DbType)
+	}
+
+	var catalog *advisor.Catalog
+	if taskCheckRun.Type == api.TaskCheckDatabaseStatementCompatibility {
+		task, err := server.TaskService.FindTask(ctx, &api.TaskFind{ID: &taskCheckRun.TaskID})
+		if err != nil {
+			return []api.TaskCheckResult{}, common.Errorf(common.Internal, fmt.Errorf("failed to find task %d: %w", taskCheckRun.TaskID, err))
+		}
+		if task == nil {
+			return []api.TaskCheckResult{}, common.Errorf(common.NotFound, fmt.Errorf("task ID not found %d", taskCheckRun.TaskID))
+		}
+		if task.DatabaseID != nil {
+			if catalog, err = server.getAdvisorCatalog(ctx, *task.DatabaseID); err != nil {
+				return []api.TaskCheckResult{}, common.Errorf(common.Internal, err)
+			}
+		}
 	}
 
 	adviceList, err := advisor.Check(
@@ -50,6 +83,7 @@ func (exec *TaskCheckStatementAdvisorExecutor) Run(ctx context.Context, server *
 			Logger:    exec.l,
 			Charset:   payload.Charset,
 			Collation: payload.Collation,
+			Catalog:   catalog,
 		},
 		payload.Statement,
 	)
@@ -57,7 +91,112 @@ func (exec *TaskCheckStatementAdvisorExecutor) Run(ctx context.Context, server *
 		return []api.TaskCheckResult{}, common.Errorf(common.Internal, fmt.Errorf("failed to check statement: %w", err))
 	}
 
-	result = []api.TaskCheckResult{}
+	return convertAdviceList(adviceList), nil
+}
+
+// sqlReviewCheck checks the statement by the SQL review policy of the environment which the task instance belongs to.
+func (exec *TaskCheckStatementAdvisorExecutor) sqlReviewCheck(ctx context.Context, server *Server, taskCheckRun *api.TaskCheckRun, payload *api.TaskCheckDatabaseStatementAdvisePayload) ([]advisor.Advice, error) {
+	task, err := server.TaskService.FindTask(ctx, &api.TaskFind{ID: &taskCheckRun.TaskID})
+	if err != nil {
+		return nil, common.Errorf(common.Internal, fmt.Errorf("failed to find task %d: %w", taskCheckRun.TaskID, err))
+	}
+	if task == nil {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("task ID not found %d", taskCheckRun.TaskID))
+	}
+	instance, err := server.InstanceService.FindInstance(ctx, &api.InstanceFind{ID: &task.InstanceID})
+	if err != nil {
+		return nil, common.Errorf(common.Internal, fmt.Errorf("failed to find instance %d: %w", task.InstanceID, err))
+	}
+	if instance == nil {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("instance ID not found %d", task.InstanceID))
+	}
+	policy, err := server.PolicyService.GetSQLReviewPolicy(ctx, instance.EnvironmentID)
+	if err != nil {
+		return nil, common.Errorf(common.Internal, fmt.Errorf("failed to get SQL review policy of environment %d: %w", instance.EnvironmentID, err))
+	}
+
+	var catalog *advisor.Catalog
+	if task.DatabaseID != nil {
+		if catalog, err = server.getAdvisorCatalog(ctx, *task.DatabaseID); err != nil {
+			return nil, common.Errorf(common.Internal, err)
+		}
+	}
+
+	adviceList, err := advisor.SQLReviewCheck(payload.Statement, policy.RuleList, advisor.SQLReviewCheckContext{
+		Logger:    exec.l,
+		DbType:    payload.DbType,
+		Charset:   payload.Charset,
+		Collation: payload.Collation,
+		Catalog:   catalog,
+	})
+	if err != nil {
+		return nil, common.Errorf(common.Internal, fmt.Errorf("failed to review statement: %w", err))
+	}
+	return adviceList, nil
+}
+
+// getAdvisorCatalog returns the catalog of the database from the synced schema.
+func (s *Server) getAdvisorCatalog(ctx context.Context, databaseID int) (*advisor.Catalog, error) {
+	tableList, err := s.TableService.FindTableList(ctx, &api.TableFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tables of database %d: %w", databaseID, err)
+	}
+	columnList, err := s.ColumnService.FindColumnList(ctx, &api.ColumnFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch columns of database %d: %w", databaseID, err)
+	}
+	indexList, err := s.IndexService.FindIndexList(ctx, &api.IndexFind{DatabaseID: &databaseID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch indexes of database %d: %w", databaseID, err)
+	}
+
+	catalog := &advisor.Catalog{}
+	tableMap := make(map[int]*advisor.CatalogTable)
+	for _, table := range tableList {
+		catalogTable := &advisor.CatalogTable{Name: table.Name}
+		tableMap[table.ID] = catalogTable
+		catalog.TableList = append(catalog.TableList, catalogTable)
+	}
+	sort.Slice(columnList, func(i, j int) bool {
+		return columnList[i].Position < columnList[j].Position
+	})
+	for _, column := range columnList {
+		catalogTable, ok := tableMap[column.TableID]
+		if !ok {
+			continue
+		}
+		catalogTable.ColumnList = append(catalogTable.ColumnList, &advisor.CatalogColumn{
+			Name:         column.Name,
+			Type:         column.Type,
+			Nullable:     column.Nullable,
+			Default:      column.Default,
+			CharacterSet: column.CharacterSet,
+			Collation:    column.Collation,
+			Comment:      column.Comment,
+			Extra:        column.Extra,
+		})
+	}
+	// The index has a row for each of its expressions.
+	for _, index := range indexList {
+		catalogTable, ok := tableMap[index.TableID]
+		if !ok {
+			continue
+		}
+		if n := len(catalogTable.IndexList); n > 0 && catalogTable.IndexList[n-1].Name == index.Name {
+			catalogTable.IndexList[n-1].ExpressionList = append(catalogTable.IndexList[n-1].ExpressionList, index.Expression)
+			continue
+		}
+		catalogTable.IndexList = append(catalogTable.IndexList, &advisor.CatalogIndex{
+			Name:           index.Name,
+			Unique:         index.Unique,
+			ExpressionList: []string{index.Expression},
+		})
+	}
+	return catalog, nil
+}
+
+func convertAdviceList(adviceList []advisor.Advice) []api.TaskCheckResult {
+	result := []api.TaskCheckResult{}
 	for _, advice := range adviceList {
 		status := api.TaskCheckStatusSuccess
 		switch advice.Status {
@@ -69,13 +208,22 @@ func (exec *TaskCheckStatementAdvisorExecutor) Run(ctx context.Context, server *
 			status = api.TaskCheckStatusError
 		}
 
-		result = append(result, api.TaskCheckResult{
-			Status:  status,
-			Code:    advice.Code,
-			Title:   advice.Title,
-			Content: advice.Content,
-		})
+		checkResult := api.TaskCheckResult{
+			Status:      status,
+			Code:        advice.Code,
+			Title:       advice.Title,
+			Content:     advice.Content,
+			Replacement: advice.Replacement,
+		}
+		if advice.Position != nil {
+			checkResult.Position = &api.TaskCheckResultPosition{
+				StatementIndex: advice.Position.StatementIndex,
+				Line:           advice.Position.Line,
+				Column:         advice.Position.Column,
+				Snippet:        advice.Position.Snippet,
+			}
+		}
+		result = append(result, checkResult)
 	}
-
-	return result, nil
+	return result
 }
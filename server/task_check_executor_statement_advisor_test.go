@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/kr/pretty"
+)
+
+type fakeTableService struct {
+	api.TableService
+	tableList []*api.Table
+}
+
+func (s *fakeTableService) FindTableList(_ context.Context, _ *api.TableFind) ([]*api.Table, error) {
+	return s.tableList, nil
+}
+
+type fakeColumnService struct {
+	api.ColumnService
+	columnList []*api.Column
+}
+
+func (s *fakeColumnService) FindColumnList(_ context.Context, _ *api.ColumnFind) ([]*api.Column, error) {
+	return s.columnList, nil
+}
+
+type fakeIndexService struct {
+	api.IndexService
+	indexList []*api.Index
+}
+
+func (s *fakeIndexService) FindIndexList(_ context.Context, _ *api.IndexFind) ([]*api.Index, error) {
+	return s.indexList, nil
+}
+
+func TestGetAdvisorCatalog(t *testing.T) {
+	s := &Server{
+		TableService: &fakeTableService{
+			tableList: []*api.Table{
+				{ID: 1, Name: "t"},
+				{ID: 2, Name: "s"},
+			},
+		},
+		ColumnService: &fakeColumnService{
+			columnList: []*api.Column{
+				{TableID: 1, Name: "b", Position: 2, Type: "int"},
+				{TableID: 1, Name: "a", Position: 1, Type: "int", Extra: "auto_increment"},
+			},
+		},
+		// The indexes are ordered by the table, the name and the position.
+		IndexService: &fakeIndexService{
+			indexList: []*api.Index{
+				{TableID: 1, Name: "PRIMARY", Expression: "a", Unique: true},
+				{TableID: 1, Name: "idx_a_b", Expression: "a", Position: 1},
+				{TableID: 1, Name: "idx_a_b", Expression: "b", Position: 2},
+				{TableID: 2, Name: "uk_c", Expression: "c", Unique: true},
+			},
+		},
+	}
+	catalog, err := s.getAdvisorCatalog(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("getAdvisorCatalog() got error: %v", err)
+	}
+	want := &advisor.Catalog{
+		TableList: []*advisor.CatalogTable{
+			{
+				Name: "t",
+				ColumnList: []*advisor.CatalogColumn{
+					{Name: "a", Type: "int", Extra: "auto_increment"},
+					{Name: "b", Type: "int"},
+				},
+				IndexList: []*advisor.CatalogIndex{
+					{Name: "PRIMARY", Unique: true, ExpressionList: []string{"a"}},
+					{Name: "idx_a_b", ExpressionList: []string{"a", "b"}},
+				},
+			},
+			{
+				Name: "s",
+				IndexList: []*advisor.CatalogIndex{
+					{Name: "uk_c", Unique: true, ExpressionList: []string{"c"}},
+				},
+			},
+		},
+	}
+	if diff := pretty.Diff(catalog, want); len(diff) > 0 {
+		t.Errorf("getAdvisorCatalog() got %+v, want %+v, diff %+v", catalog, want, diff)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
 	"github.com/bytebase/bytebase/plugin/db"
 	"go.uber.org/zap"
 )
@@ -298,39 +299,45 @@ func (s *TaskCheckScheduler) ScheduleCheckIfNeeded(ctx context.Context, task *ap
 			return nil, err
 		}
 
-		// For now we only supported MySQL dialect syntax and compatibility check
-		if database.Instance.Engine == db.MySQL || database.Instance.Engine == db.TiDB {
-			payload, err := json.Marshal(api.TaskCheckDatabaseStatementAdvisePayload{
-				Statement: statement,
-				DbType:    database.Instance.Engine,
-				Charset:   database.CharacterSet,
-				Collation: database.Collation,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal statement advise payload: %v, err: %w", task.Name, err)
+		payload, err := json.Marshal(api.TaskCheckDatabaseStatementAdvisePayload{
+			Statement: statement,
+			DbType:    database.Instance.Engine,
+			Charset:   database.CharacterSet,
+			Collation: database.Collation,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal statement advise payload: %v, err: %w", task.Name, err)
+		}
+		for _, checkType := range []api.TaskCheckType{api.TaskCheckDatabaseStatementSyntax, api.TaskCheckDatabaseStatementCompatibility} {
+			if !s.server.isStatementCheckApplicable(checkType, database.Instance) {
+				continue
 			}
 			_, err = s.server.TaskCheckRunService.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
 				CreatorID:               creatorID,
 				TaskID:                  task.ID,
-				Type:                    api.TaskCheckDatabaseStatementSyntax,
+				Type:                    checkType,
 				Payload:                 string(payload),
 				SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
 			})
 			if err != nil {
 				return nil, err
 			}
+		}
 
-			if s.server.feature(api.FeatureBackwardCompatibilty) {
-				_, err = s.server.TaskCheckRunService.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
-					CreatorID:               creatorID,
-					TaskID:                  task.ID,
-					Type:                    api.TaskCheckDatabaseStatementCompatibility,
-					Payload:                 string(payload),
-					SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
-				})
-				if err != nil {
-					return nil, err
-				}
+		supported, err := s.server.isSQLReviewSupported(ctx, database.Instance)
+		if err != nil {
+			return nil, err
+		}
+		if supported {
+			_, err = s.server.TaskCheckRunService.CreateTaskCheckRunIfNeeded(ctx, &api.TaskCheckRunCreate{
+				CreatorID:               creatorID,
+				TaskID:                  task.ID,
+				Type:                    api.TaskCheckDatabaseStatementSQLReview,
+				Payload:                 string(payload),
+				SkipIfAlreadyTerminated: skipIfAlreadyTerminated,
+			})
+			if err != nil {
+				return nil, err
 			}
 		}
 
@@ -391,3 +398,85 @@ func (s *Server) passCheck(ctx context.Context, server *Server, task *api.Task,
 
 	return true, nil
 }
+
+// isStatementCheckApplicable returns whether the statement check is scheduled for the task of the instance, which requires
+// the advisor of the instance engine and the capability the check depends on, and the compatibility check requires the feature.
+func (s *Server) isStatementCheckApplicable(checkType api.TaskCheckType, instance *api.Instance) bool {
+	if checkType == api.TaskCheckDatabaseStatementCompatibility && !s.feature(api.FeatureBackwardCompatibilty) {
+		return false
+	}
+	capability, err := db.GetCapability(instance.Engine)
+	if err != nil {
+		return false
+	}
+	return isStatementCheckSupported(checkType, instance.Engine, capability)
+}
+
+// isStatementCheckSupported returns whether the engine with the capability can run the statement check.
+func isStatementCheckSupported(checkType api.TaskCheckType, engine db.Type, capability db.Capability) bool {
+	switch checkType {
+	case api.TaskCheckDatabaseStatementSyntax:
+		_, ok := advisor.GetSyntaxAdvisorType(engine)
+		return ok
+	// The compatibility check looks up the synced indexes, e.g. the primary key columns and the renamed indexes,
+	// which are missing from the catalog without the index sync.
+	case api.TaskCheckDatabaseStatementCompatibility:
+		_, ok := advisor.GetMigrationCompatibilityAdvisorType(engine)
+		return ok && capability.IndexSync
+	}
+	return true
+}
+
+// isSQLReviewSupported returns whether the SQL review policy of the instance environment has any enabled rule for the instance engine.
+func (s *Server) isSQLReviewSupported(ctx context.Context, instance *api.Instance) (bool, error) {
+	policy, err := s.PolicyService.GetSQLReviewPolicy(ctx, instance.EnvironmentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get SQL review policy of environment %d: %w", instance.EnvironmentID, err)
+	}
+	return advisor.IsSQLReviewSupported(instance.Engine, policy.RuleList), nil
+}
+
+// Returns true if the latest SQL review check has no error, or there is no SQL review rule for the task instance.
+// Unlike passCheck, the warnings of the SQL review rules don't block the task, since the rule level is configured by the policy.
+func (s *Server) passSQLReviewCheck(ctx context.Context, task *api.Task) (bool, error) {
+	instance, err := s.InstanceService.FindInstance(ctx, &api.InstanceFind{ID: &task.InstanceID})
+	if err != nil {
+		return false, err
+	}
+	if instance == nil {
+		return false, fmt.Errorf("instance ID not found %v", task.InstanceID)
+	}
+	supported, err := s.isSQLReviewSupported(ctx, instance)
+	if err != nil {
+		return false, err
+	}
+	if !supported {
+		return true, nil
+	}
+
+	checkType := api.TaskCheckDatabaseStatementSQLReview
+	statusList := []api.TaskCheckRunStatus{api.TaskCheckRunDone, api.TaskCheckRunFailed}
+	taskCheckRunList, err := s.TaskCheckRunService.FindTaskCheckRunList(ctx, &api.TaskCheckRunFind{
+		TaskID:     &task.ID,
+		Type:       &checkType,
+		StatusList: &statusList,
+		Latest:     true,
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(taskCheckRunList) == 0 || taskCheckRunList[0].Status == api.TaskCheckRunFailed {
+		return false, nil
+	}
+
+	checkResult := &api.TaskCheckRunResultPayload{}
+	if err := json.Unmarshal([]byte(taskCheckRunList[0].Result), checkResult); err != nil {
+		return false, err
+	}
+	for _, result := range checkResult.ResultList {
+		if result.Status == api.TaskCheckStatusError {
+			return false, nil
+		}
+	}
+	return true, nil
+}
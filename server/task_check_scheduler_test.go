@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	// Register the advisors of the engines.
+	_ "github.com/bytebase/bytebase/plugin/advisor/mysql"
+	_ "github.com/bytebase/bytebase/plugin/advisor/pg"
+)
+
+func TestIsStatementCheckSupported(t *testing.T) {
+	tests := []struct {
+		checkType  api.TaskCheckType
+		engine     db.Type
+		capability db.Capability
+		want       bool
+	}{
+		{
+			checkType:  api.TaskCheckDatabaseStatementSyntax,
+			engine:     db.MySQL,
+			capability: db.Capability{IndexSync: true},
+			want:       true,
+		},
+		{
+			checkType:  api.TaskCheckDatabaseStatementCompatibility,
+			engine:     db.Postgres,
+			capability: db.Capability{IndexSync: true},
+			want:       true,
+		},
+		// The compatibility check is skipped without the synced indexes.
+		{
+			checkType:  api.TaskCheckDatabaseStatementCompatibility,
+			engine:     db.MySQL,
+			capability: db.Capability{},
+			want:       false,
+		},
+		// SQLite has no advisor.
+		{
+			checkType:  api.TaskCheckDatabaseStatementSyntax,
+			engine:     db.SQLite,
+			capability: db.Capability{DumpWithData: true},
+			want:       false,
+		},
+		{
+			checkType:  api.TaskCheckDatabaseConnect,
+			engine:     db.ClickHouse,
+			capability: db.Capability{},
+			want:       true,
+		},
+	}
+
+	for _, test := range tests {
+		if got := isStatementCheckSupported(test.checkType, test.engine, test.capability); got != test.want {
+			t.Errorf("isStatementCheckSupported(%s, %s, %+v) = %v, want %v", test.checkType, test.engine, test.capability, got, test.want)
+		}
+	}
+}
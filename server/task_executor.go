@@ -78,10 +78,21 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 			return true, nil, fmt.Errorf("repository not found with project ID %v", task.Database.ProjectID)
 		}
 
-		mi, err = db.ParseMigrationInfo(
-			vcsPushEvent.FileCommit.Added,
-			filepath.Join(vcsPushEvent.BaseDirectory, repository.FilePathTemplate),
-		)
+		if isSchemaFile(repository, vcsPushEvent.FileCommit.Added) {
+			// The statement of a declarative schema migration is generated, so we use the default version as UI does.
+			mi, err = db.ParseSchemaFileInfo(
+				vcsPushEvent.FileCommit.Added,
+				filepath.Join(vcsPushEvent.BaseDirectory, repository.SchemaPathTemplate),
+			)
+			if err == nil {
+				mi.Version = defaultMigrationVersionFromTaskID(task.ID)
+			}
+		} else {
+			mi, err = db.ParseMigrationInfo(
+				vcsPushEvent.FileCommit.Added,
+				filepath.Join(vcsPushEvent.BaseDirectory, repository.FilePathTemplate),
+			)
+		}
 		// This should not happen normally as we already check this when creating the issue. Just in case.
 		if err != nil {
 			return true, nil, fmt.Errorf("failed to start migration, error: %w", err)
@@ -161,8 +172,7 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 		return true, nil, err
 	}
 
-	// If VCS based and schema path template is specified, then we will write back the latest schema file after migration.
-	writeBack := (vcsPushEvent != nil) && (repository.SchemaPathTemplate != "")
+	writeBack := needWriteBackLatestSchema(repository, vcsPushEvent)
 	// For tenant mode project, we will only write back latest schema file on the last task.
 	if writeBack && issue != nil {
 		project, err := server.composeProjectByID(ctx, task.Database.ProjectID)
@@ -209,21 +219,34 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 			bytebaseURL = fmt.Sprintf("%s:%d/issue/%s?stage=%d", server.frontendHost, server.frontendPort, api.IssueSlug(issue), task.StageID)
 		}
 
-		commitID, err := writeBackLatestSchema(ctx, server, repository, vcsPushEvent, mi, branch, latestSchemaFile, schema, bytebaseURL)
+		result, err := writeBackLatestSchema(ctx, server, repository, vcsPushEvent, mi, branch, writeBackMergeRequestBranch(repository, mi, task.ID), latestSchemaFile, schema, bytebaseURL)
 		if err != nil {
 			return true, nil, err
 		}
 
 		// Create file commit activity
 		{
-			payload, err := json.Marshal(api.ActivityPipelineTaskFileCommitPayload{
+			payload := api.ActivityPipelineTaskFileCommitPayload{
 				TaskID:             task.ID,
 				VCSInstanceURL:     repository.VCS.InstanceURL,
 				RepositoryFullPath: vcsPushEvent.RepositoryFullPath,
-				Branch:             branch,
+				Branch:             result.branch,
 				FilePath:           latestSchemaFile,
-				CommitID:           commitID,
-			})
+				CommitID:           result.commitID,
+			}
+			comment := fmt.Sprintf("Committed the latest schema after applying migration version %s to %q.",
+				mi.Version,
+				mi.Database,
+			)
+			if result.mergeRequest != nil {
+				payload.MergeRequestURL = result.mergeRequest.URL
+				comment = fmt.Sprintf("Opened merge request %s for the latest schema after applying migration version %s to %q.",
+					result.mergeRequest.URL,
+					mi.Version,
+					mi.Database,
+				)
+			}
+			bytes, err := json.Marshal(payload)
 			if err != nil {
 				l.Error("Failed to marshal file commit activity after writing back the latest schema",
 					zap.Int("task_id", task.ID),
@@ -242,11 +265,8 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 				ContainerID: containerID,
 				Type:        api.ActivityPipelineTaskFileCommit,
 				Level:       api.ActivityInfo,
-				Comment: fmt.Sprintf("Committed the latest schema after applying migration version %s to %q.",
-					mi.Version,
-					mi.Database,
-				),
-				Payload: string(payload),
+				Comment:     comment,
+				Payload:     string(bytes),
 			}
 
 			_, err = server.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{})
@@ -273,22 +293,80 @@ func runMigration(ctx context.Context, l *zap.Logger, server *Server, task *api.
 	}, nil
 }
 
+// needWriteBackLatestSchema returns whether to write back the latest schema file after applying the migration of the push event.
+// For tenant mode project, the caller only writes back on the last task.
+func needWriteBackLatestSchema(repository *api.Repository, vcsPushEvent *vcs.PushEvent) bool {
+	// If VCS based and schema path template is specified, then we will write back the latest schema file after migration.
+	// The declarative schema migration is already driven by the schema file, so there is nothing to write back.
+	if vcsPushEvent == nil || repository.SchemaPathTemplate == "" || isSchemaFile(repository, vcsPushEvent.FileCommit.Added) {
+		return false
+	}
+	// The latest schema can't be committed to a tag, so there is nothing to write back for the migration deployed from a tag.
+	if _, err := vcs.Tag(vcsPushEvent.Ref); err == nil {
+		return false
+	}
+	return true
+}
+
+// writeBackMergeRequestBranch returns the new branch to commit the latest schema file to in the merge request write back mode,
+// and empty if the latest schema file is committed to the pushed branch directly.
+// Protected branches may reject the direct pushes, so we open a merge request from a new branch instead.
+func writeBackMergeRequestBranch(repository *api.Repository, mi *db.MigrationInfo, taskID int) string {
+	if repository.WriteBackMode != api.RepositoryWriteBackMergeRequest {
+		return ""
+	}
+	return fmt.Sprintf("bytebase/%s-%s-task-%d", mi.Database, mi.Version, taskID)
+}
+
+// writeBackCommitMarker marks the commits writing back the latest schema, whose push events are ignored.
+const writeBackCommitMarker = "THIS COMMIT IS AUTO-GENERATED BY BTYEBASE"
+
+// writeBackResult is the result of writing back the latest schema.
+type writeBackResult struct {
+	commitID string
+	// branch is the branch of the commit.
+	branch string
+	// mergeRequest is the merge request opened for the commit, only set in the merge request write back mode.
+	mergeRequest *vcs.MergeRequest
+}
+
 // Writes back the latest schema to the repository after migration
-// Returns the commit id on success.
-func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.Repository, pushEvent *vcs.PushEvent, mi *db.MigrationInfo, branch string, latestSchemaFile string, schema string, bytebaseURL string) (string, error) {
-	schemaFileMeta, err := vcs.Get(vcs.GitLabSelfHost, vcs.ProviderConfig{Logger: server.l}).ReadFileMeta(
+// If mergeRequestBranch is set, the latest schema is committed to this new branch created from the branch,
+// and a merge request is opened from it to the branch.
+func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.Repository, pushEvent *vcs.PushEvent, mi *db.MigrationInfo, branch string, mergeRequestBranch string, latestSchemaFile string, schema string, bytebaseURL string) (*writeBackResult, error) {
+	provider := vcs.Get(repository.VCS.Type, vcs.ProviderConfig{Logger: server.l})
+	oauthCtx := common.OauthContext{
+		ClientID:     repository.VCS.ApplicationID,
+		ClientSecret: repository.VCS.Secret,
+		AccessToken:  repository.AccessToken,
+		RefreshToken: repository.RefreshToken,
+		Refresher:    server.refreshToken(ctx, repository.ID),
+	}
+
+	commitBranch := branch
+	if mergeRequestBranch != "" {
+		// The branch name is fixed for the task, so the branch may already exist if the write back of a previous attempt
+		// of the task failed afterwards. We reuse the branch in this case, and the latest schema file is overwritten below.
+		if err := provider.CreateBranch(
+			ctx,
+			oauthCtx,
+			repository.VCS.InstanceURL,
+			repository.ExternalID,
+			mergeRequestBranch,
+			branch,
+		); err != nil && common.ErrorCode(err) != common.Conflict {
+			return nil, fmt.Errorf("failed to create branch %s for the latest schema after applying migration %s to %q: %w", mergeRequestBranch, mi.Version, mi.Database, err)
+		}
+		commitBranch = mergeRequestBranch
+	}
+
+	schemaFileMeta, err := provider.ReadFileMeta(
 		ctx,
-		common.OauthContext{
-			ClientID:     repository.VCS.ApplicationID,
-			ClientSecret: repository.VCS.Secret,
-			AccessToken:  repository.AccessToken,
-			RefreshToken: repository.RefreshToken,
-			Refresher:    server.refreshToken(ctx, repository.ID),
-		},
+		oauthCtx,
 		repository.VCS.InstanceURL,
 		repository.ExternalID,
 		latestSchemaFile,
-		branch,
+		commitBranch,
 	)
 
 	createSchemaFile := false
@@ -298,12 +376,12 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 			createSchemaFile = true
 			verb = "Create"
 		} else {
-			return "", fmt.Errorf("failed to fetch latest schema: %w", err)
+			return nil, fmt.Errorf("failed to fetch latest schema: %w", err)
 		}
 	}
 
 	commitTitle := fmt.Sprintf("[Bytebase] %s latest schema for %q after migration %s", verb, mi.Database, mi.Version)
-	commitBody := "THIS COMMIT IS AUTO-GENERATED BY BTYEBASE"
+	commitBody := writeBackCommitMarker
 	if bytebaseURL != "" {
 		commitBody += "\n\n" + bytebaseURL
 	}
@@ -314,20 +392,14 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 	)
 
 	schemaFileCommit := vcs.FileCommitCreate{
-		Branch:        branch,
+		Branch:        commitBranch,
 		CommitMessage: fmt.Sprintf("%s\n\n%s", commitTitle, commitBody),
 		Content:       schema,
 	}
 	if createSchemaFile {
-		err := vcs.Get(vcs.GitLabSelfHost, vcs.ProviderConfig{Logger: server.l}).CreateFile(
+		err := provider.CreateFile(
 			ctx,
-			common.OauthContext{
-				ClientID:     repository.VCS.ApplicationID,
-				ClientSecret: repository.VCS.Secret,
-				AccessToken:  repository.AccessToken,
-				RefreshToken: repository.RefreshToken,
-				Refresher:    server.refreshToken(ctx, repository.ID),
-			},
+			oauthCtx,
 			repository.VCS.InstanceURL,
 			repository.ExternalID,
 			latestSchemaFile,
@@ -335,47 +407,59 @@ func writeBackLatestSchema(ctx context.Context, server *Server, repository *api.
 		)
 
 		if err != nil {
-			return "", fmt.Errorf("failed to create file after applying migration %s to %q: %w", mi.Version, mi.Database, err)
+			return nil, fmt.Errorf("failed to create file after applying migration %s to %q: %w", mi.Version, mi.Database, err)
 		}
 	} else {
 		schemaFileCommit.LastCommitID = schemaFileMeta.LastCommitID
-		err := vcs.Get(vcs.GitLabSelfHost, vcs.ProviderConfig{Logger: server.l}).OverwriteFile(
+		err := provider.OverwriteFile(
 			ctx,
-			common.OauthContext{
-				ClientID:     repository.VCS.ApplicationID,
-				ClientSecret: repository.VCS.Secret,
-				AccessToken:  repository.AccessToken,
-				RefreshToken: repository.RefreshToken,
-				Refresher:    server.refreshToken(ctx, repository.ID),
-			},
+			oauthCtx,
 			repository.VCS.InstanceURL,
 			repository.ExternalID,
 			latestSchemaFile,
 			schemaFileCommit,
 		)
 		if err != nil {
-			return "", fmt.Errorf("failed to create file after applying migration %s to %q: %w", mi.Version, mi.Database, err)
+			return nil, fmt.Errorf("failed to create file after applying migration %s to %q: %w", mi.Version, mi.Database, err)
 		}
 	}
 
 	// VCS such as GitLab API doesn't return the commit on write, so we have to call ReadFileMeta again
-	schemaFileMeta, err = vcs.Get(vcs.GitLabSelfHost, vcs.ProviderConfig{Logger: server.l}).ReadFileMeta(
+	schemaFileMeta, err = provider.ReadFileMeta(
 		ctx,
-		common.OauthContext{
-			ClientID:     repository.VCS.ApplicationID,
-			ClientSecret: repository.VCS.Secret,
-			AccessToken:  repository.AccessToken,
-			RefreshToken: repository.RefreshToken,
-			Refresher:    server.refreshToken(ctx, repository.ID),
-		},
+		oauthCtx,
 		repository.VCS.InstanceURL,
 		repository.ExternalID,
 		latestSchemaFile,
-		branch,
+		commitBranch,
 	)
 
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch latest schema file %s after update: %w", latestSchemaFile, err)
+		return nil, fmt.Errorf("failed to fetch latest schema file %s after update: %w", latestSchemaFile, err)
+	}
+	result := &writeBackResult{
+		commitID: schemaFileMeta.LastCommitID,
+		branch:   commitBranch,
+	}
+
+	if mergeRequestBranch != "" {
+		result.mergeRequest, err = provider.CreateMergeRequest(
+			ctx,
+			oauthCtx,
+			repository.VCS.InstanceURL,
+			repository.ExternalID,
+			vcs.MergeRequestCreate{
+				Title:              commitTitle,
+				Description:        commitBody,
+				SourceBranch:       mergeRequestBranch,
+				TargetBranch:       branch,
+				RemoveSourceBranch: true,
+			},
+		)
+		// The merge request opened by a previous attempt of the task already includes the new commit on the branch.
+		if err != nil && common.ErrorCode(err) != common.Conflict {
+			return nil, fmt.Errorf("failed to create merge request for the latest schema after applying migration %s to %q: %w", mi.Version, mi.Database, err)
+		}
 	}
-	return schemaFileMeta.LastCommitID, nil
+	return result, nil
 }
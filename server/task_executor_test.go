@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/vcs"
+)
+
+func TestNeedWriteBackLatestSchema(t *testing.T) {
+	repository := &api.Repository{
+		BaseDirectory:      "bytebase",
+		FilePathTemplate:   "{{VERSION}}__{{DB_NAME}}__{{TYPE}}__{{DESCRIPTION}}.sql",
+		SchemaPathTemplate: ".{{DB_NAME}}__LATEST.sql",
+	}
+	tests := []struct {
+		name         string
+		repository   *api.Repository
+		vcsPushEvent *vcs.PushEvent
+		want         bool
+	}{
+		{
+			name:       "no push event",
+			repository: repository,
+			want:       false,
+		},
+		{
+			name: "no schema path template",
+			repository: &api.Repository{
+				BaseDirectory:    "bytebase",
+				FilePathTemplate: "{{VERSION}}__{{DB_NAME}}__{{TYPE}}__{{DESCRIPTION}}.sql",
+			},
+			vcsPushEvent: &vcs.PushEvent{
+				Ref:        "refs/heads/main",
+				FileCommit: vcs.FileCommit{Added: "bytebase/v1__db1__migrate__create_t.sql"},
+			},
+			want: false,
+		},
+		{
+			name:       "migration file pushed to branch",
+			repository: repository,
+			vcsPushEvent: &vcs.PushEvent{
+				Ref:        "refs/heads/main",
+				FileCommit: vcs.FileCommit{Added: "bytebase/v1__db1__migrate__create_t.sql"},
+			},
+			want: true,
+		},
+		{
+			name:       "schema file pushed to branch",
+			repository: repository,
+			vcsPushEvent: &vcs.PushEvent{
+				Ref:        "refs/heads/main",
+				FileCommit: vcs.FileCommit{Added: "bytebase/.db1__LATEST.sql"},
+			},
+			want: false,
+		},
+		{
+			name:       "migration file deployed from tag",
+			repository: repository,
+			vcsPushEvent: &vcs.PushEvent{
+				Ref:        "refs/tags/v1.0.0",
+				FileCommit: vcs.FileCommit{Added: "bytebase/v1__db1__migrate__create_t.sql"},
+			},
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		got := needWriteBackLatestSchema(test.repository, test.vcsPushEvent)
+		if got != test.want {
+			t.Errorf("%s: needWriteBackLatestSchema() got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestWriteBackMergeRequestBranch(t *testing.T) {
+	mi := &db.MigrationInfo{
+		Database: "db1",
+		Version:  "v1",
+	}
+	tests := []struct {
+		writeBackMode api.RepositoryWriteBackMode
+		want          string
+	}{
+		{
+			writeBackMode: api.RepositoryWriteBackCommit,
+			want:          "",
+		},
+		{
+			writeBackMode: api.RepositoryWriteBackMergeRequest,
+			want:          "bytebase/db1-v1-task-101",
+		},
+	}
+
+	for _, test := range tests {
+		got := writeBackMergeRequestBranch(&api.Repository{WriteBackMode: test.writeBackMode}, mi, 101)
+		if got != test.want {
+			t.Errorf("writeBackMergeRequestBranch(%q) got %q, want %q", test.writeBackMode, got, test.want)
+		}
+	}
+}
+
+// fakeWriteBackVCS is the vcs type of fakeWriteBackProvider.
+const fakeWriteBackVCS vcs.Type = "FAKE_WRITE_BACK"
+
+// fakeWriteBackProvider is an in-memory repository with a single file, keeping the last commit of the file on each branch.
+type fakeWriteBackProvider struct {
+	vcs.Provider
+	commitCount   int
+	branches      map[string]string
+	mergeRequests map[string]bool
+}
+
+func (p *fakeWriteBackProvider) ReadFileMeta(_ context.Context, _ common.OauthContext, _ string, _ string, _ string, branch string) (*vcs.FileMeta, error) {
+	commitID, ok := p.branches[branch]
+	if !ok || commitID == "" {
+		return nil, common.Errorf(common.NotFound, fmt.Errorf("file not found on branch %s", branch))
+	}
+	return &vcs.FileMeta{LastCommitID: commitID}, nil
+}
+
+func (p *fakeWriteBackProvider) CreateFile(_ context.Context, _ common.OauthContext, _ string, _ string, _ string, fileCommit vcs.FileCommitCreate) error {
+	if p.branches[fileCommit.Branch] != "" {
+		return fmt.Errorf("file already exists on branch %s", fileCommit.Branch)
+	}
+	p.commit(fileCommit.Branch)
+	return nil
+}
+
+func (p *fakeWriteBackProvider) OverwriteFile(_ context.Context, _ common.OauthContext, _ string, _ string, _ string, fileCommit vcs.FileCommitCreate) error {
+	if p.branches[fileCommit.Branch] != fileCommit.LastCommitID {
+		return fmt.Errorf("last commit %s is not the head of branch %s", fileCommit.LastCommitID, fileCommit.Branch)
+	}
+	p.commit(fileCommit.Branch)
+	return nil
+}
+
+func (p *fakeWriteBackProvider) CreateBranch(_ context.Context, _ common.OauthContext, _ string, _ string, branch string, baseBranch string) error {
+	if _, ok := p.branches[branch]; ok {
+		return common.Errorf(common.Conflict, fmt.Errorf("branch %s already exists", branch))
+	}
+	p.branches[branch] = p.branches[baseBranch]
+	return nil
+}
+
+func (p *fakeWriteBackProvider) CreateMergeRequest(_ context.Context, _ common.OauthContext, _ string, _ string, mergeRequestCreate vcs.MergeRequestCreate) (*vcs.MergeRequest, error) {
+	if p.mergeRequests[mergeRequestCreate.SourceBranch] {
+		return nil, common.Errorf(common.Conflict, fmt.Errorf("merge request from %s already exists", mergeRequestCreate.SourceBranch))
+	}
+	p.mergeRequests[mergeRequestCreate.SourceBranch] = true
+	return &vcs.MergeRequest{ID: "1", URL: "https://vcs.example.com/merge_requests/1"}, nil
+}
+
+func (p *fakeWriteBackProvider) commit(branch string) {
+	p.commitCount++
+	p.branches[branch] = fmt.Sprintf("commit%d", p.commitCount)
+}
+
+func TestWriteBackLatestSchemaRetry(t *testing.T) {
+	provider := &fakeWriteBackProvider{
+		branches:      map[string]string{"main": ""},
+		mergeRequests: map[string]bool{},
+	}
+	vcs.Register(fakeWriteBackVCS, func(vcs.ProviderConfig) vcs.Provider {
+		return provider
+	})
+
+	s := &Server{l: zap.NewNop()}
+	repository := &api.Repository{
+		VCS:           &api.VCS{Type: fakeWriteBackVCS},
+		WriteBackMode: api.RepositoryWriteBackMergeRequest,
+	}
+	mi := &db.MigrationInfo{
+		Database: "db1",
+		Version:  "v1",
+	}
+	mergeRequestBranch := writeBackMergeRequestBranch(repository, mi, 101)
+	// The task is retried, e.g. after the task failed to be updated following the first write back.
+	for i, wantCommitID := range []string{"commit1", "commit2"} {
+		result, err := writeBackLatestSchema(context.Background(), s, repository, &vcs.PushEvent{}, mi, "main", mergeRequestBranch, "bytebase/.db1__LATEST.sql", "CREATE TABLE t (id INT);", "")
+		if err != nil {
+			t.Fatalf("attempt %d: writeBackLatestSchema() got error: %v", i+1, err)
+		}
+		if result.commitID != wantCommitID || result.branch != mergeRequestBranch {
+			t.Errorf("attempt %d: writeBackLatestSchema() got commit %q on branch %q, want commit %q on branch %q", i+1, result.commitID, result.branch, wantCommitID, mergeRequestBranch)
+		}
+		if gotMergeRequest := result.mergeRequest != nil; gotMergeRequest != (i == 0) {
+			t.Errorf("attempt %d: writeBackLatestSchema() got merge request %v, want %v", i+1, gotMergeRequest, i == 0)
+		}
+	}
+	if provider.branches["main"] != "" {
+		t.Errorf("writeBackLatestSchema() committed to the base branch, got commit %q", provider.branches["main"])
+	}
+}
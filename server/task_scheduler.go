@@ -9,7 +9,6 @@ import (
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
-	"github.com/bytebase/bytebase/plugin/db"
 	"go.uber.org/zap"
 )
 
@@ -140,7 +139,41 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 							delete(runningTasks, task.ID)
 							mu.Unlock()
 						}()
-						done, result, err := executor.RunOnce(ctx, s.server, task)
+						timeout, err := s.getTaskExecutionTimeout(ctx, task)
+						if err != nil {
+							s.l.Error("Failed to get the max execution time of task, will retry",
+								zap.Int("id", task.ID),
+								zap.String("name", task.Name),
+								zap.Error(err),
+							)
+							return
+						}
+						// The execution is canceled once the task is canceled or exceeds the max execution time.
+						var executionCtx context.Context
+						var cancel context.CancelFunc
+						if timeout > 0 {
+							executionCtx, cancel = context.WithTimeout(ctx, timeout)
+						} else {
+							executionCtx, cancel = context.WithCancel(ctx)
+						}
+						unregister := s.server.executionRegistry.registerTask(task.ID, cancel)
+						done, result, err := executor.RunOnce(executionCtx, s.server, task)
+						executionErr := executionCtx.Err()
+						unregister()
+						cancel()
+						if executionErr == context.Canceled {
+							// The task status has been changed to CANCELED by the cancel request.
+							s.l.Info("Canceled task execution",
+								zap.Int("id", task.ID),
+								zap.String("name", task.Name),
+								zap.String("type", string(task.Type)),
+							)
+							return
+						}
+						if executionErr == context.DeadlineExceeded && err != nil {
+							done = true
+							err = fmt.Errorf("task execution exceeded the max execution time %v of the environment: %w", timeout, err)
+						}
 						if done {
 							if err == nil {
 								bytes, err := json.Marshal(*result)
@@ -222,6 +255,19 @@ func (s *TaskScheduler) Run(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
+// getTaskExecutionTimeout returns the max execution time of the schema and data update task by the policy of the environment.
+// It returns 0 if there is no max execution time to enforce.
+func (s *TaskScheduler) getTaskExecutionTimeout(ctx context.Context, task *api.Task) (time.Duration, error) {
+	if task.Type != api.TaskDatabaseSchemaUpdate && task.Type != api.TaskDatabaseDataUpdate {
+		return 0, nil
+	}
+	policy, err := s.server.PolicyService.GetMaxExecutionTimePolicy(ctx, task.Instance.EnvironmentID)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(policy.TaskSeconds) * time.Second, nil
+}
+
 // Register will register a task executor.
 func (s *TaskScheduler) Register(taskType string, executor TaskExecutor) {
 	if executor == nil {
@@ -274,25 +320,29 @@ func (s *TaskScheduler) ScheduleIfNeeded(ctx context.Context, task *api.Task) (*
 		if instance == nil {
 			return nil, fmt.Errorf("instance ID not found %v", task.InstanceID)
 		}
-		// For now we only supported MySQL dialect syntax and compatibility check
-		if instance.Engine == db.MySQL || instance.Engine == db.TiDB {
-			pass, err = s.server.passCheck(ctx, s.server, task, api.TaskCheckDatabaseStatementSyntax)
+		for _, checkType := range []api.TaskCheckType{api.TaskCheckDatabaseStatementSyntax, api.TaskCheckDatabaseStatementCompatibility} {
+			if !s.server.isStatementCheckApplicable(checkType, instance) {
+				continue
+			}
+			pass, err = s.server.passCheck(ctx, s.server, task, checkType)
 			if err != nil {
 				return nil, err
 			}
 			if !pass {
 				return task, nil
 			}
+		}
 
-			if s.server.feature(api.FeatureBackwardCompatibilty) {
-				pass, err = s.server.passCheck(ctx, s.server, task, api.TaskCheckDatabaseStatementCompatibility)
-				if err != nil {
-					return nil, err
-				}
-				if !pass {
-					return task, nil
-				}
-			}
+		pass, err = s.server.passSQLReviewCheck(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		if !pass {
+			s.l.Debug("Task is waiting for SQL review check to pass",
+				zap.Int("task_id", task.ID),
+				zap.String("task_name", task.Name),
+			)
+			return task, nil
 		}
 	}
 	updatedTask, err := s.server.changeTaskStatus(ctx, task, api.TaskRunning, api.SystemBotID)
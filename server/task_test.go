@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+
+	// Register the ClickHouse driver, which doesn't cancel the running statement on the database server.
+	_ "github.com/bytebase/bytebase/plugin/db/clickhouse"
+)
+
+func TestValidateTaskCancel(t *testing.T) {
+	s := &Server{
+		InstanceService: &fakeInstanceService{
+			instanceList: []*api.Instance{
+				{ID: 1, Name: "sqlite", Engine: db.SQLite},
+				{ID: 2, Name: "clickhouse", Engine: db.ClickHouse},
+			},
+		},
+	}
+	tests := []struct {
+		task     *api.Task
+		wantCode common.Code
+	}{
+		{
+			task:     &api.Task{ID: 101, Name: "Update sqlite", InstanceID: 1},
+			wantCode: common.Ok,
+		},
+		{
+			task:     &api.Task{ID: 102, Name: "Update clickhouse", InstanceID: 2},
+			wantCode: common.Invalid,
+		},
+	}
+
+	for _, test := range tests {
+		err := s.validateTaskCancel(context.Background(), test.task)
+		if code := common.ErrorCode(err); code != test.wantCode {
+			t.Errorf("validateTaskCancel(%q) got error %v, want code %v", test.task.Name, err, test.wantCode)
+		}
+	}
+}
@@ -6,8 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,7 +16,10 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/differ"
 	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/gitea"
+	"github.com/bytebase/bytebase/plugin/vcs/github"
 	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
@@ -23,8 +27,30 @@ import (
 
 var (
 	gitLabWebhookPath = "hook/gitlab"
+	gitHubWebhookPath = "hook/github"
+	giteaWebhookPath  = "hook/gitea"
 )
 
+// webhookCommit is a commit of the push event, converted from the VCS specific webhook payload.
+type webhookCommit struct {
+	// fileCommit is the commit info, the added file is set per committed file.
+	fileCommit   vcs.FileCommit
+	addedList    []string
+	modifiedList []string
+	removedList  []string
+}
+
+// pushEventScope is the scope of the databases receiving the changes of the push event.
+type pushEventScope struct {
+	// environmentList is the names of the environments matched by the branch filter rules, nil means all environments.
+	environmentList []string
+	// skipApplied skips the databases which have applied the migration version or have a pending task created from the file.
+	// This is set for the tag push since the tag contains all the migration files instead of the changed ones.
+	skipApplied bool
+	// migrationVersionCache caches the applied migration versions of the databases checked within the push event.
+	migrationVersionCache *migrationVersionCache
+}
+
 func (s *Server) registerWebhookRoutes(g *echo.Group) {
 	g.POST("/gitlab/:id", func(c echo.Context) error {
 		ctx := context.Background()
@@ -39,194 +65,1366 @@ func (s *Server) registerWebhookRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted push event").SetInternal(err)
 		}
 
-		// This shouldn't happen as we only setup webhook to receive push event, just in case.
-		if pushEvent.ObjectKind != gitlab.WebhookPush {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want push", pushEvent.ObjectKind))
+		// This shouldn't happen as we only setup webhook to receive push, tag push and merge request events, just in case.
+		if pushEvent.ObjectKind != gitlab.WebhookPush && pushEvent.ObjectKind != gitlab.WebhookTagPush && pushEvent.ObjectKind != gitlab.WebhookMergeRequest {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want push, tag_push or merge_request", pushEvent.ObjectKind))
+		}
+
+		repository, err := s.findWebhookRepository(ctx, c.Param("id"))
+		if err != nil {
+			return err
+		}
+
+		if c.Request().Header.Get("X-Gitlab-Token") != repository.WebhookSecretToken {
+			return echo.NewHTTPError(http.StatusBadRequest, "Secret token mismatch")
+		}
+
+		if strconv.Itoa(pushEvent.Project.ID) != repository.ExternalID {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project mismatch, got %d, want %s", pushEvent.Project.ID, repository.ExternalID))
+		}
+
+		if pushEvent.ObjectKind == gitlab.WebhookMergeRequest {
+			mergeRequestEvent := &gitlab.WebhookMergeRequestEvent{}
+			if err := json.Unmarshal(b, mergeRequestEvent); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformatted merge request event").SetInternal(err)
+			}
+			mergeRequest := mergeRequestEvent.ObjectAttributes
+			// Only review when the merge request is opened or has new commits.
+			if mergeRequest.Action != "open" && mergeRequest.Action != "reopen" && (mergeRequest.Action != "update" || mergeRequest.OldRev == "") {
+				return c.String(http.StatusOK, fmt.Sprintf("Ignored merge request event, action %q", mergeRequest.Action))
+			}
+			if !matchBranchFilter(repository.BranchFilter, mergeRequest.TargetBranch) {
+				return c.String(http.StatusOK, fmt.Sprintf("Ignored merge request event, target branch %q doesn't match the branch filter %q", mergeRequest.TargetBranch, repository.BranchFilter))
+			}
+
+			message, err := s.reviewMergeRequest(ctx, repository, strconv.Itoa(mergeRequest.IID), mergeRequest.LastCommit.ID)
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, message)
+		}
+
+		// GitLab only filters the push events by a single branch pattern, so we check the branch filter rules here.
+		scope, err := matchPushEventScope(repository.BranchFilter, pushEvent.Ref)
+		if err != nil {
+			return c.String(http.StatusOK, fmt.Sprintf("Ignored push event, %s", err.Error()))
+		}
+		scope.migrationVersionCache = s.newMigrationVersionCache()
+		vcsPushEvent := vcs.PushEvent{
+			VCSType:            repository.VCS.Type,
+			BaseDirectory:      repository.BaseDirectory,
+			Ref:                pushEvent.Ref,
+			RepositoryID:       strconv.Itoa(pushEvent.Project.ID),
+			RepositoryURL:      pushEvent.Project.WebURL,
+			RepositoryFullPath: pushEvent.Project.FullPath,
+			AuthorName:         pushEvent.AuthorName,
+		}
+
+		if pushEvent.ObjectKind == gitlab.WebhookTagPush {
+			if repository.Project.TenantMode == api.TenantModeTenant {
+				return c.String(http.StatusOK, "Ignored tag push event, deploying from tags isn't supported for tenant mode project")
+			}
+			// The checkout SHA is empty if the tag is deleted.
+			if pushEvent.CheckoutSHA == "" {
+				return c.String(http.StatusOK, fmt.Sprintf("Ignored tag push event, tag %q is deleted", pushEvent.Ref))
+			}
+			tag, err := vcs.Tag(pushEvent.Ref)
+			if err != nil {
+				return c.String(http.StatusOK, fmt.Sprintf("Ignored tag push event, %s", err.Error()))
+			}
+			commit, err := s.composeTagCommit(ctx, repository, tag, pushEvent.CheckoutSHA, fmt.Sprintf("%s/-/tags/%s", pushEvent.Project.WebURL, url.PathEscape(tag)), pushEvent.Message, pushEvent.AuthorName, scope)
+			if err != nil {
+				return err
+			}
+			if len(commit.addedList) == 0 {
+				return c.String(http.StatusOK, fmt.Sprintf("Ignored tag push event, no pending migration file in tag %q", tag))
+			}
+
+			scope.skipApplied = true
+			createdMessageList, err := s.createIssueFromPushEvent(ctx, repository, vcsPushEvent, []webhookCommit{*commit}, scope)
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, strings.Join(createdMessageList, "\n"))
+		}
+
+		var commitList []webhookCommit
+		for _, commit := range pushEvent.CommitList {
+			createdTime, err := time.Parse(time.RFC3339, commit.Timestamp)
+			if err != nil {
+				s.l.Warn("Failed to parse commit timestamp.", zap.String("commit", commit.ID), zap.String("timestamp", commit.Timestamp), zap.Error(err))
+			}
+			commitList = append(commitList, webhookCommit{
+				fileCommit: vcs.FileCommit{
+					ID:         commit.ID,
+					Title:      commit.Title,
+					Message:    commit.Message,
+					CreatedTs:  createdTime.Unix(),
+					URL:        commit.URL,
+					AuthorName: commit.Author.Name,
+				},
+				addedList:    commit.AddedList,
+				modifiedList: commit.ModifiedList,
+				removedList:  commit.RemovedList,
+			})
 		}
 
-		webhookEndpointID := c.Param("id")
-		repositoryFind := &api.RepositoryFind{
-			WebhookEndpointID: &webhookEndpointID,
+		createdMessageList, err := s.createIssueFromPushEvent(ctx, repository, vcsPushEvent, commitList, scope)
+		if err != nil {
+			return err
 		}
-		repository, err := s.RepositoryService.FindRepository(ctx, repositoryFind)
+
+		return c.String(http.StatusOK, strings.Join(createdMessageList, "\n"))
+	})
+
+	g.POST("/github/:id", func(c echo.Context) error {
+		ctx := context.Background()
+		var b []byte
+		b, err := io.ReadAll(c.Request().Body)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to respond webhook event for endpoint: %v", webhookEndpointID)).SetInternal(err)
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
 		}
-		if repository == nil {
-			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Endpoint not found: %v", webhookEndpointID))
+
+		repository, err := s.findWebhookRepository(ctx, c.Param("id"))
+		if err != nil {
+			return err
 		}
 
-		if err := s.composeRepositoryRelationship(ctx, repository); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch repository relationship: %v", repository.Name)).SetInternal(err)
+		// GitHub signs the payload with the secret instead of sending the secret itself.
+		if !github.ValidateWebhookSignature(c.Request().Header.Get(github.SignatureHeader), repository.WebhookSecretToken, b) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Signature mismatch")
 		}
-		if repository.VCS == nil {
-			err := fmt.Errorf("VCS not found for ID: %v", repository.VCSID)
-			return echo.NewHTTPError(http.StatusInternalServerError, err).SetInternal(err)
+
+		// GitHub sends a ping event when the webhook is created.
+		eventType := github.WebhookType(c.Request().Header.Get(github.EventHeader))
+		if eventType == github.WebhookPing {
+			return c.String(http.StatusOK, "OK")
 		}
+		if eventType == github.WebhookPullRequest {
+			pullRequestEvent := &github.WebhookPullRequestEvent{}
+			if err := json.Unmarshal(b, pullRequestEvent); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformatted pull request event").SetInternal(err)
+			}
+			if !strings.EqualFold(pullRequestEvent.Repository.FullName, repository.ExternalID) {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Repository mismatch, got %s, want %s", pullRequestEvent.Repository.FullName, repository.ExternalID))
+			}
+			// Only review when the pull request is opened or has new commits.
+			if pullRequestEvent.Action != "opened" && pullRequestEvent.Action != "reopened" && pullRequestEvent.Action != "synchronize" {
+				return c.String(http.StatusOK, fmt.Sprintf("Ignored pull request event, action %q", pullRequestEvent.Action))
+			}
+			pullRequest := pullRequestEvent.PullRequest
+			if !matchBranchFilter(repository.BranchFilter, pullRequest.Base.Ref) {
+				return c.String(http.StatusOK, fmt.Sprintf("Ignored pull request event, base branch %q doesn't match the branch filter %q", pullRequest.Base.Ref, repository.BranchFilter))
+			}
 
-		if c.Request().Header.Get("X-Gitlab-Token") != repository.WebhookSecretToken {
-			return echo.NewHTTPError(http.StatusBadRequest, "Secret token mismatch")
+			message, err := s.reviewMergeRequest(ctx, repository, strconv.Itoa(pullRequestEvent.Number), pullRequest.Head.SHA)
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, message)
+		}
+		// This shouldn't happen as we only setup webhook to receive push and pull request events, just in case.
+		if eventType != github.WebhookPush {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want push or pull_request", eventType))
 		}
 
-		if strconv.Itoa(pushEvent.Project.ID) != repository.ExternalID {
-			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Project mismatch, got %d, want %s", pushEvent.Project.ID, repository.ExternalID))
+		pushEvent := &github.WebhookPushEvent{}
+		if err := json.Unmarshal(b, pushEvent); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted push event").SetInternal(err)
 		}
 
-		createdMessageList := []string{}
+		if !strings.EqualFold(pushEvent.Repository.FullName, repository.ExternalID) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Repository mismatch, got %s, want %s", pushEvent.Repository.FullName, repository.ExternalID))
+		}
+
+		// GitHub doesn't filter the push events by branch, so we ignore the pushes to other branches and tags here.
+		scope, err := matchPushEventScope(repository.BranchFilter, pushEvent.Ref)
+		if err != nil {
+			return c.String(http.StatusOK, fmt.Sprintf("Ignored push event, %s", err.Error()))
+		}
+		scope.migrationVersionCache = s.newMigrationVersionCache()
+
+		var commitList []webhookCommit
 		for _, commit := range pushEvent.CommitList {
-			for _, added := range commit.AddedList {
-				if !strings.HasPrefix(added, repository.BaseDirectory) {
-					s.l.Debug("Ignored committed file, not under base directory.", zap.String("file", added), zap.String("base_directory", repository.BaseDirectory))
-					continue
-				}
+			createdTime, err := time.Parse(time.RFC3339, commit.Timestamp)
+			if err != nil {
+				s.l.Warn("Failed to parse commit timestamp.", zap.String("commit", commit.ID), zap.String("timestamp", commit.Timestamp), zap.Error(err))
+			}
+			commitList = append(commitList, webhookCommit{
+				fileCommit: vcs.FileCommit{
+					ID:         commit.ID,
+					Title:      commit.Title(),
+					Message:    commit.Message,
+					CreatedTs:  createdTime.Unix(),
+					URL:        commit.URL,
+					AuthorName: commit.Author.Name,
+				},
+				addedList:    commit.AddedList,
+				modifiedList: commit.ModifiedList,
+				removedList:  commit.RemovedList,
+			})
+		}
 
-				createdTime, err := time.Parse(time.RFC3339, commit.Timestamp)
-				if err != nil {
-					s.l.Warn("Ignored committed file, failed to parse commit timestamp.", zap.String("file", added), zap.String("timestamp", commit.Timestamp), zap.Error(err))
-				}
+		createdMessageList, err := s.createIssueFromPushEvent(ctx, repository, vcs.PushEvent{
+			VCSType:            repository.VCS.Type,
+			BaseDirectory:      repository.BaseDirectory,
+			Ref:                pushEvent.Ref,
+			RepositoryID:       pushEvent.Repository.FullName,
+			RepositoryURL:      pushEvent.Repository.HTMLURL,
+			RepositoryFullPath: pushEvent.Repository.FullName,
+			AuthorName:         pushEvent.Pusher.Name,
+		}, commitList, scope)
+		if err != nil {
+			return err
+		}
 
-				// Ignored the schema file we auto generated to the repository.
-				if isSkipGeneratedSchemaFile(repository, added, s.l) {
-					continue
-				}
+		return c.String(http.StatusOK, strings.Join(createdMessageList, "\n"))
+	})
 
-				vcsPushEvent := vcs.PushEvent{
-					VCSType:            repository.VCS.Type,
-					BaseDirectory:      repository.BaseDirectory,
-					Ref:                pushEvent.Ref,
-					RepositoryID:       strconv.Itoa(pushEvent.Project.ID),
-					RepositoryURL:      pushEvent.Project.WebURL,
-					RepositoryFullPath: pushEvent.Project.FullPath,
-					AuthorName:         pushEvent.AuthorName,
-					FileCommit: vcs.FileCommit{
-						ID:         commit.ID,
-						Title:      commit.Title,
-						Message:    commit.Message,
-						CreatedTs:  createdTime.Unix(),
-						URL:        commit.URL,
-						AuthorName: commit.Author.Name,
-						Added:      added,
-					},
-				}
+	g.POST("/gitea/:id", func(c echo.Context) error {
+		ctx := context.Background()
+		var b []byte
+		b, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Failed to read webhook request").SetInternal(err)
+		}
 
-				// Create a WARNING project activity if committed file is ignored
-				var createIgnoredFileActivity = func(err error) {
-					s.l.Warn("Ignored committed file", zap.String("file", added), zap.Error(err))
-					bytes, marshalErr := json.Marshal(api.ActivityProjectRepositoryPushPayload{
-						VCSPushEvent: vcsPushEvent,
-					})
-					if marshalErr != nil {
-						s.l.Warn("Failed to construct project activity payload to record ignored repository committed file", zap.Error(marshalErr))
-						return
-					}
-
-					activityCreate := &api.ActivityCreate{
-						CreatorID:   api.SystemBotID,
-						ContainerID: repository.ProjectID,
-						Type:        api.ActivityProjectRepositoryPush,
-						Level:       api.ActivityWarn,
-						Comment:     fmt.Sprintf("Ignored committed file %q, %s.", added, err.Error()),
-						Payload:     string(bytes),
-					}
-					_, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{})
-					if err != nil {
-						s.l.Warn("Failed to create project activity to record ignored repository committed file", zap.Error(err))
-					}
-				}
+		repository, err := s.findWebhookRepository(ctx, c.Param("id"))
+		if err != nil {
+			return err
+		}
 
-				mi, err := db.ParseMigrationInfo(added, filepath.Join(repository.BaseDirectory, repository.FilePathTemplate))
-				if err != nil {
-					createIgnoredFileActivity(err)
-					continue
-				}
+		if !gitea.ValidateWebhookSignature(c.Request().Header.Get(gitea.SignatureHeader), repository.WebhookSecretToken, b) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Signature mismatch")
+		}
 
-				// Retrieve sql by reading the file content
-				content, err := vcs.Get(vcs.GitLabSelfHost, vcs.ProviderConfig{Logger: s.l}).ReadFile(
-					ctx,
-					common.OauthContext{
-						ClientID:     repository.VCS.ApplicationID,
-						ClientSecret: repository.VCS.Secret,
-						AccessToken:  repository.AccessToken,
-						RefreshToken: repository.RefreshToken,
-						Refresher:    s.refreshToken(ctx, repository.ID),
-					},
-					repository.VCS.InstanceURL,
-					repository.ExternalID,
-					added,
-					commit.ID,
-				)
-				if err != nil {
-					createIgnoredFileActivity(err)
-					continue
-				}
+		eventType := gitea.WebhookType(c.Request().Header.Get(gitea.EventHeader))
+		if eventType == gitea.WebhookPullRequest {
+			pullRequestEvent := &gitea.WebhookPullRequestEvent{}
+			if err := json.Unmarshal(b, pullRequestEvent); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformatted pull request event").SetInternal(err)
+			}
+			if !strings.EqualFold(pullRequestEvent.Repository.FullName, repository.ExternalID) {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Repository mismatch, got %s, want %s", pullRequestEvent.Repository.FullName, repository.ExternalID))
+			}
+			// Only review when the pull request is opened or has new commits.
+			if pullRequestEvent.Action != "opened" && pullRequestEvent.Action != "reopened" && pullRequestEvent.Action != "synchronized" {
+				return c.String(http.StatusOK, fmt.Sprintf("Ignored pull request event, action %q", pullRequestEvent.Action))
+			}
+			// Gitea only applies the branch filter to the push events.
+			pullRequest := pullRequestEvent.PullRequest
+			if !matchBranchFilter(repository.BranchFilter, pullRequest.Base.Ref) {
+				return c.String(http.StatusOK, fmt.Sprintf("Ignored pull request event, base branch %q doesn't match the branch filter %q", pullRequest.Base.Ref, repository.BranchFilter))
+			}
 
-				// Create schema update issue.
-				var createContext string
-				if repository.Project.TenantMode == api.TenantModeTenant {
-					if !s.feature(api.FeatureMultiTenancy) {
-						return echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
-					}
-					createContext, err = s.createTenantSchemaUpdateIssue(ctx, repository, mi, vcsPushEvent, commit, added, content)
-				} else {
-					createContext, err = s.createSchemaUpdateIssue(ctx, repository, mi, vcsPushEvent, commit, added, content)
-				}
-				if err != nil {
-					createIgnoredFileActivity(err)
-					continue
-				}
+			message, err := s.reviewMergeRequest(ctx, repository, strconv.Itoa(pullRequestEvent.Number), pullRequest.Head.SHA)
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, message)
+		}
+		// This shouldn't happen as we only setup webhook to receive push and pull request events, just in case.
+		if eventType != gitea.WebhookPush {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid webhook event type, got %s, want push or pull_request", eventType))
+		}
 
-				issueType := api.IssueDatabaseSchemaUpdate
-				if mi.Type == db.Data {
-					issueType = api.IssueDatabaseDataUpdate
-				}
-				issueCreate := &api.IssueCreate{
-					ProjectID:     repository.ProjectID,
-					Name:          commit.Title,
-					Type:          issueType,
-					Description:   commit.Message,
-					AssigneeID:    api.SystemBotID,
-					CreateContext: createContext,
-				}
-				issue, err := s.createIssue(ctx, issueCreate, api.SystemBotID)
-				if err != nil {
-					errMsg := "Failed to create schema update issue"
-					if issueType == api.IssueDatabaseDataUpdate {
-						errMsg = "Failed to create data update issue"
-					}
-					return echo.NewHTTPError(http.StatusInternalServerError, errMsg).SetInternal(err)
-				}
+		pushEvent := &gitea.WebhookPushEvent{}
+		if err := json.Unmarshal(b, pushEvent); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformatted push event").SetInternal(err)
+		}
 
-				createdMessageList = append(createdMessageList, fmt.Sprintf("Created issue %q on adding %s", issue.Name, added))
+		if !strings.EqualFold(pushEvent.Repository.FullName, repository.ExternalID) {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Repository mismatch, got %s, want %s", pushEvent.Repository.FullName, repository.ExternalID))
+		}
 
-				// Create a project activity after successfully creating the issue as the result of the push event
-				bytes, err := json.Marshal(api.ActivityProjectRepositoryPushPayload{
-					VCSPushEvent: vcsPushEvent,
-					IssueID:      issue.ID,
-					IssueName:    issue.Name,
-				})
-				if err != nil {
-					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
-				}
+		// Gitea only filters the push events by a single branch pattern, so we check the branch filter rules here.
+		scope, err := matchPushEventScope(repository.BranchFilter, pushEvent.Ref)
+		if err != nil {
+			return c.String(http.StatusOK, fmt.Sprintf("Ignored push event, %s", err.Error()))
+		}
+		scope.migrationVersionCache = s.newMigrationVersionCache()
 
-				activityCreate := &api.ActivityCreate{
-					CreatorID:   api.SystemBotID,
-					ContainerID: repository.ProjectID,
-					Type:        api.ActivityProjectRepositoryPush,
-					Level:       api.ActivityInfo,
-					Comment:     fmt.Sprintf("Created issue %q.", issue.Name),
-					Payload:     string(bytes),
-				}
-				if _, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
-					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create project activity after creating issue from repository push event: %d", issue.ID)).SetInternal(err)
-				}
+		var commitList []webhookCommit
+		for _, commit := range pushEvent.CommitList {
+			createdTime, err := time.Parse(time.RFC3339, commit.Timestamp)
+			if err != nil {
+				s.l.Warn("Failed to parse commit timestamp.", zap.String("commit", commit.ID), zap.String("timestamp", commit.Timestamp), zap.Error(err))
 			}
+			commitList = append(commitList, webhookCommit{
+				fileCommit: vcs.FileCommit{
+					ID:         commit.ID,
+					Title:      commit.Title(),
+					Message:    commit.Message,
+					CreatedTs:  createdTime.Unix(),
+					URL:        commit.URL,
+					AuthorName: commit.Author.Name,
+				},
+				addedList:    commit.AddedList,
+				modifiedList: commit.ModifiedList,
+				removedList:  commit.RemovedList,
+			})
+		}
+
+		authorName := pushEvent.Pusher.FullName
+		if authorName == "" {
+			authorName = pushEvent.Pusher.Login
+		}
+		createdMessageList, err := s.createIssueFromPushEvent(ctx, repository, vcs.PushEvent{
+			VCSType:            repository.VCS.Type,
+			BaseDirectory:      repository.BaseDirectory,
+			Ref:                pushEvent.Ref,
+			RepositoryID:       pushEvent.Repository.FullName,
+			RepositoryURL:      pushEvent.Repository.HTMLURL,
+			RepositoryFullPath: pushEvent.Repository.FullName,
+			AuthorName:         authorName,
+		}, commitList, scope)
+		if err != nil {
+			return err
 		}
 
 		return c.String(http.StatusOK, strings.Join(createdMessageList, "\n"))
 	})
 }
 
-func (s *Server) createSchemaUpdateIssue(ctx context.Context, repository *api.Repository, mi *db.MigrationInfo, vcsPushEvent vcs.PushEvent, commit gitlab.WebhookCommit, added string, statement string) (string, error) {
+// findWebhookRepository finds the repository with its VCS by the webhook endpoint ID.
+// The returned error is an *echo.HTTPError.
+func (s *Server) findWebhookRepository(ctx context.Context, webhookEndpointID string) (*api.Repository, error) {
+	repositoryFind := &api.RepositoryFind{
+		WebhookEndpointID: &webhookEndpointID,
+	}
+	repository, err := s.RepositoryService.FindRepository(ctx, repositoryFind)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to respond webhook event for endpoint: %v", webhookEndpointID)).SetInternal(err)
+	}
+	if repository == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Endpoint not found: %v", webhookEndpointID))
+	}
+
+	if err := s.composeRepositoryRelationship(ctx, repository); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch repository relationship: %v", repository.Name)).SetInternal(err)
+	}
+	if repository.VCS == nil {
+		err := fmt.Errorf("VCS not found for ID: %v", repository.VCSID)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err).SetInternal(err)
+	}
+	return repository, nil
+}
+
+// createIssueFromPushEvent creates an issue for each committed migration file and schema file in the push event.
+// The pushEvent carries the repository info, and its file commit is filled per committed file.
+// The scope limits the databases receiving the changes.
+// Returns the messages of the created issues, the returned error is an *echo.HTTPError.
+func (s *Server) createIssueFromPushEvent(ctx context.Context, repository *api.Repository, pushEvent vcs.PushEvent, commitList []webhookCommit, scope pushEventScope) ([]string, error) {
+	createdMessageList := []string{}
+	// The migration files are bundled into one issue per database after all the commits in the push are handled.
+	var migrationFileList []committedMigrationFile
+	// The rollback files are attached to the tasks of their migrations after all the migration files in the push are handled,
+	// because the migration file may be committed along with its rollback file.
+	var rollbackFileList []committedRollbackFile
+	for _, commit := range commitList {
+		// Ignore the latest schema file written back by Bytebase after migration.
+		if strings.Contains(commit.fileCommit.Message, writeBackCommitMarker) {
+			s.l.Debug("Ignored commit, auto-generated by Bytebase.", zap.String("commit", commit.fileCommit.ID))
+			continue
+		}
+
+		// Besides the added migration files, a modified schema file is applied as a declarative schema migration.
+		fileList := append([]string{}, commit.addedList...)
+		for _, modified := range commit.modifiedList {
+			if isSchemaFile(repository, modified) {
+				fileList = append(fileList, modified)
+				continue
+			}
+			vcsPushEvent := pushEvent
+			vcsPushEvent.FileCommit = commit.fileCommit
+			vcsPushEvent.FileCommit.Added = modified
+			if mi, ok := s.parseCommittedMigrationFile(repository, modified); ok && mi.Type == db.Rollback {
+				rollbackFileList = append(rollbackFileList, committedRollbackFile{mi: mi, vcsPushEvent: vcsPushEvent})
+				continue
+			}
+			messageList, err := s.updateIssueFromModifiedFile(ctx, repository, scope.migrationVersionCache, vcsPushEvent, modified)
+			if err != nil {
+				return nil, err
+			}
+			createdMessageList = append(createdMessageList, messageList...)
+		}
+		for _, removed := range commit.removedList {
+			if isSchemaFile(repository, removed) {
+				continue
+			}
+			vcsPushEvent := pushEvent
+			vcsPushEvent.FileCommit = commit.fileCommit
+			vcsPushEvent.FileCommit.Added = removed
+			s.checkRemovedFile(ctx, repository, scope.migrationVersionCache, vcsPushEvent, removed)
+		}
+		for _, added := range fileList {
+			if !strings.HasPrefix(added, repository.BaseDirectory) {
+				s.l.Debug("Ignored committed file, not under base directory.", zap.String("file", added), zap.String("base_directory", repository.BaseDirectory))
+				continue
+			}
+
+			declarative := isSchemaFile(repository, added)
+
+			vcsPushEvent := pushEvent
+			vcsPushEvent.FileCommit = commit.fileCommit
+			vcsPushEvent.FileCommit.Added = added
+
+			var mi *db.MigrationInfo
+			var err error
+			if declarative {
+				mi, err = db.ParseSchemaFileInfo(added, filepath.Join(repository.BaseDirectory, repository.SchemaPathTemplate))
+			} else {
+				mi, err = db.ParseMigrationInfo(added, filepath.Join(repository.BaseDirectory, repository.FilePathTemplate))
+			}
+			if err != nil {
+				s.createIgnoredFileActivity(ctx, repository, vcsPushEvent, err)
+				continue
+			}
+			if mi.Type == db.Rollback {
+				rollbackFileList = append(rollbackFileList, committedRollbackFile{mi: mi, vcsPushEvent: vcsPushEvent})
+				continue
+			}
+
+			// Retrieve sql by reading the file content
+			content, err := vcs.Get(repository.VCS.Type, vcs.ProviderConfig{Logger: s.l}).ReadFile(
+				ctx,
+				common.OauthContext{
+					ClientID:     repository.VCS.ApplicationID,
+					ClientSecret: repository.VCS.Secret,
+					AccessToken:  repository.AccessToken,
+					RefreshToken: repository.RefreshToken,
+					Refresher:    s.refreshToken(ctx, repository.ID),
+				},
+				repository.VCS.InstanceURL,
+				repository.ExternalID,
+				added,
+				commit.fileCommit.ID,
+			)
+			if err != nil {
+				s.createIgnoredFileActivity(ctx, repository, vcsPushEvent, err)
+				continue
+			}
+
+			if !declarative {
+				migrationFileList = append(migrationFileList, committedMigrationFile{
+					mi:           mi,
+					vcsPushEvent: vcsPushEvent,
+					statement:    content,
+				})
+				continue
+			}
+
+			// Create schema update issue.
+			createContext, err := s.createDeclarativeSchemaUpdateIssue(ctx, repository, scope, mi, vcsPushEvent, added, content)
+			if err != nil {
+				s.createIgnoredFileActivity(ctx, repository, vcsPushEvent, err)
+				continue
+			}
+			issue, err := s.createPushEventIssue(ctx, repository, vcsPushEvent, &api.IssueCreate{
+				ProjectID:     repository.ProjectID,
+				Name:          commit.fileCommit.Title,
+				Type:          api.IssueDatabaseSchemaUpdate,
+				Description:   commit.fileCommit.Message,
+				AssigneeID:    api.SystemBotID,
+				CreateContext: createContext,
+			})
+			if err != nil {
+				return nil, err
+			}
+			createdMessageList = append(createdMessageList, fmt.Sprintf("Created issue %q on changing schema file %s", issue.Name, added))
+		}
+	}
+
+	for _, fileList := range groupMigrationFileList(migrationFileList) {
+		message, err := s.createMigrationFileIssue(ctx, repository, scope, fileList)
+		if err != nil {
+			return nil, err
+		}
+		if message != "" {
+			createdMessageList = append(createdMessageList, message)
+		}
+	}
+
+	for _, rollbackFile := range rollbackFileList {
+		messageList, err := s.attachRollbackFile(ctx, repository, rollbackFile.vcsPushEvent, rollbackFile.mi)
+		if err != nil {
+			return nil, err
+		}
+		createdMessageList = append(createdMessageList, messageList...)
+	}
+
+	return createdMessageList, nil
+}
+
+// committedMigrationFile is a committed migration file with its migration info and statement.
+type committedMigrationFile struct {
+	mi           *db.MigrationInfo
+	vcsPushEvent vcs.PushEvent
+	statement    string
+}
+
+// migrationFileGroupKey is the key grouping the migration files applied to the same databases.
+type migrationFileGroupKey struct {
+	// environment is the lower-case environment name, which is matched case-insensitively. It's empty if the path template has no environment.
+	environment string
+	database    string
+}
+
+// groupMigrationFileList groups the migration files by environment and database in the order of their first appearance,
+// and sorts the migration files of each group by version. The files for the databases of the same name in different
+// environments are grouped separately, since they're applied to different databases.
+// The versions are compared by the numeric segments, since they aren't required to be zero-padded.
+func groupMigrationFileList(migrationFileList []committedMigrationFile) [][]committedMigrationFile {
+	var groupList [][]committedMigrationFile
+	groupIndexByKey := make(map[migrationFileGroupKey]int)
+	for _, file := range migrationFileList {
+		key := migrationFileGroupKey{
+			environment: strings.ToLower(file.mi.Environment),
+			database:    file.mi.Database,
+		}
+		index, ok := groupIndexByKey[key]
+		if !ok {
+			index = len(groupList)
+			groupIndexByKey[key] = index
+			groupList = append(groupList, nil)
+		}
+		groupList[index] = append(groupList[index], file)
+	}
+	for _, fileList := range groupList {
+		sort.SliceStable(fileList, func(i, j int) bool {
+			return migrationVersionLess(fileList[i].mi.Version, fileList[j].mi.Version)
+		})
+	}
+	return groupList
+}
+
+// createMigrationFileIssue creates a single issue for the migration files of a database, or of a database in an environment, committed in the push,
+// whose pipeline performs one task per migration file in the version order, so a failed migration blocks the later ones.
+// The file which can't be applied is ignored with a WARNING project activity.
+// Returns the message of the created issue, or empty if all the files are ignored. The returned error is an *echo.HTTPError.
+func (s *Server) createMigrationFileIssue(ctx context.Context, repository *api.Repository, scope pushEventScope, fileList []committedMigrationFile) (string, error) {
+	var m *api.UpdateSchemaContext
+	var issueFileList []committedMigrationFile
+	for _, file := range fileList {
+		vcsPushEvent := file.vcsPushEvent
+		var detailList []*api.UpdateSchemaDetail
+		var err error
+		if repository.Project.TenantMode == api.TenantModeTenant {
+			if !s.feature(api.FeatureMultiTenancy) {
+				return "", echo.NewHTTPError(http.StatusForbidden, api.FeatureMultiTenancy.AccessErrorMessage())
+			}
+			detailList, err = s.composeTenantSchemaUpdateDetailList(scope, file.mi, file.statement)
+		} else {
+			detailList, err = s.composeSchemaUpdateDetailList(ctx, repository, scope, file.mi, vcsPushEvent.FileCommit.Added, file.statement)
+		}
+		if err != nil {
+			s.createIgnoredFileActivity(ctx, repository, vcsPushEvent, err)
+			continue
+		}
+		for _, detail := range detailList {
+			detail.MigrationType = file.mi.Type
+			detail.VCSPushEvent = &vcsPushEvent
+		}
+
+		if m == nil {
+			m = &api.UpdateSchemaContext{
+				MigrationType: file.mi.Type,
+				VCSPushEvent:  &vcsPushEvent,
+			}
+		} else if m.MigrationType != file.mi.Type {
+			// The issue bundling different types of migrations is a schema update issue.
+			m.MigrationType = db.Migrate
+		}
+		m.UpdateSchemaDetailList = append(m.UpdateSchemaDetailList, detailList...)
+		issueFileList = append(issueFileList, file)
+	}
+	if len(issueFileList) == 0 {
+		return "", nil
+	}
+
+	createContext, err := json.Marshal(m)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct issue create context payload").SetInternal(err)
+	}
+	issueType := api.IssueDatabaseSchemaUpdate
+	if m.MigrationType == db.Data {
+		issueType = api.IssueDatabaseDataUpdate
+	}
+	firstCommit := issueFileList[0].vcsPushEvent.FileCommit
+	lastPushEvent := issueFileList[len(issueFileList)-1].vcsPushEvent
+	sameCommit := true
+	var addedList []string
+	for _, file := range issueFileList {
+		addedList = append(addedList, file.vcsPushEvent.FileCommit.Added)
+		if file.vcsPushEvent.FileCommit.ID != firstCommit.ID {
+			sameCommit = false
+		}
+	}
+	// Name the issue after the commit if all the files are committed in the same commit.
+	name, description := firstCommit.Title, firstCommit.Message
+	if !sameCommit {
+		name = fmt.Sprintf("Apply %d migration files to database %q", len(issueFileList), issueFileList[0].mi.Database)
+		if environment := issueFileList[0].mi.Environment; environment != "" {
+			name = fmt.Sprintf("Apply %d migration files to database %q in environment %q", len(issueFileList), issueFileList[0].mi.Database, environment)
+		}
+		description = lastPushEvent.FileCommit.Message
+	}
+	issue, err := s.createPushEventIssue(ctx, repository, lastPushEvent, &api.IssueCreate{
+		ProjectID:     repository.ProjectID,
+		Name:          name,
+		Type:          issueType,
+		Description:   description,
+		AssigneeID:    api.SystemBotID,
+		CreateContext: string(createContext),
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Created issue %q on adding %s", issue.Name, strings.Join(addedList, ", ")), nil
+}
+
+// createPushEventIssue creates the issue as the result of the push event, along with a project activity.
+// The returned error is an *echo.HTTPError.
+func (s *Server) createPushEventIssue(ctx context.Context, repository *api.Repository, vcsPushEvent vcs.PushEvent, issueCreate *api.IssueCreate) (*api.Issue, error) {
+	issue, err := s.createIssue(ctx, issueCreate, api.SystemBotID)
+	if err != nil {
+		errMsg := "Failed to create schema update issue"
+		if issueCreate.Type == api.IssueDatabaseDataUpdate {
+			errMsg = "Failed to create data update issue"
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, errMsg).SetInternal(err)
+	}
+
+	// Create a project activity after successfully creating the issue as the result of the push event
+	bytes, err := json.Marshal(api.ActivityProjectRepositoryPushPayload{
+		VCSPushEvent: vcsPushEvent,
+		IssueID:      issue.ID,
+		IssueName:    issue.Name,
+	})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to construct activity payload").SetInternal(err)
+	}
+
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: repository.ProjectID,
+		Type:        api.ActivityProjectRepositoryPush,
+		Level:       api.ActivityInfo,
+		Comment:     fmt.Sprintf("Created issue %q.", issue.Name),
+		Payload:     string(bytes),
+	}
+	if _, err = s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to create project activity after creating issue from repository push event: %d", issue.ID)).SetInternal(err)
+	}
+	return issue, nil
+}
+
+// createIgnoredFileActivity creates a WARNING project activity if the committed file is ignored.
+func (s *Server) createIgnoredFileActivity(ctx context.Context, repository *api.Repository, vcsPushEvent vcs.PushEvent, err error) {
+	added := vcsPushEvent.FileCommit.Added
+	s.l.Warn("Ignored committed file", zap.String("file", added), zap.Error(err))
+	s.createRepositoryPushWarningActivity(ctx, repository, vcsPushEvent, fmt.Sprintf("Ignored committed file %q, %s.", added, err.Error()))
+}
+
+// composeTagCommit composes the commit of the pushed tag, whose added files are the migration files in the tag pending to apply within the scope.
+// Unlike the branch push event, the tag push event doesn't carry the changed files, so we list all the migration files in the tag instead.
+// The returned error is an *echo.HTTPError.
+func (s *Server) composeTagCommit(ctx context.Context, repository *api.Repository, tag string, commitID string, tagURL string, message string, authorName string, scope pushEventScope) (*webhookCommit, error) {
+	fileList, err := vcs.Get(repository.VCS.Type, vcs.ProviderConfig{Logger: s.l}).ListRepositoryFiles(
+		ctx,
+		common.OauthContext{
+			ClientID:     repository.VCS.ApplicationID,
+			ClientSecret: repository.VCS.Secret,
+			AccessToken:  repository.AccessToken,
+			RefreshToken: repository.RefreshToken,
+			Refresher:    s.refreshToken(ctx, repository.ID),
+		},
+		repository.VCS.InstanceURL,
+		repository.ExternalID,
+		repository.BaseDirectory,
+		commitID,
+	)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to list files of tag %q", tag)).SetInternal(err)
+	}
+
+	scope.skipApplied = true
+	type pendingFile struct {
+		file    string
+		version string
+	}
+	var pendingFileList []pendingFile
+	for _, file := range fileList {
+		// The schema file at the tag is written back by Bytebase or applied as the declarative schema migration on the branch.
+		if isSchemaFile(repository, file) {
+			continue
+		}
+		mi, ok := s.parseCommittedMigrationFile(repository, file)
+		if !ok {
+			continue
+		}
+		if _, err := s.findScopeDatabaseList(ctx, repository, scope, mi, file); err != nil {
+			s.l.Debug("Ignored file in tag, no pending database.", zap.String("tag", tag), zap.String("file", file), zap.Error(err))
+			continue
+		}
+		pendingFileList = append(pendingFileList, pendingFile{
+			file:    file,
+			version: mi.Version,
+		})
+	}
+	// Sort the files in the version order, so the migrations are applied in order.
+	sort.SliceStable(pendingFileList, func(i, j int) bool {
+		return migrationVersionLess(pendingFileList[i].version, pendingFileList[j].version)
+	})
+
+	commit := &webhookCommit{
+		fileCommit: vcs.FileCommit{
+			ID:         commitID,
+			Title:      fmt.Sprintf("Deploy tag %s", tag),
+			Message:    message,
+			CreatedTs:  time.Now().Unix(),
+			URL:        tagURL,
+			AuthorName: authorName,
+		},
+	}
+	for _, pendingFile := range pendingFileList {
+		commit.addedList = append(commit.addedList, pendingFile.file)
+	}
+	return commit, nil
+}
+
+// migrationVersionLess returns true if the migration version a is before b. The versions are compared segment by segment,
+// where the digit segments are compared numerically, so "2" is before "10" even if the versions aren't zero-padded.
+func migrationVersionLess(a, b string) bool {
+	aList, bList := splitMigrationVersion(a), splitMigrationVersion(b)
+	for i := 0; i < len(aList) && i < len(bList); i++ {
+		x, y := aList[i], bList[i]
+		if x == y {
+			continue
+		}
+		if isDigitSegment(x) && isDigitSegment(y) {
+			x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+			if len(x) != len(y) {
+				return len(x) < len(y)
+			}
+			if x != y {
+				return x < y
+			}
+			// The same number with different zero paddings, e.g. "01" and "1", fall back to the text order.
+			return aList[i] < bList[i]
+		}
+		return x < y
+	}
+	return len(aList) < len(bList)
+}
+
+// splitMigrationVersion splits the migration version into the segments of digits and non-digits, e.g. "v1.10" to "v", "1", ".", "10".
+func splitMigrationVersion(version string) []string {
+	var segmentList []string
+	start := 0
+	for i := 1; i <= len(version); i++ {
+		if i == len(version) || isDigit(version[i]) != isDigit(version[start]) {
+			segmentList = append(segmentList, version[start:i])
+			start = i
+		}
+	}
+	return segmentList
+}
+
+func isDigitSegment(segment string) bool {
+	return segment != "" && isDigit(segment[0])
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// createRepositoryPushWarningActivity creates a WARNING project activity for the committed file of the push event.
+func (s *Server) createRepositoryPushWarningActivity(ctx context.Context, repository *api.Repository, vcsPushEvent vcs.PushEvent, comment string) {
+	bytes, err := json.Marshal(api.ActivityProjectRepositoryPushPayload{
+		VCSPushEvent: vcsPushEvent,
+	})
+	if err != nil {
+		s.l.Warn("Failed to construct project activity payload to record repository committed file", zap.Error(err))
+		return
+	}
+
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: repository.ProjectID,
+		Type:        api.ActivityProjectRepositoryPush,
+		Level:       api.ActivityWarn,
+		Comment:     comment,
+		Payload:     string(bytes),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{}); err != nil {
+		s.l.Warn("Failed to create project activity to record repository committed file", zap.Error(err))
+	}
+}
+
+// updateIssueFromModifiedFile updates the statement of the pending tasks created from the modified migration file.
+// The migration can't be changed once applied, so we create a WARNING project activity if it has been applied to any database.
+// Returns the messages of the updated tasks, the returned error is an *echo.HTTPError.
+func (s *Server) updateIssueFromModifiedFile(ctx context.Context, repository *api.Repository, cache *migrationVersionCache, vcsPushEvent vcs.PushEvent, modified string) ([]string, error) {
+	mi, ok := s.parseCommittedMigrationFile(repository, modified)
+	if !ok {
+		return nil, nil
+	}
+
+	if appliedDatabaseList := s.findMigrationAppliedDatabaseList(ctx, repository, cache, mi, modified); len(appliedDatabaseList) > 0 {
+		s.createRepositoryPushWarningActivity(ctx, repository, vcsPushEvent, fmt.Sprintf("Modified migration file %q has already been applied to %s, the change won't be applied.", modified, strings.Join(appliedDatabaseList, ", ")))
+	}
+
+	pendingTaskList, err := s.findPendingTaskListByFile(ctx, repository, modified)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find pending tasks for modified file %q", modified)).SetInternal(err)
+	}
+	// The tasks created from other branches or tags don't receive the changes on this ref.
+	var refPendingTaskList []pendingFileTask
+	for _, pendingTask := range pendingTaskList {
+		if pendingTask.vcsPushEvent.Ref == vcsPushEvent.Ref {
+			refPendingTaskList = append(refPendingTaskList, pendingTask)
+		}
+	}
+	if len(refPendingTaskList) == 0 {
+		s.l.Debug("Ignored modified file, no pending task created from it on the ref.", zap.String("file", modified), zap.String("ref", vcsPushEvent.Ref))
+		return nil, nil
+	}
+
+	statement, err := vcs.Get(repository.VCS.Type, vcs.ProviderConfig{Logger: s.l}).ReadFile(
+		ctx,
+		common.OauthContext{
+			ClientID:     repository.VCS.ApplicationID,
+			ClientSecret: repository.VCS.Secret,
+			AccessToken:  repository.AccessToken,
+			RefreshToken: repository.RefreshToken,
+			Refresher:    s.refreshToken(ctx, repository.ID),
+		},
+		repository.VCS.InstanceURL,
+		repository.ExternalID,
+		modified,
+		vcsPushEvent.FileCommit.ID,
+	)
+	if err != nil {
+		s.createRepositoryPushWarningActivity(ctx, repository, vcsPushEvent, fmt.Sprintf("Ignored modified file %q, %s.", modified, err.Error()))
+		return nil, nil
+	}
+
+	var messageList []string
+	for _, pendingTask := range refPendingTaskList {
+		updated, err := s.patchTaskStatementFromPushEvent(ctx, pendingTask, vcsPushEvent, statement)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update task %q for modified file %q", pendingTask.task.Name, modified)).SetInternal(err)
+		}
+		if updated {
+			messageList = append(messageList, fmt.Sprintf("Updated task %q of issue %q on modifying %s", pendingTask.task.Name, pendingTask.issue.Name, modified))
+		}
+	}
+	return messageList, nil
+}
+
+// committedRollbackFile is a committed rollback file with its migration info.
+type committedRollbackFile struct {
+	mi           *db.MigrationInfo
+	vcsPushEvent vcs.PushEvent
+}
+
+// attachRollbackFile sets the content of the rollback file as the rollback statement of the pending tasks created from
+// the migration file of the same version on the same ref. The rollback file doesn't create any issue on its own,
+// so we create a WARNING project activity if there is no such pending task.
+// Returns the messages of the updated tasks, the returned error is an *echo.HTTPError.
+func (s *Server) attachRollbackFile(ctx context.Context, repository *api.Repository, vcsPushEvent vcs.PushEvent, mi *db.MigrationInfo) ([]string, error) {
+	file := vcsPushEvent.FileCommit.Added
+	pendingTaskList, err := s.findPendingTaskListByRollbackFile(ctx, repository, mi)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find pending tasks for rollback file %q", file)).SetInternal(err)
+	}
+	var refPendingTaskList []pendingFileTask
+	for _, pendingTask := range pendingTaskList {
+		if pendingTask.vcsPushEvent.Ref == vcsPushEvent.Ref {
+			refPendingTaskList = append(refPendingTaskList, pendingTask)
+		}
+	}
+	if len(refPendingTaskList) == 0 {
+		s.createRepositoryPushWarningActivity(ctx, repository, vcsPushEvent, fmt.Sprintf("Ignored rollback file %q, no pending task of migration version %s for database %q.", file, mi.Version, mi.Database))
+		return nil, nil
+	}
+
+	rollbackStatement, err := vcs.Get(repository.VCS.Type, vcs.ProviderConfig{Logger: s.l}).ReadFile(
+		ctx,
+		common.OauthContext{
+			ClientID:     repository.VCS.ApplicationID,
+			ClientSecret: repository.VCS.Secret,
+			AccessToken:  repository.AccessToken,
+			RefreshToken: repository.RefreshToken,
+			Refresher:    s.refreshToken(ctx, repository.ID),
+		},
+		repository.VCS.InstanceURL,
+		repository.ExternalID,
+		file,
+		vcsPushEvent.FileCommit.ID,
+	)
+	if err != nil {
+		s.createRepositoryPushWarningActivity(ctx, repository, vcsPushEvent, fmt.Sprintf("Ignored rollback file %q, %s.", file, err.Error()))
+		return nil, nil
+	}
+
+	var messageList []string
+	for _, pendingTask := range refPendingTaskList {
+		updated, err := s.patchTaskRollbackStatement(ctx, pendingTask, vcsPushEvent, rollbackStatement)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to attach rollback file %q to task %q", file, pendingTask.task.Name)).SetInternal(err)
+		}
+		if updated {
+			messageList = append(messageList, fmt.Sprintf("Attached rollback statement to task %q of issue %q from %s", pendingTask.task.Name, pendingTask.issue.Name, file))
+		}
+	}
+	return messageList, nil
+}
+
+// checkRemovedFile creates a WARNING project activity if the removed migration file has been applied or still has pending tasks,
+// because removing the file neither reverts the applied migration nor cancels the pending tasks.
+func (s *Server) checkRemovedFile(ctx context.Context, repository *api.Repository, cache *migrationVersionCache, vcsPushEvent vcs.PushEvent, removed string) {
+	mi, ok := s.parseCommittedMigrationFile(repository, removed)
+	if !ok {
+		return
+	}
+	// The rollback file is never applied on its own.
+	if mi.Type == db.Rollback {
+		return
+	}
+
+	if appliedDatabaseList := s.findMigrationAppliedDatabaseList(ctx, repository, cache, mi, removed); len(appliedDatabaseList) > 0 {
+		s.createRepositoryPushWarningActivity(ctx, repository, vcsPushEvent, fmt.Sprintf("Removed migration file %q has already been applied to %s, removing the file doesn't revert the migration.", removed, strings.Join(appliedDatabaseList, ", ")))
+	}
+
+	pendingTaskList, err := s.findPendingTaskListByFile(ctx, repository, removed)
+	if err != nil {
+		s.l.Warn("Failed to find pending tasks for removed file", zap.String("file", removed), zap.Error(err))
+		return
+	}
+	for _, pendingTask := range pendingTaskList {
+		s.createRepositoryPushWarningActivity(ctx, repository, vcsPushEvent, fmt.Sprintf("Removed migration file %q still has pending task %q in issue %q.", removed, pendingTask.task.Name, pendingTask.issue.Name))
+	}
+}
+
+// parseCommittedMigrationFile parses the migration info of the committed file, returns false if it's not a migration file under the base directory.
+func (s *Server) parseCommittedMigrationFile(repository *api.Repository, file string) (*db.MigrationInfo, bool) {
+	if !strings.HasPrefix(file, repository.BaseDirectory) {
+		s.l.Debug("Ignored committed file, not under base directory.", zap.String("file", file), zap.String("base_directory", repository.BaseDirectory))
+		return nil, false
+	}
+	mi, err := db.ParseMigrationInfo(file, filepath.Join(repository.BaseDirectory, repository.FilePathTemplate))
+	if err != nil {
+		s.l.Debug("Ignored committed file, not a migration file.", zap.String("file", file), zap.Error(err))
+		return nil, false
+	}
+	return mi, true
+}
+
+// findMigrationAppliedDatabaseList returns the databases which have applied the migration version of the committed file.
+// The database whose migration history can't be fetched is skipped.
+func (s *Server) findMigrationAppliedDatabaseList(ctx context.Context, repository *api.Repository, cache *migrationVersionCache, mi *db.MigrationInfo, file string) []string {
+	databaseList, err := s.findCommittedFileDatabaseList(ctx, repository, mi, file)
+	if err != nil {
+		s.l.Debug("Failed to find databases of committed file", zap.String("file", file), zap.Error(err))
+		return nil
+	}
+
+	var appliedDatabaseList []string
+	for _, database := range databaseList {
+		applied, err := cache.isApplied(ctx, database, mi.Version)
+		if err != nil {
+			s.l.Warn("Failed to find migration history of committed file",
+				zap.String("file", file),
+				zap.String("database", database.Name),
+				zap.Error(err),
+			)
+			continue
+		}
+		if applied {
+			appliedDatabaseList = append(appliedDatabaseList, fmt.Sprintf("database %q in environment %q", database.Name, database.Instance.Environment.Name))
+		}
+	}
+	return appliedDatabaseList
+}
+
+// migrationVersionCache caches the applied migration versions of the databases within a push event.
+// A push or a tag may carry many migration files of the same databases, so the migration history of each database
+// is fetched once with a single driver connection instead of once per file.
+type migrationVersionCache struct {
+	// findAppliedVersionSet finds the applied migration versions of the database.
+	findAppliedVersionSet func(ctx context.Context, database *api.Database) (map[string]bool, error)
+	// versionSetMap is the applied migration versions keyed by the database ID.
+	versionSetMap map[int]map[string]bool
+}
+
+func (s *Server) newMigrationVersionCache() *migrationVersionCache {
+	return &migrationVersionCache{
+		findAppliedVersionSet: s.findAppliedMigrationVersionSet,
+		versionSetMap:         make(map[int]map[string]bool),
+	}
+}
+
+// isApplied returns true if the database has applied the migration version.
+func (c *migrationVersionCache) isApplied(ctx context.Context, database *api.Database, version string) (bool, error) {
+	versionSet, ok := c.versionSetMap[database.ID]
+	if !ok {
+		var err error
+		versionSet, err = c.findAppliedVersionSet(ctx, database)
+		if err != nil {
+			return false, err
+		}
+		c.versionSetMap[database.ID] = versionSet
+	}
+	return versionSet[version], nil
+}
+
+// findAppliedMigrationVersionSet returns the migration versions the database has applied.
+func (s *Server) findAppliedMigrationVersionSet(ctx context.Context, database *api.Database) (map[string]bool, error) {
+	driver, err := getDatabaseDriver(ctx, database.Instance, database.Name, s.l)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+
+	historyList, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{
+		Database: &database.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	versionSet := make(map[string]bool)
+	for _, history := range historyList {
+		if history.Status == db.Done {
+			versionSet[history.Version] = true
+		}
+	}
+	return versionSet, nil
+}
+
+// pendingFileTask is a pending task created from a committed file, with the open issue containing it.
+type pendingFileTask struct {
+	issue *api.Issue
+	task  *api.Task
+	// vcsPushEvent is the push event creating the task.
+	vcsPushEvent *vcs.PushEvent
+}
+
+// findPendingTaskListByFile finds the not yet applied tasks of the open issues in the project, which are created from the committed file.
+func (s *Server) findPendingTaskListByFile(ctx context.Context, repository *api.Repository, file string) ([]pendingFileTask, error) {
+	return s.findPendingTaskList(ctx, repository, &file, func(added string) bool {
+		return added == file
+	})
+}
+
+// findPendingTaskListByRollbackFile finds the not yet applied tasks of the open issues in the project, which are created from
+// the migration file reverted by the rollback file, i.e. the migration file of the same version, database and environment.
+func (s *Server) findPendingTaskListByRollbackFile(ctx context.Context, repository *api.Repository, rollback *db.MigrationInfo) ([]pendingFileTask, error) {
+	filePathTemplate := filepath.Join(repository.BaseDirectory, repository.FilePathTemplate)
+	return s.findPendingTaskList(ctx, repository, nil, func(added string) bool {
+		mi, err := db.ParseMigrationInfo(added, filePathTemplate)
+		if err != nil {
+			return false
+		}
+		return mi.Type != db.Rollback && mi.Version == rollback.Version && mi.Database == rollback.Database && mi.Environment == rollback.Environment
+	})
+}
+
+// findPendingTaskList finds the not yet applied tasks of the open issues in the project, whose committed file matches.
+// The tasks are found by the committed file in the store if the file is given.
+func (s *Server) findPendingTaskList(ctx context.Context, repository *api.Repository, file *string, match func(added string) bool) ([]pendingFileTask, error) {
+	issueStatusList := []api.IssueStatus{api.IssueOpen}
+	taskStatusList := []api.TaskStatus{api.TaskPending, api.TaskPendingApproval, api.TaskFailed}
+	taskList, err := s.TaskService.FindTaskList(ctx, &api.TaskFind{
+		ProjectID:        &repository.ProjectID,
+		StatusList:       &taskStatusList,
+		IssueStatusList:  &issueStatusList,
+		VCSPushEventFile: file,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending tasks of project %d: %w", repository.ProjectID, err)
+	}
+
+	var pendingTaskList []pendingFileTask
+	// issueMap is the issue keyed by the pipeline ID.
+	issueMap := make(map[int]*api.Issue)
+	for _, task := range taskList {
+		var vcsPushEvent *vcs.PushEvent
+		switch task.Type {
+		case api.TaskDatabaseSchemaUpdate:
+			payload := &api.TaskDatabaseSchemaUpdatePayload{}
+			if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+				return nil, fmt.Errorf("invalid database schema update payload of task %d: %w", task.ID, err)
+			}
+			vcsPushEvent = payload.VCSPushEvent
+		case api.TaskDatabaseDataUpdate:
+			payload := &api.TaskDatabaseDataUpdatePayload{}
+			if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+				return nil, fmt.Errorf("invalid database data update payload of task %d: %w", task.ID, err)
+			}
+			vcsPushEvent = payload.VCSPushEvent
+		}
+		if vcsPushEvent == nil || vcsPushEvent.RepositoryID != repository.ExternalID || !match(vcsPushEvent.FileCommit.Added) {
+			continue
+		}
+		issue, ok := issueMap[task.PipelineID]
+		if !ok {
+			issue, err = s.IssueService.FindIssue(ctx, &api.IssueFind{
+				PipelineID: &task.PipelineID,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to find issue of pipeline %d: %w", task.PipelineID, err)
+			}
+			issueMap[task.PipelineID] = issue
+		}
+		if issue == nil {
+			continue
+		}
+		pendingTaskList = append(pendingTaskList, pendingFileTask{
+			issue:        issue,
+			task:         task,
+			vcsPushEvent: vcsPushEvent,
+		})
+	}
+	return pendingTaskList, nil
+}
+
+// patchTaskStatementFromPushEvent updates the statement and the push event of the pending task to the modified file,
+// then creates the statement update activity and triggers the statement checks.
+// Returns false if the statement isn't changed.
+func (s *Server) patchTaskStatementFromPushEvent(ctx context.Context, pendingTask pendingFileTask, vcsPushEvent vcs.PushEvent, statement string) (bool, error) {
+	task := pendingTask.task
+	oldStatement, payloadStr, err := composeTaskStatementPayload(task, vcsPushEvent, statement)
+	if err != nil {
+		return false, err
+	}
+	if oldStatement == statement {
+		return false, nil
+	}
+
+	updatedTask, err := s.TaskService.PatchTask(ctx, &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Payload:   &payloadStr,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to patch task %d: %w", task.ID, err)
+	}
+	if err := s.composeTaskRelationship(ctx, updatedTask); err != nil {
+		return false, fmt.Errorf("failed to fetch updated task %d relationship: %w", task.ID, err)
+	}
+
+	activityPayload, err := json.Marshal(api.ActivityPipelineTaskStatementUpdatePayload{
+		TaskID:       updatedTask.ID,
+		OldStatement: oldStatement,
+		NewStatement: statement,
+		TaskName:     task.Name,
+		IssueName:    pendingTask.issue.Name,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal task statement update activity payload: %w", err)
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: pendingTask.issue.ID,
+		Type:        api.ActivityPipelineTaskStatementUpdate,
+		Payload:     string(activityPayload),
+		Level:       api.ActivityInfo,
+		Comment:     fmt.Sprintf("Updated statement on modifying %s in commit %s.", vcsPushEvent.FileCommit.Added, vcsPushEvent.FileCommit.ID),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{
+		issue: pendingTask.issue,
+	}); err != nil {
+		return false, fmt.Errorf("failed to create activity after updating task %d statement: %w", task.ID, err)
+	}
+
+	s.triggerTaskStatementCheck(ctx, updatedTask, statement)
+	return true, nil
+}
+
+// composeTaskStatementPayload composes the payload of the task with the statement and the push event of the modified file.
+// Returns the old statement of the task and the new payload.
+func composeTaskStatementPayload(task *api.Task, vcsPushEvent vcs.PushEvent, statement string) (string, string, error) {
+	var oldStatement string
+	var payload []byte
+	var err error
+	switch task.Type {
+	case api.TaskDatabaseSchemaUpdate:
+		taskPayload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
+			return "", "", fmt.Errorf("invalid database schema update payload of task %d: %w", task.ID, err)
+		}
+		oldStatement = taskPayload.Statement
+		taskPayload.Statement = statement
+		taskPayload.VCSPushEvent = &vcsPushEvent
+		payload, err = json.Marshal(taskPayload)
+	case api.TaskDatabaseDataUpdate:
+		taskPayload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
+			return "", "", fmt.Errorf("invalid database data update payload of task %d: %w", task.ID, err)
+		}
+		oldStatement = taskPayload.Statement
+		taskPayload.Statement = statement
+		taskPayload.VCSPushEvent = &vcsPushEvent
+		payload, err = json.Marshal(taskPayload)
+	default:
+		return "", "", fmt.Errorf("task %d of type %s doesn't have statement", task.ID, task.Type)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal payload of task %d: %w", task.ID, err)
+	}
+	return oldStatement, string(payload), nil
+}
+
+// patchTaskRollbackStatement updates the rollback statement of the pending task to the content of the rollback file,
+// then creates a comment activity on the issue. Returns false if the rollback statement isn't changed.
+func (s *Server) patchTaskRollbackStatement(ctx context.Context, pendingTask pendingFileTask, vcsPushEvent vcs.PushEvent, rollbackStatement string) (bool, error) {
+	task := pendingTask.task
+	var oldRollbackStatement string
+	var payload []byte
+	var err error
+	switch task.Type {
+	case api.TaskDatabaseSchemaUpdate:
+		taskPayload := &api.TaskDatabaseSchemaUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
+			return false, fmt.Errorf("invalid database schema update payload of task %d: %w", task.ID, err)
+		}
+		oldRollbackStatement = taskPayload.RollbackStatement
+		taskPayload.RollbackStatement = rollbackStatement
+		payload, err = json.Marshal(taskPayload)
+	case api.TaskDatabaseDataUpdate:
+		taskPayload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), taskPayload); err != nil {
+			return false, fmt.Errorf("invalid database data update payload of task %d: %w", task.ID, err)
+		}
+		oldRollbackStatement = taskPayload.RollbackStatement
+		taskPayload.RollbackStatement = rollbackStatement
+		payload, err = json.Marshal(taskPayload)
+	default:
+		return false, fmt.Errorf("task %d of type %s doesn't have rollback statement", task.ID, task.Type)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal payload of task %d: %w", task.ID, err)
+	}
+	if oldRollbackStatement == rollbackStatement {
+		return false, nil
+	}
+
+	payloadStr := string(payload)
+	if _, err := s.TaskService.PatchTask(ctx, &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Payload:   &payloadStr,
+	}); err != nil {
+		return false, fmt.Errorf("failed to patch task %d: %w", task.ID, err)
+	}
+
+	activityPayload, err := json.Marshal(api.ActivityIssueCommentCreatePayload{
+		IssueName: pendingTask.issue.Name,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal issue comment activity payload: %w", err)
+	}
+	activityCreate := &api.ActivityCreate{
+		CreatorID:   api.SystemBotID,
+		ContainerID: pendingTask.issue.ID,
+		Type:        api.ActivityIssueCommentCreate,
+		Level:       api.ActivityInfo,
+		Comment:     fmt.Sprintf("Attached rollback statement of task %q from %s in commit %s.", task.Name, vcsPushEvent.FileCommit.Added, vcsPushEvent.FileCommit.ID),
+		Payload:     string(activityPayload),
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, activityCreate, &ActivityMeta{
+		issue: pendingTask.issue,
+	}); err != nil {
+		return false, fmt.Errorf("failed to create activity after updating task %d rollback statement: %w", task.ID, err)
+	}
+	return true, nil
+}
+
+// composeSchemaUpdateDetailList composes the update schema details applying the committed migration file to the databases within the scope.
+func (s *Server) composeSchemaUpdateDetailList(ctx context.Context, repository *api.Repository, scope pushEventScope, mi *db.MigrationInfo, added string, statement string) ([]*api.UpdateSchemaDetail, error) {
+	filteredDatabaseList, err := s.findScopeDatabaseList(ctx, repository, scope, mi, added)
+	if err != nil {
+		return nil, err
+	}
+
+	var detailList []*api.UpdateSchemaDetail
+	for _, database := range filteredDatabaseList {
+		detailList = append(detailList,
+			&api.UpdateSchemaDetail{
+				DatabaseID: database.ID,
+				Statement:  statement,
+			})
+	}
+	return detailList, nil
+}
+
+// createDeclarativeSchemaUpdateIssue composes the schema update issue migrating each database to the committed schema file.
+// The statement of each database is the diff between its live schema and the desired schema, so it can be reviewed before it runs.
+func (s *Server) createDeclarativeSchemaUpdateIssue(ctx context.Context, repository *api.Repository, scope pushEventScope, mi *db.MigrationInfo, vcsPushEvent vcs.PushEvent, added string, content string) (string, error) {
+	// Tenant databases share the same migration statement, while the diff is computed per database.
+	if repository.Project.TenantMode == api.TenantModeTenant {
+		return "", fmt.Errorf("declarative schema migration isn't supported for tenant mode project")
+	}
+	filteredDatabaseList, err := s.findScopeDatabaseList(ctx, repository, scope, mi, added)
+	if err != nil {
+		return "", err
+	}
+
+	if err := validateDeclarativeSchemaDatabaseList(filteredDatabaseList); err != nil {
+		return "", err
+	}
+
+	m := &api.UpdateSchemaContext{
+		MigrationType: mi.Type,
+		VCSPushEvent:  &vcsPushEvent,
+	}
+	for _, database := range filteredDatabaseList {
+		desiredSchema, err := differ.ParseSchema(database.Instance.Engine, content)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse the committed schema file for database %q: %w", database.Name, err)
+		}
+		liveSchema, err := getDatabaseSchema(ctx, database.Instance, database.Name, s.l)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the schema of database %q: %w", database.Name, err)
+		}
+		statement, err := differ.SchemaDiff(database.Instance.Engine, liveSchema, desiredSchema)
+		if err != nil {
+			return "", fmt.Errorf("failed to diff the committed schema file with database %q: %w", database.Name, err)
+		}
+		if statement == "" {
+			continue
+		}
+		m.UpdateSchemaDetailList = append(m.UpdateSchemaDetailList,
+			&api.UpdateSchemaDetail{
+				DatabaseID: database.ID,
+				Statement:  statement,
+			})
+	}
+	if len(m.UpdateSchemaDetailList) == 0 {
+		return "", fmt.Errorf("database %q already matches the committed schema file", mi.Database)
+	}
+	createContext, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("Failed to construct issue create context payload, error %v", err)
+	}
+	return string(createContext), nil
+}
+
+// validateDeclarativeSchemaDatabaseList validates the databases support the declarative schema migration,
+// which parses the schema file to diff with the live schema.
+func validateDeclarativeSchemaDatabaseList(databaseList []*api.Database) error {
+	for _, database := range databaseList {
+		if !differ.IsParseSchemaSupported(database.Instance.Engine) {
+			return fmt.Errorf("declarative schema migration isn't supported for %s database %q", database.Instance.Engine, database.Name)
+		}
+	}
+	return nil
+}
+
+// findCommittedFileDatabaseList finds the project databases referenced by the committed file.
+func (s *Server) findCommittedFileDatabaseList(ctx context.Context, repository *api.Repository, mi *db.MigrationInfo, added string) ([]*api.Database, error) {
 	// Find matching database list
 	databaseFind := &api.DatabaseFind{
 		ProjectID: &repository.ProjectID,
@@ -234,9 +1432,9 @@ func (s *Server) createSchemaUpdateIssue(ctx context.Context, repository *api.Re
 	}
 	databaseList, err := s.composeDatabaseListByFind(ctx, databaseFind)
 	if err != nil {
-		return "", fmt.Errorf("failed to find database matching database %q referenced by the committed file", mi.Database)
+		return nil, fmt.Errorf("failed to find database matching database %q referenced by the committed file", mi.Database)
 	} else if len(databaseList) == 0 {
-		return "", fmt.Errorf("project ID %d does not own database %q referenced by the committed file", repository.ProjectID, mi.Database)
+		return nil, fmt.Errorf("project ID %d does not own database %q referenced by the committed file", repository.ProjectID, mi.Database)
 	}
 
 	// We support 3 patterns on how to organize the schema files.
@@ -260,7 +1458,7 @@ func (s *Server) createSchemaUpdateIssue(ctx context.Context, repository *api.Re
 			}
 		}
 		if len(filteredDatabaseList) == 0 {
-			return "", fmt.Errorf("project does not contain committed file database %q for environment %q", mi.Database, mi.Environment)
+			return nil, fmt.Errorf("project does not contain committed file database %q for environment %q", mi.Database, mi.Environment)
 		}
 	} else {
 		filteredDatabaseList = databaseList
@@ -279,70 +1477,131 @@ func (s *Server) createSchemaUpdateIssue(ctx context.Context, repository *api.Re
 		}
 	}
 	if len(multipleDatabaseForSameEnv) > 0 {
-		return "", fmt.Errorf("Ignored committed files with multiple ambiguous databases %s", strings.Join(multipleDatabaseForSameEnv, ", "))
+		return nil, fmt.Errorf("Ignored committed files with multiple ambiguous databases %s", strings.Join(multipleDatabaseForSameEnv, ", "))
 	}
 
-	// Compose the new issue
-	m := &api.UpdateSchemaContext{
-		MigrationType: mi.Type,
-		VCSPushEvent:  &vcsPushEvent,
+	return filteredDatabaseList, nil
+}
+
+// findScopeDatabaseList finds the databases referenced by the committed file within the scope of the push event.
+func (s *Server) findScopeDatabaseList(ctx context.Context, repository *api.Repository, scope pushEventScope, mi *db.MigrationInfo, added string) ([]*api.Database, error) {
+	databaseList, err := s.findCommittedFileDatabaseList(ctx, repository, mi, added)
+	if err != nil {
+		return nil, err
 	}
-	for _, database := range filteredDatabaseList {
-		m.UpdateSchemaDetailList = append(m.UpdateSchemaDetailList,
-			&api.UpdateSchemaDetail{
-				DatabaseID: database.ID,
-				Statement:  statement,
-			})
+
+	pendingDatabaseSet := map[int]bool{}
+	if scope.skipApplied {
+		pendingTaskList, err := s.findPendingTaskListByFile(ctx, repository, added)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find pending tasks of committed file %q: %w", added, err)
+		}
+		for _, pendingTask := range pendingTaskList {
+			if pendingTask.task.DatabaseID != nil {
+				pendingDatabaseSet[*pendingTask.task.DatabaseID] = true
+			}
+		}
 	}
-	createContext, err := json.Marshal(m)
-	if err != nil {
-		return "", fmt.Errorf("Failed to construct issue create context payload, error %v", err)
+
+	var filteredDatabaseList []*api.Database
+	for _, database := range databaseList {
+		if scope.environmentList != nil {
+			matched := false
+			for _, environment := range scope.environmentList {
+				// Environment name comparison is case insensitive
+				if strings.EqualFold(database.Instance.Environment.Name, environment) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		if scope.skipApplied {
+			if pendingDatabaseSet[database.ID] {
+				continue
+			}
+			applied, err := scope.migrationVersionCache.isApplied(ctx, database, mi.Version)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find migration history of database %q: %w", database.Name, err)
+			}
+			if applied {
+				continue
+			}
+		}
+		filteredDatabaseList = append(filteredDatabaseList, database)
 	}
-	return string(createContext), nil
+
+	if len(filteredDatabaseList) == 0 {
+		if scope.skipApplied {
+			return nil, fmt.Errorf("committed file database %q has applied or is applying version %s", mi.Database, mi.Version)
+		}
+		return nil, fmt.Errorf("project does not contain committed file database %q for environment(s) %q matched by the branch filter", mi.Database, strings.Join(scope.environmentList, ", "))
+	}
+	return filteredDatabaseList, nil
 }
 
-func (s *Server) createTenantSchemaUpdateIssue(ctx context.Context, repository *api.Repository, mi *db.MigrationInfo, vcsPushEvent vcs.PushEvent, commit gitlab.WebhookCommit, added string, statement string) (string, error) {
+// composeTenantSchemaUpdateDetailList composes the update schema detail applying the committed migration file to the tenant databases.
+func (s *Server) composeTenantSchemaUpdateDetailList(scope pushEventScope, mi *db.MigrationInfo, statement string) ([]*api.UpdateSchemaDetail, error) {
 	// We don't take environment for tenant mode project because the databases needing schema update are determined by database name and deployment configuration.
 	if mi.Environment != "" {
-		return "", fmt.Errorf("environment isn't accepted in schema update for tenant mode project")
-	}
-	m := &api.UpdateSchemaContext{
-		MigrationType: mi.Type,
-		VCSPushEvent:  &vcsPushEvent,
-		UpdateSchemaDetailList: []*api.UpdateSchemaDetail{
-			{
-				DatabaseName: mi.Database,
-				Statement:    statement,
-			},
-		},
+		return nil, fmt.Errorf("environment isn't accepted in schema update for tenant mode project")
 	}
-	createContext, err := json.Marshal(m)
-	if err != nil {
-		return "", fmt.Errorf("Failed to construct issue create context payload, error %v", err)
+	if scope.environmentList != nil {
+		return nil, fmt.Errorf("environment scoped branch filter rule isn't accepted in schema update for tenant mode project")
 	}
-	return string(createContext), nil
+	return []*api.UpdateSchemaDetail{
+		{
+			DatabaseName: mi.Database,
+			Statement:    statement,
+		},
+	}, nil
 }
 
-func isSkipGeneratedSchemaFile(repository *api.Repository, added string, logger *zap.Logger) bool {
-	if repository.SchemaPathTemplate != "" {
-		placeholderList := []string{
-			"ENV_NAME",
-			"DB_NAME",
-		}
-		schemafilePathRegex := repository.SchemaPathTemplate
-		for _, placeholder := range placeholderList {
-			schemafilePathRegex = strings.ReplaceAll(schemafilePathRegex, fmt.Sprintf("{{%s}}", placeholder), fmt.Sprintf("(?P<%s>[a-zA-Z0-9+-=/_#?!$. ]+)", placeholder))
+// matchBranchFilter returns true if the branch matches any rule of the branch filter, an empty branch filter matches any branch.
+func matchBranchFilter(branchFilter string, branch string) bool {
+	_, err := matchPushEventScope(branchFilter, "refs/heads/"+branch)
+	return err == nil
+}
+
+// matchPushEventScope matches the Git ref of the push event against the branch filter rules.
+// Returns the scope of the databases receiving the changes on the ref, or an error if the ref doesn't match any rule.
+// An empty branch filter matches any branch.
+func matchPushEventScope(branchFilter string, ref string) (pushEventScope, error) {
+	ruleList, err := api.ParseBranchFilter(branchFilter)
+	if err != nil {
+		return pushEventScope{}, err
+	}
+	if len(ruleList) == 0 {
+		if _, err := vcs.Branch(ref); err != nil {
+			return pushEventScope{}, err
 		}
-		myRegex, err := regexp.Compile(schemafilePathRegex)
-		if err != nil {
-			logger.Warn("Invalid schema path template.", zap.String("schema_path_template",
-				repository.SchemaPathTemplate),
-				zap.Error(err),
-			)
+		return pushEventScope{}, nil
+	}
+
+	var environmentList []string
+	for _, rule := range ruleList {
+		if !rule.MatchRef(ref) {
+			continue
 		}
-		if myRegex.MatchString(added) {
-			return true
+		// The rule without the environment name applies to all environments.
+		if rule.Environment == "" {
+			return pushEventScope{}, nil
 		}
+		environmentList = append(environmentList, rule.Environment)
+	}
+	if len(environmentList) == 0 {
+		return pushEventScope{}, fmt.Errorf("ref %q doesn't match the branch filter %q", ref, branchFilter)
+	}
+	return pushEventScope{environmentList: environmentList}, nil
+}
+
+// isSchemaFile returns true if the file matches the schema path template of the repository.
+func isSchemaFile(repository *api.Repository, file string) bool {
+	if repository.SchemaPathTemplate == "" {
+		return false
 	}
-	return false
+	_, err := db.ParseSchemaFileInfo(file, filepath.Join(repository.BaseDirectory, repository.SchemaPathTemplate))
+	return err == nil
 }
@@ -0,0 +1,724 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/kr/pretty"
+	"go.uber.org/zap"
+)
+
+func TestMatchPushEventScope(t *testing.T) {
+	tests := []struct {
+		branchFilter string
+		ref          string
+		want         pushEventScope
+		wantErr      bool
+	}{
+		{
+			branchFilter: "",
+			ref:          "refs/heads/feature/foo",
+			want:         pushEventScope{},
+		},
+		{
+			branchFilter: "",
+			ref:          "refs/tags/v1.0",
+			wantErr:      true,
+		},
+		{
+			branchFilter: "main",
+			ref:          "refs/heads/main",
+			want:         pushEventScope{},
+		},
+		{
+			branchFilter: "Dev=main,Staging=release/*,Prod=refs/tags/v*",
+			ref:          "refs/heads/main",
+			want:         pushEventScope{environmentList: []string{"Dev"}},
+		},
+		{
+			branchFilter: "Dev=main,Staging=release/*,Prod=refs/tags/v*",
+			ref:          "refs/tags/v1.0",
+			want:         pushEventScope{environmentList: []string{"Prod"}},
+		},
+		{
+			branchFilter: "Dev=main,Test=main",
+			ref:          "refs/heads/main",
+			want:         pushEventScope{environmentList: []string{"Dev", "Test"}},
+		},
+		{
+			// The rule without environment name takes all environments.
+			branchFilter: "Dev=main,main",
+			ref:          "refs/heads/main",
+			want:         pushEventScope{},
+		},
+		{
+			branchFilter: "Dev=main,Staging=release/*,Prod=refs/tags/v*",
+			ref:          "refs/heads/feature/foo",
+			wantErr:      true,
+		},
+		{
+			branchFilter: "Prod=refs/tags/v*",
+			ref:          "refs/tags/release-1.0",
+			wantErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		scope, err := matchPushEventScope(test.branchFilter, test.ref)
+		if err != nil {
+			if !test.wantErr {
+				t.Errorf("matchPushEventScope(%q, %q) got error %v, want OK", test.branchFilter, test.ref, err)
+			}
+			continue
+		}
+		if test.wantErr {
+			t.Errorf("matchPushEventScope(%q, %q) got OK, want error", test.branchFilter, test.ref)
+			continue
+		}
+		diff := pretty.Diff(scope, test.want)
+		if len(diff) > 0 {
+			t.Errorf("matchPushEventScope(%q, %q) got %+v, want %+v, diff %+v", test.branchFilter, test.ref, scope, test.want, diff)
+		}
+	}
+}
+
+func TestWebhookBranchFilter(t *testing.T) {
+	tests := []struct {
+		branchFilter string
+		want         string
+	}{
+		{
+			branchFilter: "",
+			want:         "",
+		},
+		{
+			branchFilter: "release/*",
+			want:         "release/*",
+		},
+		{
+			branchFilter: "Prod=main",
+			want:         "main",
+		},
+		{
+			branchFilter: "Dev=main,Staging=release/*",
+			want:         "",
+		},
+		{
+			branchFilter: "Prod=refs/tags/v*",
+			want:         "",
+		},
+	}
+
+	for _, test := range tests {
+		got := webhookBranchFilter(test.branchFilter)
+		if got != test.want {
+			t.Errorf("webhookBranchFilter(%q) = %q, want %q", test.branchFilter, got, test.want)
+		}
+	}
+}
+func TestMatchBranchFilter(t *testing.T) {
+	tests := []struct {
+		branchFilter string
+		branch       string
+		want         bool
+	}{
+		{
+			branchFilter: "",
+			branch:       "feature/foo",
+			want:         true,
+		},
+		{
+			branchFilter: "main",
+			branch:       "main",
+			want:         true,
+		},
+		{
+			branchFilter: "main",
+			branch:       "master",
+			want:         false,
+		},
+		{
+			branchFilter: "release/*",
+			branch:       "release/1.0",
+			want:         true,
+		},
+		{
+			branchFilter: "release/*",
+			branch:       "release/1.0/hotfix",
+			want:         false,
+		},
+		{
+			branchFilter: "Dev=main,Staging=release/*,Prod=refs/tags/v*",
+			branch:       "release/1.0",
+			want:         true,
+		},
+		{
+			branchFilter: "Dev=main,Prod=refs/tags/v*",
+			branch:       "v1.0",
+			want:         false,
+		},
+	}
+
+	for _, test := range tests {
+		got := matchBranchFilter(test.branchFilter, test.branch)
+		if got != test.want {
+			t.Errorf("matchBranchFilter(%q, %q) = %v, want %v", test.branchFilter, test.branch, got, test.want)
+		}
+	}
+}
+
+func TestGroupMigrationFileList(t *testing.T) {
+	file := func(database, version string) committedMigrationFile {
+		return committedMigrationFile{
+			mi: &db.MigrationInfo{
+				Database: database,
+				Version:  version,
+			},
+		}
+	}
+	envFile := func(environment, database, version string) committedMigrationFile {
+		return committedMigrationFile{
+			mi: &db.MigrationInfo{
+				Environment: environment,
+				Database:    database,
+				Version:     version,
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		migrationFileList []committedMigrationFile
+		want              [][]committedMigrationFile
+	}{
+		{
+			name:              "empty",
+			migrationFileList: nil,
+			want:              nil,
+		},
+		{
+			name: "single database",
+			migrationFileList: []committedMigrationFile{
+				file("db1", "003"),
+				file("db1", "001"),
+				file("db1", "002"),
+			},
+			want: [][]committedMigrationFile{
+				{file("db1", "001"), file("db1", "002"), file("db1", "003")},
+			},
+		},
+		{
+			name: "multiple databases",
+			migrationFileList: []committedMigrationFile{
+				file("db2", "002"),
+				file("db1", "002"),
+				file("db2", "001"),
+				file("db1", "001"),
+			},
+			want: [][]committedMigrationFile{
+				{file("db2", "001"), file("db2", "002")},
+				{file("db1", "001"), file("db1", "002")},
+			},
+		},
+		{
+			name: "multiple environments",
+			migrationFileList: []committedMigrationFile{
+				envFile("prod", "db1", "002"),
+				envFile("dev", "db1", "001"),
+				envFile("Prod", "db1", "001"),
+				envFile("dev", "db1", "002"),
+			},
+			want: [][]committedMigrationFile{
+				{envFile("Prod", "db1", "001"), envFile("prod", "db1", "002")},
+				{envFile("dev", "db1", "001"), envFile("dev", "db1", "002")},
+			},
+		},
+		{
+			name: "versions not zero-padded",
+			migrationFileList: []committedMigrationFile{
+				file("db1", "10"),
+				file("db1", "9"),
+				file("db1", "v1.10"),
+				file("db1", "v1.9"),
+			},
+			want: [][]committedMigrationFile{
+				{file("db1", "9"), file("db1", "10"), file("db1", "v1.9"), file("db1", "v1.10")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		got := groupMigrationFileList(test.migrationFileList)
+		if diff := pretty.Diff(got, test.want); len(diff) > 0 {
+			t.Errorf("%q: groupMigrationFileList() got %+v, want %+v, diff %+v.", test.name, got, test.want, diff)
+		}
+	}
+}
+
+func TestValidateDeclarativeSchemaDatabaseList(t *testing.T) {
+	newDatabase := func(name string, engine db.Type) *api.Database {
+		return &api.Database{
+			Name:     name,
+			Instance: &api.Instance{Engine: engine},
+		}
+	}
+	tests := []struct {
+		databaseList []*api.Database
+		wantErr      bool
+	}{
+		{
+			databaseList: nil,
+		},
+		{
+			databaseList: []*api.Database{newDatabase("db1", db.MySQL), newDatabase("db2", db.TiDB)},
+		},
+		{
+			databaseList: []*api.Database{newDatabase("db1", db.MySQL), newDatabase("db2", db.Postgres)},
+			wantErr:      true,
+		},
+		{
+			databaseList: []*api.Database{newDatabase("db1", db.ClickHouse)},
+			wantErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		err := validateDeclarativeSchemaDatabaseList(test.databaseList)
+		if (err != nil) != test.wantErr {
+			t.Errorf("validateDeclarativeSchemaDatabaseList(%v) got error %v, wantErr %v", test.databaseList, err, test.wantErr)
+		}
+	}
+}
+
+// fakeTaskService returns the tasks regardless of the find, and records the finds.
+type fakeTaskService struct {
+	api.TaskService
+	taskList []*api.Task
+	findList []*api.TaskFind
+}
+
+func (s *fakeTaskService) FindTaskList(_ context.Context, find *api.TaskFind) ([]*api.Task, error) {
+	s.findList = append(s.findList, find)
+	return s.taskList, nil
+}
+
+// fakeIssueService finds the issues by the pipeline ID.
+type fakeIssueService struct {
+	api.IssueService
+	// issueMap is the issue keyed by the pipeline ID.
+	issueMap  map[int]*api.Issue
+	findCount int
+}
+
+func (s *fakeIssueService) FindIssue(_ context.Context, find *api.IssueFind) (*api.Issue, error) {
+	s.findCount++
+	return s.issueMap[*find.PipelineID], nil
+}
+
+// fakeDatabaseService finds the databases in the database list by the instance and the name.
+type fakeDatabaseService struct {
+	api.DatabaseService
+	databaseList []*api.Database
+}
+
+func (s *fakeDatabaseService) FindDatabaseList(_ context.Context, find *api.DatabaseFind) ([]*api.Database, error) {
+	var databaseList []*api.Database
+	for _, database := range s.databaseList {
+		if find.InstanceID != nil && *find.InstanceID != database.InstanceID {
+			continue
+		}
+		if find.Name != nil && *find.Name != database.Name {
+			continue
+		}
+		databaseList = append(databaseList, database)
+	}
+	return databaseList, nil
+}
+
+func (s *fakeDatabaseService) FindDatabase(ctx context.Context, find *api.DatabaseFind) (*api.Database, error) {
+	databaseList, err := s.FindDatabaseList(ctx, find)
+	if err != nil || len(databaseList) == 0 {
+		return nil, err
+	}
+	return databaseList[0], nil
+}
+
+// fakeActivityService records the created activities.
+type fakeActivityService struct {
+	api.ActivityService
+	createList []*api.ActivityCreate
+}
+
+func (s *fakeActivityService) CreateActivity(_ context.Context, create *api.ActivityCreate) (*api.Activity, error) {
+	s.createList = append(s.createList, create)
+	return &api.Activity{}, nil
+}
+
+// webhookTestServer is the server with the fake services for the webhook tests.
+type webhookTestServer struct {
+	*Server
+	taskService     *fakeTaskService
+	issueService    *fakeIssueService
+	activityService *fakeActivityService
+}
+
+func newWebhookTestServer(taskList []*api.Task, issueList []*api.Issue) *webhookTestServer {
+	ts := &webhookTestServer{
+		Server: &Server{
+			l:               zap.NewNop(),
+			DatabaseService: &fakeDatabaseService{},
+		},
+		taskService: &fakeTaskService{
+			taskList: taskList,
+		},
+		issueService: &fakeIssueService{
+			issueMap: make(map[int]*api.Issue),
+		},
+		activityService: &fakeActivityService{},
+	}
+	for _, issue := range issueList {
+		ts.issueService.issueMap[issue.PipelineID] = issue
+	}
+	ts.TaskService = ts.taskService
+	ts.IssueService = ts.issueService
+	ts.ActivityManager = NewActivityManager(ts.Server, ts.activityService)
+	return ts
+}
+
+var webhookTestRepository = &api.Repository{
+	ProjectID:        101,
+	ExternalID:       "1",
+	BaseDirectory:    "bytebase",
+	FilePathTemplate: "{{VERSION}}__{{DB_NAME}}__{{TYPE}}__{{DESCRIPTION}}.sql",
+}
+
+// newPushEventTask returns the schema update task created from the committed file on the ref of the repository.
+func newPushEventTask(t *testing.T, id int, pipelineID int, repositoryID string, ref string, file string, statement string) *api.Task {
+	payload, err := json.Marshal(api.TaskDatabaseSchemaUpdatePayload{
+		Statement: statement,
+		VCSPushEvent: &vcs.PushEvent{
+			Ref:          ref,
+			RepositoryID: repositoryID,
+			FileCommit:   vcs.FileCommit{Added: file},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal task payload: %v", err)
+	}
+	return &api.Task{
+		ID:         id,
+		PipelineID: pipelineID,
+		Name:       fmt.Sprintf("task %d", id),
+		Type:       api.TaskDatabaseSchemaUpdate,
+		Payload:    string(payload),
+	}
+}
+
+func TestFindPendingTaskListByFile(t *testing.T) {
+	file := "bytebase/v1__db1__migrate__create_t.sql"
+	taskList := []*api.Task{
+		newPushEventTask(t, 1, 101, "1", "refs/heads/main", file, "CREATE TABLE t (id INT)"),
+		newPushEventTask(t, 2, 101, "1", "refs/heads/main", "bytebase/v1__db1__migrate__create_tt.sql", "CREATE TABLE tt (id INT)"),
+		// The task created from the file of the same path in another repository.
+		newPushEventTask(t, 3, 102, "2", "refs/heads/main", file, "CREATE TABLE t (id INT)"),
+		newPushEventTask(t, 4, 103, "1", "refs/heads/release", file, "CREATE TABLE t (id INT)"),
+		// The task of the pipeline without issue.
+		newPushEventTask(t, 5, 104, "1", "refs/heads/main", file, "CREATE TABLE t (id INT)"),
+		newPushEventTask(t, 6, 101, "1", "refs/heads/main", file, "CREATE TABLE t (id INT)"),
+	}
+	issueList := []*api.Issue{
+		{ID: 1, PipelineID: 101},
+		{ID: 2, PipelineID: 102},
+		{ID: 3, PipelineID: 103},
+	}
+	ts := newWebhookTestServer(taskList, issueList)
+
+	pendingTaskList, err := ts.findPendingTaskListByFile(context.Background(), webhookTestRepository, file)
+	if err != nil {
+		t.Fatalf("findPendingTaskListByFile() got error: %v", err)
+	}
+	var got []string
+	for _, pendingTask := range pendingTaskList {
+		got = append(got, fmt.Sprintf("issue %d task %d", pendingTask.issue.ID, pendingTask.task.ID))
+	}
+	want := []string{"issue 1 task 1", "issue 3 task 4", "issue 1 task 6"}
+	if diff := pretty.Diff(got, want); len(diff) > 0 {
+		t.Errorf("findPendingTaskListByFile() got %v, want %v, diff %v", got, want, diff)
+	}
+
+	// The tasks are found in a single query by the file in the open issues of the project.
+	if len(ts.taskService.findList) != 1 {
+		t.Fatalf("findPendingTaskListByFile() found the tasks %d times, want once", len(ts.taskService.findList))
+	}
+	find := ts.taskService.findList[0]
+	if find.ProjectID == nil || *find.ProjectID != webhookTestRepository.ProjectID {
+		t.Errorf("findPendingTaskListByFile() found the tasks of project %v, want %d", find.ProjectID, webhookTestRepository.ProjectID)
+	}
+	if find.IssueStatusList == nil || len(*find.IssueStatusList) != 1 || (*find.IssueStatusList)[0] != api.IssueOpen {
+		t.Errorf("findPendingTaskListByFile() found the tasks of the issues in status %v, want %v", find.IssueStatusList, []api.IssueStatus{api.IssueOpen})
+	}
+	if find.VCSPushEventFile == nil || *find.VCSPushEventFile != file {
+		t.Errorf("findPendingTaskListByFile() found the tasks of file %v, want %q", find.VCSPushEventFile, file)
+	}
+	// The issue of each pipeline is found once.
+	if ts.issueService.findCount != 3 {
+		t.Errorf("findPendingTaskListByFile() found the issues %d times, want 3", ts.issueService.findCount)
+	}
+}
+
+func TestComposeTaskStatementPayload(t *testing.T) {
+	file := "bytebase/v1__db1__migrate__create_t.sql"
+	vcsPushEvent := vcs.PushEvent{
+		Ref:          "refs/heads/main",
+		RepositoryID: "1",
+		FileCommit: vcs.FileCommit{
+			ID:    "commit2",
+			Added: file,
+		},
+	}
+
+	task := newPushEventTask(t, 1, 101, "1", "refs/heads/main", file, "CREATE TABLE t (id INT)")
+	oldStatement, payload, err := composeTaskStatementPayload(task, vcsPushEvent, "CREATE TABLE t (id BIGINT)")
+	if err != nil {
+		t.Fatalf("composeTaskStatementPayload() got error: %v", err)
+	}
+	if oldStatement != "CREATE TABLE t (id INT)" {
+		t.Errorf("composeTaskStatementPayload() got old statement %q, want %q", oldStatement, "CREATE TABLE t (id INT)")
+	}
+	taskPayload := &api.TaskDatabaseSchemaUpdatePayload{}
+	if err := json.Unmarshal([]byte(payload), taskPayload); err != nil {
+		t.Fatalf("composeTaskStatementPayload() got invalid payload %q: %v", payload, err)
+	}
+	if taskPayload.Statement != "CREATE TABLE t (id BIGINT)" {
+		t.Errorf("composeTaskStatementPayload() got statement %q, want %q", taskPayload.Statement, "CREATE TABLE t (id BIGINT)")
+	}
+	if diff := pretty.Diff(taskPayload.VCSPushEvent, &vcsPushEvent); len(diff) > 0 {
+		t.Errorf("composeTaskStatementPayload() got push event %+v, want %+v, diff %v", taskPayload.VCSPushEvent, vcsPushEvent, diff)
+	}
+
+	dataTask := &api.Task{ID: 2, Type: api.TaskDatabaseDataUpdate, Payload: `{"statement":"INSERT INTO t VALUES (1)"}`}
+	oldStatement, payload, err = composeTaskStatementPayload(dataTask, vcsPushEvent, "INSERT INTO t VALUES (2)")
+	if err != nil {
+		t.Fatalf("composeTaskStatementPayload() got error: %v", err)
+	}
+	if oldStatement != "INSERT INTO t VALUES (1)" {
+		t.Errorf("composeTaskStatementPayload() got old statement %q, want %q", oldStatement, "INSERT INTO t VALUES (1)")
+	}
+	dataPayload := &api.TaskDatabaseDataUpdatePayload{}
+	if err := json.Unmarshal([]byte(payload), dataPayload); err != nil {
+		t.Fatalf("composeTaskStatementPayload() got invalid payload %q: %v", payload, err)
+	}
+	if dataPayload.Statement != "INSERT INTO t VALUES (2)" || dataPayload.VCSPushEvent == nil {
+		t.Errorf("composeTaskStatementPayload() got payload %q, want the new statement and the push event", payload)
+	}
+
+	if _, _, err := composeTaskStatementPayload(&api.Task{ID: 3, Type: api.TaskDatabaseCreate}, vcsPushEvent, "CREATE DATABASE db1"); err == nil {
+		t.Errorf("composeTaskStatementPayload() got OK for database create task, want error")
+	}
+}
+
+func TestUpdateIssueFromModifiedFile(t *testing.T) {
+	file := "bytebase/v1__db1__migrate__create_t.sql"
+	tests := []struct {
+		name     string
+		modified string
+		taskList []*api.Task
+	}{
+		{
+			name:     "not a migration file",
+			modified: "bytebase/README.md",
+			taskList: []*api.Task{newPushEventTask(t, 1, 101, "1", "refs/heads/main", "bytebase/README.md", "")},
+		},
+		{
+			name:     "outside the base directory",
+			modified: "v1__db1__migrate__create_t.sql",
+			taskList: []*api.Task{newPushEventTask(t, 1, 101, "1", "refs/heads/main", "v1__db1__migrate__create_t.sql", "CREATE TABLE t (id INT)")},
+		},
+		{
+			name:     "no pending task",
+			modified: file,
+		},
+		{
+			name:     "pending task on another ref",
+			modified: file,
+			taskList: []*api.Task{newPushEventTask(t, 1, 101, "1", "refs/heads/release", file, "CREATE TABLE t (id INT)")},
+		},
+	}
+
+	for _, test := range tests {
+		ts := newWebhookTestServer(test.taskList, []*api.Issue{{ID: 1, PipelineID: 101}})
+		vcsPushEvent := vcs.PushEvent{
+			Ref:          "refs/heads/main",
+			RepositoryID: "1",
+			FileCommit: vcs.FileCommit{
+				ID:    "commit2",
+				Added: test.modified,
+			},
+		}
+		// The file isn't read from the VCS if there is no pending task on the ref to update.
+		messageList, err := ts.updateIssueFromModifiedFile(context.Background(), webhookTestRepository, ts.newMigrationVersionCache(), vcsPushEvent, test.modified)
+		if err != nil {
+			t.Errorf("%s: updateIssueFromModifiedFile() got error: %v", test.name, err)
+			continue
+		}
+		if len(messageList) != 0 {
+			t.Errorf("%s: updateIssueFromModifiedFile() got messages %v, want none", test.name, messageList)
+		}
+		if len(ts.activityService.createList) != 0 {
+			t.Errorf("%s: updateIssueFromModifiedFile() created %d activities, want none", test.name, len(ts.activityService.createList))
+		}
+	}
+}
+
+func TestCheckRemovedFile(t *testing.T) {
+	file := "bytebase/v1__db1__migrate__create_t.sql"
+	rollbackFile := "bytebase/v1__db1__rollback__create_t.sql"
+	tests := []struct {
+		name        string
+		removed     string
+		taskList    []*api.Task
+		wantComment []string
+	}{
+		{
+			name:    "pending tasks",
+			removed: file,
+			taskList: []*api.Task{
+				newPushEventTask(t, 1, 101, "1", "refs/heads/main", file, "CREATE TABLE t (id INT)"),
+				newPushEventTask(t, 2, 102, "1", "refs/heads/main", file, "CREATE TABLE t (id INT)"),
+			},
+			wantComment: []string{
+				`Removed migration file "bytebase/v1__db1__migrate__create_t.sql" still has pending task "task 1" in issue "issue 1".`,
+				`Removed migration file "bytebase/v1__db1__migrate__create_t.sql" still has pending task "task 2" in issue "issue 2".`,
+			},
+		},
+		{
+			name:    "no pending task",
+			removed: file,
+		},
+		{
+			name:     "rollback file",
+			removed:  rollbackFile,
+			taskList: []*api.Task{newPushEventTask(t, 1, 101, "1", "refs/heads/main", rollbackFile, "DROP TABLE t")},
+		},
+		{
+			name:     "not a migration file",
+			removed:  "bytebase/README.md",
+			taskList: []*api.Task{newPushEventTask(t, 1, 101, "1", "refs/heads/main", "bytebase/README.md", "")},
+		},
+	}
+
+	for _, test := range tests {
+		ts := newWebhookTestServer(test.taskList, []*api.Issue{
+			{ID: 1, Name: "issue 1", PipelineID: 101},
+			{ID: 2, Name: "issue 2", PipelineID: 102},
+		})
+		vcsPushEvent := vcs.PushEvent{
+			Ref:          "refs/heads/main",
+			RepositoryID: "1",
+			FileCommit: vcs.FileCommit{
+				ID:    "commit2",
+				Added: test.removed,
+			},
+		}
+		ts.checkRemovedFile(context.Background(), webhookTestRepository, ts.newMigrationVersionCache(), vcsPushEvent, test.removed)
+		var commentList []string
+		for _, create := range ts.activityService.createList {
+			if create.Type != api.ActivityProjectRepositoryPush || create.Level != api.ActivityWarn || create.ContainerID != webhookTestRepository.ProjectID {
+				t.Errorf("%s: checkRemovedFile() created activity %+v, want the WARNING repository push activity of the project", test.name, create)
+			}
+			commentList = append(commentList, create.Comment)
+		}
+		if diff := pretty.Diff(commentList, test.wantComment); len(diff) > 0 {
+			t.Errorf("%s: checkRemovedFile() got comments %v, want %v, diff %v", test.name, commentList, test.wantComment, diff)
+		}
+	}
+}
+
+func TestFindMigrationAppliedDatabaseListWithoutDatabase(t *testing.T) {
+	ts := newWebhookTestServer(nil, nil)
+	file := "bytebase/v1__db1__migrate__create_t.sql"
+	mi, err := db.ParseMigrationInfo(file, "bytebase/{{VERSION}}__{{DB_NAME}}__{{TYPE}}__{{DESCRIPTION}}.sql")
+	if err != nil {
+		t.Fatalf("failed to parse migration info of %q: %v", file, err)
+	}
+	// The project doesn't own the database referenced by the file, so there is no applied database to warn.
+	if got := ts.findMigrationAppliedDatabaseList(context.Background(), webhookTestRepository, ts.newMigrationVersionCache(), mi, file); len(got) != 0 {
+		t.Errorf("findMigrationAppliedDatabaseList() got %v, want none", got)
+	}
+}
+
+func TestMigrationVersionCache(t *testing.T) {
+	findCount := make(map[int]int)
+	cache := &migrationVersionCache{
+		findAppliedVersionSet: func(_ context.Context, database *api.Database) (map[string]bool, error) {
+			findCount[database.ID]++
+			if database.ID == 2 {
+				return nil, fmt.Errorf("failed to connect to database %q", database.Name)
+			}
+			return map[string]bool{"v1": true, "v2": true}, nil
+		},
+		versionSetMap: make(map[int]map[string]bool),
+	}
+	db1 := &api.Database{ID: 1, Name: "db1"}
+	db2 := &api.Database{ID: 2, Name: "db2"}
+	tests := []struct {
+		database *api.Database
+		version  string
+		want     bool
+		wantErr  bool
+	}{
+		{database: db1, version: "v1", want: true},
+		{database: db1, version: "v2", want: true},
+		{database: db1, version: "v3", want: false},
+		{database: db2, version: "v1", wantErr: true},
+		{database: db2, version: "v2", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := cache.isApplied(context.Background(), test.database, test.version)
+		if (err != nil) != test.wantErr {
+			t.Errorf("isApplied(%q, %q) got error %v, want error %v", test.database.Name, test.version, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("isApplied(%q, %q) got %v, want %v", test.database.Name, test.version, got, test.want)
+		}
+	}
+	// The migration history is fetched once for the database, and the failed fetch is retried.
+	if diff := pretty.Diff(findCount, map[int]int{1: 1, 2: 2}); len(diff) > 0 {
+		t.Errorf("findAppliedVersionSet() got call count %v, diff %v", findCount, diff)
+	}
+}
+
+func TestMigrationVersionLess(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want bool
+	}{
+		{a: "1", b: "2", want: true},
+		{a: "2", b: "10", want: true},
+		{a: "10", b: "2", want: false},
+		{a: "v9", b: "v10", want: true},
+		{a: "v1.9", b: "v1.10", want: true},
+		{a: "1.2", b: "1.2.1", want: true},
+		{a: "001", b: "002", want: true},
+		{a: "002", b: "10", want: true},
+		{a: "01", b: "1", want: true},
+		{a: "1", b: "01", want: false},
+		{a: "1", b: "1", want: false},
+		{a: "20220101", b: "20220102", want: true},
+		{a: "v1", b: "w1", want: true},
+	}
+
+	for _, test := range tests {
+		if got := migrationVersionLess(test.a, test.b); got != test.want {
+			t.Errorf("migrationVersionLess(%q, %q) got %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
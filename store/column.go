@@ -139,10 +139,11 @@ func (s *ColumnService) createColumn(ctx context.Context, tx *Tx, create *api.Co
 			type,
 			character_set,
 			collation,
-			comment
+			comment,
+			extra
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, position, `+"`default`, "+`nullable, type, character_set, collation, comment
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, position, `+"`default`, "+`nullable, type, character_set, collation, comment, extra
 	`,
 		create.CreatorID,
 		create.CreatorID,
@@ -156,6 +157,7 @@ func (s *ColumnService) createColumn(ctx context.Context, tx *Tx, create *api.Co
 		create.CharacterSet,
 		create.Collation,
 		create.Comment,
+		create.Extra,
 	)
 
 	if err != nil {
@@ -181,6 +183,7 @@ func (s *ColumnService) createColumn(ctx context.Context, tx *Tx, create *api.Co
 		&column.CharacterSet,
 		&column.Collation,
 		&column.Comment,
+		&column.Extra,
 	); err != nil {
 		return nil, FormatError(err)
 	}
@@ -224,7 +227,8 @@ func (s *ColumnService) findColumnList(ctx context.Context, tx *Tx, find *api.Co
 			type,
 			character_set,
 			collation,
-			comment
+			comment,
+			extra
 		FROM col
 		WHERE `+strings.Join(where, " AND ")+`
 		ORDER BY database_id, table_id, position ASC`,
@@ -256,6 +260,7 @@ func (s *ColumnService) findColumnList(ctx context.Context, tx *Tx, find *api.Co
 			&column.CharacterSet,
 			&column.Collation,
 			&column.Comment,
+			&column.Extra,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -285,7 +290,7 @@ func (s *ColumnService) patchColumn(ctx context.Context, tx *Tx, patch *api.Colu
 		UPDATE col
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = ?`+
-		"RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, position, `default`, nullable, type, character_set, collation, comment"+`
+		"RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, table_id, name, position, `default`, nullable, type, character_set, collation, comment, extra"+`
 	`,
 		args...,
 	)
@@ -313,6 +318,7 @@ func (s *ColumnService) patchColumn(ctx context.Context, tx *Tx, patch *api.Colu
 			&column.CharacterSet,
 			&column.Collation,
 			&column.Comment,
+			&column.Extra,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/kr/pretty"
+	"go.uber.org/zap"
+)
+
+func TestColumnExtra(t *testing.T) {
+	db := newTestDB(t)
+	mustExec(t, db, `INSERT INTO environment (id, creator_id, updater_id, name, "order") VALUES (101, 1, 1, 'Test', 0)`)
+	mustExec(t, db, `INSERT INTO instance (id, creator_id, updater_id, environment_id, name, engine, host, port) VALUES (101, 1, 1, 101, 'mysql', 'MYSQL', 'localhost', '3306')`)
+	mustExec(t, db, `INSERT INTO project (id, creator_id, updater_id, name, key, workflow_type, visibility, db_name_template) VALUES (101, 1, 1, 'project', 'A', 'UI', 'PUBLIC', '')`)
+	mustExec(t, db, `INSERT INTO db (id, creator_id, updater_id, instance_id, project_id, sync_status, last_successful_sync_ts, schema_version, name, character_set, collation) VALUES (101, 1, 1, 101, 101, 'OK', 0, '', 'test', '', '')`)
+	mustExec(t, db, `INSERT INTO tbl (id, creator_id, updater_id, database_id, name, type, engine, collation, row_count, data_size, index_size, data_free, create_options, comment) VALUES (101, 1, 1, 101, 't', 'BASE TABLE', 'InnoDB', '', 0, 0, 0, 0, '', '')`)
+	// The column synced before the extra column is added has no extra information.
+	mustExec(t, db, `INSERT INTO col (id, creator_id, updater_id, database_id, table_id, name, position, nullable, type, character_set, collation, comment) VALUES (101, 1, 1, 101, 101, 'name', 2, 1, 'varchar(20)', '', '', '')`)
+
+	ctx := context.Background()
+	columnService := NewColumnService(zap.NewNop(), db)
+	nameColumn := "name"
+	column, err := columnService.FindColumn(ctx, &api.ColumnFind{Name: &nameColumn})
+	if err != nil {
+		t.Fatalf("FindColumn(%q) got error: %v", nameColumn, err)
+	}
+	if column.Extra != "" {
+		t.Errorf("FindColumn(%q) got extra %q, want empty", nameColumn, column.Extra)
+	}
+
+	created, err := columnService.CreateColumn(ctx, &api.ColumnCreate{
+		CreatorID:  api.SystemBotID,
+		DatabaseID: 101,
+		TableID:    101,
+		Name:       "id",
+		Position:   1,
+		Type:       "int(11)",
+		Extra:      "auto_increment",
+	})
+	if err != nil {
+		t.Fatalf("CreateColumn() got error: %v", err)
+	}
+	if created.Extra != "auto_increment" {
+		t.Errorf("CreateColumn() got extra %q, want %q", created.Extra, "auto_increment")
+	}
+	idColumn := "id"
+	found, err := columnService.FindColumn(ctx, &api.ColumnFind{Name: &idColumn})
+	if err != nil {
+		t.Fatalf("FindColumn(%q) got error: %v", idColumn, err)
+	}
+	if diff := pretty.Diff(found, created); len(diff) > 0 {
+		t.Errorf("FindColumn(%q) got %+v, want %+v, diff %v", idColumn, found, created, diff)
+	}
+}
@@ -132,10 +132,12 @@ func (s *DataSourceService) createDataSource(ctx context.Context, tx *sql.Tx, cr
 			name,
 			type,
 			username,
-			password
+			password,
+			host_override,
+			port_override
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, host_override, port_override
 	`,
 		create.CreatorID,
 		create.CreatorID,
@@ -145,6 +147,8 @@ func (s *DataSourceService) createDataSource(ctx context.Context, tx *sql.Tx, cr
 		create.Type,
 		create.Username,
 		create.Password,
+		create.HostOverride,
+		create.PortOverride,
 	)
 
 	if err != nil {
@@ -166,6 +170,8 @@ func (s *DataSourceService) createDataSource(ctx context.Context, tx *sql.Tx, cr
 		&dataSource.Type,
 		&dataSource.Username,
 		&dataSource.Password,
+		&dataSource.HostOverride,
+		&dataSource.PortOverride,
 	); err != nil {
 		return nil, FormatError(err)
 	}
@@ -198,7 +204,9 @@ func (s *DataSourceService) findDataSourceList(ctx context.Context, tx *Tx, find
 		    name,
 		    type,
 			username,
-			password
+			password,
+			host_override,
+			port_override
 		FROM data_source
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -224,6 +232,8 @@ func (s *DataSourceService) findDataSourceList(ctx context.Context, tx *Tx, find
 			&dataSource.Type,
 			&dataSource.Username,
 			&dataSource.Password,
+			&dataSource.HostOverride,
+			&dataSource.PortOverride,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -247,6 +257,12 @@ func (s *DataSourceService) patchDataSource(ctx context.Context, tx *Tx, patch *
 	if v := patch.Password; v != nil {
 		set, args = append(set, "password = ?"), append(args, *v)
 	}
+	if v := patch.HostOverride; v != nil {
+		set, args = append(set, "host_override = ?"), append(args, *v)
+	}
+	if v := patch.PortOverride; v != nil {
+		set, args = append(set, "port_override = ?"), append(args, *v)
+	}
 
 	args = append(args, patch.ID)
 
@@ -255,7 +271,7 @@ func (s *DataSourceService) patchDataSource(ctx context.Context, tx *Tx, patch *
 		UPDATE data_source
 		SET `+strings.Join(set, ", ")+`
 		WHERE id = ?
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, host_override, port_override
 	`,
 		args...,
 	)
@@ -278,6 +294,8 @@ func (s *DataSourceService) patchDataSource(ctx context.Context, tx *Tx, patch *
 			&dataSource.Type,
 			&dataSource.Username,
 			&dataSource.Password,
+			&dataSource.HostOverride,
+			&dataSource.PortOverride,
 		); err != nil {
 			return nil, FormatError(err)
 		}
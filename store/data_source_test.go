@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/kr/pretty"
+	"go.uber.org/zap"
+)
+
+func TestDataSourceHostPortOverride(t *testing.T) {
+	db := newTestDB(t)
+	mustExec(t, db, `INSERT INTO environment (id, creator_id, updater_id, name, "order") VALUES (101, 1, 1, 'Test', 0)`)
+	mustExec(t, db, `INSERT INTO instance (id, creator_id, updater_id, environment_id, name, engine, host, port) VALUES (101, 1, 1, 101, 'mysql', 'MYSQL', 'localhost', '3306')`)
+	mustExec(t, db, `INSERT INTO project (id, creator_id, updater_id, name, key, workflow_type, visibility, db_name_template) VALUES (101, 1, 1, 'project', 'A', 'UI', 'PUBLIC', '')`)
+	mustExec(t, db, `INSERT INTO db (id, creator_id, updater_id, instance_id, project_id, sync_status, last_successful_sync_ts, schema_version, name, character_set, collation) VALUES (101, 1, 1, 101, 101, 'OK', 0, '', '*', '', '')`)
+	// The data source created before the override columns are added connects to the instance host and port.
+	mustExec(t, db, `INSERT INTO data_source (id, creator_id, updater_id, instance_id, database_id, name, type, username, password) VALUES (101, 1, 1, 101, 101, 'Admin data source', 'ADMIN', 'root', '')`)
+
+	ctx := context.Background()
+	dataSourceService := NewDataSourceService(zap.NewNop(), db)
+	adminType := api.Admin
+	admin, err := dataSourceService.FindDataSource(ctx, &api.DataSourceFind{Type: &adminType})
+	if err != nil {
+		t.Fatalf("FindDataSource(%s) got error: %v", api.Admin, err)
+	}
+	if admin.HostOverride != "" || admin.PortOverride != "" {
+		t.Errorf("FindDataSource(%s) got host override %q and port override %q, want empty", api.Admin, admin.HostOverride, admin.PortOverride)
+	}
+
+	created, err := dataSourceService.CreateDataSource(ctx, &api.DataSourceCreate{
+		CreatorID:    api.SystemBotID,
+		InstanceID:   101,
+		DatabaseID:   101,
+		Name:         api.ReadOnlyDataSourceName,
+		Type:         api.RO,
+		Username:     "reader",
+		HostOverride: "replica",
+		PortOverride: "3307",
+	})
+	if err != nil {
+		t.Fatalf("CreateDataSource() got error: %v", err)
+	}
+	roType := api.RO
+	found, err := dataSourceService.FindDataSource(ctx, &api.DataSourceFind{Type: &roType})
+	if err != nil {
+		t.Fatalf("FindDataSource(%s) got error: %v", api.RO, err)
+	}
+	if diff := pretty.Diff(found, created); len(diff) > 0 {
+		t.Errorf("FindDataSource(%s) got %+v, want %+v, diff %v", api.RO, found, created, diff)
+	}
+	if found.HostOverride != "replica" || found.PortOverride != "3307" {
+		t.Errorf("FindDataSource(%s) got host override %q and port override %q, want %q and %q", api.RO, found.HostOverride, found.PortOverride, "replica", "3307")
+	}
+
+	// Patching the host override alone keeps the port override.
+	hostOverride := ""
+	patched, err := dataSourceService.PatchDataSource(ctx, &api.DataSourcePatch{
+		ID:           created.ID,
+		UpdaterID:    api.SystemBotID,
+		HostOverride: &hostOverride,
+	})
+	if err != nil {
+		t.Fatalf("PatchDataSource() got error: %v", err)
+	}
+	if patched.HostOverride != "" || patched.PortOverride != "3307" {
+		t.Errorf("PatchDataSource() got host override %q and port override %q, want %q and %q", patched.HostOverride, patched.PortOverride, "", "3307")
+	}
+}
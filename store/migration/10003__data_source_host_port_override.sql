@@ -0,0 +1,6 @@
+PRAGMA user_version = 10003;
+
+-- The data source may connect to a different host and port from the instance, e.g. the read-only data source pointing at a replica.
+-- Empty means using the host and port of the instance.
+ALTER TABLE data_source ADD COLUMN host_override TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_source ADD COLUMN port_override TEXT NOT NULL DEFAULT '';
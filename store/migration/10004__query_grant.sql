@@ -0,0 +1,35 @@
+PRAGMA user_version = 10004;
+
+-- query_grant stores the grants of querying the database in SQL editor, which are created by resolving the data source request issues.
+CREATE TABLE query_grant (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT (strftime('%s', 'now')),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    -- The grant is no longer effective after expire_ts.
+    expire_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_query_grant_database_id_principal_id ON query_grant(database_id, principal_id);
+
+INSERT INTO
+    sqlite_sequence (name, seq)
+VALUES
+    ('query_grant', 100);
+
+CREATE TRIGGER IF NOT EXISTS `trigger_update_query_grant_modification_time`
+AFTER
+UPDATE
+    ON `query_grant` FOR EACH ROW BEGIN
+UPDATE
+    `query_grant`
+SET
+    updated_ts = (strftime('%s', 'now'))
+WHERE
+    rowid = old.rowid;
+
+END;
@@ -0,0 +1,5 @@
+PRAGMA user_version = 10005;
+
+-- The extra information of the MySQL column, e.g. "auto_increment" and "on update CURRENT_TIMESTAMP".
+-- Empty for the other engines.
+ALTER TABLE col ADD COLUMN extra TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- How Bytebase writes back the latest schema file after migration.
+-- COMMIT commits the file to the branch of the push event directly.
+-- MERGE_REQUEST commits the file to a new branch and opens a merge request to the branch of the push event.
+ALTER TABLE repository ADD COLUMN write_back_mode TEXT NOT NULL CHECK (write_back_mode IN ('COMMIT', 'MERGE_REQUEST')) DEFAULT 'COMMIT';
@@ -0,0 +1,23 @@
+-- query_grant stores the grants of querying the database in SQL editor, which are created by resolving the data source request issues.
+CREATE TABLE query_grant (
+    id SERIAL PRIMARY KEY,
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id),
+    principal_id INTEGER NOT NULL REFERENCES principal (id),
+    issue_id INTEGER NOT NULL REFERENCES issue (id),
+    -- The grant is no longer effective after expire_ts.
+    expire_ts BIGINT NOT NULL
+);
+
+CREATE INDEX idx_query_grant_database_id_principal_id ON query_grant(database_id, principal_id);
+
+ALTER SEQUENCE query_grant_id_seq RESTART WITH 100;
+
+CREATE TRIGGER trigger_update_query_grant_modification_time
+AFTER
+UPDATE
+    ON query_grant FOR EACH ROW
+EXECUTE FUNCTION trigger_after_update_updated_ts();
@@ -0,0 +1,3 @@
+-- The extra information of the MySQL column, e.g. "auto_increment" and "on update CURRENT_TIMESTAMP".
+-- Empty for the other engines.
+ALTER TABLE col ADD COLUMN extra TEXT NOT NULL DEFAULT '';
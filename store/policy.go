@@ -233,3 +233,55 @@ func (s *PolicyService) GetPipelineApprovalPolicy(ctx context.Context, environme
 	}
 	return api.UnmarshalPipelineApprovalPolicy(policy.Payload)
 }
+
+// GetMaxExecutionTimePolicy will get the max execution time policy for an environment.
+func (s *PolicyService) GetMaxExecutionTimePolicy(ctx context.Context, environmentID int) (*api.MaxExecutionTimePolicy, error) {
+	pType := api.PolicyTypeMaxExecutionTime
+	policy, err := s.FindPolicy(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalMaxExecutionTimePolicy(policy.Payload)
+}
+
+// GetQueryDataSourcePolicy will get the query data source policy for an environment.
+func (s *PolicyService) GetQueryDataSourcePolicy(ctx context.Context, environmentID int) (*api.QueryDataSourcePolicy, error) {
+	pType := api.PolicyTypeQueryDataSource
+	policy, err := s.FindPolicy(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalQueryDataSourcePolicy(policy.Payload)
+}
+
+// GetDataMaskingPolicy will get the data masking policy for an environment.
+func (s *PolicyService) GetDataMaskingPolicy(ctx context.Context, environmentID int) (*api.DataMaskingPolicy, error) {
+	pType := api.PolicyTypeDataMasking
+	policy, err := s.FindPolicy(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalDataMaskingPolicy(policy.Payload)
+}
+
+// GetSQLReviewPolicy will get the SQL review policy for an environment.
+func (s *PolicyService) GetSQLReviewPolicy(ctx context.Context, environmentID int) (*api.SQLReviewPolicy, error) {
+	pType := api.PolicyTypeSQLReview
+	policy, err := s.FindPolicy(ctx, &api.PolicyFind{
+		EnvironmentID: &environmentID,
+		Type:          &pType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalSQLReviewPolicy(policy.Payload)
+}
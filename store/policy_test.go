@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"go.uber.org/zap"
+)
+
+func TestGetQueryDataSourcePolicy(t *testing.T) {
+	db := newTestDB(t)
+	for _, environmentID := range []int{101, 102} {
+		mustExec(t, db, `INSERT INTO environment (id, creator_id, updater_id, name, "order") VALUES (?, 1, 1, ?, ?)`, environmentID, fmt.Sprintf("environment%d", environmentID), environmentID)
+	}
+
+	ctx := context.Background()
+	policyService := NewPolicyService(zap.NewNop(), db, &noCacheService{})
+	payload, err := api.QueryDataSourcePolicy{AdminFallback: api.QueryDataSourceAdminFallbackDisallow}.String()
+	if err != nil {
+		t.Fatalf("failed to marshal query data source policy: %v", err)
+	}
+	if _, err := policyService.UpsertPolicy(ctx, &api.PolicyUpsert{
+		UpdaterID:     api.SystemBotID,
+		EnvironmentID: 102,
+		Type:          api.PolicyTypeQueryDataSource,
+		Payload:       payload,
+	}); err != nil {
+		t.Fatalf("UpsertPolicy() got error: %v", err)
+	}
+
+	tests := []struct {
+		environmentID int
+		want          api.QueryDataSourceAdminFallback
+	}{
+		{
+			// The environment without the policy allows the admin fallback by default.
+			environmentID: 101,
+			want:          api.QueryDataSourceAdminFallbackAllow,
+		},
+		{
+			environmentID: 102,
+			want:          api.QueryDataSourceAdminFallbackDisallow,
+		},
+	}
+
+	for _, test := range tests {
+		policy, err := policyService.GetQueryDataSourcePolicy(ctx, test.environmentID)
+		if err != nil {
+			t.Errorf("GetQueryDataSourcePolicy(%d) got error: %v", test.environmentID, err)
+			continue
+		}
+		if policy.AdminFallback != test.want {
+			t.Errorf("GetQueryDataSourcePolicy(%d) got admin fallback %q, want %q", test.environmentID, policy.AdminFallback, test.want)
+		}
+	}
+
+	// The invalid admin fallback is rejected.
+	if _, err := policyService.UpsertPolicy(ctx, &api.PolicyUpsert{
+		UpdaterID:     api.SystemBotID,
+		EnvironmentID: 101,
+		Type:          api.PolicyTypeQueryDataSource,
+		Payload:       `{"adminFallback":"SOMETIMES"}`,
+	}); err == nil {
+		t.Errorf("UpsertPolicy() with invalid admin fallback got no error")
+	}
+}
@@ -0,0 +1,241 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"go.uber.org/zap"
+)
+
+var (
+	_ api.QueryGrantService = (*QueryGrantService)(nil)
+)
+
+// QueryGrantService represents a service for managing query grant.
+type QueryGrantService struct {
+	l  *zap.Logger
+	db *DB
+}
+
+// NewQueryGrantService returns a new instance of QueryGrantService.
+func NewQueryGrantService(logger *zap.Logger, db *DB) *QueryGrantService {
+	return &QueryGrantService{l: logger, db: db}
+}
+
+// CreateQueryGrant creates a new query grant.
+func (s *QueryGrantService) CreateQueryGrant(ctx context.Context, create *api.QueryGrantCreate) (*api.QueryGrant, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Tx.Rollback()
+	defer tx.PTx.Rollback()
+
+	grant, err := createQueryGrant(ctx, tx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	if err := tx.PTx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return grant, nil
+}
+
+// FindQueryGrantList retrieves a list of query grants based on find.
+func (s *QueryGrantService) FindQueryGrantList(ctx context.Context, find *api.QueryGrantFind) ([]*api.QueryGrant, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Tx.Rollback()
+	defer tx.PTx.Rollback()
+
+	list, err := findQueryGrantList(ctx, tx, find)
+	if err != nil {
+		return []*api.QueryGrant{}, err
+	}
+
+	return list, nil
+}
+
+// FindQueryGrant retrieves a single query grant based on find.
+// Returns ECONFLICT if finding more than 1 matching records.
+func (s *QueryGrantService) FindQueryGrant(ctx context.Context, find *api.QueryGrantFind) (*api.QueryGrant, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Tx.Rollback()
+	defer tx.PTx.Rollback()
+
+	list, err := findQueryGrantList(ctx, tx, find)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(list) == 0 {
+		return nil, nil
+	} else if len(list) > 1 {
+		return nil, &common.Error{Code: common.Conflict, Err: fmt.Errorf("found %d query grants with filter %+v, expect 1", len(list), find)}
+	}
+	return list[0], nil
+}
+
+// DeleteQueryGrant deletes an existing query grant by ID.
+// Returns ENOTFOUND if query grant does not exist.
+func (s *QueryGrantService) DeleteQueryGrant(ctx context.Context, delete *api.QueryGrantDelete) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return FormatError(err)
+	}
+	defer tx.Tx.Rollback()
+	defer tx.PTx.Rollback()
+
+	if err := deleteQueryGrant(ctx, tx, delete); err != nil {
+		return FormatError(err)
+	}
+
+	if err := tx.Tx.Commit(); err != nil {
+		return FormatError(err)
+	}
+	if err := tx.PTx.Commit(); err != nil {
+		return FormatError(err)
+	}
+
+	return nil
+}
+
+// createQueryGrant creates a new query grant.
+func createQueryGrant(ctx context.Context, tx *Tx, create *api.QueryGrantCreate) (*api.QueryGrant, error) {
+	// Insert row into database.
+	row, err := tx.Tx.QueryContext(ctx, `
+		INSERT INTO query_grant (
+			creator_id,
+			updater_id,
+			database_id,
+			principal_id,
+			issue_id,
+			expire_ts
+		)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, principal_id, issue_id, expire_ts
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.PrincipalID,
+		create.IssueID,
+		create.ExpireTs,
+	)
+
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer row.Close()
+
+	row.Next()
+	var grant api.QueryGrant
+	if err := row.Scan(
+		&grant.ID,
+		&grant.CreatorID,
+		&grant.CreatedTs,
+		&grant.UpdaterID,
+		&grant.UpdatedTs,
+		&grant.DatabaseID,
+		&grant.PrincipalID,
+		&grant.IssueID,
+		&grant.ExpireTs,
+	); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return &grant, nil
+}
+
+func findQueryGrantList(ctx context.Context, tx *Tx, find *api.QueryGrantFind) (_ []*api.QueryGrant, err error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, "id = ?"), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, "database_id = ?"), append(args, *v)
+	}
+	if v := find.PrincipalID; v != nil {
+		where, args = append(where, "principal_id = ?"), append(args, *v)
+	}
+	if v := find.ExpireTsAfter; v != nil {
+		where, args = append(where, "expire_ts > ?"), append(args, *v)
+	}
+
+	rows, err := tx.Tx.QueryContext(ctx, `
+		SELECT
+		    id,
+		    creator_id,
+		    created_ts,
+		    updater_id,
+		    updated_ts,
+		    database_id,
+		    principal_id,
+		    issue_id,
+		    expire_ts
+		FROM query_grant
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY expire_ts DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	// Iterate over result set and deserialize rows into list.
+	list := make([]*api.QueryGrant, 0)
+	for rows.Next() {
+		var grant api.QueryGrant
+		if err := rows.Scan(
+			&grant.ID,
+			&grant.CreatorID,
+			&grant.CreatedTs,
+			&grant.UpdaterID,
+			&grant.UpdatedTs,
+			&grant.DatabaseID,
+			&grant.PrincipalID,
+			&grant.IssueID,
+			&grant.ExpireTs,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+
+		list = append(list, &grant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+
+	return list, nil
+}
+
+// deleteQueryGrant permanently deletes a query grant by ID.
+func deleteQueryGrant(ctx context.Context, tx *Tx, delete *api.QueryGrantDelete) error {
+	// Remove row from database.
+	result, err := tx.Tx.ExecContext(ctx, `DELETE FROM query_grant WHERE id = ?`, delete.ID)
+	if err != nil {
+		return FormatError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return &common.Error{Code: common.NotFound, Err: fmt.Errorf("query grant ID not found: %d", delete.ID)}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"go.uber.org/zap"
+)
+
+func TestQueryGrant(t *testing.T) {
+	db := newTestDB(t)
+	mustExec(t, db, `INSERT INTO environment (id, creator_id, updater_id, name, "order") VALUES (101, 1, 1, 'Test', 0)`)
+	mustExec(t, db, `INSERT INTO instance (id, creator_id, updater_id, environment_id, name, engine, host, port) VALUES (101, 1, 1, 101, 'mysql', 'MYSQL', 'localhost', '3306')`)
+	mustExec(t, db, `INSERT INTO project (id, creator_id, updater_id, name, key, workflow_type, visibility, db_name_template) VALUES (101, 1, 1, 'project', 'A', 'UI', 'PUBLIC', '')`)
+	for _, databaseID := range []int{101, 102} {
+		mustExec(t, db, `INSERT INTO db (id, creator_id, updater_id, instance_id, project_id, sync_status, last_successful_sync_ts, schema_version, name, character_set, collation) VALUES (?, 1, 1, 101, 101, 'OK', 0, '', ?, '', '')`,
+			databaseID, string(rune('a'+databaseID-101)))
+	}
+	mustExec(t, db, `INSERT INTO pipeline (id, creator_id, updater_id, name, status) VALUES (101, 1, 1, 'pipeline', 'DONE')`)
+	mustExec(t, db, `INSERT INTO issue (id, creator_id, updater_id, project_id, pipeline_id, name, status, type, assignee_id) VALUES (101, 1, 1, 101, 101, 'issue', 'DONE', 'bb.issue.data-source.request', 1)`)
+
+	ctx := context.Background()
+	queryGrantService := NewQueryGrantService(zap.NewNop(), db)
+	now := time.Now().Unix()
+	var idList []int
+	for _, create := range []*api.QueryGrantCreate{
+		{CreatorID: api.SystemBotID, DatabaseID: 101, PrincipalID: api.SystemBotID, IssueID: 101, ExpireTs: now + 3600},
+		{CreatorID: api.SystemBotID, DatabaseID: 101, PrincipalID: api.SystemBotID, IssueID: 101, ExpireTs: now - 3600},
+		{CreatorID: api.SystemBotID, DatabaseID: 102, PrincipalID: api.SystemBotID, IssueID: 101, ExpireTs: now + 3600},
+	} {
+		grant, err := queryGrantService.CreateQueryGrant(ctx, create)
+		if err != nil {
+			t.Fatalf("CreateQueryGrant(%+v) got error: %v", create, err)
+		}
+		if grant.DatabaseID != create.DatabaseID || grant.ExpireTs != create.ExpireTs {
+			t.Errorf("CreateQueryGrant(%+v) got %+v", create, grant)
+		}
+		idList = append(idList, grant.ID)
+	}
+
+	databaseID := 101
+	principalID := api.SystemBotID
+	tests := []struct {
+		name       string
+		find       *api.QueryGrantFind
+		wantIDList []int
+	}{
+		{
+			name:       "database",
+			find:       &api.QueryGrantFind{DatabaseID: &databaseID},
+			wantIDList: []int{idList[0], idList[1]},
+		},
+		{
+			name:       "effective grants of principal on database",
+			find:       &api.QueryGrantFind{DatabaseID: &databaseID, PrincipalID: &principalID, ExpireTsAfter: &now},
+			wantIDList: []int{idList[0]},
+		},
+		{
+			name:       "grant of another database",
+			find:       &api.QueryGrantFind{ID: &idList[2], DatabaseID: &databaseID},
+			wantIDList: nil,
+		},
+	}
+	for _, test := range tests {
+		grantList, err := queryGrantService.FindQueryGrantList(ctx, test.find)
+		if err != nil {
+			t.Errorf("%s: FindQueryGrantList() got error: %v", test.name, err)
+			continue
+		}
+		var gotIDList []int
+		for _, grant := range grantList {
+			gotIDList = append(gotIDList, grant.ID)
+		}
+		if len(gotIDList) != len(test.wantIDList) {
+			t.Errorf("%s: FindQueryGrantList() got %v, want %v", test.name, gotIDList, test.wantIDList)
+			continue
+		}
+		// The grants are ordered by the expiration time descending.
+		for i := range gotIDList {
+			if gotIDList[i] != test.wantIDList[i] {
+				t.Errorf("%s: FindQueryGrantList() got %v, want %v", test.name, gotIDList, test.wantIDList)
+				break
+			}
+		}
+	}
+
+	if err := queryGrantService.DeleteQueryGrant(ctx, &api.QueryGrantDelete{ID: idList[0], DeleterID: api.SystemBotID}); err != nil {
+		t.Fatalf("DeleteQueryGrant() got error: %v", err)
+	}
+	grant, err := queryGrantService.FindQueryGrant(ctx, &api.QueryGrantFind{ID: &idList[0]})
+	if err != nil || grant != nil {
+		t.Errorf("FindQueryGrant() after deletion got %+v and error %v, want neither", grant, err)
+	}
+	if err := queryGrantService.DeleteQueryGrant(ctx, &api.QueryGrantDelete{ID: idList[0], DeleterID: api.SystemBotID}); common.ErrorCode(err) != common.NotFound {
+		t.Errorf("DeleteQueryGrant() of the deleted grant got error %v, want the not found error", err)
+	}
+}
+
+func TestQueryGrantMigration(t *testing.T) {
+	db := newTestDB(t)
+	var version int
+	if err := db.Db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		t.Fatalf("failed to query user version: %v", err)
+	}
+	if version < 10004 {
+		t.Errorf("user version got %d, want at least 10004", version)
+	}
+	var index string
+	if err := db.Db.QueryRow(`SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = 'query_grant'`).Scan(&index); err != nil {
+		t.Fatalf("failed to query the index of query_grant: %v", err)
+	}
+	if index != "idx_query_grant_database_id_principal_id" {
+		t.Errorf("index of query_grant got %q, want %q", index, "idx_query_grant_database_id_principal_id")
+	}
+	// The IDs up to 100 are reserved for the seed data.
+	var seq int
+	if err := db.Db.QueryRow(`SELECT seq FROM sqlite_sequence WHERE name = 'query_grant'`).Scan(&seq); err != nil {
+		t.Fatalf("failed to query the sequence of query_grant: %v", err)
+	}
+	if seq != 100 {
+		t.Errorf("sequence of query_grant got %d, want 100", seq)
+	}
+	if _, err := db.Db.Exec(`INSERT INTO query_grant (creator_id, updater_id, database_id, principal_id, issue_id) VALUES (1, 1, 1, 1, 1)`); err == nil {
+		t.Errorf("inserting query grant without expire_ts got no error")
+	}
+}
@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"go.uber.org/zap"
+)
+
+func TestRepositoryWriteBackMode(t *testing.T) {
+	db := newTestDB(t)
+	mustExec(t, db, `INSERT INTO vcs (id, creator_id, updater_id, name, type, instance_url, api_url, application_id, secret) VALUES (101, 1, 1, 'GitLab', 'GITLAB_SELF_HOST', 'https://gitlab.example.com', 'https://gitlab.example.com/api/v4', 'app', 'secret')`)
+	for _, projectID := range []int{101, 102, 103} {
+		mustExec(t, db, `INSERT INTO project (id, creator_id, updater_id, name, key, workflow_type, visibility, db_name_template) VALUES (?, 1, 1, 'project', ?, 'UI', 'PUBLIC', '')`,
+			projectID, string(rune('A'+projectID-101)))
+	}
+
+	ctx := context.Background()
+	logger := zap.NewNop()
+	repositoryService := NewRepositoryService(logger, db, NewProjectService(logger, db, &noCacheService{}))
+	created, err := repositoryService.CreateRepository(ctx, &api.RepositoryCreate{
+		CreatorID:         api.SystemBotID,
+		VCSID:             101,
+		ProjectID:         101,
+		Name:              "repo",
+		WriteBackMode:     api.RepositoryWriteBackMergeRequest,
+		ExternalID:        "1",
+		WebhookEndpointID: "1",
+	})
+	if err != nil {
+		t.Fatalf("CreateRepository() got error: %v", err)
+	}
+	if created.WriteBackMode != api.RepositoryWriteBackMergeRequest {
+		t.Errorf("CreateRepository() got write back mode %q, want %q", created.WriteBackMode, api.RepositoryWriteBackMergeRequest)
+	}
+	found, err := repositoryService.FindRepository(ctx, &api.RepositoryFind{ID: &created.ID})
+	if err != nil {
+		t.Fatalf("FindRepository() got error: %v", err)
+	}
+	if found.WriteBackMode != api.RepositoryWriteBackMergeRequest {
+		t.Errorf("FindRepository() got write back mode %q, want %q", found.WriteBackMode, api.RepositoryWriteBackMergeRequest)
+	}
+
+	writeBackMode := api.RepositoryWriteBackCommit
+	patched, err := repositoryService.PatchRepository(ctx, &api.RepositoryPatch{
+		ID:            created.ID,
+		UpdaterID:     api.SystemBotID,
+		WriteBackMode: &writeBackMode,
+	})
+	if err != nil {
+		t.Fatalf("PatchRepository() got error: %v", err)
+	}
+	if patched.WriteBackMode != api.RepositoryWriteBackCommit {
+		t.Errorf("PatchRepository() got write back mode %q, want %q", patched.WriteBackMode, api.RepositoryWriteBackCommit)
+	}
+
+	// Migration 10002 defaults the write back mode of the existing repositories to COMMIT.
+	mustExec(t, db, `INSERT INTO repository (creator_id, updater_id, vcs_id, project_id, name, full_path, web_url, base_directory, file_path_template, schema_path_template, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token)
+		VALUES (1, 1, 101, 102, 'repo', '', '', '', '', '', '2', '', '', '2', '', '', 0, '')`)
+	projectID := 102
+	found, err = repositoryService.FindRepository(ctx, &api.RepositoryFind{ProjectID: &projectID})
+	if err != nil {
+		t.Fatalf("FindRepository() got error: %v", err)
+	}
+	if found.WriteBackMode != api.RepositoryWriteBackCommit {
+		t.Errorf("FindRepository() got default write back mode %q, want %q", found.WriteBackMode, api.RepositoryWriteBackCommit)
+	}
+
+	// Migration 10002 only allows the known write back modes.
+	if _, err := db.Db.Exec(`INSERT INTO repository (creator_id, updater_id, vcs_id, project_id, name, full_path, web_url, base_directory, file_path_template, schema_path_template, write_back_mode, external_id, external_webhook_id, webhook_url_host, webhook_endpoint_id, webhook_secret_token, access_token, expires_ts, refresh_token)
+		VALUES (1, 1, 101, 103, 'repo', '', '', '', '', '', 'PUSH', '3', '', '', '3', '', '', 0, '')`); err == nil {
+		t.Errorf("inserting repository with write back mode %q got OK, want error", "PUSH")
+	}
+}
@@ -39,7 +39,7 @@ const (
 	// If the new release requires a higher MINOR version than the schema file, then it will apply the migration upon
 	// startup.
 	majorSchemaVervion = 1
-	minorSchemaVersion = 1
+	minorSchemaVersion = 5
 )
 
 // If both debug and sqlite_trace build tags are enabled, then sqliteDriver will be set to "sqlite3_trace" in sqlite_trace.go
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"database/sql"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+
+	// Register the sqlite3 driver.
+	_ "github.com/mattn/go-sqlite3"
+	"go.uber.org/zap"
+)
+
+// newTestDB returns the store with all the SQLite migrations applied.
+// The services don't query the Postgres store yet but begin the transactions on it, so a SQLite database stands in for it.
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	db := NewDB(zap.NewNop(), filepath.Join(t.TempDir(), "bytebase_test.db"), "", "", false, false, "test")
+	var err error
+	if db.Db, err = sql.Open(sqliteDriver, db.DSN); err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	if db.PgDB, err = sql.Open(sqliteDriver, ":memory:"); err != nil {
+		t.Fatalf("failed to open stand-in Postgres store: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	names, err := fs.Glob(migrationFS, "migration/*.sql")
+	if err != nil {
+		t.Fatalf("failed to list migration files: %v", err)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := db.migrateFile(name, true); err != nil {
+			t.Fatalf("failed to apply migration file %q: %v", name, err)
+		}
+	}
+	return db
+}
+
+// mustExec executes the statement to set up the test data.
+func mustExec(t *testing.T, db *DB, statement string, args ...interface{}) {
+	t.Helper()
+	if _, err := db.Db.Exec(statement, args...); err != nil {
+		t.Fatalf("failed to execute %q: %v", statement, err)
+	}
+}
+
+// noCacheService is the cache service which never hits, so the services always read from the store.
+type noCacheService struct{}
+
+func (*noCacheService) FindCache(_ api.CacheNamespace, _ int, _ interface{}) (bool, error) {
+	return false, nil
+}
+
+func (*noCacheService) UpsertCache(_ api.CacheNamespace, _ int, _ interface{}) error {
+	return nil
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -270,6 +271,30 @@ func (s *TaskService) findTaskList(ctx context.Context, tx *Tx, find *api.TaskFi
 		}
 		where = append(where, fmt.Sprintf("status in (%s)", strings.Join(list, ",")))
 	}
+	if find.ProjectID != nil || find.IssueStatusList != nil {
+		issueWhere := []string{"1 = 1"}
+		if v := find.ProjectID; v != nil {
+			issueWhere, args = append(issueWhere, "project_id = ?"), append(args, *v)
+		}
+		if v := find.IssueStatusList; v != nil {
+			list := []string{}
+			for _, status := range *v {
+				list = append(list, "?")
+				args = append(args, status)
+			}
+			issueWhere = append(issueWhere, fmt.Sprintf("status in (%s)", strings.Join(list, ",")))
+		}
+		where = append(where, fmt.Sprintf("pipeline_id IN (SELECT pipeline_id FROM issue WHERE %s)", strings.Join(issueWhere, " AND ")))
+	}
+	if v := find.VCSPushEventFile; v != nil {
+		// The payload is matched as text, the file is the JSON string of the push event's file commit in the payload.
+		// The LIKE pattern may also match the same text elsewhere in the payload, so the caller should check the push event.
+		file, err := json.Marshal(*v)
+		if err != nil {
+			return nil, err
+		}
+		where, args = append(where, `payload LIKE ? ESCAPE '\'`), append(args, "%"+escapeLikePattern(`"added":`+string(file))+"%")
+	}
 
 	rows, err := tx.Tx.QueryContext(ctx, `
 		SELECT
@@ -530,3 +555,8 @@ func (s *TaskService) patchTaskStatus(ctx context.Context, tx *Tx, patch *api.Ta
 
 	return nil, &common.Error{Code: common.NotFound, Err: fmt.Errorf("task ID not found: %d", patch.ID)}
 }
+
+// escapeLikePattern escapes the wildcards of the LIKE pattern with the escape character '\'.
+func escapeLikePattern(pattern string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(pattern)
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/vcs"
+	"go.uber.org/zap"
+)
+
+func TestFindTaskListByProjectAndPushEventFile(t *testing.T) {
+	db := newTestDB(t)
+	mustExec(t, db, `INSERT INTO environment (id, creator_id, updater_id, name, "order") VALUES (101, 1, 1, 'Test', 0)`)
+	mustExec(t, db, `INSERT INTO instance (id, creator_id, updater_id, environment_id, name, engine, host, port) VALUES (101, 1, 1, 101, 'mysql', 'MYSQL', 'localhost', '3306')`)
+	for _, projectID := range []int{101, 102} {
+		mustExec(t, db, `INSERT INTO project (id, creator_id, updater_id, name, key, workflow_type, visibility, db_name_template) VALUES (?, 1, 1, ?, ?, 'VCS', 'PUBLIC', '')`,
+			projectID, "project", string(rune('A'+projectID-101)))
+	}
+	// The issues keyed by the pipeline ID, and the tasks are created from the files in the pipelines.
+	issueList := []struct {
+		pipelineID int
+		projectID  int
+		status     api.IssueStatus
+		fileList   []string
+	}{
+		{pipelineID: 101, projectID: 101, status: api.IssueOpen, fileList: []string{"bytebase/v1__db1__create_t.sql", "bytebase/v1__db1__creatext.sql"}},
+		{pipelineID: 102, projectID: 101, status: api.IssueDone, fileList: []string{"bytebase/v1__db1__create_t.sql"}},
+		{pipelineID: 103, projectID: 102, status: api.IssueOpen, fileList: []string{"bytebase/v1__db1__create_t.sql"}},
+		{pipelineID: 104, projectID: 101, status: api.IssueOpen, fileList: []string{"bytebase/v2__db1__100%_data.sql"}},
+	}
+	taskID := 100
+	for _, issue := range issueList {
+		mustExec(t, db, `INSERT INTO pipeline (id, creator_id, updater_id, name, status) VALUES (?, 1, 1, 'pipeline', 'OPEN')`, issue.pipelineID)
+		mustExec(t, db, `INSERT INTO stage (id, creator_id, updater_id, pipeline_id, environment_id, name) VALUES (?, 1, 1, ?, 101, 'stage')`, issue.pipelineID, issue.pipelineID)
+		mustExec(t, db, `INSERT INTO issue (creator_id, updater_id, project_id, pipeline_id, name, status, type, assignee_id) VALUES (1, 1, ?, ?, 'issue', ?, 'bb.issue.database.schema.update', 1)`,
+			issue.projectID, issue.pipelineID, issue.status)
+		for _, file := range issue.fileList {
+			payload, err := json.Marshal(api.TaskDatabaseSchemaUpdatePayload{
+				Statement: "CREATE TABLE t (id INT)",
+				VCSPushEvent: &vcs.PushEvent{
+					FileCommit: vcs.FileCommit{Added: file},
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to marshal task payload: %v", err)
+			}
+			taskID++
+			mustExec(t, db, `INSERT INTO task (id, creator_id, updater_id, pipeline_id, stage_id, instance_id, name, status, type, payload) VALUES (?, 1, 1, ?, ?, 101, 'task', 'PENDING', 'bb.task.database.schema.update', ?)`,
+				taskID, issue.pipelineID, issue.pipelineID, string(payload))
+		}
+	}
+
+	logger := zap.NewNop()
+	taskService := NewTaskService(logger, db, NewTaskRunService(logger, db), NewTaskCheckRunService(logger, db))
+	projectID := 101
+	issueStatusList := []api.IssueStatus{api.IssueOpen}
+	tests := []struct {
+		file string
+		// wantTaskIDList is the IDs of the tasks found by the file in the open issues of the project.
+		wantTaskIDList []int
+	}{
+		{
+			// The task created from "bytebase/v1__db1__creatext.sql" isn't matched by "_" as the LIKE wildcard.
+			file:           "bytebase/v1__db1__create_t.sql",
+			wantTaskIDList: []int{101},
+		},
+		{
+			file:           "bytebase/v2__db1__100%_data.sql",
+			wantTaskIDList: []int{105},
+		},
+		{
+			file: "bytebase/v3__db1__create_t.sql",
+		},
+	}
+
+	for _, test := range tests {
+		file := test.file
+		taskList, err := taskService.FindTaskList(context.Background(), &api.TaskFind{
+			ProjectID:        &projectID,
+			IssueStatusList:  &issueStatusList,
+			VCSPushEventFile: &file,
+		})
+		if err != nil {
+			t.Fatalf("FindTaskList(%q) got error: %v", test.file, err)
+		}
+		var taskIDList []int
+		for _, task := range taskList {
+			taskIDList = append(taskIDList, task.ID)
+		}
+		sort.Ints(taskIDList)
+		if len(taskIDList) != len(test.wantTaskIDList) {
+			t.Errorf("FindTaskList(%q) got tasks %v, want %v", test.file, taskIDList, test.wantTaskIDList)
+			continue
+		}
+		for i := range taskIDList {
+			if taskIDList[i] != test.wantTaskIDList[i] {
+				t.Errorf("FindTaskList(%q) got tasks %v, want %v", test.file, taskIDList, test.wantTaskIDList)
+				break
+			}
+		}
+	}
+}
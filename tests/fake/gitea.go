@@ -0,0 +1,258 @@
+package fake
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/vcs/gitea"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Gitea is a fake implementation of Gitea.
+type Gitea struct {
+	port int
+	Echo *echo.Echo
+
+	client *http.Client
+
+	nextWebhookID int
+	repositories  map[string]*giteaRepositoryData
+}
+
+type giteaRepositoryData struct {
+	webhooks []*gitea.WebhookPost
+	files    map[string]string
+}
+
+// NewGitea creates a fake Gitea.
+func NewGitea(port int) *Gitea {
+	e := echo.New()
+	// Middleware
+	e.Use(middleware.Logger())
+	e.Use(middleware.Recover())
+
+	gt := &Gitea{
+		port:          port,
+		Echo:          e,
+		client:        &http.Client{},
+		nextWebhookID: 20220113,
+		repositories:  map[string]*giteaRepositoryData{},
+	}
+
+	// Routes
+	repositoryGroup := e.Group("/api/v1")
+	repositoryGroup.POST("/repos/:owner/:repo/hooks", gt.createRepositoryHook)
+	repositoryGroup.GET("/repos/:owner/:repo/raw/*", gt.readRepositoryFile)
+	repositoryGroup.GET("/repos/:owner/:repo/contents/*", gt.readRepositoryFileMetadata)
+	repositoryGroup.POST("/repos/:owner/:repo/contents/*", gt.createRepositoryFile)
+	repositoryGroup.PUT("/repos/:owner/:repo/contents/*", gt.createRepositoryFile)
+
+	return gt
+}
+
+// Run runs a Gitea server.
+func (gt *Gitea) Run() error {
+	return gt.Echo.Start(fmt.Sprintf(":%d", gt.port))
+}
+
+// Close close a Gitea server.
+func (gt *Gitea) Close() error {
+	return gt.Echo.Close()
+}
+
+// CreateRepository creates a Gitea repository with the full name, e.g. "owner/repo".
+func (gt *Gitea) CreateRepository(fullName string) {
+	gt.repositories[fullName] = &giteaRepositoryData{
+		files: map[string]string{},
+	}
+}
+
+// createRepositoryHook creates a repository webhook.
+func (gt *Gitea) createRepositoryHook(c echo.Context) error {
+	fullName := repositoryFullName(c)
+	c.Logger().Info("create webhook for repository %q", fullName)
+	b, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return fmt.Errorf("failed to read create repository hook request body, error %w", err)
+	}
+	webhookPost := &gitea.WebhookPost{}
+	if err := json.Unmarshal(b, webhookPost); err != nil {
+		return fmt.Errorf("failed to unmarshal create repository hook request body, error %w", err)
+	}
+	rd, ok := gt.repositories[fullName]
+	if !ok {
+		return fmt.Errorf("gitea repository %q doesn't exist", fullName)
+	}
+	rd.webhooks = append(rd.webhooks, webhookPost)
+
+	if err := json.NewEncoder(c.Response().Writer).Encode(&gitea.WebhookInfo{
+		ID: gt.nextWebhookID,
+	}); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal WebhookInfo response").SetInternal(err)
+	}
+	gt.nextWebhookID++
+
+	return nil
+}
+
+// readRepositoryFile reads a repository file.
+func (gt *Gitea) readRepositoryFile(c echo.Context) error {
+	fullName := repositoryFullName(c)
+	fileName, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("failed to path unescape %q, error: %v", c.Param("*"), err))
+	}
+
+	rd, ok := gt.repositories[fullName]
+	if !ok {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("gitea repository %q doesn't exist", fullName))
+	}
+
+	content, ok := rd.files[fileName]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("file %q not found", fileName))
+	}
+
+	return c.String(http.StatusOK, content)
+}
+
+// readRepositoryFileMetadata reads the metadata of a repository file.
+func (gt *Gitea) readRepositoryFileMetadata(c echo.Context) error {
+	fullName := repositoryFullName(c)
+	fileName, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("failed to path unescape %q, error: %v", c.Param("*"), err))
+	}
+
+	rd, ok := gt.repositories[fullName]
+	if !ok {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("gitea repository %q doesn't exist", fullName))
+	}
+
+	content, ok := rd.files[fileName]
+	if !ok {
+		return c.String(http.StatusNotFound, fmt.Sprintf("file %q not found", fileName))
+	}
+
+	buf, err := json.Marshal(&gitea.File{
+		SHA: blobSHA(content),
+	})
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to marshal File, error %v", err))
+	}
+
+	return c.String(http.StatusOK, string(buf))
+}
+
+// createRepositoryFile creates or updates a repository file.
+func (gt *Gitea) createRepositoryFile(c echo.Context) error {
+	fullName := repositoryFullName(c)
+	fileName, err := url.PathUnescape(c.Param("*"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("failed to path unescape %q, error: %v", c.Param("*"), err))
+	}
+	b, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, fmt.Sprintf("failed to read create repository file request body, error %v", err))
+	}
+	fileCommit := &gitea.FileCommit{}
+	if err := json.Unmarshal(b, fileCommit); err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("failed to unmarshal create repository file request body, error %v", err))
+	}
+	content, err := base64.StdEncoding.DecodeString(fileCommit.Content)
+	if err != nil {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("failed to decode file content, error %v", err))
+	}
+
+	rd, ok := gt.repositories[fullName]
+	if !ok {
+		return c.String(http.StatusBadRequest, fmt.Sprintf("gitea repository %q doesn't exist", fullName))
+	}
+
+	// Updating a file requires the blob SHA of the existing file.
+	if c.Request().Method == http.MethodPut {
+		existing, ok := rd.files[fileName]
+		if !ok {
+			return c.String(http.StatusNotFound, fmt.Sprintf("file %q not found", fileName))
+		}
+		if fileCommit.SHA != blobSHA(existing) {
+			return c.String(http.StatusConflict, fmt.Sprintf("file %q sha mismatch", fileName))
+		}
+	}
+
+	// Save file.
+	rd.files[fileName] = string(content)
+
+	return c.String(http.StatusOK, "")
+}
+
+// SendCommits sends comments to webhooks.
+func (gt *Gitea) SendCommits(fullName string, webhookPushEvent *gitea.WebhookPushEvent) error {
+	rd, ok := gt.repositories[fullName]
+	if !ok {
+		return fmt.Errorf("gitea repository %q doesn't exist", fullName)
+	}
+
+	// Trigger webhooks.
+	for _, webhook := range rd.webhooks {
+		// Send post request.
+		buf, err := json.Marshal(webhookPushEvent)
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhookPushEvent, error %w", err)
+		}
+		req, err := http.NewRequest("POST", webhook.Config.URL, strings.NewReader(string(buf)))
+		if err != nil {
+			return fmt.Errorf("fail to create a new POST request(%q), error: %w", webhook.Config.URL, err)
+		}
+		req.Header.Set(gitea.EventHeader, string(gitea.WebhookPush))
+		req.Header.Set(gitea.SignatureHeader, gitea.Signature(webhook.Config.Secret, buf))
+		resp, err := gt.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("fail to send a POST request(%q), error: %w", webhook.Config.URL, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read http response body, error: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("http response error code %v body %q", resp.StatusCode, string(body))
+		}
+		gt.Echo.Logger.Infof("SendCommits response body %s\n", body)
+	}
+
+	return nil
+}
+
+// AddFiles add files to repository.
+func (gt *Gitea) AddFiles(fullName string, files map[string]string) error {
+	rd, ok := gt.repositories[fullName]
+	if !ok {
+		return fmt.Errorf("gitea repository %q doesn't exist", fullName)
+	}
+
+	// Save files
+	for name, content := range files {
+		rd.files[name] = content
+	}
+	return nil
+}
+
+// repositoryFullName returns the repository full name from the request path.
+func repositoryFullName(c echo.Context) string {
+	return fmt.Sprintf("%s/%s", c.Param("owner"), c.Param("repo"))
+}
+
+// blobSHA returns the Git blob SHA of the content.
+func blobSHA(content string) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("blob %d\x00%s", len(content), content)))
+	return hex.EncodeToString(sum[:])
+}
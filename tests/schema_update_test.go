@@ -11,6 +11,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/vcs"
+	"github.com/bytebase/bytebase/plugin/vcs/gitea"
 	"github.com/bytebase/bytebase/plugin/vcs/gitlab"
 	"github.com/kr/pretty"
 )
@@ -580,3 +581,149 @@ func TestVCS(t *testing.T) {
 		}
 	}
 }
+
+func TestGiteaVCS(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	ctl := &controller{}
+	dataDir := t.TempDir()
+	if err := ctl.StartMain(ctx, dataDir, getTestPort(t.Name())); err != nil {
+		t.Fatal(err)
+	}
+	defer ctl.Close()
+	if err := ctl.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctl.setLicense(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a VCS.
+	vcs, err := ctl.createVCS(api.VCSCreate{
+		Name:          "TestGiteaVCS",
+		Type:          vcs.Gitea,
+		InstanceURL:   ctl.giteaURL,
+		APIURL:        fmt.Sprintf("%s/api/v1", ctl.giteaURL),
+		ApplicationID: "testApplicationID",
+		Secret:        "testApplicationSecret",
+	})
+	if err != nil {
+		t.Fatalf("failed to create VCS, error: %v", err)
+	}
+
+	// Create a project.
+	project, err := ctl.createProject(api.ProjectCreate{
+		Name: "Test Gitea VCS Project",
+		Key:  "TestGiteaVCS",
+	})
+	if err != nil {
+		t.Fatalf("failed to create project, error: %v", err)
+	}
+
+	// Create a repository.
+	repositoryFullName := "test/schemaUpdate"
+	ctl.gitea.CreateRepository(repositoryFullName)
+	_, err = ctl.createRepository(api.RepositoryCreate{
+		VCSID:              vcs.ID,
+		ProjectID:          project.ID,
+		Name:               "Test Repository",
+		FullPath:           repositoryFullName,
+		WebURL:             fmt.Sprintf("%s/%s", ctl.giteaURL, repositoryFullName),
+		BranchFilter:       "feature/foo",
+		BaseDirectory:      "bbtest",
+		FilePathTemplate:   "{{ENV_NAME}}/{{DB_NAME}}__{{VERSION}}__{{TYPE}}__{{DESCRIPTION}}.sql",
+		SchemaPathTemplate: "{{ENV_NAME}}/.{{DB_NAME}}__LATEST.sql",
+		ExternalID:         repositoryFullName,
+		AccessToken:        "accessToken1",
+		ExpiresTs:          0,
+		RefreshToken:       "refreshToken1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create repository, error: %v", err)
+	}
+
+	// Provision an instance.
+	instanceRootDir := t.TempDir()
+	instanceName := "testInstance1"
+	instanceDir, err := ctl.provisionSQLiteInstance(instanceRootDir, instanceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	environments, err := ctl.getEnvironments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prodEnvironment, err := findEnvironment(environments, "Prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Add an instance.
+	instance, err := ctl.addInstance(api.InstanceCreate{
+		EnvironmentID: prodEnvironment.ID,
+		Name:          instanceName,
+		Engine:        db.SQLite,
+		Host:          instanceDir,
+	})
+	if err != nil {
+		t.Fatalf("failed to add instance, error: %v", err)
+	}
+
+	// Create an issue that creates a database.
+	databaseName := "testGiteaVCS"
+	if err := ctl.createDatabase(project, instance, databaseName, nil /* labelMap */); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate Git commits for schema update.
+	gitFile := "bbtest/Prod/testGiteaVCS__ver1__migrate__create_a_test_table.sql"
+	pushEvent := &gitea.WebhookPushEvent{
+		Ref: "refs/heads/feature/foo",
+		Repository: gitea.WebhookRepository{
+			FullName: repositoryFullName,
+		},
+		CommitList: []gitea.WebhookCommit{
+			{
+				Message:   "Create a test table",
+				Timestamp: "2021-01-13T13:14:00Z",
+				AddedList: []string{
+					gitFile,
+				},
+			},
+		},
+	}
+	if err := ctl.gitea.AddFiles(repositoryFullName, map[string]string{gitFile: migrationStatement}); err != nil {
+		t.Fatalf("failed to add files to gitea repository %v, error %v", repositoryFullName, err)
+	}
+	if err := ctl.gitea.SendCommits(repositoryFullName, pushEvent); err != nil {
+		t.Fatalf("failed to send commits to gitea repository %v, error %v", repositoryFullName, err)
+	}
+
+	// Get schema update issue.
+	openStatus := []api.IssueStatus{api.IssueOpen}
+	issues, err := ctl.getIssues(api.IssueFind{ProjectID: &project.ID, StatusList: &openStatus})
+	if err != nil {
+		t.Fatalf("failed to get open issues for project %v, error: %v", project.ID, err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("invalid number of open issues %v in project %v, expecting one issue", len(issues), project.ID)
+	}
+	issue := issues[0]
+	status, err := ctl.waitIssuePipeline(issue.ID)
+	if err != nil {
+		t.Fatalf("failed to wait for issue %v pipeline %v, error: %v", issue.ID, issue.Pipeline.ID, err)
+	}
+	if status != api.TaskDone {
+		t.Fatalf("issue %v pipeline %v is expected to finish with status done got %v", issue.ID, issue.Pipeline.ID, status)
+	}
+
+	// Query schema.
+	result, err := ctl.query(instance, databaseName, bookTableQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bookSchemaSQLResult != result {
+		t.Fatalf("SQL result want %q, got %q, diff %q", bookSchemaSQLResult, result, pretty.Diff(bookSchemaSQLResult, result))
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/tests/fake"
 	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
 )
 
 //go:embed fake
@@ -98,26 +99,30 @@ type controller struct {
 	client    *http.Client
 	cookie    string
 	gitlab    *fake.GitLab
+	gitea     *fake.Gitea
 	rootURL   string
 	gitURL    string
 	gitAPIURL string
+	giteaURL  string
 }
 
 func getTestPort(testName string) int {
-	// The port should be incremented by 3 for bytebase, Postgres, and GitLab.
+	// The port should be incremented by 4 for bytebase, Postgres, GitLab and Gitea.
 	switch testName {
 	case "TestServiceStart":
 		return 1234
 	case "TestSchemaAndDataUpdate":
-		return 1237
+		return 1238
 	case "TestVCS":
-		return 1240
+		return 1242
 	case "TestTenant":
-		return 1243
-	case "TestTenantVCS":
 		return 1246
+	case "TestTenantVCS":
+		return 1250
 	case "TestTenantDatabaseNameTemplate":
-		return 1249
+		return 1254
+	case "TestGiteaVCS":
+		return 1258
 	}
 	panic(fmt.Sprintf("test %q doesn't have assigned port, please set it in getTestPort()", testName))
 }
@@ -144,6 +149,11 @@ func (ctl *controller) StartMain(ctx context.Context, dataDir string, port int)
 	ctl.gitURL = fmt.Sprintf("http://localhost:%d", gitlabPort)
 	ctl.gitAPIURL = fmt.Sprintf("%s/api/v4", ctl.gitURL)
 
+	// set up gitea.
+	giteaPort := port + 3
+	ctl.gitea = fake.NewGitea(giteaPort)
+	ctl.giteaURL = fmt.Sprintf("http://localhost:%d", giteaPort)
+
 	errChan := make(chan error, 1)
 	go func() {
 		if err := ctl.main.Run(ctx); err != nil {
@@ -155,13 +165,21 @@ func (ctl *controller) StartMain(ctx context.Context, dataDir string, port int)
 			errChan <- fmt.Errorf("failed to run gitlab server, error: %w", err)
 		}
 	}()
+	go func() {
+		if err := ctl.gitea.Run(); err != nil {
+			errChan <- fmt.Errorf("failed to run gitea server, error: %w", err)
+		}
+	}()
 
 	if err := waitForServerStart(ctl.main, errChan); err != nil {
 		return fmt.Errorf("failed to wait for server to start, error: %w", err)
 	}
-	if err := waitForGitLabStart(ctl.gitlab, errChan); err != nil {
+	if err := waitForFakeVCSStart(ctl.gitlab.Echo, errChan); err != nil {
 		return fmt.Errorf("failed to wait for gitlab to start, error: %w", err)
 	}
+	if err := waitForFakeVCSStart(ctl.gitea.Echo, errChan); err != nil {
+		return fmt.Errorf("failed to wait for gitea to start, error: %w", err)
+	}
 
 	// initialize controller clients.
 	ctl.client = &http.Client{}
@@ -196,17 +214,18 @@ func waitForServerStart(m *cmd.Main, errChan <-chan error) error {
 	}
 }
 
-func waitForGitLabStart(g *fake.GitLab, errChan <-chan error) error {
+// waitForFakeVCSStart waits for the fake VCS server such as GitLab and Gitea to start.
+func waitForFakeVCSStart(e *echo.Echo, errChan <-chan error) error {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			if g.Echo == nil {
+			if e == nil {
 				continue
 			}
-			addr := g.Echo.ListenerAddr()
+			addr := e.ListenerAddr()
 			if addr != nil && strings.Contains(addr.String(), ":") {
 				return nil // was started
 			}
@@ -229,6 +248,11 @@ func (ctl *controller) Close() error {
 			e = err
 		}
 	}
+	if ctl.gitea != nil {
+		if err := ctl.gitea.Close(); err != nil {
+			e = err
+		}
+	}
 	return e
 }
 